@@ -47,6 +47,9 @@ type EventingTriggers map[string]*EventingTrigger // Key here is resource id -->
 // FileStoreRules is a map which stores database config information
 type FileStoreRules map[string]*FileRule // Key here is resource id --> clusterId--projectId--resourceType--fileRuleId
 
+// GraphQLPersistedQueries is a map which stores the graphql queries allowed to be executed
+type GraphQLPersistedQueries map[string]*GraphQLPersistedQuery // Key here is resource id --> clusterId--projectId--resourceType--queryId
+
 // IngressRoutes is a map which stores database config information
 type IngressRoutes map[string]*Route // Key here is resource id --> clusterId--projectId--resourceType--routeId
 
@@ -75,6 +78,8 @@ type Project struct {
 	IngressGlobal *GlobalRoutesConfig `json:"ingressGlobal" yaml:"ingressGlobal" mapstructure:"ingressGlobal"`
 
 	RemoteService Services `json:"remoteServices" yaml:"remoteServices" mapstructure:"remoteServices"`
+
+	GraphQLPersistedQueries GraphQLPersistedQueries `json:"graphqlPersistedQueries" yaml:"graphqlPersistedQueries" mapstructure:"graphqlPersistedQueries"`
 }
 
 // ProjectConfig stores information of individual project
@@ -87,6 +92,36 @@ type ProjectConfig struct {
 	AESKey             string    `json:"aesKey,omitempty" yaml:"aesKey,omitempty" mapstructure:"aesKey"`
 	DockerRegistry     string    `json:"dockerRegistry,omitempty" yaml:"dockerRegistry,omitempty" mapstructure:"dockerRegistry"`
 	ContextTimeGraphQL int       `json:"contextTimeGraphQL,omitempty" yaml:"contextTimeGraphQL,omitempty" mapstructure:"contextTimeGraphQL"` // contextTime sets the timeout of query
+	// GraphQLLimits limits the depth, size and cost of the graphql queries
+	GraphQLLimits *GraphQLLimits `json:"graphqlLimits,omitempty" yaml:"graphqlLimits,omitempty" mapstructure:"graphqlLimits"`
+	// EnforcePersistedQueries rejects the graphql queries which aren't persisted unless they are made by an admin
+	EnforcePersistedQueries bool `json:"enforcePersistedQueries,omitempty" yaml:"enforcePersistedQueries,omitempty" mapstructure:"enforcePersistedQueries"`
+	// PublicIntrospection allows the graphql introspection queries of the users other than admins. Introspection exposes
+	// the tables, fields, prepared queries and remote services of the project, hence it is only allowed for admins by default
+	PublicIntrospection bool `json:"publicIntrospection,omitempty" yaml:"publicIntrospection,omitempty" mapstructure:"publicIntrospection"`
+}
+
+// GraphQLLimits holds the limits of the graphql queries of a project. A limit is not enforced if its value is zero.
+// The cost of a query is the sum of the cost of the database queries, joins and remote service calls it makes. The cost
+// of the fields nested in a query is multiplied by the number of rows the query is expected to return.
+type GraphQLLimits struct {
+	MaxDepth  int `json:"maxDepth,omitempty" yaml:"maxDepth,omitempty" mapstructure:"maxDepth"`    // maximum nesting of the fields of a query
+	MaxFields int `json:"maxFields,omitempty" yaml:"maxFields,omitempty" mapstructure:"maxFields"` // maximum number of fields selected by a query
+	MaxCost   int `json:"maxCost,omitempty" yaml:"maxCost,omitempty" mapstructure:"maxCost"`
+
+	// Weights of the cost model. Default values are used if these aren't provided
+	DBCost       int `json:"dbCost,omitempty" yaml:"dbCost,omitempty" mapstructure:"dbCost"`                   // cost of a database query. Defaults to 1
+	JoinCost     int `json:"joinCost,omitempty" yaml:"joinCost,omitempty" mapstructure:"joinCost"`             // cost of a join. Defaults to 2
+	FuncCost     int `json:"funcCost,omitempty" yaml:"funcCost,omitempty" mapstructure:"funcCost"`             // cost of a remote service call. Defaults to 10
+	DefaultLimit int `json:"defaultLimit,omitempty" yaml:"defaultLimit,omitempty" mapstructure:"defaultLimit"` // rows expected from a query without a limit. Defaults to 100
+}
+
+// GraphQLPersistedQuery is a graphql query registered in the allow list of a project. The id of the query is the
+// sha256 hash of the query
+type GraphQLPersistedQuery struct {
+	ID    string `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	Name  string `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	Query string `json:"query,omitempty" yaml:"query,omitempty" mapstructure:"query"`
 }
 
 // DriverConfig stores the parameters for drivers of Databases.
@@ -109,8 +144,22 @@ type DatabaseConfig struct {
 	BatchRecords int          `json:"batchRecords,omitempty" yaml:"batchRecords" mapstructure:"batchRecords"` // indicates number of records per batch
 	Limit        int64        `json:"limit,omitempty" yaml:"limit" mapstructure:"limit"`                      // indicates number of records to send per request
 	DriverConf   DriverConfig `json:"driverConf,omitempty" yaml:"driverConf" mapstructure:"driverConf"`
+	// Replicas are the connection strings of the read replicas. Reads are served by the replicas when provided
+	Replicas        []string        `json:"replicas,omitempty" yaml:"replicas" mapstructure:"replicas"`
+	ReplicaStrategy ReplicaStrategy `json:"replicaStrategy,omitempty" yaml:"replicaStrategy" mapstructure:"replicaStrategy"`
 }
 
+// ReplicaStrategy describes the way a read replica is picked for a request. Default value - round-robin
+type ReplicaStrategy string
+
+const (
+	// ReplicaStrategyRoundRobin picks the read replicas one after the other
+	ReplicaStrategyRoundRobin ReplicaStrategy = "round-robin"
+
+	// ReplicaStrategyLeastConnections picks the read replica with the least number of requests in flight
+	ReplicaStrategyLeastConnections ReplicaStrategy = "least-connections"
+)
+
 // DatabaseSchema stores information of db schemas
 type DatabaseSchema struct {
 	Table   string `json:"col,omitempty" yaml:"col" mapstructure:"col"`
@@ -124,6 +173,7 @@ type DatabaseRule struct {
 	DbAlias                 string           `json:"dbAlias,omitempty" yaml:"dbAlias" mapstructure:"dbAlias"`
 	IsRealTimeEnabled       bool             `json:"isRealtimeEnabled,omitempty" yaml:"isRealtimeEnabled" mapstructure:"isRealtimeEnabled"`
 	EnableCacheInvalidation bool             `json:"enableCacheInvalidation,omitempty" yaml:"enableCacheInvalidation" mapstructure:"enableCacheInvalidation"`
+	EnableHistory           bool             `json:"enableHistory,omitempty" yaml:"enableHistory" mapstructure:"enableHistory"`
 	Rules                   map[string]*Rule `json:"rules,omitempty" yaml:"rules" mapstructure:"rules"`
 }
 
@@ -248,6 +298,20 @@ type DatbasePreparedQuery struct {
 	Rule      *Rule    `json:"rule" yaml:"rule" mapstructure:"rule"`
 	DbAlias   string   `json:"dbAlias" yaml:"dbAlias" mapstructure:"dbAlias"`
 	Arguments []string `json:"args" yaml:"args" mapstructure:"args"`
+
+	// Mongo holds the query of the prepared queries of mongo databases
+	Mongo *MongoPreparedQuery `json:"mongo,omitempty" yaml:"mongo,omitempty" mapstructure:"mongo"`
+}
+
+// MongoPreparedQuery is the query of a prepared query of a mongo database. Either an aggregation pipeline or a find
+// filter needs to be provided as an extended json template. String values of the form "$1", "$2" and so on in the
+// template are replaced by the respective arguments of the prepared query. Only scalar arguments are allowed unless
+// AllowObjectArgs is set since objects could inject query operators.
+type MongoPreparedQuery struct {
+	Collection      string `json:"collection" yaml:"collection" mapstructure:"collection"`
+	Pipeline        string `json:"pipeline,omitempty" yaml:"pipeline,omitempty" mapstructure:"pipeline"` // eg. [{"$match": {"author": "$1"}}]
+	Find            string `json:"find,omitempty" yaml:"find,omitempty" mapstructure:"find"`             // eg. {"author": "$1"}
+	AllowObjectArgs bool   `json:"allowObjectArgs,omitempty" yaml:"allowObjectArgs,omitempty" mapstructure:"allowObjectArgs"`
 }
 
 // TableRule contains the config at the collection level
@@ -292,6 +356,50 @@ type AuthStub struct {
 	ID      string `json:"id" yaml:"id" mapstructure:"id"`
 	Enabled bool   `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
 	Secret  string `json:"secret" yaml:"secret" mapstructure:"secret"`
+
+	// Fields of an OpenID Connect or OAuth2 provider. The endpoints of the provider are discovered from the issuer if not provided
+	Issuer       string            `json:"issuer,omitempty" yaml:"issuer,omitempty" mapstructure:"issuer"`
+	ClientID     string            `json:"clientId,omitempty" yaml:"clientId,omitempty" mapstructure:"clientId"`
+	ClientSecret string            `json:"clientSecret,omitempty" yaml:"clientSecret,omitempty" mapstructure:"clientSecret"`
+	Scopes       []string          `json:"scopes,omitempty" yaml:"scopes,omitempty" mapstructure:"scopes"`
+	AuthURL      string            `json:"authUrl,omitempty" yaml:"authUrl,omitempty" mapstructure:"authUrl"`
+	TokenURL     string            `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty" mapstructure:"tokenUrl"`
+	UserInfoURL  string            `json:"userInfoUrl,omitempty" yaml:"userInfoUrl,omitempty" mapstructure:"userInfoUrl"`
+	JwksURL      string            `json:"jwksUrl,omitempty" yaml:"jwksUrl,omitempty" mapstructure:"jwksUrl"`
+	CallbackURL  string            `json:"callbackUrl,omitempty" yaml:"callbackUrl,omitempty" mapstructure:"callbackUrl"`    // The redirect uri registered with the provider
+	RedirectURLs []string          `json:"redirectUrls,omitempty" yaml:"redirectUrls,omitempty" mapstructure:"redirectUrls"` // The urls of the app a user can be sent to after signing in
+	ClaimMapping map[string]string `json:"claimMapping,omitempty" yaml:"claimMapping,omitempty" mapstructure:"claimMapping"` // The key is the field of the users table
+	TrustEmail   bool              `json:"trustEmail,omitempty" yaml:"trustEmail,omitempty" mapstructure:"trustEmail"`       // Treat the emails of a provider which doesn't return the email_verified claim as verified
+
+	// Fields of the email provider to verify emails and reset passwords
+	RequireEmailVerification bool    `json:"requireEmailVerification,omitempty" yaml:"requireEmailVerification,omitempty" mapstructure:"requireEmailVerification"`
+	Mailer                   *Mailer `json:"mailer,omitempty" yaml:"mailer,omitempty" mapstructure:"mailer"`
+}
+
+// Mailer holds the config of the mailer used to send emails to the users of a project
+type Mailer struct {
+	Type string `json:"type" yaml:"type" mapstructure:"type"` // Can be smtp or webhook
+	From string `json:"from,omitempty" yaml:"from,omitempty" mapstructure:"from"`
+
+	// Fields of the smtp mailer
+	Host     string `json:"host,omitempty" yaml:"host,omitempty" mapstructure:"host"`
+	Port     int    `json:"port,omitempty" yaml:"port,omitempty" mapstructure:"port"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty" mapstructure:"username"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty" mapstructure:"password"`
+
+	// Fields of the webhook mailer
+	URL     string  `json:"url,omitempty" yaml:"url,omitempty" mapstructure:"url"`
+	Headers Headers `json:"headers,omitempty" yaml:"headers,omitempty" mapstructure:"headers"`
+
+	// The go templates of the emails. The key can be verify-email or reset-password
+	Templates map[string]*MailTemplate `json:"templates,omitempty" yaml:"templates,omitempty" mapstructure:"templates"`
+}
+
+// MailTemplate holds the go templates of the subject and body of an email
+type MailTemplate struct {
+	Subject string `json:"subject" yaml:"subject" mapstructure:"subject"`
+	Body    string `json:"body" yaml:"body" mapstructure:"body"`
+	HTML    bool   `json:"html,omitempty" yaml:"html,omitempty" mapstructure:"html"`
 }
 
 // ServicesModule holds the config for the service module
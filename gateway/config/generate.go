@@ -31,5 +31,6 @@ func GenerateEmptyProject(project *ProjectConfig) *Project {
 		IngressRoutes:           make(IngressRoutes),
 		IngressGlobal:           new(GlobalRoutesConfig),
 		RemoteService:           make(Services),
+		GraphQLPersistedQueries: make(GraphQLPersistedQueries),
 	}
 }
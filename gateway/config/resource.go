@@ -19,6 +19,7 @@ var ResourceFetchingOrder = []Resource{
 	ResourceEventingRule,
 	ResourceEventingSchema,
 	ResourceRemoteService,
+	ResourceGraphQLPersistedQuery,
 	ResourceIngressGlobal,
 	ResourceIngressRoute,
 	ResourceAuthProvider,
@@ -73,6 +74,9 @@ const (
 	// ResourceRemoteService is a resource
 	ResourceRemoteService Resource = "remote-service"
 
+	// ResourceGraphQLPersistedQuery is a resource
+	ResourceGraphQLPersistedQuery Resource = "graphql-persisted-query"
+
 	// ResourceIntegration is a resource
 	ResourceIntegration Resource = "integration"
 	// ResourceIntegrationHook is a resource
@@ -95,6 +99,8 @@ const (
 	// ResourceDatabaseRead        Resource = "db-read"
 	// ResourceDatabaseUpdate      Resource = "db-update"
 	// ResourceDatabaseDelete      Resource = "db-delete"
+	// ResourceDatabaseRestore     Resource = "db-restore"
+	// ResourceDatabaseHistory     Resource = "db-history"
 	// ResourceDatabaseAggregate   Resource = "db-aggregate"
 	// ResourceDatabasePreparedSQL Resource = "db-prepared-sql"
 	// ResourceEventTrigger        Resource = "eventing-trigger"
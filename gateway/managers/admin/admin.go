@@ -43,6 +43,17 @@ func (m *Manager) SetSyncMan(s model.SyncManAdminInterface) {
 	m.syncMan = s
 }
 
+// IsLeaderGateway checks if the current gateway is the leader of the cluster
+func (m *Manager) IsLeaderGateway() (bool, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.syncMan == nil {
+		return false, nil
+	}
+	return m.syncMan.CheckIfLeaderGateway(m.nodeID)
+}
+
 // SetIntegrationMan sets integration manager
 func (m *Manager) SetIntegrationMan(i IntegrationInterface) {
 	m.lock.Lock()
@@ -356,6 +356,19 @@ func (s *Manager) validateResource(ctx context.Context, eventType string, resour
 			}
 		}
 		return false, nil
+	case config.ResourceGraphQLPersistedQuery:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.GraphQLPersistedQuery)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.GraphQLPersistedQuery{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if reflect.DeepEqual(project.GraphQLPersistedQueries[resourceID], value) {
+				return true, nil
+			}
+		}
+		return false, nil
 	default:
 		return false, fmt.Errorf("unknown resource type (%s) provided", resourceType)
 	}
@@ -739,6 +752,26 @@ func updateResource(ctx context.Context, eventType string, globalConfig *config.
 
 		return nil
 
+	case config.ResourceGraphQLPersistedQuery:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.GraphQLPersistedQuery)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.GraphQLPersistedQuery{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if project.GraphQLPersistedQueries == nil {
+				project.GraphQLPersistedQueries = config.GraphQLPersistedQueries{resourceID: value}
+			} else {
+				project.GraphQLPersistedQueries[resourceID] = value
+			}
+
+		case config.ResourceDeleteEvent:
+			delete(project.GraphQLPersistedQueries, resourceID)
+		}
+
+		return nil
+
 	default:
 		return fmt.Errorf("unknown resource type (%s) provided", resourceType)
 	}
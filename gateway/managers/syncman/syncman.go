@@ -232,6 +232,9 @@ func (s *Manager) Start(port int) error {
 		case config.ResourceRemoteService:
 			_ = s.modules.SetRemoteServiceConfig(ctx, projectID, s.projectConfig.Projects[projectID].RemoteService)
 
+		case config.ResourceGraphQLPersistedQuery:
+			_ = s.modules.SetGraphQLPersistedQueriesConfig(ctx, projectID, s.projectConfig.Projects[projectID].GraphQLPersistedQueries)
+
 		case config.ResourceCluster:
 			s.globalModules.SetMetricsConfig(s.projectConfig.ClusterConfig.EnableTelemetry)
 			s.modules.LetsEncrypt().SetLetsEncryptEmail(s.projectConfig.ClusterConfig.LetsEncryptEmail)
@@ -0,0 +1,73 @@
+package syncman
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// SetCopyTableSchema creates the table which rows of another table are copied to. The schema of the source table is
+// used if it has one, else it is inferred from the rows of the source table. Tables which already have a schema are left as is
+func (s *Manager) SetCopyTableSchema(ctx context.Context, project, dbAlias, col, targetDbAlias, targetCol string, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	for _, alias := range []string{dbAlias, targetDbAlias} {
+		if _, p := s.checkIfDbAliasExists(projectConfig.DatabaseConfigs, alias); !p {
+			return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to create table to copy to as provided db alias (%s) does not exists", alias), nil, nil)
+		}
+	}
+
+	if _, p := projectConfig.DatabaseSchemas[config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseSchema, targetDbAlias, targetCol)]; p {
+		return http.StatusOK, nil
+	}
+
+	var schema string
+	if dbSchema, p := projectConfig.DatabaseSchemas[config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseSchema, dbAlias, col)]; p {
+		schema = dbSchema.Schema
+	} else {
+		schemaMod, err := s.modules.GetSchemaModuleForSyncMan(project)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		schema, err = schemaMod.InferSchema(ctx, dbAlias, col)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	return s.setModifySchemaWithoutLock(ctx, project, projectConfig, targetDbAlias, targetCol, &config.DatabaseSchema{Schema: renameSchemaType(schema, col, targetCol)})
+}
+
+// renameSchemaType renames the type of a table in its schema. The other types in the schema are left as is
+func renameSchemaType(schema, from, to string) string {
+	if from == to {
+		return schema
+	}
+	re := regexp.MustCompile(`\btype\s+` + regexp.QuoteMeta(from) + `\s*\{`)
+	return re.ReplaceAllString(schema, "type "+to+" {")
+}
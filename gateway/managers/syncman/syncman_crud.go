@@ -12,6 +12,7 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/modules/crud"
 	helpers2 "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
 )
@@ -370,6 +371,11 @@ func (s *Manager) SetModifySchema(ctx context.Context, project, dbAlias, col str
 		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to modify schema provided db alias (%s) does not exists", dbAlias), nil, nil)
 	}
 
+	return s.setModifySchemaWithoutLock(ctx, project, projectConfig, dbAlias, col, v)
+}
+
+// setModifySchemaWithoutLock modifies the table as per the schema provided and stores the schema in config
+func (s *Manager) setModifySchemaWithoutLock(ctx context.Context, project string, projectConfig *config.Project, dbAlias, col string, v *config.DatabaseSchema) (int, error) {
 	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseSchema, dbAlias, col)
 	v.DbAlias = dbAlias
 	v.Table = col
@@ -425,10 +431,17 @@ func (s *Manager) SetCollectionRules(ctx context.Context, project, dbAlias, col
 
 func (s *Manager) setCollectionRules(ctx context.Context, projectConfig *config.Project, project, dbAlias, col string, v *config.DatabaseRule) (int, error) {
 	// update collection rules & is realtime in config
-	if _, p := s.checkIfDbAliasExists(projectConfig.DatabaseConfigs, dbAlias); !p {
+	dbConfig, p := s.checkIfDbAliasExists(projectConfig.DatabaseConfigs, dbAlias)
+	if !p {
 		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to set collection/table rules as provided db alias (%s) does not exists", dbAlias), nil, nil)
 	}
 
+	if v.EnableHistory {
+		if status, err := s.applyHistorySchema(ctx, projectConfig, project, dbAlias, col, dbConfig.DBName); err != nil {
+			return status, err
+		}
+	}
+
 	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseRule, dbAlias, col, "rule")
 	v.Table = col
 	v.DbAlias = dbAlias
@@ -449,6 +462,24 @@ func (s *Manager) setCollectionRules(ctx context.Context, projectConfig *config.
 	return http.StatusOK, nil
 }
 
+// applyHistorySchema creates the companion table storing the change history of the rows of a table. The table can only
+// be read through the history api of the table. Hence all operations on it are denied.
+func (s *Manager) applyHistorySchema(ctx context.Context, projectConfig *config.Project, project, dbAlias, col, dbName string) (int, error) {
+	historyTable := utils.HistoryTable(col)
+	if _, p := projectConfig.DatabaseRules[config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseRule, dbAlias, historyTable, "rule")]; p {
+		return http.StatusOK, nil
+	}
+
+	rules := map[string]*config.Rule{"create": {Rule: "deny"}, "read": {Rule: "deny"}, "update": {Rule: "deny"}, "delete": {Rule: "deny"}}
+	if err := s.applySchemas(ctx, project, dbAlias, projectConfig, config.CrudStub{
+		Collections: map[string]*config.TableRule{historyTable: {Schema: utils.HistorySchema(col), Rules: rules}},
+		DBName:      dbName,
+	}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return s.setCollectionRules(ctx, projectConfig, project, dbAlias, historyTable, &config.DatabaseRule{Rules: rules})
+}
+
 // DeleteCollectionRules deletes the collection rules of the database
 func (s *Manager) DeleteCollectionRules(ctx context.Context, project, dbAlias, col string, params model.RequestParams) (int, error) {
 	// Check if the request has been hijacked
@@ -844,6 +875,41 @@ func (s *Manager) GetSchemas(ctx context.Context, project, dbAlias, col, format
 	return http.StatusOK, arr, nil
 }
 
+// ExportSchemas exports the schemas from config as json schema documents or as an openapi spec
+func (s *Manager) ExportSchemas(ctx context.Context, project, dbAlias, col, format string, params model.RequestParams) (int, interface{}, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), hookResponse.Result(), nil
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	schemaMod, err := s.modules.GetSchemaModuleForSyncMan(project)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	result, err := schemaMod.ExportSchemas(ctx, dbAlias, col, format)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	return http.StatusOK, result, nil
+}
+
 type result struct {
 	Result []*secret `json:"result,omitempty"`
 }
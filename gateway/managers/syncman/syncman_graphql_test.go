@@ -0,0 +1,154 @@
+package syncman
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func TestManager_SetGraphQLPersistedQuery(t *testing.T) {
+	type mockArgs struct {
+		method         string
+		args           []interface{}
+		paramsReturned []interface{}
+	}
+	type args struct {
+		ctx     context.Context
+		project string
+		id      string
+		value   *config.GraphQLPersistedQuery
+	}
+
+	query := "query { users @db { id } }"
+	id := "3dd1bea47c575c15f4305c12b47274de2be65018dd0396fc334c653eb1dee1cf" // sha256 hash of the query
+
+	tests := []struct {
+		name            string
+		s               *Manager
+		args            args
+		modulesMockArgs []mockArgs
+		storeMockArgs   []mockArgs
+		wantErr         bool
+	}{
+		{
+			name: "Id is not the hash of the query",
+			s: &Manager{
+				clusterID: "chicago",
+				projectConfig: &config.Config{
+					Projects: config.Projects{
+						"myproject": &config.Project{
+							ProjectConfig: &config.ProjectConfig{ID: "myproject"},
+						},
+					},
+				},
+			},
+			args:    args{ctx: context.Background(), project: "myproject", id: "getUsers", value: &config.GraphQLPersistedQuery{Query: query}},
+			wantErr: true,
+		},
+		{
+			name: "Project config not found",
+			s: &Manager{
+				clusterID: "chicago",
+				projectConfig: &config.Config{
+					Projects: config.Projects{
+						"myproject": &config.Project{
+							ProjectConfig: &config.ProjectConfig{ID: "myproject"},
+						},
+					},
+				},
+			},
+			args:    args{ctx: context.Background(), project: "test", id: id, value: &config.GraphQLPersistedQuery{Query: query}},
+			wantErr: true,
+		},
+		{
+			name: "Persisted queries are nil and add a new query",
+			s: &Manager{
+				clusterID: "chicago",
+				projectConfig: &config.Config{
+					Projects: config.Projects{
+						"myproject": &config.Project{
+							ProjectConfig: &config.ProjectConfig{ID: "myproject"},
+						},
+					},
+				},
+			},
+			args: args{ctx: context.Background(), project: "myproject", id: id, value: &config.GraphQLPersistedQuery{Name: "getUsers", Query: query}},
+			modulesMockArgs: []mockArgs{
+				{
+					method: "SetGraphQLPersistedQueriesConfig",
+					args: []interface{}{mock.Anything, "myproject", config.GraphQLPersistedQueries{
+						config.GenerateResourceID("chicago", "myproject", config.ResourceGraphQLPersistedQuery, id): &config.GraphQLPersistedQuery{ID: id, Name: "getUsers", Query: query},
+					}},
+					paramsReturned: []interface{}{nil},
+				},
+			},
+			storeMockArgs: []mockArgs{
+				{
+					method:         "SetResource",
+					args:           []interface{}{mock.Anything, config.GenerateResourceID("chicago", "myproject", config.ResourceGraphQLPersistedQuery, id), &config.GraphQLPersistedQuery{ID: id, Name: "getUsers", Query: query}},
+					paramsReturned: []interface{}{nil},
+				},
+			},
+		},
+		{
+			name: "unable to set resource",
+			s: &Manager{
+				clusterID: "chicago",
+				projectConfig: &config.Config{
+					Projects: config.Projects{
+						"myproject": &config.Project{
+							ProjectConfig: &config.ProjectConfig{ID: "myproject"},
+						},
+					},
+				},
+			},
+			args: args{ctx: context.Background(), project: "myproject", id: id, value: &config.GraphQLPersistedQuery{Query: query}},
+			modulesMockArgs: []mockArgs{
+				{
+					method:         "SetGraphQLPersistedQueriesConfig",
+					args:           []interface{}{mock.Anything, mock.Anything, mock.Anything},
+					paramsReturned: []interface{}{nil},
+				},
+			},
+			storeMockArgs: []mockArgs{
+				{
+					method:         "SetResource",
+					args:           []interface{}{mock.Anything, mock.Anything, mock.Anything},
+					paramsReturned: []interface{}{errors.New("unable to set resource")},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			mockModules := mockModulesInterface{}
+			mockStore := mockStoreInterface{}
+
+			for _, m := range tt.modulesMockArgs {
+				mockModules.On(m.method, m.args...).Return(m.paramsReturned...)
+			}
+			for _, m := range tt.storeMockArgs {
+				mockStore.On(m.method, m.args...).Return(m.paramsReturned...)
+			}
+
+			tt.s.modules = &mockModules
+			tt.s.store = &mockStore
+			tt.s.integrationMan = &mockIntegrationManager{skip: true}
+
+			_, err := tt.s.SetGraphQLPersistedQuery(tt.args.ctx, tt.args.project, tt.args.id, tt.args.value, model.RequestParams{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Manager.SetGraphQLPersistedQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			mockModules.AssertExpectations(t)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
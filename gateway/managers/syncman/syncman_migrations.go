@@ -0,0 +1,198 @@
+package syncman
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// PlanModifySchema returns the migration which would be applied on modifying the schema of a table without applying it
+func (s *Manager) PlanModifySchema(ctx context.Context, project, dbAlias, col string, v *config.DatabaseSchema, params model.RequestParams) (int, []*model.SchemaMigration, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil, nil
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	dbConfig, p := s.checkIfDbAliasExists(projectConfig.DatabaseConfigs, dbAlias)
+	if !p {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to plan schema modification as provided db alias (%s) does not exists", dbAlias), nil, nil)
+	}
+
+	v.DbAlias = dbAlias
+	v.Table = col
+	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseSchema, dbAlias, col)
+
+	schemaMod, err := s.modules.GetSchemaModuleForSyncMan(project)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	migrations, err := schemaMod.PlanSchemaModification(ctx, dbAlias, dbConfig.DBName, config.DatabaseSchemas{resourceID: v})
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	return http.StatusOK, migrations, nil
+}
+
+// GetSchemaMigrations returns the schema migrations recorded for a database
+func (s *Manager) GetSchemaMigrations(ctx context.Context, project, dbAlias string, params model.RequestParams) (int, []*model.SchemaMigration, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil, nil
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	dbConfig, p := s.checkIfDbAliasExists(projectConfig.DatabaseConfigs, dbAlias)
+	if !p {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to get schema migrations as provided db alias (%s) does not exists", dbAlias), nil, nil)
+	}
+
+	schemaMod, err := s.modules.GetSchemaModuleForSyncMan(project)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	migrations, err := schemaMod.GetSchemaMigrations(ctx, dbAlias, dbConfig.DBName)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	return http.StatusOK, migrations, nil
+}
+
+// ApplySchemaMigrations applies the rolled back schema migrations of a database till the version provided
+func (s *Manager) ApplySchemaMigrations(ctx context.Context, project, dbAlias string, version int, params model.RequestParams) (int, error) {
+	return s.migrateSchema(ctx, project, dbAlias, version, params, false)
+}
+
+// RollbackSchemaMigrations reverts the schema migrations of a database newer than the version provided
+func (s *Manager) RollbackSchemaMigrations(ctx context.Context, project, dbAlias string, version int, params model.RequestParams) (int, error) {
+	return s.migrateSchema(ctx, project, dbAlias, version, params, true)
+}
+
+// migrateSchema applies or rolls back the schema migrations and updates the schema of the tables migrated in the config
+func (s *Manager) migrateSchema(ctx context.Context, project, dbAlias string, version int, params model.RequestParams, isRollback bool) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	dbConfig, p := s.checkIfDbAliasExists(projectConfig.DatabaseConfigs, dbAlias)
+	if !p {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to migrate schema as provided db alias (%s) does not exists", dbAlias), nil, nil)
+	}
+
+	schemaMod, err := s.modules.GetSchemaModuleForSyncMan(project)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	// The config is updated for the migrations processed before a failure as well since they have changed the database
+	var migrations []*model.SchemaMigration
+	var migrateErr error
+	if isRollback {
+		migrations, migrateErr = schemaMod.RollbackSchemaMigrations(ctx, dbAlias, dbConfig.DBName, version)
+	} else {
+		migrations, migrateErr = schemaMod.ApplySchemaMigrations(ctx, dbAlias, dbConfig.DBName, version)
+	}
+	if len(migrations) == 0 {
+		if migrateErr != nil {
+			return http.StatusInternalServerError, migrateErr
+		}
+		return http.StatusOK, nil
+	}
+
+	// The schema of each table migrated is the one of the last migration processed for it
+	if projectConfig.DatabaseSchemas == nil {
+		projectConfig.DatabaseSchemas = make(config.DatabaseSchemas)
+	}
+	updatedSchemas := make(map[string]*config.DatabaseSchema)
+	for _, migration := range migrations {
+		resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseSchema, dbAlias, migration.Table)
+		schema := migration.Schema
+		if isRollback {
+			schema = migration.PreviousSchema
+		}
+		updatedSchemas[resourceID] = &config.DatabaseSchema{Table: migration.Table, DbAlias: dbAlias, Schema: schema}
+	}
+
+	for resourceID, dbSchema := range updatedSchemas {
+		if dbSchema.Schema == "" {
+			delete(projectConfig.DatabaseSchemas, resourceID)
+			continue
+		}
+		projectConfig.DatabaseSchemas[resourceID] = dbSchema
+	}
+
+	if err := s.modules.SetDatabaseSchemaConfig(ctx, project, projectConfig.DatabaseSchemas); err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set crud config", err, nil)
+	}
+
+	for resourceID, dbSchema := range updatedSchemas {
+		if dbSchema.Schema == "" {
+			if err := s.store.DeleteResource(ctx, resourceID); err != nil {
+				return http.StatusInternalServerError, err
+			}
+			continue
+		}
+		if err := s.store.SetResource(ctx, resourceID, dbSchema); err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	if migrateErr != nil {
+		return http.StatusInternalServerError, migrateErr
+	}
+	return http.StatusOK, nil
+}
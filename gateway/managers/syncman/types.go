@@ -50,6 +50,9 @@ type ModulesInterface interface {
 	// SetServicesConfig sets the config of auth and functions modules
 	SetRemoteServiceConfig(ctx context.Context, projectID string, services config.Services) error
 
+	// SetGraphQLPersistedQueriesConfig sets the allow list of the graphql module
+	SetGraphQLPersistedQueriesConfig(ctx context.Context, projectID string, queries config.GraphQLPersistedQueries) error
+
 	SetLetsencryptConfig(ctx context.Context, projectID string, c *config.LetsEncrypt) error
 
 	SetIngressRouteConfig(ctx context.Context, projectID string, routes config.IngressRoutes) error
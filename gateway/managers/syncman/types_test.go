@@ -166,6 +166,10 @@ func (m *mockModulesInterface) SetRemoteServiceConfig(ctx context.Context, proje
 	return m.Called(ctx, projectID, services).Error(0)
 }
 
+func (m *mockModulesInterface) SetGraphQLPersistedQueriesConfig(ctx context.Context, projectID string, queries config.GraphQLPersistedQueries) error {
+	return m.Called(ctx, projectID, queries).Error(0)
+}
+
 func (m *mockModulesInterface) SetLetsencryptConfig(ctx context.Context, projectID string, c *config.LetsEncrypt) error {
 	return m.Called(ctx, projectID, c).Error(0)
 }
@@ -336,3 +340,33 @@ func (m *mockSchemaEventingInterface) GetSchemaForDB(ctx context.Context, dbAlia
 	c := m.Called(ctx, dbAlias, col, format)
 	return c.Get(0).([]interface{}), c.Error(1)
 }
+
+func (m *mockSchemaEventingInterface) ExportSchemas(ctx context.Context, dbAlias, col, format string) (interface{}, error) {
+	c := m.Called(ctx, dbAlias, col, format)
+	return c.Get(0), c.Error(1)
+}
+
+func (m *mockSchemaEventingInterface) InferSchema(ctx context.Context, dbAlias, col string) (string, error) {
+	c := m.Called(ctx, dbAlias, col)
+	return c.String(0), c.Error(1)
+}
+
+func (m *mockSchemaEventingInterface) PlanSchemaModification(ctx context.Context, dbAlias, logicalDBName string, dbSchemas config.DatabaseSchemas) ([]*model.SchemaMigration, error) {
+	c := m.Called(ctx, dbAlias, logicalDBName, dbSchemas)
+	return c.Get(0).([]*model.SchemaMigration), c.Error(1)
+}
+
+func (m *mockSchemaEventingInterface) GetSchemaMigrations(ctx context.Context, dbAlias, logicalDBName string) ([]*model.SchemaMigration, error) {
+	c := m.Called(ctx, dbAlias, logicalDBName)
+	return c.Get(0).([]*model.SchemaMigration), c.Error(1)
+}
+
+func (m *mockSchemaEventingInterface) ApplySchemaMigrations(ctx context.Context, dbAlias, logicalDBName string, version int) ([]*model.SchemaMigration, error) {
+	c := m.Called(ctx, dbAlias, logicalDBName, version)
+	return c.Get(0).([]*model.SchemaMigration), c.Error(1)
+}
+
+func (m *mockSchemaEventingInterface) RollbackSchemaMigrations(ctx context.Context, dbAlias, logicalDBName string, version int) ([]*model.SchemaMigration, error) {
+	c := m.Called(ctx, dbAlias, logicalDBName, version)
+	return c.Get(0).([]*model.SchemaMigration), c.Error(1)
+}
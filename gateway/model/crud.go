@@ -23,6 +23,9 @@ type ReadRequest struct {
 	Cache     *config.ReadCacheOptions `json:"cache"`
 }
 
+// ExportCallback is invoked with every document streamed by an export
+type ExportCallback func(doc map[string]interface{}) error
+
 // ReadOptions is the options required for a read request
 type ReadOptions struct {
 	// Debug field is used internally to show
@@ -32,10 +35,16 @@ type ReadOptions struct {
 	Sort       []string         `json:"sort"`
 	Skip       *int64           `json:"skip"`
 	Limit      *int64           `json:"limit"`
+	After      *string          `json:"after"`  // cursor after which the documents are to be read
+	Before     *string          `json:"before"` // cursor before which the documents are to be read
 	Distinct   *string          `json:"distinct"`
 	Join       []*JoinOption    `json:"join"`
 	ReturnType string           `json:"returnType"`
-	HasOptions bool             `json:"hasOptions"` // used internally
+	// IncludeDeleted returns the soft deleted documents as well
+	IncludeDeleted bool `json:"includeDeleted"`
+	// ReadYourWrites serves the request from the primary database instead of a read replica
+	ReadYourWrites bool `json:"readYourWrites"`
+	HasOptions     bool `json:"hasOptions"` // used internally
 }
 
 // JoinOption describes the way a join needs to be performed
@@ -54,9 +63,10 @@ type JoinOption struct {
 
 // UpdateRequest is the http body received for an update request
 type UpdateRequest struct {
-	Find      map[string]interface{} `json:"find"`
-	Operation string                 `json:"op"`
-	Update    map[string]interface{} `json:"update"`
+	Find        map[string]interface{} `json:"find"`
+	Operation   string                 `json:"op"`
+	Update      map[string]interface{} `json:"update"`
+	IsVersioned bool                   `json:"-"` // used internally
 }
 
 // DeleteRequest is the http body received for a delete request
@@ -65,9 +75,80 @@ type DeleteRequest struct {
 	Operation string                 `json:"op"`
 }
 
+// RestoreRequest is the http body received for a request to restore soft deleted documents
+type RestoreRequest struct {
+	Find      map[string]interface{} `json:"find"`
+	Operation string                 `json:"op"`
+}
+
+// HistoryRequest is the http body received for a request to fetch the change history of a row
+type HistoryRequest struct {
+	Key   map[string]interface{} `json:"key"` // value of the primary key of the row
+	Limit *int64                 `json:"limit"`
+}
+
+const (
+	// ImportModeAtomic inserts either all the rows of an import or none of them
+	ImportModeAtomic string = "atomic"
+
+	// ImportModeBestEffort inserts all the valid rows of an import and reports the ones which failed
+	ImportModeBestEffort string = "best-effort"
+)
+
+// ImportRequest holds the rows to be inserted by an import
+type ImportRequest struct {
+	Rows      []*ImportRow
+	Mode      string
+	ChunkSize int
+	// IsCSV indicates that the values of the rows are strings which need to be parsed as per the schema
+	IsCSV bool
+}
+
+// ImportRow is a single row of an import along with its position in the imported file
+type ImportRow struct {
+	Row      int
+	Document map[string]interface{}
+}
+
+// ImportRowError describes why a row of an import could not be inserted
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// ImportResponse is the report of an import
+type ImportResponse struct {
+	Inserted int64             `json:"inserted"`
+	Errors   []*ImportRowError `json:"errors"`
+}
+
+// CopyRequest is the http body received for a request to copy the rows of a table to a table of another database
+type CopyRequest struct {
+	TargetDbAlias string `json:"targetDbAlias"`
+	TargetCol     string `json:"targetCol"`
+	ChunkSize     int    `json:"chunkSize"`
+	// Cursor is the cursor of the last checkpoint of an earlier copy. It is used to resume a copy after the last row it processed
+	Cursor string `json:"cursor"`
+	// CreateTable creates the target table from the schema of the source table if it doesn't have a schema
+	CreateTable bool `json:"createTable"`
+}
+
+// CopyCheckpoint is the progress of a copy which is reported after every chunk
+type CopyCheckpoint struct {
+	// Cursor points to the last row of the source table processed so far. It is empty for sources which cannot resume a copy
+	Cursor string `json:"cursor,omitempty"`
+	// Processed is the number of rows of the source table processed by this copy
+	Processed int64             `json:"processed"`
+	Copied    int64             `json:"copied"`
+	Errors    []*ImportRowError `json:"errors,omitempty"`
+	Done      bool              `json:"done"`
+}
+
 // PreparedQueryRequest is the http body received for a PreparedQuery request
 type PreparedQueryRequest struct {
 	Params map[string]interface{} `json:"params"`
+	// ReadYourWrites serves the request from the primary database instead of a read replica
+	ReadYourWrites bool `json:"readYourWrites"`
 	// This field is used internally to show
 	// _query meta data in the graphql
 	Debug bool
@@ -75,20 +156,23 @@ type PreparedQueryRequest struct {
 
 // AggregateRequest is the http body received for an aggregate request
 type AggregateRequest struct {
-	Pipeline  interface{} `json:"pipe"`
-	Operation string      `json:"op"`
+	Pipeline       interface{} `json:"pipe"`
+	Operation      string      `json:"op"`
+	IncludeDeleted bool        `json:"includeDeleted"`
+	ReadYourWrites bool        `json:"readYourWrites"`
 }
 
 // AllRequest is a union of parameters required in the various requests
 type AllRequest struct {
-	Col       string                 `json:"col"`
-	Document  interface{}            `json:"doc"`
-	Operation string                 `json:"op"`
-	Find      map[string]interface{} `json:"find"`
-	Update    map[string]interface{} `json:"update"`
-	Type      string                 `json:"type"`
-	DBAlias   string                 `json:"dBAlias"`
-	Extras    map[string]interface{} `json:"extras"`
+	Col         string                 `json:"col"`
+	Document    interface{}            `json:"doc"`
+	Operation   string                 `json:"op"`
+	Find        map[string]interface{} `json:"find"`
+	Update      map[string]interface{} `json:"update"`
+	Type        string                 `json:"type"`
+	DBAlias     string                 `json:"dBAlias"`
+	Extras      map[string]interface{} `json:"extras"`
+	IsVersioned bool                   `json:"-"` // used internally
 }
 
 // SQLMetaData stores sql query information
@@ -124,6 +208,9 @@ const (
 	// SQLServer is the type used for MsSQL
 	SQLServer DBType = "sqlserver"
 
+	// SQLite is the type used for SQLite
+	SQLite DBType = "sqlite"
+
 	// DefaultValidate is used for default validation operation
 	DefaultValidate = "default"
 
@@ -24,4 +24,7 @@ const (
 
 	// Aggregation is the type used for aggregations
 	Aggregation OperationType = "aggr"
+
+	// Restore is the type used for restoring soft deleted documents
+	Restore OperationType = "restore"
 )
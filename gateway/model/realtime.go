@@ -42,7 +42,8 @@ type Message struct {
 
 // LiveQueryOptions is to set the options for realtime requests
 type LiveQueryOptions struct {
-	SkipInitial bool `json:"skipInitial"`
+	SkipInitial    bool `json:"skipInitial"`
+	IncludeDeleted bool `json:"includeDeleted"`
 }
 
 // SendFeed is the function called whenever a data point (feed) is to be sent
@@ -22,6 +22,8 @@ type (
 		// For directives
 		IsCreatedAt     bool `json:"isCreatedAt"`
 		IsUpdatedAt     bool `json:"isUpdatedAt"`
+		IsVersion       bool `json:"isVersion"`
+		IsSoftDelete    bool `json:"isSoftDelete"`
 		IsLinked        bool `json:"isLinked"`
 		IsForeign       bool `json:"isForeign"`
 		IsDefault       bool `json:"isDefault"`
@@ -32,6 +34,9 @@ type (
 		JointTable      *TableProperties   `json:"jointTable"`
 		Default         interface{}        `json:"default"`
 		TypeIDSize      int                `json:"size"`
+		Enum            []interface{}      `json:"enum,omitempty"`
+		// ExpireAfter is the number of seconds after the timestamp in the field that the row expires at
+		ExpireAfter *int64 `json:"expireAfter,omitempty"`
 	}
 
 	// FieldArgs are properties of the column
@@ -49,7 +54,9 @@ type (
 		// IsIndex tells us if this is an indexed column
 		IsIndex bool `json:"isIndex"`
 		// IsUnique tells us if this is an unique indexed column
-		IsUnique       bool `json:"isUnique"`
+		IsUnique bool `json:"isUnique"`
+		// IsFullText tells us if this is a full text indexed column
+		IsFullText     bool `json:"isFullText"`
 		From           string
 		To             string
 		Table          string
@@ -97,6 +104,10 @@ const (
 	TypeID string = "ID"
 	// TypeJSON is variable used for Variable of type Jsonb
 	TypeJSON string = "JSON"
+	// TypePoint is a data type used for storing GeoJSON points
+	TypePoint string = "Point"
+	// TypeGeometry is a data type used for storing GeoJSON geometries like polygons and lines
+	TypeGeometry string = "Geometry"
 	// DefaultCharacterSize is variable used for specifying size of sql type ID
 	DefaultCharacterSize int = 100
 	// TypeObject is a string with value object
@@ -107,6 +118,8 @@ const (
 	DirectiveUnique string = "unique"
 	// DirectiveIndex is used in schema module to add index
 	DirectiveIndex string = "index"
+	// DirectiveFullText is used in schema module to add full text index
+	DirectiveFullText string = "fulltext"
 	// DirectiveForeign is used in schema module to add foreign key
 	DirectiveForeign string = "foreign"
 	// DirectivePrimary is used in schema module to add primary key
@@ -117,8 +130,18 @@ const (
 	DirectiveCreatedAt string = "createdAt"
 	// DirectiveUpdatedAt  is used in schema module to add Updated location
 	DirectiveUpdatedAt string = "updatedAt"
+	// DirectiveVersion is used in schema module to add a version field for optimistic concurrency control
+	DirectiveVersion string = "version"
+	// DirectiveSoftDelete is used in schema module to mark a field which records the deletion of a row
+	DirectiveSoftDelete string = "softDelete"
+	// DirectiveTTL is used in schema module to expire a row a number of seconds after the timestamp in a field
+	DirectiveTTL string = "ttl"
+	// DirectiveExpiresAt is used in schema module to expire a row at the timestamp in a field
+	DirectiveExpiresAt string = "expiresAt"
 	// DirectiveLink is used in schema module to add link
 	DirectiveLink string = "link"
+	// DirectiveEnum is used in schema module to restrict the values a field can take
+	DirectiveEnum string = "enum"
 	// DirectiveDefault is used to add default key
 	DirectiveDefault string = "default"
 	// DirectiveArgs is used in schema module to specify the created location
@@ -183,4 +206,28 @@ type IndexType struct {
 	IsUnique bool `db:"IS_UNIQUE"`
 	// IsPrimary specifies whether the column has a index
 	IsPrimary bool `db:"IS_PRIMARY"`
+	// IsFullText specifies whether the column has a full text index
+	IsFullText bool `db:"IS_FULLTEXT"`
+}
+
+const (
+	// MigrationApplied is the status of a schema migration which has been applied on the database
+	MigrationApplied string = "applied"
+	// MigrationRolledBack is the status of a schema migration which has been rolled back
+	MigrationRolledBack string = "rolled-back"
+	// MigrationPending is the status of a schema migration whose queries are being run on the database
+	MigrationPending string = "pending"
+)
+
+// SchemaMigration is a versioned change to the schema of a table. Up holds the queries which apply the change while Down
+// holds the queries which revert it.
+type SchemaMigration struct {
+	Version        int      `json:"version"`
+	DbAlias        string   `json:"dbAlias"`
+	Table          string   `json:"table"`
+	Schema         string   `json:"schema"`
+	PreviousSchema string   `json:"previousSchema"`
+	Up             []string `json:"up"`
+	Down           []string `json:"down"`
+	Status         string   `json:"status,omitempty"`
 }
@@ -32,6 +32,12 @@ type SchemaEventingInterface interface {
 	SchemaInspection(ctx context.Context, dbAlias, project, col string, realSchema Collection) (string, error)
 	GetSchema(dbAlias, col string) (Fields, bool)
 	GetSchemaForDB(ctx context.Context, dbAlias, col, format string) ([]interface{}, error)
+	ExportSchemas(ctx context.Context, dbAlias, col, format string) (interface{}, error)
+	InferSchema(ctx context.Context, dbAlias, col string) (string, error)
+	PlanSchemaModification(ctx context.Context, dbAlias, logicalDBName string, dbSchemas config.DatabaseSchemas) ([]*SchemaMigration, error)
+	GetSchemaMigrations(ctx context.Context, dbAlias, logicalDBName string) ([]*SchemaMigration, error)
+	ApplySchemaMigrations(ctx context.Context, dbAlias, logicalDBName string, version int) ([]*SchemaMigration, error)
+	RollbackSchemaMigrations(ctx context.Context, dbAlias, logicalDBName string, version int) ([]*SchemaMigration, error)
 }
 
 // CrudEventingInterface is an interface consisting of functions of crud module used by Eventing module
@@ -103,6 +109,10 @@ type CrudSchemaInterface interface {
 	// CreateProjectIfNotExists(ctx context.Context, project, dbAlias string) error
 	RawBatch(ctx context.Context, dbAlias string, batchedQueries []string) error
 	DescribeTable(ctx context.Context, dbAlias, col string) ([]InspectorFieldType, []IndexType, error)
+	InternalCreate(ctx context.Context, dbAlias, project, col string, req *CreateRequest, isIgnoreMetrics bool) error
+	InternalUpdate(ctx context.Context, dbAlias, project, col string, req *UpdateRequest) error
+	InternalDelete(ctx context.Context, dbAlias, project, col string, req *DeleteRequest) error
+	Read(ctx context.Context, dbAlias, col string, req *ReadRequest, params RequestParams) (interface{}, *SQLMetaData, error)
 }
 
 // CrudUserInterface is an interface consisting of functions of crud module used by User module
@@ -111,6 +121,7 @@ type CrudUserInterface interface {
 	Read(ctx context.Context, dbAlias, col string, req *ReadRequest, params RequestParams) (interface{}, *SQLMetaData, error)
 	Create(ctx context.Context, dbAlias, col string, req *CreateRequest, params RequestParams) error
 	Update(ctx context.Context, dbAlias, col string, req *UpdateRequest, params RequestParams) error
+	Delete(ctx context.Context, dbAlias, col string, req *DeleteRequest, params RequestParams) error
 }
 
 // AuthUserInterface is an interface consisting of functions of auth module used by User module
@@ -118,6 +129,8 @@ type AuthUserInterface interface {
 	IsReadOpAuthorised(ctx context.Context, project, dbType, col, token string, req *ReadRequest, stub ReturnWhereStub) (*PostProcess, RequestParams, error)
 	CreateToken(ctx context.Context, tokenClaims TokenClaims) (string, error)
 	IsUpdateOpAuthorised(ctx context.Context, project, dbType, col, token string, req *UpdateRequest) (RequestParams, error)
+	ParseToken(ctx context.Context, token string) (map[string]interface{}, error)
+	RevokeSessions(ctx context.Context, ids []string) error
 }
 
 // SyncmanEventingInterface is an interface consisting of functions of syncman module used by eventing module
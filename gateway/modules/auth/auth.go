@@ -12,6 +12,7 @@ import (
 
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 	jwtUtils "github.com/spaceuptech/space-cloud/gateway/utils/jwt"
+	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
 )
 
 // Module is responsible for authentication and authorisation
@@ -34,6 +35,9 @@ type Module struct {
 	// Admin Manager
 	adminMan       adminMan
 	integrationMan integrationManagerInterface
+
+	// Pubsub client to share the revoked sessions
+	pubsubClient *pubsub.Module
 }
 
 // Init creates a new instance of the auth object
@@ -93,3 +97,8 @@ func (m *Module) IsTokenInternal(ctx context.Context, token string) error {
 	}
 	return errors.New("token has not been created internally")
 }
+
+// IsTokenAdmin checks if the provided token is an admin token
+func (m *Module) IsTokenAdmin(ctx context.Context, token string) error {
+	return m.adminMan.CheckIfAdmin(ctx, token)
+}
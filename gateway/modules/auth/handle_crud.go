@@ -182,6 +182,28 @@ func (m *Module) IsDeleteOpAuthorised(ctx context.Context, project, dbAlias, col
 	return model.RequestParams{Claims: auth, Resource: "db-delete", Op: "access", Attributes: attr}, nil
 }
 
+// IsRestoreOpAuthorised checks if the restore operation is authorised. Only admins are allowed to restore soft
+// deleted documents.
+func (m *Module) IsRestoreOpAuthorised(ctx context.Context, project, dbAlias, col, token string, req *model.RestoreRequest) (model.RequestParams, error) {
+	if err := m.adminMan.CheckIfAdmin(ctx, token); err != nil {
+		return model.RequestParams{}, err
+	}
+
+	attr := map[string]string{"project": project, "db": dbAlias, "col": col}
+	return model.RequestParams{Resource: "db-restore", Op: "access", Attributes: attr}, nil
+}
+
+// IsHistoryOpAuthorised checks if the request to fetch the change history of a row is authorised. Only admins are
+// allowed to read the history since it holds the complete values of the rows.
+func (m *Module) IsHistoryOpAuthorised(ctx context.Context, project, dbAlias, col, token string, req *model.HistoryRequest) (model.RequestParams, error) {
+	if err := m.adminMan.CheckIfAdmin(ctx, token); err != nil {
+		return model.RequestParams{}, err
+	}
+
+	attr := map[string]string{"project": project, "db": dbAlias, "col": col}
+	return model.RequestParams{Resource: "db-history", Op: "access", Attributes: attr}, nil
+}
+
 // IsAggregateOpAuthorised checks if the crud operation is authorised
 func (m *Module) IsAggregateOpAuthorised(ctx context.Context, project, dbAlias, col, token string, req *model.AggregateRequest) (model.RequestParams, error) {
 	m.RLock()
@@ -288,7 +310,30 @@ func (m *Module) authenticatePreparedQueryRequest(ctx context.Context, projectID
 	return
 }
 
+// IsTableDenied checks if all the crud operations on a table are denied by its security rules
+func (m *Module) IsTableDenied(ctx context.Context, project, dbAlias, col string) bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	for _, op := range []model.OperationType{model.Create, model.Read, model.Update, model.Delete} {
+		rule, p := m.lookupCrudRule(project, dbAlias, col, op)
+		if !p || rule.Rule != "deny" {
+			return false
+		}
+	}
+	return true
+}
+
 func (m *Module) getCrudRule(ctx context.Context, projectID, dbAlias, col string, query model.OperationType) (*config.Rule, error) {
+	if rule, p := m.lookupCrudRule(projectID, dbAlias, col, query); p {
+		return rule, nil
+	}
+	return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Security rule not defined for collection/table (%s) in database (%s). Ensure your table has correct access rights", col, dbAlias), nil, nil)
+}
+
+// lookupCrudRule returns the rule of an operation on a table. The default rule of the database is used if the table
+// doesn't have a rule for the operation.
+func (m *Module) lookupCrudRule(projectID, dbAlias, col string, query model.OperationType) (*config.Rule, bool) {
 	resourceIDs := []string{
 		config.GenerateResourceID(m.clusterID, projectID, config.ResourceDatabaseRule, dbAlias, col, "rule"),
 		config.GenerateResourceID(m.clusterID, projectID, config.ResourceDatabaseRule, dbAlias, "default", "rule"),
@@ -297,11 +342,11 @@ func (m *Module) getCrudRule(ctx context.Context, projectID, dbAlias, col string
 		rule, ok := m.dbRules[resourceID]
 		if ok {
 			if r, p3 := rule.Rules[string(query)]; p3 {
-				return r, nil
+				return r, true
 			}
 		}
 	}
-	return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Security rule not defined for collection/table (%s) in database (%s). Ensure your table has correct access rights", col, dbAlias), nil, nil)
+	return nil, false
 }
 
 func (m *Module) getPrepareQueryRule(ctx context.Context, projectID, dbAlias, id string) (*config.Rule, error) {
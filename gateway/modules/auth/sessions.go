@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
+)
+
+const sessionRevocationTopic = "session-revocations"
+
+// SetPubsubClient sets the pubsub client used to share the revoked sessions with the other gateways of the cluster
+func (m *Module) SetPubsubClient(client *pubsub.Module) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch, err := client.Subscribe(ctx, sessionRevocationTopic)
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to subscribe to session revocations", err, nil)
+	}
+
+	m.Lock()
+	m.pubsubClient = client
+	m.Unlock()
+
+	go func() {
+		for msg := range ch {
+			ids := make([]string, 0)
+			if err := json.Unmarshal([]byte(msg.Payload), &ids); err != nil {
+				_ = helpers.Logger.LogError("session-revocation", "Unable to unmarshal incoming session revocation", err, map[string]interface{}{"payload": msg.Payload})
+				continue
+			}
+			m.jwt.RevokeSessions(ids)
+		}
+	}()
+	return nil
+}
+
+// RevokeSessions revokes the sessions so that the tokens created for them are rejected immediately. The revocation is
+// broadcasted to the other gateways of the cluster
+func (m *Module) RevokeSessions(ctx context.Context, ids []string) error {
+	m.jwt.RevokeSessions(ids)
+
+	m.RLock()
+	client := m.pubsubClient
+	m.RUnlock()
+
+	if client == nil {
+		return nil
+	}
+	if err := client.Publish(ctx, sessionRevocationTopic, ids); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to broadcast session revocation", err, nil)
+	}
+	return nil
+}
@@ -52,6 +52,10 @@ func (m *Module) CloseConfig() {
 	defer m.Unlock()
 
 	m.jwt.Close()
+	if m.pubsubClient != nil {
+		m.pubsubClient.Close()
+		m.pubsubClient = nil
+	}
 	m.funcRules = map[string]*config.Service{}
 	m.eventingRules = map[string]*config.Rule{}
 	m.fileRules = []*config.FileRule{}
@@ -9,6 +9,7 @@ import (
 
 type adminMan interface {
 	GetSecret() string
+	CheckIfAdmin(ctx context.Context, token string) error
 }
 type integrationManagerInterface interface {
 	InvokeHook(ctx context.Context, params model.RequestParams) config.IntegrationAuthResponse
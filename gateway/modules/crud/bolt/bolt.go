@@ -2,6 +2,7 @@ package bolt
 
 import (
 	"context"
+	"sync"
 
 	"github.com/spaceuptech/helpers"
 	"go.etcd.io/bbolt"
@@ -18,6 +19,11 @@ type Bolt struct {
 	connection      string
 	bucketName      string
 	client          *bbolt.DB
+
+	// Secondary indexes of the collections
+	indexLock     sync.RWMutex
+	schemaIndexes map[string][]*index // indexes required by the schema
+	indexes       map[string][]*index // indexes which have been built
 }
 
 // Init initialises a new bolt instance
@@ -71,7 +77,10 @@ func (b *Bolt) connect() error {
 	}
 	helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), "Successfully connected to bbolt database", nil)
 	b.client = client
-	return nil
+
+	b.indexLock.Lock()
+	defer b.indexLock.Unlock()
+	return b.syncIndexes(context.TODO())
 }
 
 // GetDBType returns the dbType of the crud block
@@ -53,7 +53,7 @@ func (b *Bolt) Create(ctx context.Context, col string, req *model.CreateRequest)
 					return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to insert data already exists", nil, nil)
 				}
 
-				b, err := tx.CreateBucketIfNotExists([]byte(b.bucketName))
+				bucket, err := tx.CreateBucketIfNotExists([]byte(b.bucketName))
 				if err != nil {
 					return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error creating bucket in bboltdb while inserting- %v", err), nil, nil)
 				}
@@ -65,9 +65,18 @@ func (b *Bolt) Create(ctx context.Context, col string, req *model.CreateRequest)
 				}
 
 				// insert document in bucket
-				if err = b.Put([]byte(fmt.Sprintf("%s/%s", col, id)), value); err != nil {
+				if err = bucket.Put([]byte(fmt.Sprintf("%s/%s", col, id)), value); err != nil {
 					return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error inserting in bbolt db - %v", err), nil, nil)
 				}
+
+				// index the document as stored in the database
+				doc := map[string]interface{}{}
+				if err := json.Unmarshal(value, &doc); err != nil {
+					return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to unmarshal data inserted in bbolt db", err, nil)
+				}
+				if err := b.putIndexes(ctx, tx, col, fmt.Sprintf("%v", id), doc); err != nil {
+					return err
+				}
 			}
 			return nil
 		}); err != nil {
@@ -32,6 +32,9 @@ func (b *Bolt) Delete(ctx context.Context, col string, req *model.DeleteRequest)
 				}
 				// if valid then delete
 				if utils.Validate(string(model.EmbeddedDB), req.Find, result) {
+					if err := b.deleteIndexes(tx, col, string(k[len(prefix):]), result); err != nil {
+						return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to remove index entries of bbolt db document", err, nil)
+					}
 					// delete data
 					if err := bucket.Delete(k); err != nil {
 						return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to delete bbolt key", err, nil)
@@ -0,0 +1,61 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+	"go.etcd.io/bbolt"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Export streams the documents matching the read request to the callback while iterating over the bucket with a
+// bbolt cursor. The query fetch limit is not applied. Documents are exported in the order of their _id.
+func (b *Bolt) Export(ctx context.Context, col string, req *model.ReadRequest, cb model.ExportCallback) (int64, error) {
+	if req.Options == nil {
+		req.Options = &model.ReadOptions{}
+	}
+
+	var count int64
+	err := b.client.View(func(tx *bbolt.Tx) error {
+		// Assume bucket exists and has keys
+		bucket := tx.Bucket([]byte(b.bucketName))
+		if bucket == nil {
+			return nil
+		}
+
+		cursor := bucket.Cursor()
+		prefix := []byte(col + "/")
+		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			if req.Options.Limit != nil && count >= *req.Options.Limit {
+				break
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			doc := map[string]interface{}{}
+			if err := json.Unmarshal(v, &doc); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to unmarshal while exporting from bbolt db", err, nil)
+			}
+			if !utils.Validate(string(model.EmbeddedDB), req.Find, doc) {
+				continue
+			}
+
+			if req.Options.Debug {
+				doc["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
+			}
+
+			if err := cb(doc); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
@@ -0,0 +1,80 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func TestBolt_Export(t *testing.T) {
+	limit := int64(2)
+	tests := []struct {
+		name    string
+		req     *model.ReadRequest
+		stopAt  int
+		want    []interface{}
+		wantErr bool
+	}{
+		{
+			name: "export all documents",
+			req:  &model.ReadRequest{Operation: utils.All},
+			want: []interface{}{"1", "2", "3", "4"},
+		},
+		{
+			name: "export documents matching the find clause",
+			req:  &model.ReadRequest{Operation: utils.All, Find: map[string]interface{}{"_id": "3"}},
+			want: []interface{}{"3"},
+		},
+		{
+			name: "export with an explicit limit",
+			req:  &model.ReadRequest{Operation: utils.All, Options: &model.ReadOptions{Limit: &limit}},
+			want: []interface{}{"1", "2"},
+		},
+		{
+			name:    "export stops when the callback fails",
+			req:     &model.ReadRequest{Operation: utils.All},
+			stopAt:  3,
+			want:    []interface{}{"1", "2", "3"},
+			wantErr: true,
+		},
+	}
+
+	b, err := Init(true, "export.db", "bucketName")
+	if err != nil {
+		t.Fatal("error initializing database")
+	}
+
+	if err := createDatabaseWithTestData(b); err != nil {
+		log.Fatal("error test data cannot be created for executing export test", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []interface{}{}
+			_, err := b.Export(context.Background(), "project_details", tt.req, func(doc map[string]interface{}) error {
+				got = append(got, doc["_id"])
+				if len(got) == tt.stopAt {
+					return errors.New("client disconnected")
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Export() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Export() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	utils.CloseTheCloser(b)
+	if err := os.Remove("export.db"); err != nil {
+		t.Error("error removing database file")
+	}
+}
@@ -0,0 +1,337 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+	"go.etcd.io/bbolt"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Tags prefixed to the encoded values of index keys. Values of different types are ordered by their tags.
+const (
+	indexTagNull byte = iota + 1
+	indexTagNumber
+	indexTagString
+	indexTagJSON
+)
+
+// index is a secondary index maintained for the fields of a collection marked with @index or @unique
+type index struct {
+	name     string
+	fields   []string
+	isUnique bool
+}
+
+// bucketName returns the name of the bucket storing the entries of the index. The definition of the index is made a
+// part of the name so that the index gets rebuilt whenever its definition changes.
+func (i *index) bucketName(col string) []byte {
+	kind := "index"
+	if i.isUnique {
+		kind = "unique"
+	}
+	return []byte(fmt.Sprintf("%s/%s/%s:%s", col, i.name, kind, strings.Join(i.fields, ",")))
+}
+
+// key returns the key of the index entry of a document. The _id of the document is appended to the key unless it needs
+// to be unique. Documents having a null value for any of the fields of a unique index aren't checked for uniqueness.
+func (i *index) key(doc map[string]interface{}, id string) (key []byte, checkUnique bool) {
+	hasNull := false
+	for _, field := range i.fields {
+		value := doc[field]
+		if value == nil {
+			hasNull = true
+		}
+		key = append(key, encodeDocValue(value)...)
+	}
+	if i.isUnique && !hasNull {
+		return key, true
+	}
+	return append(key, id...), false
+}
+
+// getIndexes extracts the indexes of every collection from the schema. Full text indexes are ignored.
+func getIndexes(schema model.Collection) map[string][]*index {
+	indexes := make(map[string][]*index)
+	for col, fields := range schema {
+		groups := map[string][]*model.TableProperties{}
+		isUnique := map[string]bool{}
+		for _, field := range fields {
+			for _, indexInfo := range field.IndexInfo {
+				if !indexInfo.IsIndex && !indexInfo.IsUnique {
+					continue
+				}
+				groups[indexInfo.Group] = append(groups[indexInfo.Group], indexInfo)
+				if indexInfo.IsUnique {
+					isUnique[indexInfo.Group] = true
+				}
+			}
+		}
+
+		for group, columns := range groups {
+			sort.SliceStable(columns, func(i, j int) bool { return columns[i].Order < columns[j].Order })
+			idx := &index{name: group, isUnique: isUnique[group]}
+			for _, column := range columns {
+				idx.fields = append(idx.fields, column.Field)
+			}
+			indexes[col] = append(indexes[col], idx)
+		}
+		sort.Slice(indexes[col], func(i, j int) bool { return indexes[col][i].name < indexes[col][j].name })
+	}
+	return indexes
+}
+
+// SetSchema sets the schema of the database. The secondary indexes of the collections are built from the fields marked
+// with @index or @unique. Indexes which cannot be built are skipped and collections having them are scanned instead.
+func (b *Bolt) SetSchema(ctx context.Context, schema model.Collection) error {
+	b.indexLock.Lock()
+	defer b.indexLock.Unlock()
+
+	b.schemaIndexes = getIndexes(schema)
+	if b.client == nil {
+		b.indexes = map[string][]*index{}
+		return nil
+	}
+	return b.syncIndexes(ctx)
+}
+
+// syncIndexes drops the indexes which are no longer present in the schema and builds the new ones. The persisted indexes
+// are left untouched until the schema has been set since the indexes it requires aren't known till then.
+// NOTE: the parent function should take the index lock before calling this function
+func (b *Bolt) syncIndexes(ctx context.Context) error {
+	if b.schemaIndexes == nil {
+		b.indexes = map[string][]*index{}
+		return nil
+	}
+
+	indexes := map[string][]*index{}
+	expected := map[string]bool{}
+	for col, colIndexes := range b.schemaIndexes {
+		for _, idx := range colIndexes {
+			expected[string(idx.bucketName(col))] = true
+		}
+	}
+
+	// Drop the indexes which are no longer required
+	if err := b.client.Update(func(tx *bbolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(b.indexBucketName())
+		if err != nil {
+			return err
+		}
+		var stale [][]byte
+		if err := root.ForEach(func(k, v []byte) error {
+			if v == nil && !expected[string(k)] {
+				stale = append(stale, append([]byte{}, k...))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, name := range stale {
+			if err := root.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to drop stale indexes of bbolt db", err, nil)
+	}
+
+	// Build the missing indexes. Each index is built in its own transaction so that a single unique constraint which
+	// doesn't hold for the existing documents doesn't prevent the other indexes from being built.
+	var buildErr error
+	for col, colIndexes := range b.schemaIndexes {
+		for _, idx := range colIndexes {
+			if err := b.client.Update(func(tx *bbolt.Tx) error { return b.buildIndex(ctx, tx, col, idx) }); err != nil {
+				buildErr = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to build index (%s) of collection (%s) in bbolt db", idx.name, col), err, nil)
+				continue
+			}
+			indexes[col] = append(indexes[col], idx)
+		}
+	}
+
+	b.indexes = indexes
+	return buildErr
+}
+
+// buildIndex adds the entries of every document of the collection to the index if it doesn't exist yet
+func (b *Bolt) buildIndex(ctx context.Context, tx *bbolt.Tx, col string, idx *index) error {
+	root := tx.Bucket(b.indexBucketName())
+	if root.Bucket(idx.bucketName(col)) != nil {
+		return nil
+	}
+	bucket, err := root.CreateBucket(idx.bucketName(col))
+	if err != nil {
+		return err
+	}
+
+	data := tx.Bucket([]byte(b.bucketName))
+	if data == nil {
+		return nil
+	}
+	c := data.Cursor()
+	prefix := []byte(col + "/")
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		doc := map[string]interface{}{}
+		if err := json.Unmarshal(v, &doc); err != nil {
+			return err
+		}
+		if err := putIndexEntry(ctx, bucket, col, idx, string(k[len(prefix):]), doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Bolt) indexBucketName() []byte {
+	return []byte(b.bucketName + "_indexes")
+}
+
+// getCollectionIndexes returns the indexes which have been built for the collection
+func (b *Bolt) getCollectionIndexes(col string) []*index {
+	b.indexLock.RLock()
+	defer b.indexLock.RUnlock()
+	return b.indexes[col]
+}
+
+// putIndexes adds the entries of a document to the indexes of the collection. An error is returned if the document
+// violates a unique constraint.
+func (b *Bolt) putIndexes(ctx context.Context, tx *bbolt.Tx, col, id string, doc map[string]interface{}) error {
+	for _, idx := range b.getCollectionIndexes(col) {
+		bucket := tx.Bucket(b.indexBucketName()).Bucket(idx.bucketName(col))
+		if err := putIndexEntry(ctx, bucket, col, idx, id, doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteIndexes removes the entries of a document from the indexes of the collection
+func (b *Bolt) deleteIndexes(tx *bbolt.Tx, col, id string, doc map[string]interface{}) error {
+	for _, idx := range b.getCollectionIndexes(col) {
+		bucket := tx.Bucket(b.indexBucketName()).Bucket(idx.bucketName(col))
+		key, _ := idx.key(doc, id)
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearIndexes removes every entry from the indexes of the collection
+func (b *Bolt) clearIndexes(tx *bbolt.Tx, col string) error {
+	for _, idx := range b.getCollectionIndexes(col) {
+		root := tx.Bucket(b.indexBucketName())
+		if err := root.DeleteBucket(idx.bucketName(col)); err != nil {
+			return err
+		}
+		if _, err := root.CreateBucket(idx.bucketName(col)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func putIndexEntry(ctx context.Context, bucket *bbolt.Bucket, col string, idx *index, id string, doc map[string]interface{}) error {
+	key, checkUnique := idx.key(doc, id)
+	if checkUnique {
+		if existing := bucket.Get(key); existing != nil && string(existing) != id {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Value of fields (%s) of collection (%s) violates the unique constraint (%s)", strings.Join(idx.fields, ", "), col, idx.name), nil, nil)
+		}
+	}
+	return bucket.Put(key, []byte(id))
+}
+
+// encodeValue encodes a scalar value such that the byte wise order of the encoded values matches the order of the
+// values. Booleans are encoded as numbers since they are compared as such while validating the where clause.
+func encodeValue(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case nil:
+		return []byte{indexTagNull}, true
+	case bool:
+		if v {
+			return encodeNumber(1), true
+		}
+		return encodeNumber(0), true
+	case int:
+		return encodeNumber(float64(v)), true
+	case int32:
+		return encodeNumber(float64(v)), true
+	case int64:
+		return encodeNumber(float64(v)), true
+	case float32:
+		return encodeNumber(float64(v)), true
+	case float64:
+		return encodeNumber(v), true
+	case string:
+		return encodeString(indexTagString, v), true
+	}
+	return nil, false
+}
+
+// encodeDocValue encodes the value of a field of a document. Objects and arrays are encoded as json.
+func encodeDocValue(value interface{}) []byte {
+	if key, ok := encodeValue(value); ok {
+		return key
+	}
+	data, _ := json.Marshal(value)
+	return encodeString(indexTagJSON, string(data))
+}
+
+func encodeNumber(f float64) []byte {
+	// Normalise negative zero
+	if f == 0 {
+		f = 0
+	}
+	bits := math.Float64bits(f)
+	if f >= 0 {
+		bits ^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	key := make([]byte, 9)
+	key[0] = indexTagNumber
+	for i := 0; i < 8; i++ {
+		key[8-i] = byte(bits >> (8 * i))
+	}
+	return key
+}
+
+// encodeString escapes the null bytes of the string and terminates it so that the encoded values of a composite index
+// can be told apart
+func encodeString(tag byte, s string) []byte {
+	key := make([]byte, 0, len(s)+3)
+	key = append(key, tag)
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0 {
+			key = append(key, 0, 0xff)
+			continue
+		}
+		key = append(key, s[i])
+	}
+	return append(key, 0, 1)
+}
+
+// compareDocValues compares the values of a field of two documents in the order used by the indexes
+func compareDocValues(a, b map[string]interface{}, field string) int {
+	return bytes.Compare(encodeDocValue(loadDocValue(a, field)), encodeDocValue(loadDocValue(b, field)))
+}
+
+func loadDocValue(doc map[string]interface{}, field string) interface{} {
+	if value, p := doc[field]; p {
+		return value
+	}
+	value, err := utils.LoadValue(field, doc)
+	if err != nil {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,126 @@
+package bolt
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+var indexTestSchema = model.Collection{
+	"users": model.Fields{
+		"email": &model.FieldType{FieldName: "email", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{IsUnique: true, Group: "email", Order: 1, Field: "email"}}},
+		"team":  &model.FieldType{FieldName: "team", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{IsIndex: true, Group: "team_age", Order: 1, Field: "team"}}},
+		"age":   &model.FieldType{FieldName: "age", Kind: model.TypeInteger, IndexInfo: []*model.TableProperties{{IsIndex: true, Group: "team_age", Order: 2, Field: "age"}, {IsIndex: true, Group: "age", Order: 1, Field: "age"}}},
+	},
+}
+
+func TestBolt_Indexes(t *testing.T) {
+	b, err := Init(true, "index.db", "bucketName")
+	if err != nil {
+		t.Fatal("error initializing database", err)
+	}
+	defer func() {
+		utils.CloseTheCloser(b)
+		if err := os.Remove("index.db"); err != nil {
+			t.Error("error removing database file")
+		}
+	}()
+
+	ctx := context.Background()
+	// The indexes must be built for the documents inserted before the schema is set
+	if _, err := b.Create(ctx, "users", &model.CreateRequest{Operation: utils.One, Document: map[string]interface{}{"_id": "1", "email": "a@x.com", "team": "admin", "age": 30}}); err != nil {
+		t.Fatal("error inserting document", err)
+	}
+	if err := b.SetSchema(ctx, indexTestSchema); err != nil {
+		t.Fatal("error setting schema", err)
+	}
+	docs := []interface{}{
+		map[string]interface{}{"_id": "2", "email": "b@x.com", "team": "admin", "age": 25},
+		map[string]interface{}{"_id": "3", "email": "c@x.com", "team": "dev", "age": 40},
+		map[string]interface{}{"_id": "4", "team": "dev", "age": 35},
+		map[string]interface{}{"_id": "5", "team": "admin"},
+	}
+	if _, err := b.Create(ctx, "users", &model.CreateRequest{Operation: utils.All, Document: docs}); err != nil {
+		t.Fatal("error inserting documents", err)
+	}
+
+	// Unique constraints are enforced while inserting and updating documents
+	if _, err := b.Create(ctx, "users", &model.CreateRequest{Operation: utils.One, Document: map[string]interface{}{"_id": "6", "email": "a@x.com"}}); err == nil {
+		t.Error("Create() inserted a document violating the unique constraint")
+	}
+	if _, err := b.Update(ctx, "users", &model.UpdateRequest{Operation: utils.One, Find: map[string]interface{}{"_id": "2"}, Update: map[string]interface{}{"$set": map[string]interface{}{"email": "c@x.com"}}}); err == nil {
+		t.Error("Update() updated a document violating the unique constraint")
+	}
+	if _, err := b.Update(ctx, "users", &model.UpdateRequest{Operation: utils.One, Find: map[string]interface{}{"_id": "3"}, Update: map[string]interface{}{"$set": map[string]interface{}{"email": "d@x.com", "age": 45}}}); err != nil {
+		t.Error("Update() error", err)
+	}
+	if _, err := b.Delete(ctx, "users", &model.DeleteRequest{Operation: utils.One, Find: map[string]interface{}{"email": "b@x.com"}}); err != nil {
+		t.Error("Delete() error", err)
+	}
+	if _, err := b.Create(ctx, "users", &model.CreateRequest{Operation: utils.One, Document: map[string]interface{}{"_id": "2", "email": "b@x.com", "team": "admin", "age": 20}}); err != nil {
+		t.Error("Create() could not reuse the value of a deleted document", err)
+	}
+
+	skip, limit := int64(1), int64(2)
+	tests := []struct {
+		name string
+		find map[string]interface{}
+		opts *model.ReadOptions
+		want []string
+	}{
+		{name: "equality on unique index", find: map[string]interface{}{"email": "d@x.com"}, want: []string{"3"}},
+		{name: "equality on composite index", find: map[string]interface{}{"team": "admin", "age": 30}, want: []string{"1"}},
+		{name: "null values", find: map[string]interface{}{"age": nil}, want: []string{"5"}},
+		{name: "range on index", find: map[string]interface{}{"age": map[string]interface{}{"$gt": 30, "$lte": 45}}, want: []string{"4", "3"}},
+		{name: "range sorted in descending order", find: map[string]interface{}{"age": map[string]interface{}{"$gte": 30}}, opts: &model.ReadOptions{Sort: []string{"-age"}}, want: []string{"3", "4", "1"}},
+		{name: "equality with sort on the next field", find: map[string]interface{}{"team": "admin"}, opts: &model.ReadOptions{Sort: []string{"age"}}, want: []string{"5", "2", "1"}},
+		{name: "sort with skip and limit", opts: &model.ReadOptions{Sort: []string{"age"}, Skip: &skip, Limit: &limit}, want: []string{"2", "1"}},
+		{name: "sort on field without index", find: map[string]interface{}{"team": "dev"}, opts: &model.ReadOptions{Sort: []string{"-email"}}, want: []string{"3", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, _, _, err := b.Read(ctx, "users", &model.ReadRequest{Operation: utils.All, Find: tt.find, Options: tt.opts})
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			ids := []string{}
+			for _, doc := range got.([]interface{}) {
+				ids = append(ids, doc.(map[string]interface{})["_id"].(string))
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("Read() got = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+	// The persisted indexes must survive a restart of the database till the schema is set again
+	utils.CloseTheCloser(b)
+	b, err = Init(true, "index.db", "bucketName")
+	if err != nil {
+		t.Fatal("error initializing database", err)
+	}
+	if err := b.client.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket(b.indexBucketName()).Bucket(getIndexes(indexTestSchema)["users"][0].bucketName("users")) == nil {
+			t.Error("Init() dropped the persisted indexes")
+		}
+		return nil
+	}); err != nil {
+		t.Error("error reading indexes", err)
+	}
+}
+
+func Test_encodeValue(t *testing.T) {
+	values := []interface{}{nil, -10.5, -1, 0, false, true, 2, 100.25, "", "a", "a\x00", "ab", "b"}
+	for i := 1; i < len(values); i++ {
+		prev, _ := encodeValue(values[i-1])
+		cur, _ := encodeValue(values[i])
+		if string(prev) > string(cur) {
+			t.Errorf("encodeValue() of %v is greater than %v", values[i-1], values[i])
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package bolt
+
+import (
+	"bytes"
+	"strings"
+
+	"go.etcd.io/bbolt"
+)
+
+// queryPlan describes the keys scanned to find the documents matching a read request. Documents are scanned using the
+// index which matches the most fields of the where clause. All documents of the collection are scanned otherwise.
+type queryPlan struct {
+	col   string
+	id    *string // set if a single document is looked up by its _id
+	index *index  // nil if the documents are scanned in the order of their _id
+
+	// Every scanned key has the prefix. The lower and upper bounds further narrow down the keys for range filters.
+	prefix         []byte
+	lower, upper   []byte
+	lowerExclusive bool
+	upperInclusive bool
+
+	reverse bool
+	// isOrdered is true if the documents are scanned in the order requested by the sort clause
+	isOrdered bool
+}
+
+// rangeFilter is the condition on a field of the where clause which can be answered by an index
+type rangeFilter struct {
+	eq             []byte
+	hasEq          bool
+	lower, upper   []byte
+	lowerExclusive bool
+	upperInclusive bool
+}
+
+func (r *rangeFilter) isRange() bool {
+	return r.lower != nil || r.upper != nil
+}
+
+// getRangeFilters extracts the conditions of the top level fields of the where clause which can be answered by an index
+func getRangeFilters(find map[string]interface{}) map[string]*rangeFilter {
+	filters := map[string]*rangeFilter{}
+	for field, cond := range find {
+		if strings.HasPrefix(field, "$") || strings.HasPrefix(field, "'") {
+			continue
+		}
+
+		condMap, ok := cond.(map[string]interface{})
+		if !ok {
+			if key, ok := encodeValue(cond); ok {
+				filters[field] = &rangeFilter{eq: key, hasEq: true}
+			}
+			continue
+		}
+
+		filter := new(rangeFilter)
+		for op, value := range condMap {
+			key, ok := encodeValue(value)
+			if !ok || value == nil {
+				continue
+			}
+			switch op {
+			case "$eq":
+				filter.eq, filter.hasEq = key, true
+			case "$gt":
+				filter.lower, filter.lowerExclusive = key, true
+			case "$gte":
+				filter.lower, filter.lowerExclusive = key, false
+			case "$lt":
+				filter.upper, filter.upperInclusive = key, false
+			case "$lte":
+				filter.upper, filter.upperInclusive = key, true
+			}
+		}
+		if filter.hasEq || filter.isRange() {
+			filters[field] = filter
+		}
+	}
+	return filters
+}
+
+// planQuery picks the way the documents of a collection are to be scanned for a where and sort clause
+func planQuery(col string, indexes []*index, find map[string]interface{}, sortFields []string) *queryPlan {
+	fields, isDesc, isSortUniform := parseSort(sortFields)
+	if id, ok := find["_id"].(string); ok {
+		return &queryPlan{col: col, id: &id, isOrdered: true}
+	}
+
+	filters := getRangeFilters(find)
+
+	var best *queryPlan
+	bestScore := 0
+	for _, idx := range indexes {
+		plan := &queryPlan{col: col, index: idx}
+		score, eqCount := 0, 0
+		for _, field := range idx.fields {
+			filter, p := filters[field]
+			if !p {
+				break
+			}
+			if filter.hasEq {
+				plan.prefix = append(plan.prefix, filter.eq...)
+				score += 2
+				eqCount++
+				continue
+			}
+
+			// A range filter can only be used on the field following the ones having an equality filter. The scan is
+			// restricted to values of the same type as the bounds since values of other types never match.
+			bound := filter.lower
+			if bound == nil {
+				bound = filter.upper
+			}
+			plan.prefix = append(plan.prefix, bound[0])
+			plan.lower, plan.lowerExclusive = prependPrefix(plan.prefix[:len(plan.prefix)-1], filter.lower), filter.lowerExclusive
+			plan.upper, plan.upperInclusive = prependPrefix(plan.prefix[:len(plan.prefix)-1], filter.upper), filter.upperInclusive
+			score++
+			break
+		}
+
+		// The index provides the sort order if the sorted fields follow the ones having an equality filter
+		plan.isOrdered = isSortUniform && len(fields) > 0 && hasFields(idx.fields[eqCount:], fields)
+		plan.reverse = plan.isOrdered && isDesc
+		if plan.isOrdered {
+			score++
+		}
+
+		if score > bestScore {
+			best, bestScore = plan, score
+		}
+	}
+	if best != nil {
+		return best
+	}
+
+	// Documents are stored in the order of their _id
+	plan := &queryPlan{col: col}
+	if isSortUniform && len(fields) == 1 && fields[0] == "_id" {
+		plan.isOrdered, plan.reverse = true, isDesc
+	}
+	return plan
+}
+
+// scan calls the function with the value of every document which might match the query. The scan stops if the
+// function returns false.
+func (p *queryPlan) scan(tx *bbolt.Tx, bucketName, indexBucketName []byte, fn func(v []byte) (bool, error)) error {
+	data := tx.Bucket(bucketName)
+	if data == nil {
+		return nil
+	}
+
+	if p.id != nil {
+		if v := data.Get([]byte(p.col + "/" + *p.id)); v != nil {
+			_, err := fn(v)
+			return err
+		}
+		return nil
+	}
+
+	if p.index == nil {
+		c := data.Cursor()
+		prefix := []byte(p.col + "/")
+		k, v := c.Seek(prefix)
+		if p.reverse {
+			k, v = seekBefore(c, successor(prefix))
+		}
+		return iterate(c, k, v, prefix, p.reverse, func(k, v []byte) (bool, error) { return fn(v) })
+	}
+
+	// Start the scan from the bound of the range filter if present
+	c := tx.Bucket(indexBucketName).Bucket(p.index.bucketName(p.col)).Cursor()
+	var k, v []byte
+	switch {
+	case !p.reverse && p.lower != nil:
+		k, v = c.Seek(p.lower)
+	case !p.reverse:
+		k, v = c.Seek(p.prefix)
+	case p.upper != nil && p.upperInclusive:
+		k, v = seekBefore(c, successor(p.upper))
+	case p.upper != nil:
+		k, v = seekBefore(c, p.upper)
+	default:
+		k, v = seekBefore(c, successor(p.prefix))
+	}
+	return iterate(c, k, v, p.prefix, p.reverse, func(k, id []byte) (bool, error) {
+		if p.lower != nil {
+			if c := bytes.Compare(k, p.lower); c < 0 || (p.lowerExclusive && bytes.HasPrefix(k, p.lower)) {
+				// Keys lower than the lower bound are reached only while scanning in the reverse order
+				return !p.reverse || c >= 0, nil
+			}
+		}
+		if p.upper != nil {
+			if c := bytes.Compare(k, p.upper); c >= 0 && !(p.upperInclusive && bytes.HasPrefix(k, p.upper)) {
+				// Keys greater than the upper bound are reached only while scanning in the forward order
+				return p.reverse, nil
+			}
+		}
+
+		v := data.Get([]byte(p.col + "/" + string(id)))
+		if v == nil {
+			return true, nil
+		}
+		return fn(v)
+	})
+}
+
+// iterate calls the function with every key having the prefix starting from the current position of the cursor in
+// the forward or reverse order
+func iterate(c *bbolt.Cursor, k, v, prefix []byte, reverse bool, fn func(k, v []byte) (bool, error)) error {
+	next := c.Next
+	if reverse {
+		next = c.Prev
+	}
+
+	for ; k != nil && bytes.HasPrefix(k, prefix); k, v = next() {
+		ok, err := fn(k, v)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+	return nil
+}
+
+// seekBefore moves the cursor to the last key lower than the provided key
+func seekBefore(c *bbolt.Cursor, key []byte) ([]byte, []byte) {
+	if key == nil {
+		return c.Last()
+	}
+	if k, _ := c.Seek(key); k == nil {
+		return c.Last()
+	}
+	return c.Prev()
+}
+
+// successor returns the smallest key greater than every key having the prefix. Nil is returned if no such key exists.
+func successor(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for len(end) > 0 && end[len(end)-1] == 0xff {
+		end = end[:len(end)-1]
+	}
+	if len(end) == 0 {
+		return nil
+	}
+	end[len(end)-1]++
+	return end
+}
+
+func prependPrefix(prefix, key []byte) []byte {
+	if key == nil {
+		return nil
+	}
+	return append(append([]byte{}, prefix...), key...)
+}
+
+// parseSort returns the fields of the sort clause and whether they are all sorted in the same direction
+func parseSort(sortFields []string) (fields []string, isDesc, isUniform bool) {
+	isUniform = true
+	for i, field := range sortFields {
+		desc := strings.HasPrefix(field, "-")
+		if i == 0 {
+			isDesc = desc
+		} else if desc != isDesc {
+			isUniform = false
+		}
+		fields = append(fields, strings.TrimPrefix(field, "-"))
+	}
+	return
+}
+
+// hasFields checks if the fields are a prefix of the index fields
+func hasFields(indexFields, fields []string) bool {
+	if len(fields) > len(indexFields) {
+		return false
+	}
+	for i, field := range fields {
+		if indexFields[i] != field {
+			return false
+		}
+	}
+	return true
+}
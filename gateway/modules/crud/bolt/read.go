@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spaceuptech/helpers"
@@ -24,38 +26,69 @@ func (b *Bolt) Read(ctx context.Context, col string, req *model.ReadRequest) (in
 	}
 	switch req.Operation {
 	case utils.All, utils.One:
+		pageCursor, isBefore, isCursor := utils.GetCursor(req.Options)
+		if isCursor && (len(req.Options.Sort) != 1 || req.Options.Sort[0] != "_id") {
+			return 0, nil, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Embedded db only supports cursor pagination in the ascending order of _id", nil, nil)
+		}
+
 		var count int64
 		results := []interface{}{}
-		if err := b.client.View(func(tx *bbolt.Tx) error {
-			// Assume bucket exists and has keys
-			bucket := tx.Bucket([]byte(b.bucketName))
-			if bucket == nil {
-				return nil
-			}
+		if isCursor {
+			if err := b.client.View(func(tx *bbolt.Tx) error {
+				// Assume bucket exists and has keys
+				bucket := tx.Bucket([]byte(b.bucketName))
+				if bucket == nil {
+					return nil
+				}
 
-			cursor := bucket.Cursor()
-			prefix := []byte(col + "/")
-			for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
-				result := map[string]interface{}{}
-				if err := json.Unmarshal(v, &result); err != nil {
-					return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to unmarshal while reading from bbolt db", err, nil)
+				cursor := bucket.Cursor()
+				prefix := []byte(col + "/")
+				k, v, err := seekCursor(cursor, prefix, pageCursor, isBefore)
+				if err != nil {
+					return err
+				}
+				next := cursor.Next
+				if isBefore {
+					next = cursor.Prev
 				}
-				if utils.Validate(string(model.EmbeddedDB), req.Find, result) {
-					if req.Options.Debug {
-						result["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
-					}
 
-					results = append(results, result)
-					count++
-					if req.Operation == utils.One {
+				for ; k != nil && bytes.HasPrefix(k, prefix); k, v = next() {
+					if req.Options.Limit != nil && count >= *req.Options.Limit {
 						break
 					}
+
+					result := map[string]interface{}{}
+					if err := json.Unmarshal(v, &result); err != nil {
+						return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to unmarshal while reading from bbolt db", err, nil)
+					}
+					if utils.Validate(string(model.EmbeddedDB), req.Find, result) {
+						if req.Options.Debug {
+							result["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
+						}
+
+						results = append(results, result)
+						count++
+						if req.Operation == utils.One {
+							break
+						}
+					}
 				}
+				return nil
+			}); err != nil {
+				return 0, nil, nil, nil, err
 			}
-			return nil
-		}); err != nil {
-			return 0, nil, nil, nil, err
+		} else {
+			var err error
+			results, err = b.readWithPlan(ctx, col, req)
+			if err != nil {
+				return 0, nil, nil, nil, err
+			}
+			count = int64(len(results))
 		}
+		if isCursor && isBefore {
+			utils.ReverseArray(results)
+		}
+
 		if req.Operation == utils.One {
 			if count == 0 {
 				return 0, nil, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "No match found for specified find clause", nil, nil)
@@ -87,3 +120,111 @@ func (b *Bolt) Read(ctx context.Context, col string, req *model.ReadRequest) (in
 		return 0, nil, nil, nil, utils.ErrInvalidParams
 	}
 }
+
+// readWithPlan reads the documents using the secondary index best suited for the where and sort clause. Documents are
+// sorted in memory if the index doesn't provide the requested order.
+func (b *Bolt) readWithPlan(ctx context.Context, col string, req *model.ReadRequest) ([]interface{}, error) {
+	var skip int64
+	if req.Options.Skip != nil {
+		skip = *req.Options.Skip
+	}
+
+	plan := planQuery(col, b.getCollectionIndexes(col), req.Find, req.Options.Sort)
+	// Scanning can be stopped early if the documents are scanned in the requested order
+	isOrdered := plan.isOrdered || len(req.Options.Sort) == 0
+
+	results := []interface{}{}
+	var matched int64
+	if err := b.client.View(func(tx *bbolt.Tx) error {
+		return plan.scan(tx, []byte(b.bucketName), b.indexBucketName(), func(v []byte) (bool, error) {
+			result := map[string]interface{}{}
+			if err := json.Unmarshal(v, &result); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to unmarshal while reading from bbolt db", err, nil)
+			}
+			if !utils.Validate(string(model.EmbeddedDB), req.Find, result) {
+				return true, nil
+			}
+
+			matched++
+			if isOrdered && matched <= skip {
+				return true, nil
+			}
+			if req.Options.Debug {
+				result["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
+			}
+			results = append(results, result)
+
+			if isOrdered && (req.Operation == utils.One || (req.Options.Limit != nil && int64(len(results)) >= *req.Options.Limit)) {
+				return false, nil
+			}
+			return true, nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	if isOrdered {
+		return results, nil
+	}
+
+	sortDocs(results, req.Options.Sort)
+	if skip > int64(len(results)) {
+		skip = int64(len(results))
+	}
+	results = results[skip:]
+	if req.Operation == utils.One && len(results) > 1 {
+		results = results[:1]
+	}
+	if req.Options.Limit != nil && int64(len(results)) > *req.Options.Limit {
+		results = results[:*req.Options.Limit]
+	}
+	return results, nil
+}
+
+// sortDocs sorts the documents in the order used by the indexes
+func sortDocs(docs []interface{}, sortFields []string) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		a, b := docs[i].(map[string]interface{}), docs[j].(map[string]interface{})
+		for _, field := range sortFields {
+			isDesc := strings.HasPrefix(field, "-")
+			c := compareDocValues(a, b, strings.TrimPrefix(field, "-"))
+			if c == 0 {
+				continue
+			}
+			return (c < 0) != isDesc
+		}
+		return false
+	})
+}
+
+// seekCursor moves the bbolt cursor to the first document lying after (or before) the provided cursor. Since keys are
+// stored as col/_id, this lets the documents be paginated in the order of their _id.
+func seekCursor(c *bbolt.Cursor, prefix []byte, cursor string, isBefore bool) ([]byte, []byte, error) {
+	start := prefix
+	if cursor != "" {
+		values, err := utils.DecodeCursor([]string{"_id"}, cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		start = append(append([]byte{}, prefix...), []byte(fmt.Sprintf("%v", values[0]))...)
+	} else if isBefore {
+		// Seek past the last key having the prefix to read the last page
+		start = append(append([]byte{}, prefix[:len(prefix)-1]...), prefix[len(prefix)-1]+1)
+	}
+
+	k, v := c.Seek(start)
+	if isBefore {
+		// Seek positions the cursor at the first key greater than or equal to start
+		if k == nil {
+			k, v = c.Last()
+			return k, v, nil
+		}
+		k, v = c.Prev()
+		return k, v, nil
+	}
+
+	if cursor != "" && bytes.Equal(k, start) {
+		k, v = c.Next()
+	}
+	return k, v, nil
+}
@@ -14,6 +14,10 @@ import (
 )
 
 func TestBolt_Read(t *testing.T) {
+	// Cursors for documents with _id 1 & 4
+	afterCursor, beforeCursor, emptyCursor := "WyIxIl0", "WyI0Il0", ""
+	limit, one := int64(2), int64(1)
+
 	type fields struct {
 		enabled    bool
 		connection string
@@ -106,6 +110,106 @@ func TestBolt_Read(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "read page after cursor",
+			want: 2,
+			want1: []interface{}{
+				map[string]interface{}{
+					"_id":           "2",
+					"name":          "jayesh",
+					"team":          "admin",
+					"project_count": float64(10),
+					"isPrimary":     true,
+					"project_details": map[string]interface{}{
+						"project_name": "project1",
+					},
+				}, map[string]interface{}{
+					"_id":           "3",
+					"name":          "noorain",
+					"team":          "admin",
+					"project_count": float64(52),
+					"isPrimary":     true,
+					"project_details": map[string]interface{}{
+						"project_name": "project1",
+					},
+				}},
+			args: args{
+				ctx: context.Background(),
+				col: "project_details",
+				req: &model.ReadRequest{
+					Operation: utils.All,
+					Options:   &model.ReadOptions{Sort: []string{"_id"}, After: &afterCursor, Limit: &limit},
+				},
+			},
+		},
+		{
+			name: "read page before cursor",
+			want: 2,
+			want1: []interface{}{
+				map[string]interface{}{
+					"_id":           "2",
+					"name":          "jayesh",
+					"team":          "admin",
+					"project_count": float64(10),
+					"isPrimary":     true,
+					"project_details": map[string]interface{}{
+						"project_name": "project1",
+					},
+				}, map[string]interface{}{
+					"_id":           "3",
+					"name":          "noorain",
+					"team":          "admin",
+					"project_count": float64(52),
+					"isPrimary":     true,
+					"project_details": map[string]interface{}{
+						"project_name": "project1",
+					},
+				}},
+			args: args{
+				ctx: context.Background(),
+				col: "project_details",
+				req: &model.ReadRequest{
+					Operation: utils.All,
+					Options:   &model.ReadOptions{Sort: []string{"_id"}, Before: &beforeCursor, Limit: &limit},
+				},
+			},
+		},
+		{
+			name: "read last page",
+			want: 1,
+			want1: []interface{}{
+				map[string]interface{}{
+					"_id":           "4",
+					"name":          "ali",
+					"team":          "admin",
+					"project_count": float64(100),
+					"isPrimary":     true,
+					"project_details": map[string]interface{}{
+						"project_name": "project1",
+					},
+				}},
+			args: args{
+				ctx: context.Background(),
+				col: "project_details",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"isPrimary": true},
+					Operation: utils.All,
+					Options:   &model.ReadOptions{Sort: []string{"_id"}, Before: &emptyCursor, Limit: &one},
+				},
+			},
+		},
+		{
+			name: "cursor pagination with unsupported sort",
+			args: args{
+				ctx: context.Background(),
+				col: "project_details",
+				req: &model.ReadRequest{
+					Operation: utils.All,
+					Options:   &model.ReadOptions{Sort: []string{"name", "_id"}, After: &emptyCursor},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	b, err := Init(true, "read.db", "bucketName")
@@ -37,6 +37,12 @@ func (b *Bolt) Update(ctx context.Context, col string, req *model.UpdateRequest)
 						return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to update in bbolt - $set db operator not found or the operator value is not map", nil, nil)
 					}
 
+					// remove the index entries of the current document before updating it
+					id := string(k[len(prefix):])
+					if err := b.deleteIndexes(tx, col, id, currentObj); err != nil {
+						return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to remove index entries of bbolt db document", err, nil)
+					}
+
 					for objToSetKey, objToSetValue := range objToSet {
 						currentObj[objToSetKey] = objToSetValue
 					}
@@ -49,6 +55,14 @@ func (b *Bolt) Update(ctx context.Context, col string, req *model.UpdateRequest)
 					if err = bucket.Put(k, value); err != nil {
 						return err
 					}
+
+					updatedObj := map[string]interface{}{}
+					if err := json.Unmarshal(value, &updatedObj); err != nil {
+						return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to unmarshal data updated in bbolt db", err, nil)
+					}
+					if err := b.putIndexes(ctx, tx, col, id, updatedObj); err != nil {
+						return err
+					}
 					count++
 
 					if req.Operation == utils.One {
@@ -62,6 +76,10 @@ func (b *Bolt) Update(ctx context.Context, col string, req *model.UpdateRequest)
 			return 0, err
 		}
 
+		if req.IsVersioned && count == 0 {
+			return 0, utils.ErrVersionConflict
+		}
+
 		if req.Operation == utils.Upsert && count == 0 {
 			objToSet, ok := req.Update["$set"].(map[string]interface{})
 			if !ok {
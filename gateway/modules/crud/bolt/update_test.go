@@ -190,6 +190,31 @@ func TestBolt_Update(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "update with a stale version",
+			fields: fields{
+				enabled:    true,
+				connection: "update.db",
+			},
+			args: args{
+				ctx: context.Background(),
+				col: "project_details",
+				req: &model.UpdateRequest{
+					Find: map[string]interface{}{
+						"_id":           "1",
+						"project_count": 14,
+					},
+					Operation: utils.One,
+					Update: map[string]interface{}{
+						"$set": map[string]interface{}{
+							"project_count": 15,
+						},
+					},
+					IsVersioned: true,
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	b, err := Init(true, "update.db", "bucketName")
@@ -0,0 +1,201 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Copy streams the rows of a table into a table of another database. The rows are read in the order of the primary key
+// and inserted in chunks, with a checkpoint reported to the callback after every chunk. The cursor of the last checkpoint
+// can be provided to resume a copy after the last row it processed. The rows are converted as per the schema of the target
+// table before being inserted.
+func (m *Module) Copy(ctx context.Context, dbAlias, col string, req *model.CopyRequest, params model.RequestParams, cb func(checkpoint *model.CopyCheckpoint) error) error {
+	if req.TargetCol == "" {
+		req.TargetCol = col
+	}
+	if req.TargetDbAlias == dbAlias && req.TargetCol == col {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to copy table (%s) onto itself", col), nil, nil)
+	}
+	if req.ChunkSize <= 0 {
+		req.ChunkSize = defaultImportChunkSize
+	}
+
+	dbType, sortKeys, targetFields, err := m.prepareCopy(ctx, dbAlias, col, req.TargetDbAlias, req.TargetCol)
+	if err != nil {
+		return err
+	}
+
+	// The embedded database exports the rows in the order of their keys while holding a read transaction. Hence the rows
+	// cannot be inserted in the same database and the export cannot be resumed from a cursor on the primary key
+	isEmbedded := model.DBType(dbType) == model.EmbeddedDB
+	if isEmbedded && req.TargetDbAlias == dbAlias {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to copy table (%s) to a table of the same embedded database", col), nil, nil)
+	}
+	if isEmbedded && req.Cursor != "" {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to resume copy of table (%s) as embedded databases do not support cursors", col), nil, nil)
+	}
+
+	find := map[string]interface{}{}
+	if req.Cursor != "" {
+		clause, err := utils.GenerateCursorClause(sortKeys, req.Cursor, false, utils.NullsSortFirst(dbType))
+		if err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid cursor provided to resume copy", err, nil)
+		}
+		find = clause
+	}
+
+	checkpoint := &model.CopyCheckpoint{Cursor: req.Cursor}
+	rows := make([]*model.ImportRow, 0, req.ChunkSize)
+	rowErrors := make([]*model.ImportRowError, 0)
+	var lastDoc map[string]interface{}
+	insertChunk := func() error {
+		if len(rows) > 0 {
+			res, err := m.Import(ctx, req.TargetDbAlias, req.TargetCol, &model.ImportRequest{Rows: rows, Mode: model.ImportModeBestEffort, ChunkSize: req.ChunkSize}, params)
+			if err != nil {
+				return err
+			}
+			checkpoint.Copied += res.Inserted
+			rowErrors = append(rowErrors, res.Errors...)
+		}
+
+		if !isEmbedded {
+			cursor, err := utils.EncodeCursor(sortKeys, lastDoc)
+			if err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to generate checkpoint of copy", err, nil)
+			}
+			checkpoint.Cursor = cursor
+		}
+		checkpoint.Errors = rowErrors
+		rows = make([]*model.ImportRow, 0, req.ChunkSize)
+		rowErrors = make([]*model.ImportRowError, 0)
+		return cb(checkpoint)
+	}
+
+	readRequest := &model.ReadRequest{Find: find, Operation: utils.All, Options: &model.ReadOptions{Sort: sortKeys}}
+	err = m.Export(ctx, dbAlias, col, readRequest, params, func(doc map[string]interface{}) error {
+		checkpoint.Processed++
+		lastDoc = doc
+
+		converted, err := convertCopyDocument(doc, targetFields)
+		if err != nil {
+			rowErrors = append(rowErrors, &model.ImportRowError{Row: int(checkpoint.Processed), Error: err.Error()})
+		} else {
+			rows = append(rows, &model.ImportRow{Row: int(checkpoint.Processed), Document: converted})
+		}
+
+		if len(rows)+len(rowErrors) < req.ChunkSize {
+			return nil
+		}
+		return insertChunk()
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(rows)+len(rowErrors) > 0 {
+		if err := insertChunk(); err != nil {
+			return err
+		}
+	}
+	checkpoint.Done = true
+	checkpoint.Errors = nil
+	return cb(checkpoint)
+}
+
+// prepareCopy returns the type of the source database, the fields to sort the source table on and the schema of the target table
+func (m *Module) prepareCopy(ctx context.Context, dbAlias, col, targetDbAlias, targetCol string) (string, []string, model.Fields, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	dbType, err := m.getDBType(dbAlias)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if _, err := m.getDBType(targetDbAlias); err != nil {
+		return "", nil, nil, err
+	}
+
+	// The rows are sorted on the primary key so that the cursor of a checkpoint points to the same row on every copy
+	primaryKeys := make([]*model.FieldType, 0)
+	for _, field := range m.schemaDoc[dbAlias][col] {
+		if field.IsPrimary {
+			primaryKeys = append(primaryKeys, field)
+		}
+	}
+	sort.Slice(primaryKeys, func(i, j int) bool {
+		if primaryKeys[i].PrimaryKeyInfo == nil || primaryKeys[j].PrimaryKeyInfo == nil {
+			return primaryKeys[i].FieldName < primaryKeys[j].FieldName
+		}
+		return primaryKeys[i].PrimaryKeyInfo.Order < primaryKeys[j].PrimaryKeyInfo.Order
+	})
+	sortKeys := make([]string, 0, len(primaryKeys))
+	for _, field := range primaryKeys {
+		sortKeys = append(sortKeys, field.FieldName)
+	}
+	if len(sortKeys) == 0 && model.DBType(dbType) == model.Mongo {
+		sortKeys = append(sortKeys, "_id")
+	}
+	if len(sortKeys) == 0 {
+		return "", nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to copy table (%s) as it doesn't have a primary key to order its rows on", col), nil, nil)
+	}
+
+	return dbType, sortKeys, m.schemaDoc[targetDbAlias][targetCol], nil
+}
+
+// convertCopyDocument converts the values of a row to the types of the fields in the schema of the target table. Fields
+// which aren't in the schema of the target table are dropped. The _id field of mongo is mapped to the id field if the
+// target table doesn't have an _id field. An error is returned if a value cannot be stored in its field.
+func convertCopyDocument(doc map[string]interface{}, fields model.Fields) (map[string]interface{}, error) {
+	// Values specific to a database, like the object ids of mongo, are converted to their json representations
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	converted := map[string]interface{}{}
+	if err := json.Unmarshal(data, &converted); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return converted, nil
+	}
+
+	if id, ok := converted["_id"]; ok {
+		if _, p := fields["_id"]; !p {
+			if _, p := converted["id"]; !p {
+				converted["id"] = id
+			}
+		}
+	}
+
+	for key, value := range converted {
+		field, p := fields[key]
+		if !p || field.IsLinked {
+			delete(converted, key)
+			continue
+		}
+
+		switch field.Kind {
+		case model.TypeID, model.TypeString, model.TypeVarChar, model.TypeChar:
+			switch v := value.(type) {
+			case float64:
+				converted[key] = strconv.FormatFloat(v, 'f', -1, 64)
+			case bool:
+				converted[key] = strconv.FormatBool(v)
+			}
+		case model.TypeJSON:
+			// JSON fields only accept objects
+			if _, ok := value.([]interface{}); ok && !field.IsList {
+				return nil, fmt.Errorf("array provided for json field (%s) which only accepts objects", key)
+			}
+		}
+	}
+	return converted, nil
+}
@@ -0,0 +1,106 @@
+package crud
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud/bolt"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func Test_convertCopyDocument(t *testing.T) {
+	objectID, _ := primitive.ObjectIDFromHex("5f1b7c0d9d2e3a4b5c6d7e8f")
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	fields := model.Fields{
+		"id":      &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true},
+		"name":    &model.FieldType{FieldName: "name", Kind: model.TypeString},
+		"age":     &model.FieldType{FieldName: "age", Kind: model.TypeInteger},
+		"created": &model.FieldType{FieldName: "created", Kind: model.TypeDateTime},
+		"meta":    &model.FieldType{FieldName: "meta", Kind: model.TypeJSON},
+		"posts":   &model.FieldType{FieldName: "posts", Kind: "posts", IsLinked: true},
+	}
+	tests := []struct {
+		name    string
+		doc     map[string]interface{}
+		fields  model.Fields
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:   "mongo document is mapped to the schema of the target table",
+			doc:    map[string]interface{}{"_id": objectID, "name": "john", "age": int32(20), "created": created, "extra": true, "posts": []interface{}{}},
+			fields: fields,
+			want:   map[string]interface{}{"id": "5f1b7c0d9d2e3a4b5c6d7e8f", "name": "john", "age": float64(20), "created": "2020-01-02T03:04:05Z"},
+		},
+		{
+			name:   "values are converted to the kinds of the fields",
+			doc:    map[string]interface{}{"id": 10, "name": true, "meta": map[string]interface{}{"a": 1}},
+			fields: fields,
+			want:   map[string]interface{}{"id": "10", "name": "true", "meta": map[string]interface{}{"a": float64(1)}},
+		},
+		{
+			name:    "array provided for a json field",
+			doc:     map[string]interface{}{"id": 10, "meta": []interface{}{"a"}},
+			fields:  fields,
+			wantErr: true,
+		},
+		{
+			name: "document is left as is if the target table has no schema",
+			doc:  map[string]interface{}{"_id": objectID, "extra": true},
+			want: map[string]interface{}{"_id": "5f1b7c0d9d2e3a4b5c6d7e8f", "extra": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertCopyDocument(tt.doc, tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("convertCopyDocument() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("convertCopyDocument() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModule_Copy_EmbeddedDatabase(t *testing.T) {
+	b, err := bolt.Init(true, "copy.db", "bucketName")
+	if err != nil {
+		t.Fatal("error initializing database", err)
+	}
+	defer func() {
+		utils.CloseTheCloser(b)
+		_ = os.Remove("copy.db")
+	}()
+
+	m := &Module{
+		blocks:    map[string]Crud{"db": b, "db2": b},
+		schemaDoc: model.Type{"db": model.Collection{"users": model.Fields{"id": &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true}}}},
+	}
+	tests := []struct {
+		name string
+		req  *model.CopyRequest
+	}{
+		{name: "copy to the same embedded database", req: &model.CopyRequest{TargetDbAlias: "db", TargetCol: "users_copy"}},
+		{name: "resume copy from an embedded database", req: &model.CopyRequest{TargetDbAlias: "db2", Cursor: "WyIxIl0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.Copy(context.Background(), "db", "users", tt.req, model.RequestParams{}, func(checkpoint *model.CopyCheckpoint) error {
+				t.Errorf("Copy() unexpected checkpoint = %v", checkpoint)
+				return nil
+			})
+			if err == nil {
+				t.Errorf("Copy() expected an error")
+			}
+		})
+	}
+}
@@ -36,14 +36,22 @@ type Module struct {
 
 	// Extra variables for enterprise
 	blocks         map[string]Crud
+	replicas       map[string]*replicaSet // read replicas of the blocks
 	admin          *admin.Manager
 	integrationMan integrationManagerInterface
 	caching        cachingInterface
+	eventing       eventingInterface
 	// function to get secrets from runner
 	getSecrets utils.GetSecrets
 
 	// Schema module
 	schemaDoc model.Type
+
+	// Database rules. Used to find the tables whose change history is recorded
+	dbRules config.DatabaseRules
+
+	// Channel to stop the routine which deletes the expired rows
+	sweepCloseC chan struct{}
 }
 
 type loader struct {
@@ -55,6 +63,7 @@ type loader struct {
 type Crud interface {
 	Create(ctx context.Context, col string, req *model.CreateRequest) (int64, error)
 	Read(ctx context.Context, col string, req *model.ReadRequest) (int64, interface{}, map[string]map[string]string, *model.SQLMetaData, error)
+	Export(ctx context.Context, col string, req *model.ReadRequest, cb model.ExportCallback) (int64, error)
 	Update(ctx context.Context, col string, req *model.UpdateRequest) (int64, error)
 	Delete(ctx context.Context, col string, req *model.DeleteRequest) (int64, error)
 	Aggregate(ctx context.Context, col string, req *model.AggregateRequest) (interface{}, error)
@@ -72,11 +81,12 @@ type Crud interface {
 	GetConnectionState(ctx context.Context) bool
 	SetQueryFetchLimit(limit int64)
 	SetProjectAESKey(aesKey []byte)
+	SetSchema(ctx context.Context, schema model.Collection) error
 }
 
 // Init create a new instance of the Module object
 func Init() *Module {
-	return &Module{batchMapTableToChan: make(batchMap), databaseConfigs: config.DatabaseConfigs{}, blocks: map[string]Crud{}, dataLoader: loader{loaderMap: map[string]*dataloader.Loader{}}}
+	return &Module{batchMapTableToChan: make(batchMap), databaseConfigs: config.DatabaseConfigs{}, blocks: map[string]Crud{}, replicas: map[string]*replicaSet{}, dataLoader: loader{loaderMap: map[string]*dataloader.Loader{}}}
 }
 
 func (m *Module) initBlock(dbType model.DBType, enabled bool, connection, dbName string, driverConf config.DriverConfig) (Crud, error) {
@@ -85,7 +95,7 @@ func (m *Module) initBlock(dbType model.DBType, enabled bool, connection, dbName
 		return mgo.Init(enabled, connection, dbName, driverConf)
 	case model.EmbeddedDB:
 		return bolt.Init(enabled, connection, dbName)
-	case model.MySQL, model.Postgres, model.SQLServer:
+	case model.MySQL, model.Postgres, model.SQLServer, model.SQLite:
 		c, err := sql.Init(dbType, enabled, connection, dbName, driverConf)
 		if err == nil && enabled {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -130,8 +140,17 @@ func (m *Module) CloseConfig() error {
 			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to close database connection", err, map[string]interface{}{})
 		}
 	}
+	for dbAlias, replicas := range m.replicas {
+		replicas.close()
+		delete(m.replicas, dbAlias)
+	}
 
 	m.closeBatchOperation()
 
+	if m.sweepCloseC != nil {
+		close(m.sweepCloseC)
+		m.sweepCloseC = nil
+	}
+
 	return nil
 }
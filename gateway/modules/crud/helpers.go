@@ -3,11 +3,15 @@ package crud
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spaceuptech/helpers"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
 func (m *Module) createBatch(ctx context.Context, project, dbAlias, col string, doc interface{}) (int64, error) {
@@ -80,3 +84,165 @@ func (m *Module) getDBType(dbAlias string) (string, error) {
 
 	return string(block.GetDBType()), nil
 }
+
+// prepareCursorPagination validates a cursor paginated read request and appends the primary key to its sort order. This
+// gives each document a unique position which is required to resume reading from a cursor.
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) prepareCursorPagination(ctx context.Context, dbAlias, dbType, col string, req *model.ReadRequest) error {
+	if req.Options == nil || (req.Options.After == nil && req.Options.Before == nil) {
+		return nil
+	}
+
+	if req.Options.After != nil && req.Options.Before != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Cannot provide both after & before cursors in a single read request", nil, nil)
+	}
+	if req.Operation != utils.All || len(req.Aggregate) > 0 || len(req.GroupBy) > 0 || len(req.Options.Join) > 0 {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Cursor pagination is only supported for read requests with operation all and without aggregations, group by or joins", nil, nil)
+	}
+	if req.Options.Skip != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Cannot use skip along with cursor pagination", nil, nil)
+	}
+	if utils.StringExists(req.Options.Sort, utils.SearchScoreField, "-"+utils.SearchScoreField) {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Cannot use cursor pagination while sorting by the relevance of a full text search", nil, nil)
+	}
+	if utils.StringExists(req.Options.Sort, utils.DistanceField, "-"+utils.DistanceField) {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Cannot use cursor pagination while sorting by the distance from a point", nil, nil)
+	}
+
+	primaryKeys := []string{"_id"}
+	switch model.DBType(dbType) {
+	case model.MySQL, model.Postgres, model.SQLServer, model.SQLite:
+		fields, p := m.schemaDoc[dbAlias][col]
+		if !p {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot use cursor pagination as schema of table (%s) is not provided", col), nil, nil)
+		}
+		primaryKeys = getPrimaryKeys(fields)
+		if len(primaryKeys) == 0 {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot use cursor pagination as table (%s) does not have a primary key", col), nil, nil)
+		}
+	}
+
+	for _, key := range primaryKeys {
+		if !utils.StringExists(req.Options.Sort, key, "-"+key) {
+			req.Options.Sort = append(req.Options.Sort, key)
+		}
+	}
+
+	// The values of the sort fields are required to generate the next cursor
+	if len(req.Options.Select) > 0 {
+		for _, field := range req.Options.Sort {
+			req.Options.Select[strings.TrimPrefix(field, "-")] = 1
+		}
+	}
+
+	// Cursor paginated requests cannot be merged with other requests
+	req.IsBatch = false
+	return nil
+}
+
+// filterSoftDeleted adds the condition to skip soft deleted documents to a read request and to the tables joined by it.
+// The request is left untouched if it opts in to read the soft deleted documents.
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) filterSoftDeleted(dbAlias, col string, req *model.ReadRequest) {
+	if req.Options != nil && req.Options.IncludeDeleted {
+		return
+	}
+
+	// The columns need to be qualified with the table name when tables are joined
+	tableName := ""
+	if req.Options != nil && len(req.Options.Join) > 0 {
+		tableName = col
+		m.filterSoftDeletedJoins(dbAlias, req.Options.Join)
+	}
+	req.Find = schemaHelpers.AddSoftDeleteClause(m.schemaDoc[dbAlias][col], tableName, req.Find)
+}
+
+func (m *Module) filterSoftDeletedJoins(dbAlias string, joins []*model.JoinOption) {
+	for _, j := range joins {
+		j.On = schemaHelpers.AddSoftDeleteClause(m.schemaDoc[dbAlias][j.Table], j.Table, j.On)
+		m.filterSoftDeletedJoins(dbAlias, j.Join)
+	}
+}
+
+// filterSoftDeletedPipeline adds a stage to skip soft deleted documents at the start of an aggregation pipeline
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) filterSoftDeletedPipeline(dbAlias, col string, req *model.AggregateRequest) {
+	if req.IncludeDeleted {
+		return
+	}
+
+	pipeline, ok := req.Pipeline.([]interface{})
+	if !ok {
+		return
+	}
+	if match := schemaHelpers.AddSoftDeleteClause(m.schemaDoc[dbAlias][col], "", nil); len(match) > 0 {
+		req.Pipeline = append([]interface{}{map[string]interface{}{"$match": match}}, pipeline...)
+	}
+}
+
+// prepareSoftDelete converts a delete request into an update request which marks the documents as deleted. It returns
+// false if the table doesn't have a soft delete field.
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) prepareSoftDelete(dbAlias, dbType, col, op string, find map[string]interface{}) (*model.UpdateRequest, bool) {
+	field, ok := schemaHelpers.GetSoftDeleteField(m.schemaDoc[dbAlias][col])
+	if !ok {
+		return nil, false
+	}
+
+	return &model.UpdateRequest{
+		Find:      schemaHelpers.AddSoftDeleteClause(m.schemaDoc[dbAlias][col], "", find),
+		Operation: getSoftDeleteOperation(dbType, op),
+		Update:    map[string]interface{}{"$set": map[string]interface{}{field.FieldName: schemaHelpers.GenerateSoftDeleteMarker(field, true)}},
+	}, true
+}
+
+// prepareRestore generates the update request which unmarks the soft deleted documents
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) prepareRestore(ctx context.Context, dbAlias, dbType, col, op string, find map[string]interface{}) (*model.UpdateRequest, error) {
+	field, ok := schemaHelpers.GetSoftDeleteField(m.schemaDoc[dbAlias][col])
+	if !ok {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot restore documents of table (%s) as it does not have a field with the soft delete directive", col), nil, nil)
+	}
+
+	return &model.UpdateRequest{
+		Find:      find,
+		Operation: getSoftDeleteOperation(dbType, op),
+		Update:    map[string]interface{}{"$set": map[string]interface{}{field.FieldName: schemaHelpers.GenerateSoftDeleteMarker(field, false)}},
+	}, nil
+}
+
+// getSoftDeleteOperation returns the update operation to be used for a soft delete or restore request. Sql databases
+// do not support updating a single row.
+func getSoftDeleteOperation(dbType, op string) string {
+	switch model.DBType(dbType) {
+	case model.MySQL, model.Postgres, model.SQLServer, model.SQLite:
+		return utils.All
+	}
+	if op == "" {
+		return utils.All
+	}
+	return op
+}
+
+// getPrimaryKeys returns the primary keys of a table in the order of the composite primary key
+func getPrimaryKeys(fields model.Fields) []string {
+	primaryFields := make([]*model.FieldType, 0)
+	for _, field := range fields {
+		if field.IsPrimary {
+			primaryFields = append(primaryFields, field)
+		}
+	}
+
+	sort.SliceStable(primaryFields, func(i, j int) bool {
+		if primaryFields[i].PrimaryKeyInfo == nil || primaryFields[j].PrimaryKeyInfo == nil {
+			return primaryFields[i].FieldName < primaryFields[j].FieldName
+		}
+		return primaryFields[i].PrimaryKeyInfo.Order < primaryFields[j].PrimaryKeyInfo.Order
+	})
+
+	primaryKeys := make([]string, len(primaryFields))
+	for i, field := range primaryFields {
+		primaryKeys[i] = field.FieldName
+	}
+	return primaryKeys
+}
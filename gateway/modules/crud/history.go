@@ -0,0 +1,332 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spaceuptech/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	// historyReadChunkSize is the number of rows read at once to fetch the updated values of the rows
+	historyReadChunkSize = 100
+
+	// historyMaxRows is the maximum number of rows a single request can change on a table whose history is recorded.
+	// The old values of the rows are held in memory till the request completes.
+	historyMaxRows = 10000
+)
+
+// historyEntry tracks the rows changed by a request on a table whose change history is recorded
+type historyEntry struct {
+	col       string
+	operation model.OperationType
+	isUpsert  bool
+	find      map[string]interface{}
+	oldDocs   []interface{}
+	newDocs   []interface{}
+}
+
+// isHistoryEnabled checks if the change history of the rows of a table needs to be recorded
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) isHistoryEnabled(dbAlias, col string) bool {
+	for _, rule := range m.dbRules {
+		if rule.DbAlias == dbAlias && rule.Table == col {
+			return rule.EnableHistory
+		}
+	}
+	return false
+}
+
+// trackCreate tracks the rows inserted by a create request. Nil is returned if the history of the table isn't recorded.
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) trackCreate(dbAlias, col string, docs interface{}) *historyEntry {
+	if !m.isHistoryEnabled(dbAlias, col) {
+		return nil
+	}
+
+	entry := &historyEntry{col: col, operation: model.Create}
+	switch v := docs.(type) {
+	case map[string]interface{}:
+		entry.newDocs = []interface{}{v}
+	case []interface{}:
+		entry.newDocs = v
+	}
+	return entry
+}
+
+// trackChange reads the current value of the rows which are about to be changed by an update, delete or restore
+// request. Nil is returned if the history of the table isn't recorded.
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) trackChange(ctx context.Context, crud Crud, dbAlias, col string, operation model.OperationType, op string, find map[string]interface{}) (*historyEntry, error) {
+	if !m.isHistoryEnabled(dbAlias, col) {
+		return nil, nil
+	}
+
+	// One row more than the maximum is read to find out if the request changes too many rows
+	limit := int64(historyMaxRows + 1)
+	if op == utils.One {
+		limit = 1
+	}
+	readReq := &model.ReadRequest{Find: find, Operation: utils.All, Options: &model.ReadOptions{Limit: &limit}}
+
+	entry := &historyEntry{col: col, operation: operation, isUpsert: op == utils.Upsert, find: find, oldDocs: []interface{}{}}
+	if _, err := crud.Export(ctx, col, readReq, func(doc map[string]interface{}) error {
+		if len(entry.oldDocs) == historyMaxRows {
+			return fmt.Errorf("a single request cannot change more than (%d) rows of a table whose history is recorded", historyMaxRows)
+		}
+		entry.oldDocs = append(entry.oldDocs, doc)
+		return nil
+	}); err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read the rows of table (%s) to record their history", col), err, nil)
+	}
+	return entry, nil
+}
+
+// recordHistory writes the old and new values of the rows changed by a request to the history tables. The request has
+// already been performed by now. The history of every entry is still written if one of them fails, and the first
+// failure is returned so that the request doesn't report success for a change missing from the history.
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) recordHistory(ctx context.Context, crud Crud, dbAlias string, params model.RequestParams, entries ...*historyEntry) error {
+	dbType, err := m.getDBType(dbAlias)
+	if err != nil {
+		return err
+	}
+
+	var historyErr error
+	for _, entry := range entries {
+		if entry == nil {
+			continue
+		}
+
+		if err := m.writeHistory(ctx, crud, dbAlias, dbType, entry, params); err != nil && historyErr == nil {
+			historyErr = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to record history of table (%s). The change has been applied", entry.col), err, map[string]interface{}{"dbAlias": dbAlias})
+		}
+	}
+	return historyErr
+}
+
+func (m *Module) writeHistory(ctx context.Context, crud Crud, dbAlias, dbType string, entry *historyEntry, params model.RequestParams) error {
+	primaryKeys := []string{"_id"}
+	switch model.DBType(dbType) {
+	case model.MySQL, model.Postgres, model.SQLServer, model.SQLite:
+		primaryKeys = getPrimaryKeys(m.schemaDoc[dbAlias][entry.col])
+		if len(primaryKeys) == 0 {
+			return fmt.Errorf("table (%s) does not have a primary key", entry.col)
+		}
+	}
+
+	// Fetch the new values of the updated rows
+	if entry.operation == model.Update || entry.operation == model.Restore {
+		var err error
+		entry.newDocs, err = m.readUpdatedDocs(ctx, crud, entry, primaryKeys)
+		if err != nil {
+			return err
+		}
+	}
+
+	oldDocs := map[string]interface{}{}
+	keys := make([]string, 0)
+	for _, doc := range entry.oldDocs {
+		key, err := getHistoryRowKey(primaryKeys, doc.(map[string]interface{}))
+		if err != nil {
+			return err
+		}
+		oldDocs[key] = doc
+		keys = append(keys, key)
+	}
+	newDocs := map[string]interface{}{}
+	for _, doc := range entry.newDocs {
+		docMap, ok := doc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, err := getHistoryRowKey(primaryKeys, docMap)
+		if err != nil {
+			return err
+		}
+		if _, p := oldDocs[key]; !p {
+			keys = append(keys, key)
+		}
+		newDocs[key] = doc
+	}
+
+	requestID := params.RequestID
+	if requestID == "" {
+		requestID = helpers.GetRequestID(ctx)
+	}
+
+	records := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		record := map[string]interface{}{
+			"_id":        ksuid.New().String(),
+			"row_key":    key,
+			"operation":  string(entry.operation),
+			"request_id": requestID,
+		}
+		if doc, p := oldDocs[key]; p {
+			record["old_doc"] = doc
+		}
+		if doc, p := newDocs[key]; p && entry.operation != model.Delete {
+			record["new_doc"] = doc
+		}
+		if params.Claims != nil {
+			record["claims"] = params.Claims
+			if id, ok := params.Claims["id"].(string); ok {
+				record["user_id"] = id
+			}
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	historyCol := utils.HistoryTable(entry.col)
+	req := &model.CreateRequest{Operation: utils.All, Document: records}
+	if err := schemaHelpers.ValidateCreateOperation(ctx, dbAlias, dbType, historyCol, m.schemaDoc, req); err != nil {
+		return err
+	}
+	_, err := crud.Create(ctx, historyCol, req)
+	return err
+}
+
+// readUpdatedDocs reads the current value of the rows tracked by the entry. The rows matching the where clause are read
+// if an upsert didn't find any rows to update.
+func (m *Module) readUpdatedDocs(ctx context.Context, crud Crud, entry *historyEntry, primaryKeys []string) ([]interface{}, error) {
+	docs := make([]interface{}, 0)
+	cb := func(doc map[string]interface{}) error {
+		docs = append(docs, doc)
+		return nil
+	}
+
+	if len(entry.oldDocs) == 0 {
+		if !entry.isUpsert {
+			return docs, nil
+		}
+		_, err := crud.Export(ctx, entry.col, &model.ReadRequest{Find: entry.find, Operation: utils.All, Options: &model.ReadOptions{}}, cb)
+		return docs, err
+	}
+
+	for start := 0; start < len(entry.oldDocs); start += historyReadChunkSize {
+		end := start + historyReadChunkSize
+		if end > len(entry.oldDocs) {
+			end = len(entry.oldDocs)
+		}
+
+		or := make([]interface{}, 0, end-start)
+		for _, doc := range entry.oldDocs[start:end] {
+			docMap := doc.(map[string]interface{})
+			find := map[string]interface{}{}
+			for _, key := range primaryKeys {
+				find[key] = docMap[key]
+			}
+			or = append(or, find)
+		}
+		if _, err := crud.Export(ctx, entry.col, &model.ReadRequest{Find: map[string]interface{}{"$or": or}, Operation: utils.All, Options: &model.ReadOptions{}}, cb); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// getHistoryRowKey returns the key identifying a row in the history table. The value of the primary key is used as is
+// while the values of a composite primary key are encoded as a json array.
+func getHistoryRowKey(primaryKeys []string, doc map[string]interface{}) (string, error) {
+	values := make([]interface{}, len(primaryKeys))
+	for i, key := range primaryKeys {
+		value, p := doc[key]
+		if !p || value == nil {
+			return "", fmt.Errorf("value of primary key (%s) not provided", key)
+		}
+		switch v := value.(type) {
+		case float64:
+			// Numbers read from json are float64 while the ones read from the database are integers
+			if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+				value = int64(v)
+			}
+		case primitive.ObjectID:
+			value = v.Hex()
+		}
+		values[i] = value
+	}
+
+	if len(values) == 1 {
+		return fmt.Sprintf("%v", values[0]), nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// History returns the change history of a row of a table in the order in which the changes were made
+func (m *Module) History(ctx context.Context, dbAlias, col string, req *model.HistoryRequest, params model.RequestParams) ([]interface{}, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if !m.isHistoryEnabled(dbAlias, col) {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("History is not enabled for table (%s)", col), nil, map[string]interface{}{"dbAlias": dbAlias})
+	}
+
+	dbType, err := m.getDBType(dbAlias)
+	if err != nil {
+		return nil, err
+	}
+	primaryKeys := []string{"_id"}
+	switch model.DBType(dbType) {
+	case model.MySQL, model.Postgres, model.SQLServer, model.SQLite:
+		primaryKeys = getPrimaryKeys(m.schemaDoc[dbAlias][col])
+	}
+	key, err := getHistoryRowKey(primaryKeys, req.Key)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid key provided to fetch the history of table (%s)", col), err, nil)
+	}
+
+	params.Payload = req
+	hookResponse := m.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return nil, err
+		}
+
+		// Gracefully return
+		docs, _ := hookResponse.Result().([]interface{})
+		return docs, nil
+	}
+
+	crud, err := m.getCrudBlock(dbAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := crud.IsClientSafe(ctx); err != nil {
+		return nil, err
+	}
+
+	readReq := &model.ReadRequest{Find: map[string]interface{}{"row_key": key}, Operation: utils.All, Options: &model.ReadOptions{Sort: []string{"ts", "_id"}, Limit: req.Limit}}
+	historyCol := utils.HistoryTable(col)
+	if err := schemaHelpers.AdjustWhereClause(ctx, dbAlias, model.DBType(dbType), historyCol, m.schemaDoc, readReq.Find); err != nil {
+		return nil, err
+	}
+	_, result, _, _, err := crud.Read(ctx, historyCol, readReq)
+	if err != nil {
+		return nil, err
+	}
+	if err := schemaHelpers.CrudPostProcess(ctx, dbAlias, dbType, historyCol, m.schemaDoc, result); err != nil {
+		return nil, err
+	}
+
+	docs, _ := result.([]interface{})
+	m.metricHook(m.project, dbAlias, historyCol, int64(len(docs)), model.Read)
+	return docs, nil
+}
@@ -0,0 +1,139 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud/bolt"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func Test_getHistoryRowKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		primaryKeys []string
+		doc         map[string]interface{}
+		want        string
+		wantErr     bool
+	}{
+		{name: "single primary key", primaryKeys: []string{"id"}, doc: map[string]interface{}{"id": "1", "name": "a"}, want: "1"},
+		{name: "integral number read from json", primaryKeys: []string{"id"}, doc: map[string]interface{}{"id": float64(12)}, want: "12"},
+		{name: "composite primary key", primaryKeys: []string{"org", "id"}, doc: map[string]interface{}{"id": int64(1), "org": "x"}, want: `["x",1]`},
+		{name: "missing primary key", primaryKeys: []string{"id"}, doc: map[string]interface{}{"name": "a"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getHistoryRowKey(tt.primaryKeys, tt.doc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getHistoryRowKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("getHistoryRowKey() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModule_recordHistory(t *testing.T) {
+	ctx := context.Background()
+	b, err := bolt.Init(true, "history.db", "bucketName")
+	if err != nil {
+		t.Fatal("error initializing database", err)
+	}
+	defer func() {
+		utils.CloseTheCloser(b)
+		_ = os.Remove("history.db")
+	}()
+
+	schemaDoc, err := schemaHelpers.Parser(config.DatabaseSchemas{"history": &config.DatabaseSchema{DbAlias: "db", Table: utils.HistoryTable("users"), Schema: utils.HistorySchema("users")}})
+	if err != nil {
+		t.Fatal("error parsing history schema", err)
+	}
+	m := &Module{
+		blocks:    map[string]Crud{"db": b},
+		schemaDoc: schemaDoc,
+		dbRules:   config.DatabaseRules{"rule": &config.DatabaseRule{DbAlias: "db", Table: "users", EnableHistory: true}},
+	}
+	params := model.RequestParams{RequestID: "req", Claims: map[string]interface{}{"id": "u1"}}
+
+	doc := map[string]interface{}{"_id": "1", "name": "a"}
+	if _, err := b.Create(ctx, "users", &model.CreateRequest{Operation: utils.One, Document: doc}); err != nil {
+		t.Fatal("error inserting document", err)
+	}
+	if err := m.recordHistory(ctx, b, "db", params, m.trackCreate("db", "users", doc)); err != nil {
+		t.Fatal("recordHistory() error", err)
+	}
+
+	entry, err := m.trackChange(ctx, b, "db", "users", model.Update, utils.All, map[string]interface{}{"_id": "1"})
+	if err != nil {
+		t.Fatal("trackChange() error", err)
+	}
+	if _, err := b.Update(ctx, "users", &model.UpdateRequest{Operation: utils.All, Find: map[string]interface{}{"_id": "1"}, Update: map[string]interface{}{"$set": map[string]interface{}{"name": "b"}}}); err != nil {
+		t.Fatal("error updating document", err)
+	}
+	if err := m.recordHistory(ctx, b, "db", params, entry); err != nil {
+		t.Fatal("recordHistory() error", err)
+	}
+
+	// Failures to record the history are returned
+	if err := m.recordHistory(ctx, b, "db", params, m.trackCreate("db", "users", map[string]interface{}{"name": "c"})); err == nil {
+		t.Error("recordHistory() recorded the history of a row without a primary key")
+	}
+
+	// Tables without history aren't tracked
+	if entry := m.trackCreate("db", "posts", doc); entry != nil {
+		t.Error("trackCreate() tracked a table without history")
+	}
+
+	_, result, _, _, err := b.Read(ctx, utils.HistoryTable("users"), &model.ReadRequest{Operation: utils.All, Find: map[string]interface{}{"row_key": "1"}})
+	if err != nil {
+		t.Fatal("error reading history", err)
+	}
+	records := result.([]interface{})
+	if len(records) != 2 {
+		t.Fatalf("recordHistory() got %d records, want 2", len(records))
+	}
+	for _, r := range records {
+		record := r.(map[string]interface{})
+		if record["user_id"] != "u1" || record["request_id"] != "req" {
+			t.Errorf("recordHistory() got record without caller details - %v", record)
+		}
+		switch record["operation"] {
+		case string(model.Create):
+			if record["old_doc"] != nil {
+				t.Errorf("recordHistory() got old value for a created row - %v", record)
+			}
+		case string(model.Update):
+			// Values of json fields are stored as strings in the embedded database
+			var oldDoc, newDoc map[string]interface{}
+			_ = json.Unmarshal([]byte(record["old_doc"].(string)), &oldDoc)
+			_ = json.Unmarshal([]byte(record["new_doc"].(string)), &newDoc)
+			if oldDoc["name"] != "a" || newDoc["name"] != "b" {
+				t.Errorf("recordHistory() got = %v, want old name (a) and new name (b)", record)
+			}
+		default:
+			t.Errorf("recordHistory() got unknown operation %v", record["operation"])
+		}
+	}
+	// Requests changing too many rows of a table with history are rejected
+	docs := make([]interface{}, historyMaxRows)
+	for i := range docs {
+		docs[i] = map[string]interface{}{"_id": fmt.Sprintf("bulk-%d", i)}
+	}
+	if _, err := b.Create(ctx, "users", &model.CreateRequest{Operation: utils.All, Document: docs}); err != nil {
+		t.Fatal("error inserting documents", err)
+	}
+	if _, err := m.trackChange(ctx, b, "db", "users", model.Delete, utils.All, map[string]interface{}{}); err == nil {
+		t.Errorf("trackChange() tracked more than (%d) rows", historyMaxRows)
+	}
+	if _, err := m.trackChange(ctx, b, "db", "users", model.Delete, utils.One, map[string]interface{}{}); err != nil {
+		t.Error("trackChange() error", err)
+	}
+}
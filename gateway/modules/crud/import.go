@@ -0,0 +1,201 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// defaultImportChunkSize is the number of rows inserted at once if the chunk size isn't provided
+const defaultImportChunkSize = 100
+
+// Import validates the rows against the schema and inserts them in chunks. In the atomic mode, nothing is inserted if
+// any row is invalid and all the chunks are inserted in a single transaction. In the best effort mode, each chunk of
+// valid rows is inserted in its own transaction and the rows which could not be inserted are reported back.
+func (m *Module) Import(ctx context.Context, dbAlias, col string, req *model.ImportRequest, params model.RequestParams) (*model.ImportResponse, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	dbType, err := m.getDBType(dbAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Mode {
+	case model.ImportModeAtomic, model.ImportModeBestEffort:
+	default:
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid import mode (%s) provided", req.Mode), nil, nil)
+	}
+	if req.ChunkSize <= 0 {
+		req.ChunkSize = defaultImportChunkSize
+	}
+
+	// Validate every row against the schema
+	res := &model.ImportResponse{Errors: []*model.ImportRowError{}}
+	fields, hasSchema := m.schemaDoc[dbAlias][col]
+	rows := make([]*model.ImportRow, 0, len(req.Rows))
+	for _, row := range req.Rows {
+		if req.IsCSV {
+			if err := parseCSVDocument(fields, row.Document); err != nil {
+				res.Errors = append(res.Errors, &model.ImportRowError{Row: row.Row, Error: err.Error()})
+				continue
+			}
+		}
+		if hasSchema {
+			doc, err := schemaHelpers.SchemaValidator(ctx, dbAlias, dbType, col, fields, row.Document)
+			if err != nil {
+				res.Errors = append(res.Errors, &model.ImportRowError{Row: row.Row, Error: err.Error()})
+				continue
+			}
+			row.Document = doc
+		}
+		rows = append(rows, row)
+	}
+	if req.Mode == model.ImportModeAtomic && len(res.Errors) > 0 {
+		return res, nil
+	}
+
+	params.Payload = req
+	hookResponse := m.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return nil, err
+		}
+
+		// Gracefully return
+		return res, nil
+	}
+
+	crud, err := m.getCrudBlock(dbAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := crud.IsClientSafe(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.Mode == model.ImportModeAtomic {
+		batch := &model.BatchRequest{Requests: []*model.AllRequest{}}
+		for start := 0; start < len(rows); start += req.ChunkSize {
+			batch.Requests = append(batch.Requests, &model.AllRequest{Type: string(model.Create), Col: col, Operation: utils.All, Document: getImportDocuments(rows, start, req.ChunkSize)})
+		}
+		if len(batch.Requests) == 0 {
+			return res, nil
+		}
+
+		counts, err := crud.Batch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range counts {
+			res.Inserted += n
+		}
+		m.metricHook(m.project, dbAlias, col, res.Inserted, model.Create)
+		if err := m.recordHistory(ctx, crud, dbAlias, params, m.trackCreate(dbAlias, col, getImportDocuments(rows, 0, len(rows)))); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	for start := 0; start < len(rows); start += req.ChunkSize {
+		docs := getImportDocuments(rows, start, req.ChunkSize)
+		n, err := insertImportChunk(ctx, crud, dbType, col, docs)
+		if err == nil {
+			res.Inserted += n
+			m.metricHook(m.project, dbAlias, col, n, model.Create)
+			if err := m.recordHistory(ctx, crud, dbAlias, params, m.trackCreate(dbAlias, col, docs)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Nothing from the failed chunk was inserted. Insert its rows one by one to find the ones which caused the failure
+		for i, doc := range docs {
+			n, err := crud.Create(ctx, col, &model.CreateRequest{Operation: utils.All, Document: []interface{}{doc}})
+			if err != nil {
+				res.Errors = append(res.Errors, &model.ImportRowError{Row: rows[start+i].Row, Error: err.Error()})
+				continue
+			}
+			res.Inserted += n
+			m.metricHook(m.project, dbAlias, col, n, model.Create)
+			if err := m.recordHistory(ctx, crud, dbAlias, params, m.trackCreate(dbAlias, col, []interface{}{doc})); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
+}
+
+// insertImportChunk inserts a chunk of documents in a transaction so that either all or none of them get inserted. The
+// embedded database inserts the documents of a create request in a single transaction already.
+func insertImportChunk(ctx context.Context, crud Crud, dbType, col string, docs []interface{}) (int64, error) {
+	if dbType == string(model.EmbeddedDB) {
+		return crud.Create(ctx, col, &model.CreateRequest{Operation: utils.All, Document: docs})
+	}
+
+	counts, err := crud.Batch(ctx, &model.BatchRequest{Requests: []*model.AllRequest{{Type: string(model.Create), Col: col, Operation: utils.All, Document: docs}}})
+	if err != nil {
+		return 0, err
+	}
+	return counts[0], nil
+}
+
+func getImportDocuments(rows []*model.ImportRow, start, chunkSize int) []interface{} {
+	end := start + chunkSize
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	docs := make([]interface{}, 0, end-start)
+	for _, row := range rows[start:end] {
+		docs = append(docs, row.Document)
+	}
+	return docs
+}
+
+// parseCSVDocument converts the string values of a csv row to the types of the fields in the schema. Empty values are
+// removed so that the defaults of the schema get applied.
+func parseCSVDocument(fields model.Fields, doc map[string]interface{}) error {
+	for key, value := range doc {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if str == "" {
+			delete(doc, key)
+			continue
+		}
+
+		field, p := fields[key]
+		if !p {
+			continue
+		}
+
+		var err error
+		switch {
+		case field.IsList || field.Kind == model.TypeJSON || field.Kind == model.TypeObject || field.Kind == model.TypePoint || field.Kind == model.TypeGeometry:
+			var v interface{}
+			err = json.Unmarshal([]byte(str), &v)
+			doc[key] = v
+		case field.Kind == model.TypeInteger || field.Kind == model.TypeSmallInteger || field.Kind == model.TypeBigInteger:
+			doc[key], err = strconv.Atoi(str)
+		case field.Kind == model.TypeFloat || field.Kind == model.TypeDecimal:
+			doc[key], err = strconv.ParseFloat(str, 64)
+		case field.Kind == model.TypeBoolean:
+			doc[key], err = strconv.ParseBool(str)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value (%s) provided for field (%s) of type (%s)", str, key, field.Kind)
+		}
+	}
+	return nil
+}
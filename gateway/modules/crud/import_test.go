@@ -0,0 +1,111 @@
+package crud
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_parseCSVDocument(t *testing.T) {
+	fields := model.Fields{
+		"id":      &model.FieldType{FieldName: "id", Kind: model.TypeID},
+		"age":     &model.FieldType{FieldName: "age", Kind: model.TypeInteger},
+		"score":   &model.FieldType{FieldName: "score", Kind: model.TypeFloat},
+		"active":  &model.FieldType{FieldName: "active", Kind: model.TypeBoolean},
+		"meta":    &model.FieldType{FieldName: "meta", Kind: model.TypeJSON},
+		"tags":    &model.FieldType{FieldName: "tags", Kind: model.TypeString, IsList: true},
+		"created": &model.FieldType{FieldName: "created", Kind: model.TypeDateTime},
+	}
+	tests := []struct {
+		name    string
+		doc     map[string]interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "values are parsed as per the schema",
+			doc:  map[string]interface{}{"id": "1", "age": "20", "score": "2.5", "active": "true", "meta": `{"a":1}`, "tags": `["x"]`, "created": "2020-01-02T03:04:05Z"},
+			want: map[string]interface{}{"id": "1", "age": 20, "score": 2.5, "active": true, "meta": map[string]interface{}{"a": float64(1)}, "tags": []interface{}{"x"}, "created": "2020-01-02T03:04:05Z"},
+		},
+		{
+			name: "empty values are removed",
+			doc:  map[string]interface{}{"id": "1", "age": ""},
+			want: map[string]interface{}{"id": "1"},
+		},
+		{
+			name: "fields not in schema are left as is",
+			doc:  map[string]interface{}{"id": "1", "unknown": "20"},
+			want: map[string]interface{}{"id": "1", "unknown": "20"},
+		},
+		{
+			name:    "invalid integer",
+			doc:     map[string]interface{}{"age": "twenty"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseCSVDocument(fields, tt.doc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseCSVDocument() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.doc, tt.want) {
+				t.Errorf("parseCSVDocument() got = %v, want %v", tt.doc, tt.want)
+			}
+		})
+	}
+}
+
+// importCrud records how the chunks of an import get inserted
+type importCrud struct {
+	Crud
+	batches []*model.BatchRequest
+	creates []*model.CreateRequest
+}
+
+func (c *importCrud) Batch(ctx context.Context, req *model.BatchRequest) ([]int64, error) {
+	c.batches = append(c.batches, req)
+	return []int64{int64(len(req.Requests[0].Document.([]interface{})))}, nil
+}
+
+func (c *importCrud) Create(ctx context.Context, col string, req *model.CreateRequest) (int64, error) {
+	c.creates = append(c.creates, req)
+	return int64(len(req.Document.([]interface{}))), nil
+}
+
+func Test_insertImportChunk(t *testing.T) {
+	docs := []interface{}{map[string]interface{}{"id": "1"}, map[string]interface{}{"id": "2"}}
+	tests := []struct {
+		name        string
+		dbType      string
+		wantBatches int
+		wantCreates int
+	}{
+		{name: "chunk is inserted in a transaction", dbType: string(model.Postgres), wantBatches: 1},
+		{name: "chunk is inserted in a transaction on mongo", dbType: string(model.Mongo), wantBatches: 1},
+		{name: "embedded database inserts the chunk in a single create", dbType: string(model.EmbeddedDB), wantCreates: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crud := &importCrud{}
+			n, err := insertImportChunk(context.Background(), crud, tt.dbType, "users", docs)
+			if err != nil {
+				t.Fatalf("insertImportChunk() error = %v", err)
+			}
+			if n != 2 {
+				t.Errorf("insertImportChunk() got = %d, want 2", n)
+			}
+			if len(crud.batches) != tt.wantBatches || len(crud.creates) != tt.wantCreates {
+				t.Errorf("insertImportChunk() got batches = %d creates = %d, want batches = %d creates = %d", len(crud.batches), len(crud.creates), tt.wantBatches, tt.wantCreates)
+			}
+			for _, batch := range crud.batches {
+				if len(batch.Requests) != 1 || batch.Requests[0].Type != string(model.Create) || !reflect.DeepEqual(batch.Requests[0].Document, docs) {
+					t.Errorf("insertImportChunk() got batch = %v, want a single create request with the chunk", batch.Requests)
+				}
+			}
+		})
+	}
+}
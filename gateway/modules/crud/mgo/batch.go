@@ -34,7 +34,7 @@ func (m *Mongo) Batch(ctx context.Context, req *model.BatchRequest) ([]int64, er
 				op := req.Operation
 				update := req.Update
 
-				counts[i], err = m.Update(session, col, &model.UpdateRequest{Find: find, Operation: op, Update: update})
+				counts[i], err = m.Update(session, col, &model.UpdateRequest{Find: find, Operation: op, Update: update, IsVersioned: req.IsVersioned})
 				if err != nil {
 					_ = session.AbortTransaction(session)
 					return err
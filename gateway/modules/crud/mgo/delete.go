@@ -13,7 +13,15 @@ import (
 // Delete removes the document(s) from the database which match the condition
 func (m *Mongo) Delete(ctx context.Context, col string, req *model.DeleteRequest) (int64, error) {
 	collection := m.getClient().Database(m.dbName).Collection(col)
-	req.Find = sanitizeWhereClause(ctx, col, req.Find)
+	find, err := sanitizeSearchClause(sanitizeWhereClause(ctx, col, req.Find))
+	if err != nil {
+		return 0, err
+	}
+	find, err = sanitizeGeoClause(find, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Find = find
 
 	switch req.Operation {
 	case utils.One:
@@ -0,0 +1,71 @@
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// Export streams the documents matching the read request to the callback. Documents are decoded one at a time from
+// the mongo cursor and the query fetch limit is not applied.
+func (m *Mongo) Export(ctx context.Context, col string, req *model.ReadRequest, cb model.ExportCallback) (int64, error) {
+	collection := m.getClient().Database(m.dbName).Collection(col)
+
+	if req.Options == nil {
+		req.Options = &model.ReadOptions{}
+	}
+
+	find, err := sanitizeSearchClause(sanitizeWhereClause(ctx, col, req.Find))
+	if err != nil {
+		return 0, err
+	}
+	find, err = sanitizeGeoClause(find, req.Options.Sort)
+	if err != nil {
+		return 0, err
+	}
+	req.Find = find
+
+	findOptions := options.Find()
+	if req.Options.Select != nil || isSearchScoreSorted(req.Options.Sort) {
+		findOptions = findOptions.SetProjection(generateProjection(req.Options.Select, req.Options.Sort))
+	}
+	if req.Options.Skip != nil {
+		findOptions = findOptions.SetSkip(*req.Options.Skip)
+	}
+	if req.Options.Limit != nil {
+		findOptions = findOptions.SetLimit(*req.Options.Limit)
+	}
+	if req.Options.Sort != nil {
+		findOptions = findOptions.SetSort(generateSortOptions(req.Options.Sort))
+	}
+
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Mongo export", map[string]interface{}{"col": col, "find": req.Find, "options": findOptions})
+	cur, err := collection.Find(ctx, req.Find, findOptions)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = cur.Close(ctx) }()
+
+	var count int64
+	for cur.Next(ctx) {
+		var doc map[string]interface{}
+		if err := cur.Decode(&doc); err != nil {
+			return count, err
+		}
+
+		if req.Options.Debug {
+			doc["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
+		}
+
+		if err := cb(doc); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, cur.Err()
+}
@@ -2,7 +2,14 @@ package mgo
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
 func sanitizeWhereClause(ctx context.Context, col string, find map[string]interface{}) map[string]interface{} {
@@ -32,3 +39,213 @@ func sanitizeWhereClause(ctx context.Context, col string, find map[string]interf
 	}
 	return find
 }
+
+// sanitizeSearchClause converts the full text search clause of a field to the $text operator. Mongo performs the text
+// search on all the fields of the text index of the collection irrespective of the field the clause was provided on,
+// hence only a single search clause is allowed
+func sanitizeSearchClause(find map[string]interface{}) (map[string]interface{}, error) {
+	searchKey := ""
+	for key, value := range find {
+		if obj, ok := value.(map[string]interface{}); ok && key != "$text" {
+			if _, p := obj["$search"]; p {
+				if searchKey != "" {
+					return nil, fmt.Errorf("only a single _search clause is supported by mongo, found one on (%s) and (%s)", searchKey, key)
+				}
+				searchKey = key
+			}
+		}
+	}
+	if searchKey == "" {
+		return find, nil
+	}
+
+	obj := find[searchKey].(map[string]interface{})
+	query := obj["$search"]
+	delete(obj, "$search")
+	if len(obj) == 0 {
+		delete(find, searchKey)
+	}
+	find["$text"] = map[string]interface{}{"$search": query}
+	return find, nil
+}
+
+// sanitizeGeoClause converts the geo clauses to the geo operators of mongo. The near clause is converted to $nearSphere,
+// which returns the documents sorted by their distance, only if the documents are to be sorted by their distance. Mongo
+// can only sort by the distance in the ascending order.
+func sanitizeGeoClause(find map[string]interface{}, sort []string) (map[string]interface{}, error) {
+	sortByDistance := false
+	for _, value := range sort {
+		switch value {
+		case utils.DistanceField:
+			sortByDistance = true
+		case "-" + utils.DistanceField:
+			return nil, fmt.Errorf("mongo can only sort by (%s) in the ascending order", utils.DistanceField)
+		}
+	}
+
+	for key, value := range find {
+		if key == "$or" {
+			arr, _ := value.([]interface{})
+			for _, item := range arr {
+				if obj, ok := item.(map[string]interface{}); ok {
+					if _, err := sanitizeGeoClause(obj, nil); err != nil {
+						return nil, err
+					}
+				}
+			}
+			continue
+		}
+
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		_, hasNear := obj["$near"]
+		_, hasWithin := obj["$within"]
+		if !hasNear && !hasWithin {
+			continue
+		}
+
+		if v, p := obj["$near"]; p {
+			near, err := utils.ParseGeoNear(v)
+			if err != nil {
+				return nil, err
+			}
+			delete(obj, "$near")
+
+			switch {
+			case sortByDistance:
+				nearSphere := map[string]interface{}{"$geometry": near.Point}
+				if near.MaxDistance != nil {
+					nearSphere["$maxDistance"] = *near.MaxDistance
+				}
+				obj["$nearSphere"] = nearSphere
+				sortByDistance = false
+			case near.MaxDistance != nil:
+				obj["$geoWithin"] = map[string]interface{}{"$centerSphere": []interface{}{near.Point["coordinates"], *near.MaxDistance / utils.EarthRadius}}
+			}
+		}
+
+		if v, p := obj["$within"]; p {
+			within, err := utils.ParseGeoWithin(v)
+			if err != nil {
+				return nil, err
+			}
+			delete(obj, "$within")
+
+			if within.Polygon != nil {
+				obj["$geoWithin"] = map[string]interface{}{"$geometry": within.Polygon}
+			} else {
+				obj["$geoWithin"] = map[string]interface{}{"$centerSphere": []interface{}{within.Center["coordinates"], within.Radius / utils.EarthRadius}}
+			}
+		}
+
+		// A near clause without a max distance doesn't filter the documents
+		if len(obj) == 0 {
+			delete(find, key)
+		}
+	}
+
+	if sortByDistance {
+		return nil, fmt.Errorf("cannot sort by (%s) without a _near clause in the where clause", utils.DistanceField)
+	}
+	return find, nil
+}
+
+// generateProjection returns the projection for a read. The relevance of a text search needs to be projected in order
+// to sort by it
+func generateProjection(selectMap map[string]int32, sort []string) interface{} {
+	if !isSearchScoreSorted(sort) {
+		return selectMap
+	}
+
+	projection := bson.M{utils.SearchScoreField: bson.M{"$meta": "textScore"}}
+	for k, v := range selectMap {
+		projection[k] = v
+	}
+	return projection
+}
+
+func isSearchScoreSorted(sort []string) bool {
+	for _, value := range sort {
+		if strings.TrimPrefix(value, "-") == utils.SearchScoreField {
+			return true
+		}
+	}
+	return false
+}
+
+// mongoIndex is the description of an index of a collection returned by mongo
+type mongoIndex struct {
+	Name               string      `bson:"name"`
+	Key                bson.D      `bson:"key"`
+	Unique             bool        `bson:"unique"`
+	ExpireAfterSeconds interface{} `bson:"expireAfterSeconds"`
+}
+
+// planTTLIndexes returns the indexes to be dropped and the fields to create ttl indexes on so that the ttl indexes of a
+// collection match its schema. Mongo doesn't allow two indexes on the same key, hence an index which isn't a ttl index
+// is replaced by the ttl index. Unique indexes are not replaced since the ttl index would drop the constraint.
+func planTTLIndexes(col string, fields model.Fields, existing []*mongoIndex) ([]string, []*model.FieldType, error) {
+	expiring := map[string]*model.FieldType{}
+	for _, field := range fields {
+		if field.ExpireAfter != nil {
+			expiring[field.FieldName] = field
+		}
+	}
+
+	drop := make([]string, 0)
+	for _, index := range existing {
+		var field *model.FieldType
+		if len(index.Key) == 1 && isAscendingIndexKey(index.Key[0].Value) {
+			field = expiring[index.Key[0].Key]
+		}
+
+		switch {
+		case field == nil:
+			// Drop the ttl indexes of the fields which no longer expire
+			if strings.HasPrefix(index.Name, ttlIndexPrefix) {
+				drop = append(drop, index.Name)
+			}
+		case index.Name == ttlIndexPrefix+field.FieldName && getIndexExpiry(index) == *field.ExpireAfter:
+			delete(expiring, field.FieldName)
+		case index.Unique:
+			return nil, nil, fmt.Errorf("unable to create ttl index on field (%s) of collection (%s) as it has a unique index (%s)", field.FieldName, col, index.Name)
+		default:
+			drop = append(drop, index.Name)
+		}
+	}
+
+	create := make([]*model.FieldType, 0, len(expiring))
+	for _, field := range expiring {
+		create = append(create, field)
+	}
+	sort.Slice(create, func(i, j int) bool { return create[i].FieldName < create[j].FieldName })
+	return drop, create, nil
+}
+
+// isAscendingIndexKey checks if the value of an index key is an ascending order
+func isAscendingIndexKey(value interface{}) bool {
+	switch v := value.(type) {
+	case int32:
+		return v == 1
+	case int64:
+		return v == 1
+	case float64:
+		return v == 1
+	}
+	return false
+}
+
+// getIndexExpiry returns the expiry of a ttl index in seconds. It returns -1 if the index isn't a ttl index
+func getIndexExpiry(index *mongoIndex) int64 {
+	switch v := index.ExpireAfterSeconds.(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return -1
+}
@@ -4,6 +4,11 @@ import (
 	"context"
 	"reflect"
 	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
 func Test_sanitizeWhereClause(t *testing.T) {
@@ -85,3 +90,192 @@ func Test_sanitizeWhereClause(t *testing.T) {
 		})
 	}
 }
+
+func Test_sanitizeSearchClause(t *testing.T) {
+	tests := []struct {
+		name    string
+		find    map[string]interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "search clause is converted to text operator",
+			find: map[string]interface{}{"title": map[string]interface{}{"$search": "space cloud"}, "age": 10},
+			want: map[string]interface{}{"$text": map[string]interface{}{"$search": "space cloud"}, "age": 10},
+		},
+		{
+			name: "other operators of the field are retained",
+			find: map[string]interface{}{"title": map[string]interface{}{"$search": "space cloud", "$ne": "cloud"}},
+			want: map[string]interface{}{"$text": map[string]interface{}{"$search": "space cloud"}, "title": map[string]interface{}{"$ne": "cloud"}},
+		},
+		{
+			name: "where clause without search clause",
+			find: map[string]interface{}{"title": map[string]interface{}{"$eq": "space cloud"}},
+			want: map[string]interface{}{"title": map[string]interface{}{"$eq": "space cloud"}},
+		},
+		{
+			name:    "multiple search clauses",
+			find:    map[string]interface{}{"title": map[string]interface{}{"$search": "space"}, "body": map[string]interface{}{"$search": "cloud"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitizeSearchClause(tt.find)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sanitizeSearchClause() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sanitizeSearchClause() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sanitizeGeoClause(t *testing.T) {
+	point := map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.87, 19.07}}
+	tests := []struct {
+		name    string
+		find    map[string]interface{}
+		sort    []string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "near clause sorted by distance is converted to near sphere",
+			find: map[string]interface{}{"location": map[string]interface{}{"$near": map[string]interface{}{"point": point, "maxDistance": float64(5000)}}},
+			sort: []string{"_distance"},
+			want: map[string]interface{}{"location": map[string]interface{}{"$nearSphere": map[string]interface{}{"$geometry": point, "$maxDistance": float64(5000)}}},
+		},
+		{
+			name: "near clause without sort is converted to geo within",
+			find: map[string]interface{}{"location": map[string]interface{}{"$near": map[string]interface{}{"point": point, "maxDistance": utils.EarthRadius}}},
+			want: map[string]interface{}{"location": map[string]interface{}{"$geoWithin": map[string]interface{}{"$centerSphere": []interface{}{point["coordinates"], float64(1)}}}},
+		},
+		{
+			name: "near clause without max distance and sort is dropped",
+			find: map[string]interface{}{"location": map[string]interface{}{"$near": map[string]interface{}{"point": point}}, "age": 10},
+			want: map[string]interface{}{"age": 10},
+		},
+		{
+			name: "within clause with a polygon",
+			find: map[string]interface{}{"location": map[string]interface{}{"$within": map[string]interface{}{"polygon": map[string]interface{}{"type": "Polygon", "coordinates": []interface{}{}}}}},
+			want: map[string]interface{}{"location": map[string]interface{}{"$geoWithin": map[string]interface{}{"$geometry": map[string]interface{}{"type": "Polygon", "coordinates": []interface{}{}}}}},
+		},
+		{
+			name:    "descending sort by distance",
+			find:    map[string]interface{}{"location": map[string]interface{}{"$near": map[string]interface{}{"point": point}}},
+			sort:    []string{"-_distance"},
+			wantErr: true,
+		},
+		{
+			name:    "sort by distance without near clause",
+			find:    map[string]interface{}{"age": 10},
+			sort:    []string{"_distance"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitizeGeoClause(tt.find, tt.sort)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sanitizeGeoClause() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sanitizeGeoClause() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_planTTLIndexes(t *testing.T) {
+	hour, day := int64(3600), int64(86400)
+	fields := model.Fields{
+		"id":        &model.FieldType{FieldName: "id", Kind: model.TypeID},
+		"createdAt": &model.FieldType{FieldName: "createdAt", Kind: model.TypeDateTime, ExpireAfter: &hour},
+	}
+	tests := []struct {
+		name       string
+		fields     model.Fields
+		existing   []*mongoIndex
+		wantDrop   []string
+		wantCreate []string
+		wantErr    bool
+	}{
+		{
+			name:       "ttl index is created",
+			fields:     fields,
+			existing:   []*mongoIndex{{Name: "_id_", Key: bson.D{{Key: "_id", Value: int32(1)}}}},
+			wantDrop:   []string{},
+			wantCreate: []string{"createdAt"},
+		},
+		{
+			name:       "ttl index with the same expiry is left as is",
+			fields:     fields,
+			existing:   []*mongoIndex{{Name: "ttl_createdAt", Key: bson.D{{Key: "createdAt", Value: int32(1)}}, ExpireAfterSeconds: int32(3600)}},
+			wantDrop:   []string{},
+			wantCreate: []string{},
+		},
+		{
+			name:       "ttl index with a different expiry is recreated",
+			fields:     fields,
+			existing:   []*mongoIndex{{Name: "ttl_createdAt", Key: bson.D{{Key: "createdAt", Value: int32(1)}}, ExpireAfterSeconds: int32(day)}},
+			wantDrop:   []string{"ttl_createdAt"},
+			wantCreate: []string{"createdAt"},
+		},
+		{
+			name:       "index which isn't a ttl index on the same key is replaced",
+			fields:     fields,
+			existing:   []*mongoIndex{{Name: "createdAt_1", Key: bson.D{{Key: "createdAt", Value: int32(1)}}}},
+			wantDrop:   []string{"createdAt_1"},
+			wantCreate: []string{"createdAt"},
+		},
+		{
+			name:       "index on the same field in the descending order is left as is",
+			fields:     fields,
+			existing:   []*mongoIndex{{Name: "createdAt_-1", Key: bson.D{{Key: "createdAt", Value: int32(-1)}}}},
+			wantDrop:   []string{},
+			wantCreate: []string{"createdAt"},
+		},
+		{
+			name:     "unique index on the same key",
+			fields:   fields,
+			existing: []*mongoIndex{{Name: "createdAt_1", Key: bson.D{{Key: "createdAt", Value: int32(1)}}, Unique: true}},
+			wantErr:  true,
+		},
+		{
+			name:   "stale ttl index is dropped",
+			fields: model.Fields{"id": &model.FieldType{FieldName: "id", Kind: model.TypeID}},
+			existing: []*mongoIndex{
+				{Name: "ttl_createdAt", Key: bson.D{{Key: "createdAt", Value: int32(1)}}, ExpireAfterSeconds: int32(3600)},
+				{Name: "name_1", Key: bson.D{{Key: "name", Value: int32(1)}}},
+			},
+			wantDrop:   []string{"ttl_createdAt"},
+			wantCreate: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drop, create, err := planTTLIndexes("users", tt.fields, tt.existing)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("planTTLIndexes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(drop, tt.wantDrop) {
+				t.Errorf("planTTLIndexes() drop = %v, want %v", drop, tt.wantDrop)
+			}
+			gotCreate := make([]string, 0, len(create))
+			for _, field := range create {
+				gotCreate = append(gotCreate, field.FieldName)
+			}
+			if !reflect.DeepEqual(gotCreate, tt.wantCreate) {
+				t.Errorf("planTTLIndexes() create = %v, want %v", gotCreate, tt.wantCreate)
+			}
+		})
+	}
+}
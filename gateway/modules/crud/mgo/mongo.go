@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/spaceuptech/helpers"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
@@ -16,6 +17,12 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
+// namespaceNotFoundCode is the error code returned by mongo when the indexes of a collection which doesn't exist are listed
+const namespaceNotFoundCode int32 = 26
+
+// ttlIndexPrefix is the prefix of the names of the ttl indexes created by space cloud
+const ttlIndexPrefix = "ttl_"
+
 // Mongo holds the mongo session
 type Mongo struct {
 	lock                sync.RWMutex
@@ -158,6 +165,72 @@ func (m *Mongo) SetQueryFetchLimit(limit int64) {
 func (m *Mongo) SetProjectAESKey(aesKey []byte) {
 }
 
+// SetSchema sets the schema of the database. Mongo maintains its own indexes, except for the 2dsphere indexes which are
+// required to query the fields having geo types and the ttl indexes which expire the documents of a collection
+func (m *Mongo) SetSchema(ctx context.Context, schema model.Collection) error {
+	client := m.getClient()
+	if client == nil {
+		return nil
+	}
+
+	for col, fields := range schema {
+		if err := m.setTTLIndexes(ctx, client, col, fields); err != nil {
+			return err
+		}
+
+		for _, field := range fields {
+			if field.Kind != model.TypePoint && field.Kind != model.TypeGeometry {
+				continue
+			}
+
+			// Creating an index which already exists is a no-op
+			index := mongo.IndexModel{Keys: bson.D{{Key: field.FieldName, Value: "2dsphere"}}}
+			if _, err := client.Database(m.dbName).Collection(col).Indexes().CreateOne(ctx, index); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to create 2dsphere index on field (%s) of collection (%s)", field.FieldName, col), err, nil)
+			}
+		}
+	}
+	return nil
+}
+
+// setTTLIndexes creates the ttl indexes which let mongo delete the expired documents of a collection. The ttl indexes of
+// fields which no longer expire are dropped and the indexes having a different expiry are recreated
+func (m *Mongo) setTTLIndexes(ctx context.Context, client *mongo.Client, col string, fields model.Fields) error {
+	indexes := client.Database(m.dbName).Collection(col).Indexes()
+
+	existing := make([]*mongoIndex, 0)
+	cur, err := indexes.List(ctx)
+	if cmdErr, ok := err.(mongo.CommandError); ok && cmdErr.Code == namespaceNotFoundCode {
+		err = nil
+	} else if err == nil {
+		err = cur.All(ctx, &existing)
+	}
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to list indexes of collection (%s)", col), err, nil)
+	}
+
+	drop, create, err := planTTLIndexes(col, fields, existing)
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set ttl indexes", err, nil)
+	}
+
+	for _, name := range drop {
+		if _, err := indexes.DropOne(ctx, name); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to drop index (%s) of collection (%s)", name, col), err, nil)
+		}
+	}
+	for _, field := range create {
+		index := mongo.IndexModel{
+			Keys:    bson.D{{Key: field.FieldName, Value: 1}},
+			Options: options.Index().SetName(ttlIndexPrefix + field.FieldName).SetExpireAfterSeconds(int32(*field.ExpireAfter)),
+		}
+		if _, err := indexes.CreateOne(ctx, index); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to create ttl index on field (%s) of collection (%s)", field.FieldName, col), err, nil)
+		}
+	}
+	return nil
+}
+
 func (m *Mongo) setClient(c *mongo.Client) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
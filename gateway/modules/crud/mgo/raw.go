@@ -2,23 +2,168 @@ package mgo
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/spaceuptech/helpers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 )
 
+// argumentRegex matches the placeholders in the prepared queries which are replaced by the arguments
+var argumentRegex = regexp.MustCompile(`^\$(\d+)$`)
+
 // RawBatch performs a batch operation for schema creation
 // NOTE: not to be exposed externally
 func (m *Mongo) RawBatch(ctx context.Context, queries []string) error {
 	return errors.New("raw batch operation cannot be performed on mongo")
 }
 
-// RawQuery query document(s) from the database
+// RawQuery query document(s) from the database. The query is a json encoded mongo prepared query containing either an
+// aggregation pipeline or a find filter.
 func (m *Mongo) RawQuery(ctx context.Context, query string, isDebug bool, args []interface{}) (int64, interface{}, *model.SQLMetaData, error) {
-	return 0, "", nil, errors.New("error raw query operation cannot be performed on mongo")
+	preparedQuery := new(config.MongoPreparedQuery)
+	if err := json.Unmarshal([]byte(query), preparedQuery); err != nil {
+		return 0, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to parse mongo prepared query", err, nil)
+	}
+	if preparedQuery.Collection == "" {
+		return 0, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Collection of mongo prepared query not provided", nil, nil)
+	}
+
+	collection := m.getClient().Database(m.dbName).Collection(preparedQuery.Collection)
+	metaData := &model.SQLMetaData{SQL: query, Args: args}
+
+	var cur *mongo.Cursor
+	start := time.Now()
+	switch {
+	case preparedQuery.Pipeline != "":
+		pipeline, err := parsePreparedQueryTemplate(preparedQuery.Pipeline, args, preparedQuery.AllowObjectArgs)
+		if err != nil {
+			return 0, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to parse pipeline of mongo prepared query", err, nil)
+		}
+		cur, err = collection.Aggregate(ctx, pipeline)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+
+	case preparedQuery.Find != "":
+		find, err := parsePreparedQueryTemplate(preparedQuery.Find, args, preparedQuery.AllowObjectArgs)
+		if err != nil {
+			return 0, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to parse find filter of mongo prepared query", err, nil)
+		}
+		cur, err = collection.Find(ctx, find)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+
+	default:
+		return 0, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Either pipeline or find must be provided in mongo prepared query", nil, nil)
+	}
+	defer func() { _ = cur.Close(ctx) }()
+	metaData.QueryTime = time.Since(start).String()
+
+	var count int64
+	results := []interface{}{}
+	for cur.Next(ctx) {
+		var doc map[string]interface{}
+		if err := cur.Decode(&doc); err != nil {
+			return 0, nil, nil, err
+		}
+		results = append(results, doc)
+		count++
+	}
+	if err := cur.Err(); err != nil {
+		return 0, nil, nil, err
+	}
+
+	return count, results, metaData, nil
+}
+
+// parsePreparedQueryTemplate parses the extended json template of a mongo prepared query and replaces the placeholders
+// ($1, $2 ...) with the corresponding arguments. Arguments which aren't scalars are rejected unless allowed since an
+// object like {"$ne": null} would change the meaning of the query.
+func parsePreparedQueryTemplate(template string, args []interface{}, allowObjectArgs bool) (interface{}, error) {
+	if !allowObjectArgs {
+		for i, arg := range args {
+			if !isScalarArg(arg) {
+				return nil, fmt.Errorf("argument ($%d) of type (%T) is not a scalar value", i+1, arg)
+			}
+		}
+	}
+
+	// Extended json can only be unmarshalled into a document. Hence the template is wrapped in one
+	wrapper := struct {
+		Value interface{} `bson:"value"`
+	}{}
+	if err := bson.UnmarshalExtJSON([]byte(fmt.Sprintf(`{"value": %s}`, template)), false, &wrapper); err != nil {
+		return nil, err
+	}
+	return replacePreparedQueryArgs(wrapper.Value, args)
+}
+
+func replacePreparedQueryArgs(value interface{}, args []interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		matches := argumentRegex.FindStringSubmatch(v)
+		if matches == nil {
+			return v, nil
+		}
+		index, _ := strconv.Atoi(matches[1])
+		if index < 1 || index > len(args) {
+			return nil, fmt.Errorf("argument (%s) not provided in prepared query", v)
+		}
+		return args[index-1], nil
+
+	case primitive.D:
+		for i, elem := range v {
+			newValue, err := replacePreparedQueryArgs(elem.Value, args)
+			if err != nil {
+				return nil, err
+			}
+			v[i].Value = newValue
+		}
+		return v, nil
+
+	case primitive.A:
+		for i, elem := range v {
+			newValue, err := replacePreparedQueryArgs(elem, args)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = newValue
+		}
+		return v, nil
+
+	case primitive.M:
+		for key, elem := range v {
+			newValue, err := replacePreparedQueryArgs(elem, args)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = newValue
+		}
+		return v, nil
+
+	default:
+		return v, nil
+	}
+}
+
+func isScalarArg(arg interface{}) bool {
+	switch arg.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64,
+		time.Time, primitive.DateTime, primitive.ObjectID, primitive.Decimal128:
+		return true
+	}
+	return false
 }
 
 // GetConnectionState : function to check connection state
@@ -0,0 +1,77 @@
+package mgo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func Test_parsePreparedQueryTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		args     []interface{}
+		allowObj bool
+		want     interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "find filter with arguments",
+			template: `{"author": "$1", "likes": {"$gt": "$2"}}`,
+			args:     []interface{}{"john", 10},
+			want:     primitive.D{{Key: "author", Value: "john"}, {Key: "likes", Value: primitive.D{{Key: "$gt", Value: 10}}}},
+		},
+		{
+			name:     "pipeline with arguments and field paths",
+			template: `[{"$match": {"author": "$1"}}, {"$group": {"_id": "$category", "count": {"$sum": 1}}}]`,
+			args:     []interface{}{"john"},
+			want: primitive.A{
+				primitive.D{{Key: "$match", Value: primitive.D{{Key: "author", Value: "john"}}}},
+				primitive.D{{Key: "$group", Value: primitive.D{{Key: "_id", Value: "$category"}, {Key: "count", Value: primitive.D{{Key: "$sum", Value: int32(1)}}}}}},
+			},
+		},
+		{
+			name:     "object argument injecting an operator",
+			template: `{"author": "$1", "password": "$2"}`,
+			args:     []interface{}{"john", map[string]interface{}{"$ne": nil}},
+			wantErr:  true,
+		},
+		{
+			name:     "array argument",
+			template: `{"author": {"$in": "$1"}}`,
+			args:     []interface{}{[]interface{}{"john", "jane"}},
+			wantErr:  true,
+		},
+		{
+			name:     "array argument allowed by the query",
+			template: `{"author": {"$in": "$1"}}`,
+			args:     []interface{}{[]interface{}{"john", "jane"}},
+			allowObj: true,
+			want:     primitive.D{{Key: "author", Value: primitive.D{{Key: "$in", Value: []interface{}{"john", "jane"}}}}},
+		},
+		{
+			name:     "argument not provided",
+			template: `{"author": "$2"}`,
+			args:     []interface{}{"john"},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid template",
+			template: `{"author": }`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePreparedQueryTemplate(tt.template, tt.args, tt.allowObj)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parsePreparedQueryTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePreparedQueryTemplate() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -24,11 +24,19 @@ func (m *Mongo) Read(ctx context.Context, col string, req *model.ReadRequest) (i
 	}
 	collection := m.getClient().Database(m.dbName).Collection(col)
 
-	req.Find = sanitizeWhereClause(ctx, col, req.Find)
-
 	if req.Options == nil {
 		req.Options = &model.ReadOptions{}
 	}
+
+	find, err := sanitizeSearchClause(sanitizeWhereClause(ctx, col, req.Find))
+	if err != nil {
+		return 0, nil, nil, nil, err
+	}
+	find, err = sanitizeGeoClause(find, req.Options.Sort)
+	if err != nil {
+		return 0, nil, nil, nil, err
+	}
+	req.Find = find
 	if req.Options.Limit == nil {
 		req.Options.Limit = m.queryFetchLimit
 		req.Options.HasOptions = true
@@ -69,9 +77,29 @@ func (m *Mongo) Read(ctx context.Context, col string, req *model.ReadRequest) (i
 	case utils.All:
 		findOptions := options.Find()
 
+		// Documents before a cursor are read in the reverse order and reversed back once read
+		find := req.Find
+		sortOrder := req.Options.Sort
+		cursor, isBefore, isCursor := utils.GetCursor(req.Options)
+		if isCursor {
+			if cursor != "" {
+				clause, err := utils.GenerateCursorClause(sortOrder, cursor, isBefore, utils.NullsSortFirst(string(model.Mongo)))
+				if err != nil {
+					return 0, nil, nil, nil, err
+				}
+				find = clause
+				if len(req.Find) > 0 {
+					find = map[string]interface{}{"$and": []interface{}{req.Find, clause}}
+				}
+			}
+			if isBefore {
+				sortOrder = utils.ReverseSort(sortOrder)
+			}
+		}
+
 		if req.Options != nil {
-			if req.Options.Select != nil {
-				findOptions = findOptions.SetProjection(req.Options.Select)
+			if req.Options.Select != nil || isSearchScoreSorted(sortOrder) {
+				findOptions = findOptions.SetProjection(generateProjection(req.Options.Select, sortOrder))
 			}
 
 			if req.Options.Skip != nil {
@@ -82,8 +110,8 @@ func (m *Mongo) Read(ctx context.Context, col string, req *model.ReadRequest) (i
 				findOptions = findOptions.SetLimit(*req.Options.Limit)
 			}
 
-			if req.Options.Sort != nil {
-				findOptions = findOptions.SetSort(generateSortOptions(req.Options.Sort))
+			if sortOrder != nil {
+				findOptions = findOptions.SetSort(generateSortOptions(sortOrder))
 			}
 		}
 
@@ -134,8 +162,8 @@ func (m *Mongo) Read(ctx context.Context, col string, req *model.ReadRequest) (i
 			helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Mongo aggregate", map[string]interface{}{"col": col, "pipeline": pipeline})
 			cur, err = collection.Aggregate(ctx, pipeline)
 		} else {
-			helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Mongo query", map[string]interface{}{"col": col, "find": req.Find, "options": findOptions})
-			cur, err = collection.Find(ctx, req.Find, findOptions)
+			helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Mongo query", map[string]interface{}{"col": col, "find": find, "options": findOptions})
+			cur, err = collection.Find(ctx, find, findOptions)
 		}
 		if err != nil {
 			return 0, nil, nil, nil, err
@@ -173,14 +201,18 @@ func (m *Mongo) Read(ctx context.Context, col string, req *model.ReadRequest) (i
 			return 0, nil, nil, nil, err
 		}
 
+		if isCursor && isBefore {
+			utils.ReverseArray(results)
+		}
+
 		return count, results, nil, nil, nil
 
 	case utils.One:
 		findOneOptions := options.FindOne()
 
 		if req.Options != nil {
-			if req.Options.Select != nil {
-				findOneOptions = findOneOptions.SetProjection(req.Options.Select)
+			if req.Options.Select != nil || isSearchScoreSorted(req.Options.Sort) {
+				findOneOptions = findOneOptions.SetProjection(generateProjection(req.Options.Select, req.Options.Sort))
 			}
 
 			if req.Options.Skip != nil {
@@ -224,10 +256,18 @@ func generateSortFields(sortColumn, currentColumn, newColumnName string) string
 	return ""
 }
 
+// generateSortOptions converts the sort order to the mongo sort document. Documents can only be sorted by the relevance
+// of a text search in the descending order. The distance isn't a part of the sort document since the $nearSphere operator
+// returns the documents sorted by their distance
 func generateSortOptions(array []string) bson.D {
 	sort := bson.D{}
 	for _, value := range array {
-		if strings.HasPrefix(value, "-") {
+		if value == utils.DistanceField {
+			continue
+		}
+		if strings.TrimPrefix(value, "-") == utils.SearchScoreField {
+			sort = append(sort, primitive.E{Key: utils.SearchScoreField, Value: bson.M{"$meta": "textScore"}})
+		} else if strings.HasPrefix(value, "-") {
 			sort = append(sort, primitive.E{Key: strings.TrimPrefix(value, "-"), Value: -1})
 		} else {
 			sort = append(sort, primitive.E{Key: value, Value: 1})
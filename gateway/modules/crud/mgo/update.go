@@ -12,14 +12,25 @@ import (
 // Update updates the document(s) which match the condition provided.
 func (m *Mongo) Update(ctx context.Context, col string, req *model.UpdateRequest) (int64, error) {
 	collection := m.getClient().Database(m.dbName).Collection(col)
-	req.Find = sanitizeWhereClause(ctx, col, req.Find)
+	find, err := sanitizeSearchClause(sanitizeWhereClause(ctx, col, req.Find))
+	if err != nil {
+		return 0, err
+	}
+	find, err = sanitizeGeoClause(find, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Find = find
 
 	switch req.Operation {
 	case utils.One:
-		_, err := collection.UpdateOne(ctx, req.Find, req.Update)
+		res, err := collection.UpdateOne(ctx, req.Find, req.Update)
 		if err != nil {
 			return 0, err
 		}
+		if req.IsVersioned && res.MatchedCount == 0 {
+			return 0, utils.ErrVersionConflict
+		}
 
 		return 1, nil
 
@@ -28,6 +39,9 @@ func (m *Mongo) Update(ctx context.Context, col string, req *model.UpdateRequest
 		if err != nil {
 			return 0, err
 		}
+		if req.IsVersioned && res.MatchedCount == 0 {
+			return 0, utils.ErrVersionConflict
+		}
 
 		return res.MatchedCount, nil
 
@@ -0,0 +1,46 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+)
+
+// SplitNestedCreate splits a create request having documents nested under linked fields into one create request per
+// table. The requests are returned in the order they need to be executed and the foreign keys of the nested documents
+// are filled with the keys of their parents. A single request is returned if no nested documents are present.
+func (m *Module) SplitNestedCreate(ctx context.Context, dbAlias, col string, req *model.CreateRequest) ([]*model.AllRequest, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	var docs []interface{}
+	switch v := req.Document.(type) {
+	case map[string]interface{}:
+		docs = []interface{}{v}
+	case []interface{}:
+		docs = v
+	default:
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid document type (%T) provided for create request", req.Document), nil, nil)
+	}
+
+	nested := &schemaHelpers.NestedCreate{GetSchema: func(dbAlias, col string) (model.Fields, bool) {
+		fields, p := m.schemaDoc[dbAlias][col]
+		return fields, p
+	}}
+	reqs, _, err := nested.SplitNestedDocs(ctx, dbAlias, col, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nested documents are inserted in the same transaction as their parent
+	for _, r := range reqs {
+		if r.DBAlias != dbAlias {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot insert nested documents of table (%s) as it belongs to another database (%s)", r.Col, r.DBAlias), nil, nil)
+		}
+	}
+	return reqs, nil
+}
@@ -0,0 +1,128 @@
+package crud
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func TestModule_SplitNestedCreate(t *testing.T) {
+	schemaDoc := model.Type{
+		"db": model.Collection{
+			"authors": model.Fields{
+				"id":         &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true, IsFieldTypeRequired: true},
+				"profile_id": &model.FieldType{FieldName: "profile_id", Kind: model.TypeInteger},
+				"books":      &model.FieldType{FieldName: "books", Kind: "books", IsList: true, IsLinked: true, LinkedTable: &model.TableProperties{Table: "books", From: "id", To: "author_id", DBType: "db"}},
+				"profile":    &model.FieldType{FieldName: "profile", Kind: "profiles", IsLinked: true, LinkedTable: &model.TableProperties{Table: "profiles", From: "profile_id", To: "id", DBType: "db"}},
+				"reviews":    &model.FieldType{FieldName: "reviews", Kind: "reviews", IsList: true, IsLinked: true, LinkedTable: &model.TableProperties{Table: "reviews", From: "id", To: "author_id", DBType: "other"}},
+			},
+			"books": model.Fields{
+				"id":        &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true},
+				"author_id": &model.FieldType{FieldName: "author_id", Kind: model.TypeID},
+			},
+			"profiles": model.Fields{
+				"id": &model.FieldType{FieldName: "id", Kind: model.TypeInteger, IsPrimary: true},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		col     string
+		req     *model.CreateRequest
+		want    []*model.AllRequest
+		wantErr bool
+	}{
+		{
+			name: "document without nested documents",
+			col:  "authors",
+			req:  &model.CreateRequest{Operation: utils.One, Document: map[string]interface{}{"id": "a1"}},
+			want: []*model.AllRequest{
+				{Type: string(model.Create), Col: "authors", Operation: utils.All, DBAlias: "db", Document: []interface{}{map[string]interface{}{"id": "a1"}}},
+			},
+		},
+		{
+			name: "children are inserted after the parent",
+			col:  "authors",
+			req: &model.CreateRequest{Operation: utils.All, Document: []interface{}{
+				map[string]interface{}{"id": "a1", "books": []interface{}{map[string]interface{}{"id": "b1"}, map[string]interface{}{"id": "b2"}}},
+			}},
+			want: []*model.AllRequest{
+				{Type: string(model.Create), Col: "authors", Operation: utils.All, DBAlias: "db", Document: []interface{}{map[string]interface{}{"id": "a1"}}},
+				{Type: string(model.Create), Col: "books", Operation: utils.All, DBAlias: "db", Document: []interface{}{map[string]interface{}{"id": "b1", "author_id": "a1"}, map[string]interface{}{"id": "b2", "author_id": "a1"}}},
+			},
+		},
+		{
+			name: "referenced document is inserted before the parent",
+			col:  "authors",
+			req:  &model.CreateRequest{Operation: utils.One, Document: map[string]interface{}{"id": "a1", "profile": map[string]interface{}{"id": 1}}},
+			want: []*model.AllRequest{
+				{Type: string(model.Create), Col: "profiles", Operation: utils.All, DBAlias: "db", Document: []interface{}{map[string]interface{}{"id": 1}}},
+				{Type: string(model.Create), Col: "authors", Operation: utils.All, DBAlias: "db", Document: []interface{}{map[string]interface{}{"id": "a1", "profile_id": 1}}},
+			},
+		},
+		{
+			name:    "referenced document without a key",
+			col:     "authors",
+			req:     &model.CreateRequest{Operation: utils.One, Document: map[string]interface{}{"id": "a1", "profile": map[string]interface{}{}}},
+			wantErr: true,
+		},
+		{
+			name:    "nested documents of another database",
+			col:     "authors",
+			req:     &model.CreateRequest{Operation: utils.One, Document: map[string]interface{}{"id": "a1", "reviews": []interface{}{map[string]interface{}{}}}},
+			wantErr: true,
+		},
+		{
+			name:    "object provided for a list",
+			col:     "authors",
+			req:     &model.CreateRequest{Operation: utils.One, Document: map[string]interface{}{"id": "a1", "books": map[string]interface{}{}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Module{schemaDoc: schemaDoc}
+			got, err := m.SplitNestedCreate(context.Background(), "db", tt.col, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SplitNestedCreate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitNestedCreate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModule_SplitNestedCreate_GeneratesParentKey(t *testing.T) {
+	m := &Module{schemaDoc: model.Type{
+		"db": model.Collection{
+			"authors": model.Fields{
+				"id":    &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true, IsFieldTypeRequired: true},
+				"books": &model.FieldType{FieldName: "books", Kind: "books", IsList: true, IsLinked: true, LinkedTable: &model.TableProperties{Table: "books", From: "id", To: "author_id", DBType: "db"}},
+			},
+			"books": model.Fields{
+				"author_id": &model.FieldType{FieldName: "author_id", Kind: model.TypeID},
+			},
+		},
+	}}
+
+	author := map[string]interface{}{"books": []interface{}{map[string]interface{}{}}}
+	got, err := m.SplitNestedCreate(context.Background(), "db", "authors", &model.CreateRequest{Operation: utils.One, Document: author})
+	if err != nil {
+		t.Fatalf("SplitNestedCreate() error = %v", err)
+	}
+
+	id, ok := author["id"].(string)
+	if !ok || id == "" {
+		t.Fatalf("SplitNestedCreate() did not generate the primary key of the parent")
+	}
+	book := got[1].Document.([]interface{})[0].(map[string]interface{})
+	if book["author_id"] != id {
+		t.Errorf("SplitNestedCreate() got author_id = %v, want %v", book["author_id"], id)
+	}
+}
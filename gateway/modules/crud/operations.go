@@ -2,10 +2,13 @@ package crud
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/spaceuptech/helpers"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
@@ -54,9 +57,10 @@ func (m *Module) Create(ctx context.Context, dbAlias, col string, req *model.Cre
 		n, err = crud.Create(ctx, col, req)
 	}
 
-	// Invoke the metric hook if the operation was successful
+	// Invoke the metric hook and record the history if the operation was successful
 	if err == nil {
 		m.metricHook(m.project, dbAlias, col, n, model.Create)
+		err = m.recordHistory(ctx, crud, dbAlias, params, m.trackCreate(dbAlias, col, req.Document))
 	}
 
 	return err
@@ -75,11 +79,16 @@ func (m *Module) Read(ctx context.Context, dbAlias, col string, req *model.ReadR
 	if err := schemaHelpers.AdjustWhereClause(ctx, dbAlias, model.DBType(dbType), col, m.schemaDoc, req.Find); err != nil {
 		return nil, nil, err
 	}
+	m.filterSoftDeleted(dbAlias, col, req)
+	if err := m.prepareCursorPagination(ctx, dbAlias, dbType, col, req); err != nil {
+		return nil, nil, err
+	}
 
-	crud, err := m.getCrudBlock(dbAlias)
+	crud, done, err := m.getReadCrudBlock(ctx, dbAlias, req.Options != nil && req.Options.ReadYourWrites)
 	if err != nil {
 		return nil, nil, err
 	}
+	defer done()
 
 	if err := crud.IsClientSafe(ctx); err != nil {
 		return nil, nil, err
@@ -164,6 +173,90 @@ func (m *Module) Read(ctx context.Context, dbAlias, col string, req *model.ReadR
 	return result, metaData, err
 }
 
+// Export streams all the documents matching the read request to the callback. Unlike Read, the query fetch limit is not
+// applied and the documents are neither cached nor batched.
+func (m *Module) Export(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams, cb model.ExportCallback) error {
+	crud, dbType, schemaDoc, done, err := m.prepareExport(ctx, dbAlias, col, req)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	params.Payload = req
+	hookResponse := m.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return err
+		}
+
+		// Stream the result of the hook
+		docs, ok := hookResponse.Result().([]interface{})
+		if !ok {
+			return nil
+		}
+		for _, doc := range docs {
+			if obj, ok := doc.(map[string]interface{}); ok {
+				if err := cb(obj); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	n, err := crud.Export(ctx, col, req, func(doc map[string]interface{}) error {
+		if err := schemaHelpers.CrudPostProcess(ctx, dbAlias, dbType, col, schemaDoc, doc); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error executing export request in crud module unable to perform schema post process for un marshalling json for project (%s) col (%s)", m.project, col), err, nil)
+		}
+		return cb(doc)
+	})
+	m.metricHook(m.project, dbAlias, col, n, model.Read)
+	return err
+}
+
+// prepareExport returns the block which should serve an export. The lock on the module is only held while preparing
+// the request so that a long running export doesn't block config changes.
+func (m *Module) prepareExport(ctx context.Context, dbAlias, col string, req *model.ReadRequest) (Crud, string, model.Type, func(), error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if req.Operation != utils.All {
+		return nil, "", nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid operation (%s) provided for export", req.Operation), nil, nil)
+	}
+	if len(req.Aggregate) > 0 || len(req.GroupBy) > 0 {
+		return nil, "", nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Aggregations cannot be exported", nil, nil)
+	}
+	if req.Options != nil {
+		if len(req.Options.Join) > 0 {
+			return nil, "", nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Joins cannot be exported", nil, nil)
+		}
+		if _, _, isCursor := utils.GetCursor(req.Options); isCursor {
+			return nil, "", nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Cursor pagination cannot be used while exporting", nil, nil)
+		}
+	}
+
+	dbType, err := m.getDBType(dbAlias)
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+	if err := schemaHelpers.AdjustWhereClause(ctx, dbAlias, model.DBType(dbType), col, m.schemaDoc, req.Find); err != nil {
+		return nil, "", nil, nil, err
+	}
+	m.filterSoftDeleted(dbAlias, col, req)
+
+	crud, done, err := m.getReadCrudBlock(ctx, dbAlias, req.Options != nil && req.Options.ReadYourWrites)
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+
+	if err := crud.IsClientSafe(ctx); err != nil {
+		done()
+		return nil, "", nil, nil, err
+	}
+	return crud, dbType, m.schemaDoc, done, nil
+}
+
 // Update updates the documents(s) which match a query from the database based on dbType
 func (m *Module) Update(ctx context.Context, dbAlias, col string, req *model.UpdateRequest, params model.RequestParams) error {
 	m.RLock()
@@ -177,6 +270,14 @@ func (m *Module) Update(ctx context.Context, dbAlias, col string, req *model.Upd
 		return err
 	}
 
+	// Internal callers mark an update as versioned when it must be rejected if no documents match the where clause
+	isVersioned := req.IsVersioned
+	req.Find, req.IsVersioned, err = schemaHelpers.PrepareVersionedUpdate(ctx, dbAlias, col, req.Operation, req.Update, req.Find, m.schemaDoc)
+	if err != nil {
+		return err
+	}
+	req.IsVersioned = req.IsVersioned || isVersioned
+
 	params.Payload = req
 	hookResponse := m.integrationMan.InvokeHook(ctx, params)
 	if hookResponse.CheckResponse() {
@@ -203,12 +304,18 @@ func (m *Module) Update(ctx context.Context, dbAlias, col string, req *model.Upd
 		return err
 	}
 
+	entry, err := m.trackChange(ctx, crud, dbAlias, col, model.Update, req.Operation, req.Find)
+	if err != nil {
+		return err
+	}
+
 	// Perform the update operation
 	n, err := crud.Update(ctx, col, req)
 
-	// Invoke the metric hook if the operation was successful
+	// Invoke the metric hook and record the history if the operation was successful
 	if err == nil {
 		m.metricHook(m.project, dbAlias, col, n, model.Update)
+		err = m.recordHistory(ctx, crud, dbAlias, params, entry)
 	}
 
 	return err
@@ -249,12 +356,82 @@ func (m *Module) Delete(ctx context.Context, dbAlias, col string, req *model.Del
 		return nil
 	}
 
-	// Perform the delete operation
-	n, err := crud.Delete(ctx, col, req)
+	// Perform the delete operation. Tables with a soft delete field only get their documents marked as deleted
+	var n int64
+	var entry *historyEntry
+	if updateReq, ok := m.prepareSoftDelete(dbAlias, dbType, col, req.Operation, req.Find); ok {
+		if entry, err = m.trackChange(ctx, crud, dbAlias, col, model.Delete, updateReq.Operation, updateReq.Find); err != nil {
+			return err
+		}
+		n, err = crud.Update(ctx, col, updateReq)
+	} else {
+		if entry, err = m.trackChange(ctx, crud, dbAlias, col, model.Delete, req.Operation, req.Find); err != nil {
+			return err
+		}
+		n, err = crud.Delete(ctx, col, req)
+	}
 
-	// Invoke the metric hook if the operation was successful
+	// Invoke the metric hook and record the history if the operation was successful
 	if err == nil {
 		m.metricHook(m.project, dbAlias, col, n, model.Delete)
+		err = m.recordHistory(ctx, crud, dbAlias, params, entry)
+	}
+
+	return err
+}
+
+// Restore unmarks the soft deleted documents(s) which match a query
+func (m *Module) Restore(ctx context.Context, dbAlias, col string, req *model.RestoreRequest, params model.RequestParams) error {
+	m.RLock()
+	defer m.RUnlock()
+
+	crud, err := m.getCrudBlock(dbAlias)
+	if err != nil {
+		return err
+	}
+
+	if err := crud.IsClientSafe(ctx); err != nil {
+		return err
+	}
+
+	// Adjust where clause
+	dbType, err := m.getDBType(dbAlias)
+	if err != nil {
+		return err
+	}
+	if err := schemaHelpers.AdjustWhereClause(ctx, dbAlias, model.DBType(dbType), col, m.schemaDoc, req.Find); err != nil {
+		return err
+	}
+
+	updateReq, err := m.prepareRestore(ctx, dbAlias, dbType, col, req.Operation, req.Find)
+	if err != nil {
+		return err
+	}
+
+	params.Payload = req
+	hookResponse := m.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return err
+		}
+
+		// Gracefully return
+		return nil
+	}
+
+	entry, err := m.trackChange(ctx, crud, dbAlias, col, model.Restore, updateReq.Operation, updateReq.Find)
+	if err != nil {
+		return err
+	}
+
+	// Perform the restore operation
+	n, err := crud.Update(ctx, col, updateReq)
+
+	// Invoke the metric hook and record the history if the operation was successful
+	if err == nil {
+		m.metricHook(m.project, dbAlias, col, n, model.Update)
+		err = m.recordHistory(ctx, crud, dbAlias, params, entry)
 	}
 
 	return err
@@ -277,21 +454,23 @@ func (m *Module) ExecPreparedQuery(ctx context.Context, dbAlias, id string, req
 		return hookResponse.Result(), nil, nil
 	}
 
-	crud, err := m.getCrudBlock(dbAlias)
+	// Check if prepared query exists
+	preparedQuery, p := m.queries[getPreparedQueryKey(dbAlias, id)]
+	if !p {
+		return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Prepared Query for given id (%s) does not exist", id), nil, nil)
+	}
+
+	// Only the prepared queries which read data can be served by a read replica
+	crud, done, err := m.getReadCrudBlock(ctx, dbAlias, req.ReadYourWrites || !isReadQuery(preparedQuery))
 	if err != nil {
 		return nil, nil, err
 	}
+	defer done()
 
 	if err := crud.IsClientSafe(ctx); err != nil {
 		return nil, nil, err
 	}
 
-	// Check if prepared query exists
-	preparedQuery, p := m.queries[getPreparedQueryKey(dbAlias, id)]
-	if !p {
-		return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Prepared Query for given id (%s) does not exist", id), nil, nil)
-	}
-
 	// Load the arguments
 	var args []interface{}
 	for i := 0; i < len(preparedQuery.Arguments); i++ {
@@ -302,8 +481,18 @@ func (m *Module) ExecPreparedQuery(ctx context.Context, dbAlias, id string, req
 		args = append(args, arg)
 	}
 
+	// The query of mongo prepared queries is passed to the database as json
+	query := preparedQuery.SQL
+	if preparedQuery.Mongo != nil {
+		data, err := json.Marshal(preparedQuery.Mongo)
+		if err != nil {
+			return nil, nil, err
+		}
+		query = string(data)
+	}
+
 	// Fire the query and return the result
-	_, b, metaData, err := crud.RawQuery(ctx, preparedQuery.SQL, req.Debug, args)
+	_, b, metaData, err := crud.RawQuery(ctx, query, req.Debug, args)
 	if metaData != nil {
 		metaData.DbAlias = dbAlias
 		metaData.Col = id
@@ -316,6 +505,8 @@ func (m *Module) Aggregate(ctx context.Context, dbAlias, col string, req *model.
 	m.RLock()
 	defer m.RUnlock()
 
+	m.filterSoftDeletedPipeline(dbAlias, col, req)
+
 	params.Payload = req
 	hookResponse := m.integrationMan.InvokeHook(ctx, params)
 	if hookResponse.CheckResponse() {
@@ -328,10 +519,11 @@ func (m *Module) Aggregate(ctx context.Context, dbAlias, col string, req *model.
 		return hookResponse.Result(), nil
 	}
 
-	crud, err := m.getCrudBlock(dbAlias)
+	crud, done, err := m.getReadCrudBlock(ctx, dbAlias, req.ReadYourWrites)
 	if err != nil {
 		return nil, err
 	}
+	defer done()
 
 	if err := crud.IsClientSafe(ctx); err != nil {
 		return nil, err
@@ -354,7 +546,10 @@ func (m *Module) Batch(ctx context.Context, dbAlias string, req *model.BatchRequ
 	if err != nil {
 		return err
 	}
-	for _, r := range req.Requests {
+	// The types of the requests are needed to record the history since soft deletes & restores get converted to updates
+	operations := make([]model.OperationType, len(req.Requests))
+	for i, r := range req.Requests {
+		operations[i] = model.OperationType(r.Type)
 		switch r.Type {
 		case string(model.Create):
 			v := &model.CreateRequest{Document: r.Document, Operation: r.Operation}
@@ -367,6 +562,20 @@ func (m *Module) Batch(ctx context.Context, dbAlias string, req *model.BatchRequ
 			if err := schemaHelpers.ValidateUpdateOperation(ctx, dbAlias, dbType, r.Col, r.Operation, r.Update, r.Find, m.schemaDoc); err != nil {
 				return err
 			}
+			r.Find, r.IsVersioned, err = schemaHelpers.PrepareVersionedUpdate(ctx, dbAlias, r.Col, r.Operation, r.Update, r.Find, m.schemaDoc)
+			if err != nil {
+				return err
+			}
+		case string(model.Delete):
+			if updateReq, ok := m.prepareSoftDelete(dbAlias, dbType, r.Col, r.Operation, r.Find); ok {
+				r.Type, r.Find, r.Operation, r.Update = string(model.Update), updateReq.Find, updateReq.Operation, updateReq.Update
+			}
+		case string(model.Restore):
+			updateReq, err := m.prepareRestore(ctx, dbAlias, dbType, r.Col, r.Operation, r.Find)
+			if err != nil {
+				return err
+			}
+			r.Type, r.Find, r.Operation, r.Update = string(model.Update), updateReq.Find, updateReq.Operation, updateReq.Update
 		}
 	}
 
@@ -386,14 +595,27 @@ func (m *Module) Batch(ctx context.Context, dbAlias string, req *model.BatchRequ
 		return err
 	}
 
+	entries := make([]*historyEntry, len(req.Requests))
+	for i, r := range req.Requests {
+		switch r.Type {
+		case string(model.Create):
+			entries[i] = m.trackCreate(dbAlias, r.Col, r.Document)
+		case string(model.Update), string(model.Delete):
+			if entries[i], err = m.trackChange(ctx, crud, dbAlias, r.Col, operations[i], r.Operation, r.Find); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Perform the batch operation
 	counts, err := crud.Batch(ctx, req)
 
-	// Invoke the metric hook if the operation was successful
+	// Invoke the metric hook and record the history if the operation was successful
 	if err == nil {
 		for i, r := range req.Requests {
 			m.metricHook(m.project, dbAlias, r.Col, counts[i], model.OperationType(r.Type))
 		}
+		err = m.recordHistory(ctx, crud, dbAlias, params, entries...)
 	}
 
 	return err
@@ -492,6 +714,20 @@ func (m *Module) IsPreparedQueryPresent(dbAlias, id string) bool {
 	return p
 }
 
+// GetPreparedQueries returns the prepared queries of all the databases
+func (m *Module) GetPreparedQueries() config.DatabasePreparedQueries {
+	m.RLock()
+	defer m.RUnlock()
+
+	queries := make(config.DatabasePreparedQueries, len(m.queries))
+	for key, query := range m.queries {
+		q := *query
+		q.DbAlias = strings.TrimPrefix(q.DbAlias, "sql-")
+		queries[key] = &q
+	}
+	return queries
+}
+
 // GetSchema function gets schema
 func (m *Module) GetSchema(dbAlias, col string) (model.Fields, bool) {
 	m.RLock()
@@ -0,0 +1,205 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud/mgo"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud/sql"
+)
+
+// replicaSet holds the connections to the read replicas of a database
+type replicaSet struct {
+	strategy config.ReplicaStrategy
+	blocks   []Crud
+
+	// next is the counter used by the round robin strategy
+	next uint64
+	// inFlight stores the number of requests being served by each replica
+	inFlight []int64
+}
+
+func newReplicaSet(strategy config.ReplicaStrategy, blocks []Crud) *replicaSet {
+	if strategy == "" {
+		strategy = config.ReplicaStrategyRoundRobin
+	}
+	return &replicaSet{strategy: strategy, blocks: blocks, inFlight: make([]int64, len(blocks))}
+}
+
+// pick returns the index of the replica which should serve the next request
+func (r *replicaSet) pick() int {
+	switch r.strategy {
+	case config.ReplicaStrategyLeastConnections:
+		index := 0
+		for i := range r.inFlight {
+			if atomic.LoadInt64(&r.inFlight[i]) < atomic.LoadInt64(&r.inFlight[index]) {
+				index = i
+			}
+		}
+		return index
+	default:
+		return int((atomic.AddUint64(&r.next, 1) - 1) % uint64(len(r.blocks)))
+	}
+}
+
+// acquire returns a replica along with the function to be called once the request has been served
+func (r *replicaSet) acquire() (Crud, func()) {
+	index := r.pick()
+	atomic.AddInt64(&r.inFlight[index], 1)
+	return r.blocks[index], func() { atomic.AddInt64(&r.inFlight[index], -1) }
+}
+
+// isSame checks if the replica set has been initialised with the provided config
+func (r *replicaSet) isSame(strategy config.ReplicaStrategy, connections []string, dbName string, driverConf config.DriverConfig) bool {
+	if strategy == "" {
+		strategy = config.ReplicaStrategyRoundRobin
+	}
+	if r.strategy != strategy || len(r.blocks) != len(connections) {
+		return false
+	}
+	for i, block := range r.blocks {
+		if !block.IsSame(connections[i], dbName, driverConf) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *replicaSet) close() {
+	for _, block := range r.blocks {
+		if err := block.Close(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to close read replica connection", err, nil)
+		}
+	}
+}
+
+// initReplicaBlock connects to a read replica. Unlike the primary, the database is never created on a replica.
+func initReplicaBlock(dbType model.DBType, enabled bool, connection, dbName string, driverConf config.DriverConfig) (Crud, error) {
+	switch dbType {
+	case model.Mongo:
+		return mgo.Init(enabled, connection, dbName, driverConf)
+	case model.MySQL:
+		return sql.Init(dbType, enabled, fmt.Sprintf("%s%s", connection, dbName), dbName, driverConf)
+	case model.Postgres, model.SQLServer:
+		return sql.Init(dbType, enabled, connection, dbName, driverConf)
+	default:
+		return nil, fmt.Errorf("read replicas are not supported for database (%s)", dbType)
+	}
+}
+
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) setReplicas(project, blockKey string, dbConfig *config.DatabaseConfig) error {
+	replicas, p := m.replicas[blockKey]
+	if len(dbConfig.Replicas) == 0 {
+		if p {
+			replicas.close()
+			delete(m.replicas, blockKey)
+		}
+		return nil
+	}
+
+	switch dbConfig.ReplicaStrategy {
+	case "", config.ReplicaStrategyRoundRobin, config.ReplicaStrategyLeastConnections:
+	default:
+		return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Invalid replica strategy (%s) provided for database (%s)", dbConfig.ReplicaStrategy, dbConfig.DbAlias), nil, nil)
+	}
+
+	// Load the connection strings stored in secrets
+	connections := make([]string, len(dbConfig.Replicas))
+	for i, conn := range dbConfig.Replicas {
+		connections[i] = conn
+		if secretName, isSecretExists := splitConnectionString(conn); isSecretExists {
+			var err error
+			connections[i], err = m.getSecrets(project, secretName, "CONN")
+			if err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to fetch replica connection string secret from runner", err, map[string]interface{}{"project": project})
+			}
+		}
+	}
+
+	if p {
+		for _, block := range replicas.blocks {
+			block.SetQueryFetchLimit(dbConfig.Limit)
+		}
+
+		// Skip if the replicas are the same
+		if replicas.isSame(dbConfig.ReplicaStrategy, connections, dbConfig.DBName, dbConfig.DriverConf) {
+			return nil
+		}
+		replicas.close()
+		delete(m.replicas, blockKey)
+	}
+
+	blocks := make([]Crud, len(connections))
+	for i, conn := range connections {
+		block, err := initReplicaBlock(model.DBType(strings.TrimPrefix(dbConfig.Type, "sql-")), dbConfig.Enabled, conn, dbConfig.DBName, dbConfig.DriverConf)
+		if err != nil {
+			for _, b := range blocks[:i] {
+				_ = b.Close()
+			}
+			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Cannot connect to read replica", err, map[string]interface{}{"project": project, "dbAlias": dbConfig.DbAlias, "replica": i})
+		}
+		block.SetQueryFetchLimit(dbConfig.Limit)
+		blocks[i] = block
+	}
+	m.replicas[blockKey] = newReplicaSet(dbConfig.ReplicaStrategy, blocks)
+	return nil
+}
+
+// getReadCrudBlock returns the block which should serve a read request. Reads are sent to the read replicas unless the
+// request opts in for read your writes consistency. The primary is used if the chosen replica is not reachable.
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) getReadCrudBlock(ctx context.Context, dbAlias string, readYourWrites bool) (Crud, func(), error) {
+	block, err := m.getCrudBlock(dbAlias)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replicas, p := m.replicas[dbAlias]
+	if !p || readYourWrites {
+		return block, func() {}, nil
+	}
+
+	replica, done := replicas.acquire()
+	if err := replica.IsClientSafe(ctx); err != nil {
+		done()
+		helpers.Logger.LogWarn(helpers.GetRequestID(ctx), "Read replica is not reachable. Falling back to the primary database", map[string]interface{}{"dbAlias": dbAlias, "error": err.Error()})
+		return block, func() {}, nil
+	}
+	return replica, done, nil
+}
+
+// GetReplicasConnectionState gets the current state of the read replicas of a database
+func (m *Module) GetReplicasConnectionState(ctx context.Context, dbAlias string) []bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	replicas, p := m.replicas[dbAlias]
+	if !p {
+		return []bool{}
+	}
+
+	states := make([]bool, len(replicas.blocks))
+	for i, block := range replicas.blocks {
+		states[i] = block.IsClientSafe(ctx) == nil && block.GetConnectionState(ctx)
+	}
+	return states
+}
+
+// isReadQuery checks if a prepared query only reads data and hence can be served by a read replica
+func isReadQuery(preparedQuery *config.DatbasePreparedQuery) bool {
+	if preparedQuery.Mongo != nil {
+		// Aggregation pipelines can write their result to a collection
+		pipeline := preparedQuery.Mongo.Pipeline
+		return !strings.Contains(pipeline, `"$out"`) && !strings.Contains(pipeline, `"$merge"`)
+	}
+
+	query := strings.ToLower(strings.TrimSpace(preparedQuery.SQL))
+	return strings.HasPrefix(query, "select")
+}
@@ -0,0 +1,71 @@
+package crud
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func Test_replicaSet_pick(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy config.ReplicaStrategy
+		inFlight []int64
+		want     []int
+	}{
+		{
+			name:     "round robin",
+			strategy: config.ReplicaStrategyRoundRobin,
+			inFlight: []int64{0, 0, 0},
+			want:     []int{0, 1, 2, 0},
+		},
+		{
+			name:     "default strategy is round robin",
+			inFlight: []int64{5, 0},
+			want:     []int{0, 1, 0},
+		},
+		{
+			name:     "least connections",
+			strategy: config.ReplicaStrategyLeastConnections,
+			inFlight: []int64{3, 1, 2},
+			want:     []int{1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReplicaSet(tt.strategy, make([]Crud, len(tt.inFlight)))
+			copy(r.inFlight, tt.inFlight)
+
+			got := make([]int, len(tt.want))
+			for i := range got {
+				got[i] = r.pick()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pick() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isReadQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *config.DatbasePreparedQuery
+		want  bool
+	}{
+		{name: "select query", query: &config.DatbasePreparedQuery{SQL: "  SELECT * FROM users WHERE id = $1"}, want: true},
+		{name: "update query", query: &config.DatbasePreparedQuery{SQL: "UPDATE users SET name = $1"}, want: false},
+		{name: "insert with select", query: &config.DatbasePreparedQuery{SQL: "INSERT INTO archive SELECT * FROM users"}, want: false},
+		{name: "mongo find", query: &config.DatbasePreparedQuery{Mongo: &config.MongoPreparedQuery{Collection: "users", Find: `{"age": {"$gt": "$1"}}`}}, want: true},
+		{name: "mongo aggregation", query: &config.DatbasePreparedQuery{Mongo: &config.MongoPreparedQuery{Collection: "users", Pipeline: `[{"$match": {"age": "$1"}}]`}}, want: true},
+		{name: "mongo aggregation with out stage", query: &config.DatbasePreparedQuery{Mongo: &config.MongoPreparedQuery{Collection: "users", Pipeline: `[{"$match": {"age": "$1"}}, {"$out": "adults"}]`}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isReadQuery(tt.query); got != tt.want {
+				t.Errorf("isReadQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,6 +25,12 @@ func (m *Module) SetConfig(project string, crud config.DatabaseConfigs) error {
 
 	m.project = project
 
+	// Start the routine which deletes the expired rows of databases which don't expire them on their own
+	if m.sweepCloseC == nil {
+		m.sweepCloseC = make(chan struct{})
+		go m.routineSweepExpiredRows(m.sweepCloseC)
+	}
+
 	for dbAlias, v := range m.blocks {
 		doesExist := false
 		for _, databaseConfig := range crud {
@@ -38,6 +44,10 @@ func (m *Module) SetConfig(project string, crud config.DatabaseConfigs) error {
 			// Database that has been removed, close the db connections to free connection pool
 			_ = v.Close()
 			delete(m.blocks, dbAlias)
+			if replicas, p := m.replicas[dbAlias]; p {
+				replicas.close()
+				delete(m.replicas, dbAlias)
+			}
 		}
 	}
 
@@ -73,6 +83,10 @@ func (m *Module) SetConfig(project string, crud config.DatabaseConfigs) error {
 			}
 		}
 
+		if err := m.setReplicas(project, blockKey, v); err != nil {
+			return err
+		}
+
 		if block, p := m.blocks[blockKey]; p {
 
 			block.SetQueryFetchLimit(v.Limit)
@@ -105,6 +119,11 @@ func (m *Module) SetConfig(project string, crud config.DatabaseConfigs) error {
 		m.databaseConfigs[blockKey] = v
 		m.blocks[blockKey] = c
 		c.SetQueryFetchLimit(v.Limit)
+		if v.Enabled {
+			if err := c.SetSchema(context.TODO(), m.schemaDoc[blockKey]); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to set schema of database", err, map[string]interface{}{"project": project, "dbAlias": v.DbAlias})
+			}
+		}
 	}
 
 	return nil
@@ -139,6 +158,12 @@ func (m *Module) SetSchemaConfig(ctx context.Context, schemaDoc model.Type, sche
 	}
 
 	m.schemaDoc = schemaDoc
+	for dbAlias, block := range m.blocks {
+		if err := block.SetSchema(ctx, schemaDoc[dbAlias]); err != nil {
+			// Queries are still served without the indexes which couldn't be built
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set schema of database", err, map[string]interface{}{"dbAlias": dbAlias})
+		}
+	}
 
 	m.closeBatchOperation()
 	if err := m.initBatchOperation(m.project, schemas); err != nil {
@@ -147,6 +172,14 @@ func (m *Module) SetSchemaConfig(ctx context.Context, schemaDoc model.Type, sche
 	return nil
 }
 
+// SetDatabaseRules sets the database rules of the crud module
+func (m *Module) SetDatabaseRules(dbRules config.DatabaseRules) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.dbRules = dbRules
+}
+
 // SetGetSecrets sets the GetSecrets function
 func (m *Module) SetGetSecrets(function utils.GetSecrets) {
 	m.Lock()
@@ -173,6 +206,11 @@ func (m *Module) SetProjectAESKey(aesKey string) error {
 	for _, block := range m.blocks {
 		block.SetProjectAESKey(decodedAESKey)
 	}
+	for _, replicas := range m.replicas {
+		for _, block := range replicas.blocks {
+			block.SetProjectAESKey(decodedAESKey)
+		}
+	}
 
 	return nil
 }
@@ -191,3 +229,8 @@ func (m *Module) SetIntegrationManager(i integrationManagerInterface) {
 func (m *Module) SetCachingModule(c cachingInterface) {
 	m.caching = c
 }
+
+// SetEventingModule sets the eventing module
+func (m *Module) SetEventingModule(e eventingInterface) {
+	m.eventing = e
+}
@@ -2,12 +2,556 @@ package sql
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/doug-martin/goqu/v8/exp"
+	"github.com/spaceuptech/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
-// Aggregate performs a mongo db pipeline aggregation
+// aggregateIDPrefix is used to alias the columns of a composite group key
+const aggregateIDPrefix = "_id__"
+
+// aggregateState keeps track of the sql query being generated while compiling a pipeline
+type aggregateState struct {
+	col     string
+	dialect goqu.DialectWrapper
+	query   *goqu.SelectDataset
+
+	// columns holds the output columns of the last $group / $project stage.
+	// It is nil when the query selects all columns of the table
+	columns []string
+
+	hasOrder    bool
+	hasPaginate bool
+	isGrouped   bool
+	limit       *int64
+	skip        *int64
+	sort        []string
+	regexArr    []string
+	wrapCounter int
+}
+
+// Aggregate performs a mongo style aggregation pipeline by compiling it to sql. Only the
+// $match, $group, $sort, $project, $limit & $skip stages are supported
 func (s *SQL) Aggregate(ctx context.Context, col string, req *model.AggregateRequest) (interface{}, error) {
-	return nil, errors.New("Aggregation is not supported for SQL databases")
+	if req.Operation != utils.One && req.Operation != utils.All {
+		return nil, utils.ErrInvalidParams
+	}
+
+	sqlString, args, err := s.generateAggregateQuery(ctx, col, req)
+	if err != nil {
+		return nil, err
+	}
+
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Executing sql aggregate query", map[string]interface{}{"sqlQuery": sqlString, "queryArgs": args})
+
+	_, result, _, _, err := s.readExec(ctx, col, sqlString, args, s.getClient(), &model.ReadRequest{Operation: req.Operation, Options: &model.ReadOptions{}})
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := result.(type) {
+	case map[string]interface{}:
+		processAggregateID(v)
+	case []interface{}:
+		for _, row := range v {
+			processAggregateID(row.(map[string]interface{}))
+		}
+	}
+	return result, nil
+}
+
+// generateAggregateQuery compiles the aggregation pipeline to a prepared sql query
+func (s *SQL) generateAggregateQuery(ctx context.Context, col string, req *model.AggregateRequest) (string, []interface{}, error) {
+	stages, err := getPipelineStages(req.Pipeline)
+	if err != nil {
+		return "", nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid aggregation pipeline provided", err, nil)
+	}
+
+	dbType := s.dbType
+	if dbType == string(model.SQLServer) {
+		dbType = string(model.Postgres)
+	}
+
+	dialect := goqu.Dialect(dbType)
+	state := &aggregateState{col: col, dialect: dialect, query: dialect.From(s.getColName(col)).Prepared(true)}
+	for _, stage := range stages {
+		if len(stage) != 1 {
+			return "", nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Each pipeline stage must contain exactly one operator", nil, map[string]interface{}{"stage": stage})
+		}
+
+		for operator, value := range stage {
+			var err error
+			switch operator {
+			case "$match":
+				err = s.aggregateMatch(ctx, state, value)
+			case "$group":
+				err = aggregateGroup(state, value)
+			case "$project":
+				err = aggregateProject(state, value)
+			case "$sort":
+				err = aggregateSort(state, value)
+			case "$limit", "$skip":
+				err = aggregatePaginate(state, operator, value)
+			default:
+				err = fmt.Errorf("pipeline stage (%s) is not supported for sql databases", operator)
+			}
+			if err != nil {
+				return "", nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to compile aggregation pipeline", err, map[string]interface{}{"stage": operator})
+			}
+		}
+	}
+
+	sqlString, args, err := state.query.ToSQL()
+	if err != nil {
+		return "", nil, err
+	}
+
+	sqlString = strings.Replace(sqlString, "\"", "", -1)
+
+	if model.DBType(s.dbType) == model.SQLServer {
+		sqlString, err = mutateSQLServerLimitAndOffsetOperation(sqlString, &model.ReadRequest{Options: &model.ReadOptions{Limit: state.limit, Skip: state.skip, Sort: state.sort}})
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	sqlString, err = s.replaceRegexOperators(ctx, sqlString, state.regexArr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if s.dbType == string(model.SQLServer) {
+		sqlString = s.generateQuerySQLServer(sqlString)
+	}
+	return sqlString, args, nil
+}
+
+// getPipelineStages converts the pipeline provided in the request to an array of stages
+func getPipelineStages(pipeline interface{}) ([]map[string]interface{}, error) {
+	switch v := pipeline.(type) {
+	case []map[string]interface{}:
+		return v, nil
+	case []interface{}:
+		stages := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			stage, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid type (%T) provided for pipeline stage at index (%d)", item, i)
+			}
+			stages[i] = stage
+		}
+		return stages, nil
+	default:
+		return nil, fmt.Errorf("invalid type (%T) provided for pipeline", pipeline)
+	}
+}
+
+// wrap converts the current query to a sub query so that the next stage operates on the output of the
+// previous stages. This is required when a stage references the fields computed by $group or $project
+func (state *aggregateState) wrap(operator string) error {
+	if state.hasOrder || state.hasPaginate {
+		return fmt.Errorf("(%s) cannot be used after a $sort, $limit or $skip stage", operator)
+	}
+
+	state.wrapCounter++
+	state.query = state.dialect.From(state.query.As(fmt.Sprintf("t%d", state.wrapCounter))).Prepared(true)
+	return nil
+}
+
+func (s *SQL) aggregateMatch(ctx context.Context, state *aggregateState, value interface{}) error {
+	find, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid type (%T) provided for $match", value)
+	}
+
+	if state.columns != nil || state.hasPaginate {
+		if err := state.wrap("$match"); err != nil {
+			return err
+		}
+		state.columns = nil
+	}
+
+	if len(find) == 0 {
+		return nil
+	}
+
+	e, regexArr, err := s.generator(ctx, state.col, find, false)
+	if err != nil {
+		return err
+	}
+	state.query = state.query.Where(e)
+	state.regexArr = append(state.regexArr, regexArr...)
+	return nil
+}
+
+func aggregateGroup(state *aggregateState, value interface{}) error {
+	group, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid type (%T) provided for $group", value)
+	}
+
+	id, p := group["_id"]
+	if !p {
+		return fmt.Errorf("$group must specify an _id field")
+	}
+
+	if state.columns != nil || state.isGrouped {
+		if err := state.wrap("$group"); err != nil {
+			return err
+		}
+	} else if state.hasOrder || state.hasPaginate {
+		return fmt.Errorf("$group cannot be used after a $sort, $limit or $skip stage")
+	}
+
+	columns := make([]string, 0)
+	selArray := make([]interface{}, 0)
+	groupBy := make([]interface{}, 0)
+
+	// Add the group keys in the select & group by clause
+	switch v := id.(type) {
+	case nil:
+		columns = append(columns, "_id")
+		selArray = append(selArray, goqu.L("NULL").As("_id"))
+	case string:
+		field, err := getAggregateFieldRef(v)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, "_id")
+		selArray = append(selArray, goqu.I(field).As("_id"))
+		groupBy = append(groupBy, goqu.I(field))
+	case map[string]interface{}:
+		for _, key := range getSortedKeys(v) {
+			ref, ok := v[key].(string)
+			if !ok {
+				return fmt.Errorf("invalid type (%T) provided for group key (%s)", v[key], key)
+			}
+			field, err := getAggregateFieldRef(ref)
+			if err != nil {
+				return err
+			}
+			columns = append(columns, aggregateIDPrefix+key)
+			selArray = append(selArray, goqu.I(field).As(aggregateIDPrefix+key))
+			groupBy = append(groupBy, goqu.I(field))
+		}
+	default:
+		return fmt.Errorf("invalid type (%T) provided for _id in $group", id)
+	}
+
+	// Add the accumulators
+	for _, key := range getSortedKeys(group) {
+		if key == "_id" {
+			continue
+		}
+
+		e, err := getAccumulatorExpression(key, group[key])
+		if err != nil {
+			return err
+		}
+		columns = append(columns, key)
+		selArray = append(selArray, e.As(key))
+	}
+
+	state.query = state.query.Select(selArray...)
+	if len(groupBy) > 0 {
+		state.query = state.query.GroupBy(groupBy...)
+	}
+	state.columns = columns
+	state.isGrouped = true
+	return nil
+}
+
+func getAccumulatorExpression(key string, value interface{}) (exp.SQLFunctionExpression, error) {
+	acc, ok := value.(map[string]interface{})
+	if !ok || len(acc) != 1 {
+		return nil, fmt.Errorf("accumulator for field (%s) must be an object with a single operator", key)
+	}
+
+	for operator, arg := range acc {
+		if operator == "$count" {
+			return goqu.COUNT(goqu.Star()), nil
+		}
+
+		// A numeric argument for $sum is the mongo way of counting documents
+		if operator == "$sum" {
+			if n, ok := getAggregateNumber(arg); ok {
+				if n == 1 {
+					return goqu.COUNT(goqu.Star()), nil
+				}
+				return goqu.SUM(goqu.L(strconv.FormatInt(n, 10))), nil
+			}
+		}
+
+		ref, ok := arg.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid argument (%v) provided for accumulator (%s) of field (%s)", arg, operator, key)
+		}
+		field, err := getAggregateFieldRef(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		switch operator {
+		case "$sum":
+			return goqu.SUM(goqu.I(field)), nil
+		case "$avg":
+			return goqu.AVG(goqu.I(field)), nil
+		case "$min":
+			return goqu.MIN(goqu.I(field)), nil
+		case "$max":
+			return goqu.MAX(goqu.I(field)), nil
+		default:
+			return nil, fmt.Errorf("accumulator (%s) is not supported for sql databases", operator)
+		}
+	}
+
+	return nil, nil
+}
+
+func aggregateProject(state *aggregateState, value interface{}) error {
+	project, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid type (%T) provided for $project", value)
+	}
+
+	prevColumns := state.columns
+	if prevColumns != nil || state.hasPaginate {
+		if err := state.wrap("$project"); err != nil {
+			return err
+		}
+	}
+
+	// The _id field is included by default if the previous stage produced it
+	includeID := true
+	columns := make([]string, 0)
+	selArray := make([]interface{}, 0)
+	for _, key := range getSortedKeys(project) {
+		v := project[key]
+		if ref, ok := v.(string); ok {
+			field, err := getAggregateFieldRef(ref)
+			if err != nil {
+				return err
+			}
+			columns = append(columns, key)
+			selArray = append(selArray, goqu.I(field).As(key))
+			continue
+		}
+
+		include, ok := getAggregateBool(v)
+		if !ok {
+			return fmt.Errorf("invalid value (%v) provided for field (%s) in $project", v, key)
+		}
+		if key == "_id" {
+			includeID = include
+			continue
+		}
+		if !include {
+			return fmt.Errorf("field exclusion is only supported for the _id field in $project")
+		}
+		columns = append(columns, key)
+		selArray = append(selArray, goqu.I(key))
+	}
+
+	if includeID {
+		idColumns := make([]string, 0)
+		for _, column := range prevColumns {
+			if column == "_id" || strings.HasPrefix(column, aggregateIDPrefix) {
+				idColumns = append(idColumns, column)
+			}
+		}
+		if _, p := project["_id"]; p && len(idColumns) == 0 {
+			idColumns = append(idColumns, "_id")
+		}
+		for i := len(idColumns) - 1; i >= 0; i-- {
+			columns = append([]string{idColumns[i]}, columns...)
+			selArray = append([]interface{}{goqu.I(idColumns[i])}, selArray...)
+		}
+	}
+
+	if len(selArray) == 0 {
+		return fmt.Errorf("$project must include atleast one field")
+	}
+
+	state.query = state.query.Select(selArray...)
+	state.columns = columns
+	return nil
+}
+
+// aggregateSort adds the order by clause. The sort stage can either be a mongo style object or an array
+// of fields similar to the sort option of read requests. Objects decoded by the http handler are ordered
+// documents which keep the order of their keys. The keys of a plain map are sorted by their name
+func aggregateSort(state *aggregateState, value interface{}) error {
+	if state.hasPaginate {
+		return fmt.Errorf("$sort cannot be used after a $limit or $skip stage")
+	}
+
+	sortFields := make([]string, 0)
+	switch v := value.(type) {
+	case primitive.D:
+		for _, elem := range v {
+			field, err := getAggregateSortField(elem.Key, elem.Value)
+			if err != nil {
+				return err
+			}
+			sortFields = append(sortFields, field)
+		}
+	case map[string]interface{}:
+		for _, key := range getSortedKeys(v) {
+			field, err := getAggregateSortField(key, v[key])
+			if err != nil {
+				return err
+			}
+			sortFields = append(sortFields, field)
+		}
+	case []interface{}:
+		for _, item := range v {
+			field, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("invalid type (%T) provided for sort field", item)
+			}
+			sortFields = append(sortFields, field)
+		}
+	default:
+		return fmt.Errorf("invalid type (%T) provided for $sort", value)
+	}
+
+	orderBys := make([]exp.OrderedExpression, 0)
+	for _, field := range sortFields {
+		isDesc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+
+		// Sorting on a composite group key sorts on each of its columns
+		columns := []string{field}
+		if field == "_id" {
+			columns = getIDColumns(state.columns)
+		}
+
+		for _, column := range columns {
+			if isDesc {
+				orderBys = append(orderBys, goqu.I(column).Desc())
+				state.sort = append(state.sort, "-"+column)
+				continue
+			}
+			orderBys = append(orderBys, goqu.I(column).Asc())
+			state.sort = append(state.sort, column)
+		}
+	}
+
+	state.query = state.query.OrderAppend(orderBys...)
+	state.hasOrder = true
+	return nil
+}
+
+func getAggregateSortField(key string, value interface{}) (string, error) {
+	order, ok := getAggregateNumber(value)
+	if !ok || (order != 1 && order != -1) {
+		return "", fmt.Errorf("invalid sort order (%v) provided for field (%s)", value, key)
+	}
+	if order == -1 {
+		return "-" + key, nil
+	}
+	return key, nil
+}
+
+func aggregatePaginate(state *aggregateState, operator string, value interface{}) error {
+	n, ok := getAggregateNumber(value)
+	if !ok || n < 0 {
+		return fmt.Errorf("invalid value (%v) provided for %s", value, operator)
+	}
+
+	switch operator {
+	case "$limit":
+		if state.limit != nil {
+			return fmt.Errorf("$limit can only be used once in a pipeline")
+		}
+		state.limit = &n
+		state.query = state.query.Limit(uint(n))
+	case "$skip":
+		if state.skip != nil || state.limit != nil {
+			return fmt.Errorf("$skip can only be used once and before the $limit stage")
+		}
+		state.skip = &n
+		state.query = state.query.Offset(uint(n))
+	}
+	state.hasPaginate = true
+	return nil
+}
+
+// processAggregateID converts the aliased columns of a composite group key back to an _id object
+func processAggregateID(row map[string]interface{}) {
+	id := map[string]interface{}{}
+	for k, v := range row {
+		if strings.HasPrefix(k, aggregateIDPrefix) {
+			id[strings.TrimPrefix(k, aggregateIDPrefix)] = v
+			delete(row, k)
+		}
+	}
+	if len(id) > 0 {
+		row["_id"] = id
+	}
+}
+
+func getIDColumns(columns []string) []string {
+	idColumns := make([]string, 0)
+	for _, column := range columns {
+		if strings.HasPrefix(column, aggregateIDPrefix) {
+			idColumns = append(idColumns, column)
+		}
+	}
+	if len(idColumns) == 0 {
+		return []string{"_id"}
+	}
+	return idColumns
+}
+
+func getAggregateFieldRef(ref string) (string, error) {
+	if !strings.HasPrefix(ref, "$") || len(ref) == 1 {
+		return "", fmt.Errorf("invalid field reference (%s) provided, field references must start with $", ref)
+	}
+	return strings.TrimPrefix(ref, "$"), nil
+}
+
+func getAggregateNumber(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, false
+		}
+		return int64(v), true
+	}
+	return 0, false
+}
+
+func getAggregateBool(value interface{}) (bool, bool) {
+	if v, ok := value.(bool); ok {
+		return v, true
+	}
+	if v, ok := getAggregateNumber(value); ok && (v == 0 || v == 1) {
+		return v == 1, true
+	}
+	return false, false
+}
+
+func getSortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,167 @@
+package sql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func TestSQL_generateAggregateQuery(t *testing.T) {
+	type args struct {
+		col string
+		req *model.AggregateRequest
+	}
+	tests := []struct {
+		name    string
+		dbType  string
+		args    args
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name:   "match, group by single field, sort & limit",
+			dbType: "mysql",
+			args: args{col: "orders", req: &model.AggregateRequest{Operation: "all", Pipeline: []interface{}{
+				map[string]interface{}{"$match": map[string]interface{}{"status": "paid"}},
+				map[string]interface{}{"$group": map[string]interface{}{"_id": "$city", "total": map[string]interface{}{"$sum": "$amount"}, "count": map[string]interface{}{"$sum": float64(1)}}},
+				map[string]interface{}{"$sort": map[string]interface{}{"total": float64(-1)}},
+				map[string]interface{}{"$limit": float64(5)},
+			}}},
+			want:  "SELECT city AS _id, COUNT(*) AS count, SUM(amount) AS total FROM orders WHERE (status = ?) GROUP BY city ORDER BY total DESC LIMIT ?",
+			want1: []interface{}{"paid", int64(5)},
+		},
+		{
+			name:   "group with composite key and every accumulator",
+			dbType: "postgres",
+			args: args{col: "orders", req: &model.AggregateRequest{Operation: "all", Pipeline: []interface{}{
+				map[string]interface{}{"$group": map[string]interface{}{
+					"_id": map[string]interface{}{"city": "$city", "year": "$year"},
+					"avg": map[string]interface{}{"$avg": "$amount"},
+					"cnt": map[string]interface{}{"$count": map[string]interface{}{}},
+					"max": map[string]interface{}{"$max": "$amount"},
+					"min": map[string]interface{}{"$min": "$amount"},
+				}},
+				map[string]interface{}{"$sort": map[string]interface{}{"_id": float64(1)}},
+			}}},
+			want:  "SELECT city AS _id__city, year AS _id__year, AVG(amount) AS avg, COUNT(*) AS cnt, MAX(amount) AS max, MIN(amount) AS min FROM test.orders GROUP BY city, year ORDER BY _id__city ASC, _id__year ASC",
+			want1: []interface{}{},
+		},
+		{
+			name:   "group on whole table",
+			dbType: "mysql",
+			args: args{col: "orders", req: &model.AggregateRequest{Operation: "one", Pipeline: []interface{}{
+				map[string]interface{}{"$group": map[string]interface{}{"_id": nil, "total": map[string]interface{}{"$sum": "$amount"}}},
+			}}},
+			want:  "SELECT NULL AS _id, SUM(amount) AS total FROM orders",
+			want1: []interface{}{},
+		},
+		{
+			name:   "match after group is applied on a sub query",
+			dbType: "postgres",
+			args: args{col: "orders", req: &model.AggregateRequest{Operation: "all", Pipeline: []interface{}{
+				map[string]interface{}{"$group": map[string]interface{}{"_id": "$city", "total": map[string]interface{}{"$sum": "$amount"}}},
+				map[string]interface{}{"$match": map[string]interface{}{"total": map[string]interface{}{"$gt": float64(100)}}},
+			}}},
+			want:  "SELECT * FROM (SELECT city AS _id, SUM(amount) AS total FROM test.orders GROUP BY city) AS t1 WHERE (total > $1)",
+			want1: []interface{}{float64(100)},
+		},
+		{
+			name:   "project with rename and skip",
+			dbType: "mysql",
+			args: args{col: "users", req: &model.AggregateRequest{Operation: "all", Pipeline: []interface{}{
+				map[string]interface{}{"$project": map[string]interface{}{"name": float64(1), "mail": "$email"}},
+				map[string]interface{}{"$sort": []interface{}{"name"}},
+				map[string]interface{}{"$skip": float64(10)},
+				map[string]interface{}{"$limit": float64(10)},
+			}}},
+			want:  "SELECT email AS mail, name FROM users ORDER BY name ASC LIMIT ? OFFSET ?",
+			want1: []interface{}{int64(10), int64(10)},
+		},
+		{
+			name:   "sort on multiple fields keeps the order of the sort stage",
+			dbType: "mysql",
+			args: args{col: "users", req: &model.AggregateRequest{Operation: "all", Pipeline: []interface{}{
+				map[string]interface{}{"$sort": primitive.D{{Key: "name", Value: float64(1)}, {Key: "age", Value: float64(-1)}}},
+			}}},
+			want:  "SELECT * FROM users ORDER BY name ASC, age DESC",
+			want1: []interface{}{},
+		},
+		{
+			name:   "sql server limit",
+			dbType: "sqlserver",
+			args: args{col: "orders", req: &model.AggregateRequest{Operation: "all", Pipeline: []interface{}{
+				map[string]interface{}{"$group": map[string]interface{}{"_id": "$city", "total": map[string]interface{}{"$sum": "$amount"}}},
+				map[string]interface{}{"$limit": float64(5)},
+			}}},
+			want:  "SELECT TOP 5 city AS _id, SUM(amount) AS total FROM test.orders GROUP BY city",
+			want1: []interface{}{int64(5)},
+		},
+		{
+			name:   "unsupported stage",
+			dbType: "mysql",
+			args: args{col: "orders", req: &model.AggregateRequest{Operation: "all", Pipeline: []interface{}{
+				map[string]interface{}{"$unwind": "$items"},
+			}}},
+			wantErr: true,
+		},
+		{
+			name:   "group after limit",
+			dbType: "mysql",
+			args: args{col: "orders", req: &model.AggregateRequest{Operation: "all", Pipeline: []interface{}{
+				map[string]interface{}{"$limit": float64(5)},
+				map[string]interface{}{"$group": map[string]interface{}{"_id": "$city"}},
+			}}},
+			wantErr: true,
+		},
+		{
+			name:   "invalid field reference",
+			dbType: "mysql",
+			args: args{col: "orders", req: &model.AggregateRequest{Operation: "all", Pipeline: []interface{}{
+				map[string]interface{}{"$group": map[string]interface{}{"_id": "city"}},
+			}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pipeline",
+			dbType:  "mysql",
+			args:    args{col: "orders", req: &model.AggregateRequest{Operation: "all", Pipeline: map[string]interface{}{}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SQL{dbType: tt.dbType, name: "test"}
+			got, got1, err := s.generateAggregateQuery(context.Background(), tt.args.col, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("generateAggregateQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("generateAggregateQuery() got = %v, want %v", got, tt.want)
+			}
+			if len(got1) != 0 || len(tt.want1) != 0 {
+				if !reflect.DeepEqual(got1, tt.want1) {
+					t.Errorf("generateAggregateQuery() got1 = %v, want %v", got1, tt.want1)
+				}
+			}
+		})
+	}
+}
+
+func Test_processAggregateID(t *testing.T) {
+	row := map[string]interface{}{"_id__city": "pune", "_id__year": int64(2020), "total": float64(10)}
+	processAggregateID(row)
+
+	want := map[string]interface{}{"_id": map[string]interface{}{"city": "pune", "year": int64(2020)}, "total": float64(10)}
+	if !reflect.DeepEqual(row, want) {
+		t.Errorf("processAggregateID() got = %v, want %v", row, want)
+	}
+}
@@ -23,10 +23,12 @@ func (s *SQL) Batch(ctx context.Context, req *model.BatchRequest) ([]int64, erro
 		case string(model.Create):
 			sqlQuery, args, err := s.generateCreateQuery(req.Col, &model.CreateRequest{Document: req.Document, Operation: req.Operation})
 			if err != nil {
+				_ = tx.Rollback()
 				return counts, err
 			}
 			res, err := doExecContext(ctx, sqlQuery, args, tx)
 			if err != nil {
+				_ = tx.Rollback()
 				return counts, err
 			}
 			counts[i], _ = res.RowsAffected()
@@ -34,17 +36,20 @@ func (s *SQL) Batch(ctx context.Context, req *model.BatchRequest) ([]int64, erro
 		case string(model.Delete):
 			sqlQuery, args, err := s.generateDeleteQuery(ctx, &model.DeleteRequest{Find: req.Find, Operation: req.Operation}, req.Col)
 			if err != nil {
+				_ = tx.Rollback()
 				return counts, err
 			}
 			res, err := doExecContext(ctx, sqlQuery, args, tx)
 			if err != nil {
+				_ = tx.Rollback()
 				return counts, err
 			}
 			counts[i], _ = res.RowsAffected()
 
 		case string(model.Update):
-			n, err := s.update(ctx, req.Col, &model.UpdateRequest{Find: req.Find, Operation: req.Operation, Update: req.Update}, tx)
+			n, err := s.update(ctx, req.Col, &model.UpdateRequest{Find: req.Find, Operation: req.Operation, Update: req.Update, IsVersioned: req.IsVersioned}, tx)
 			if err != nil {
+				_ = tx.Rollback()
 				return counts, err
 			}
 			counts[i] = n
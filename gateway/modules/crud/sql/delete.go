@@ -42,7 +42,11 @@ func (s *SQL) generateDeleteQuery(ctx context.Context, req *model.DeleteRequest,
 
 	if req.Find != nil {
 		// Get the where clause from query object
-		query, _ = s.generateWhereClause(ctx, query, req.Find, nil)
+		var err error
+		query, _, err = s.generateWhereClause(ctx, col, query, req.Find, nil)
+		if err != nil {
+			return "", nil, err
+		}
 	}
 
 	// Generate SQL string and arguments
@@ -10,6 +10,10 @@ import (
 // DescribeTable return a description of sql table & foreign keys in table
 // NOTE: not to be exposed externally
 func (s *SQL) DescribeTable(ctx context.Context, col string) ([]model.InspectorFieldType, []model.IndexType, error) {
+	if model.DBType(s.dbType) == model.SQLite {
+		return s.describeSQLiteTable(ctx, col)
+	}
+
 	fields, err := s.getDescribeDetails(ctx, s.name, col)
 	if err != nil {
 		return nil, nil, err
@@ -71,7 +75,10 @@ where a.table_name= ? and a.table_schema= ? ;
        c.table_name AS "TABLE_NAME",
 
        c.column_name AS "COLUMN_NAME",
-       c.data_type "DATA_TYPE",
+       case when c.data_type = 'USER-DEFINED' then (select format_type(a.atttypid, a.atttypmod) from pg_attribute a
+                                                    where a.attrelid = (quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass
+                                                      and a.attname = c.column_name)
+            else c.data_type end "DATA_TYPE",
        c.is_nullable AS "IS_NULLABLE",
        c.ordinal_position AS "ORDINAL_POSITION",
        SPLIT_PART(REPLACE(coalesce(c.column_default,''),'''',''), '::', 1) AS "DEFAULT",
@@ -206,13 +213,15 @@ select b.table_schema AS 'TABLE_SCHEMA',
        b.column_name AS 'COLUMN_NAME',
        b.index_name AS 'INDEX_NAME',
        b.seq_in_index AS 'SEQ_IN_INDEX',
-	   case when b.collation = "A" then "asc" else "desc" end as SORT,
+	   case when b.collation = "D" then "desc" else "asc" end as SORT,
        case when b.non_unique=0 then true else false end 'IS_UNIQUE',
-       case when upper(b.index_name)='PRIMARY' then 1 else 0 end 'IS_PRIMARY'
+       case when upper(b.index_name)='PRIMARY' then 1 else 0 end 'IS_PRIMARY',
+       case when b.index_type='FULLTEXT' then 1 else 0 end 'IS_FULLTEXT'
 from INFORMATION_SCHEMA.STATISTICS  b
 where b.table_schema= ? and b.table_name= ?;`
 
 	case model.Postgres:
+		// Full text indexes are expression indexes. Their columns can only be found through the dependencies of the index
 		queryString = `select
     n.nspname AS "TABLE_SCHEMA",
     t.relname AS "TABLE_NAME" ,
@@ -221,13 +230,32 @@ where b.table_schema= ? and b.table_name= ?;`
     array_position(i.indkey, b.attnum)+1 "SEQ_IN_INDEX",
 	case when i.indoption[array_position(i.indkey, b.attnum)] = 0 then 'asc' else 'desc' END AS "SORT",
     i.indisunique AS "IS_UNIQUE",
-    i.indisprimary "IS_PRIMARY"
+    i.indisprimary "IS_PRIMARY",
+    false AS "IS_FULLTEXT"
 from pg_class a
          left join pg_namespace n on n.oid = a.relnamespace
          left join pg_index i on a.oid = i.indexrelid and a.relkind='i' and i.indisvalid = true
          left join pg_class t on t.oid = i.indrelid
          left join pg_attribute b on b.attrelid = t.oid and b.attnum = ANY(i.indkey)
-where n.nspname= $1 and t.relname= $2;`
+where n.nspname= $1 and t.relname= $2 and b.attname is not null
+union all
+select
+    n.nspname AS "TABLE_SCHEMA",
+    t.relname AS "TABLE_NAME" ,
+    b.attname AS "COLUMN_NAME",
+    a.relname AS "INDEX_NAME",
+    1 "SEQ_IN_INDEX",
+    'asc' AS "SORT",
+    false AS "IS_UNIQUE",
+    false AS "IS_PRIMARY",
+    true AS "IS_FULLTEXT"
+from pg_class a
+         join pg_namespace n on n.oid = a.relnamespace
+         join pg_index i on a.oid = i.indexrelid and a.relkind='i' and i.indisvalid = true
+         join pg_class t on t.oid = i.indrelid
+         join pg_depend d on d.classid = 'pg_class'::regclass and d.objid = a.oid and d.refobjid = t.oid and d.refobjsubid > 0
+         join pg_attribute b on b.attrelid = t.oid and b.attnum = d.refobjsubid
+where n.nspname= $1 and t.relname= $2 and 0 = ANY(i.indkey) and pg_get_indexdef(a.oid) like '%to_tsvector(%';`
 	case model.SQLServer:
 		queryString = `
 select
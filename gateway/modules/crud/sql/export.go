@@ -0,0 +1,61 @@
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// Export streams the rows matching the read request to the callback. Rows are scanned one at a time from the
+// server side cursor and the query fetch limit is not applied.
+func (s *SQL) Export(ctx context.Context, col string, req *model.ReadRequest, cb model.ExportCallback) (int64, error) {
+	sqlString, args, err := s.generateReadQuery(ctx, col, req)
+	if err != nil {
+		return 0, err
+	}
+
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Executing sql export query", map[string]interface{}{"sqlQuery": sqlString, "queryArgs": args})
+
+	stmt, err := s.getClient().PreparexContext(ctx, sqlString)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	rows, err := stmt.QueryxContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	rowTypes, _ := rows.ColumnTypes()
+
+	var count int64
+	for rows.Next() {
+		row := make(map[string]interface{})
+		if err := rows.MapScan(row); err != nil {
+			return count, err
+		}
+
+		switch s.GetDBType() {
+		case model.MySQL, model.Postgres, model.SQLServer:
+			mysqlTypeCheck(ctx, s.GetDBType(), rowTypes, row)
+		case model.SQLite:
+			sqliteTypeCheck(rowTypes, row)
+		}
+
+		if req.Options.Debug {
+			row["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
+		}
+
+		if err := cb(row); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, rows.Err()
+}
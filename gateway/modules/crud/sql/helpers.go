@@ -15,12 +15,13 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/doug-martin/goqu/v8"
+	"github.com/doug-martin/goqu/v8/exp"
 
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
-func (s *SQL) generator(ctx context.Context, find map[string]interface{}, isJoin bool) (goqu.Expression, []string) {
+func (s *SQL) generator(ctx context.Context, col string, find map[string]interface{}, isJoin bool) (goqu.Expression, []string, error) {
 	var regxarr []string
 	array := []goqu.Expression{}
 	for k, v := range find {
@@ -37,7 +38,10 @@ func (s *SQL) generator(ctx context.Context, find map[string]interface{}, isJoin
 					continue
 				}
 
-				exp, a := s.generator(ctx, f2, isJoin)
+				exp, a, err := s.generator(ctx, col, f2, isJoin)
+				if err != nil {
+					return nil, nil, err
+				}
 				orFinalArray = append(orFinalArray, exp)
 				regxarr = append(regxarr, a...)
 			}
@@ -57,7 +61,7 @@ func (s *SQL) generator(ctx context.Context, find map[string]interface{}, isJoin
 					switch s.dbType {
 					case "postgres":
 						regxarr = append(regxarr, fmt.Sprintf("%s = $", k))
-					case "mysql":
+					case "mysql", "sqlite":
 						regxarr = append(regxarr, fmt.Sprintf("%s = ?", k))
 					}
 					array = append(array, goqu.I(k).Eq(v2))
@@ -81,6 +85,20 @@ func (s *SQL) generator(ctx context.Context, find map[string]interface{}, isJoin
 					default:
 						_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("_contains not supported for database (%s)", s.dbType), nil, nil)
 					}
+				case "$search":
+					exp, err := s.generateSearchExpression(col, k, v2)
+					if err != nil {
+						return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to generate full text search clause", err, nil)
+					}
+					array = append(array, exp)
+				case "$near", "$within":
+					exp, err := s.generateGeoExpression(col, k, k2, v2)
+					if err != nil {
+						return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to generate %s clause", strings.TrimPrefix(k2, "$")), err, nil)
+					}
+					if exp != nil {
+						array = append(array, exp)
+					}
 				case "$gt":
 					array = append(array, goqu.I(k).Gt(v2))
 
@@ -108,20 +126,26 @@ func (s *SQL) generator(ctx context.Context, find map[string]interface{}, isJoin
 		}
 	}
 
-	return goqu.And(array...), regxarr
+	return goqu.And(array...), regxarr, nil
 }
 
-func (s *SQL) generateWhereClause(ctx context.Context, q *goqu.SelectDataset, find map[string]interface{}, matchWhere []map[string]interface{}) (query *goqu.SelectDataset, arr []string) {
+func (s *SQL) generateWhereClause(ctx context.Context, col string, q *goqu.SelectDataset, find map[string]interface{}, matchWhere []map[string]interface{}) (query *goqu.SelectDataset, arr []string, err error) {
 	query = q
 
 	exps := make([]goqu.Expression, len(matchWhere))
 	for i, f := range matchWhere {
-		exps[i], _ = s.generator(ctx, f, false)
+		exps[i], _, err = s.generator(ctx, col, f, false)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	regexArr := make([]string, 0)
 	if len(find) > 0 {
-		exp, arr := s.generator(ctx, find, false)
+		exp, arr, err := s.generator(ctx, col, find, false)
+		if err != nil {
+			return nil, nil, err
+		}
 		exps = append(exps, exp)
 		regexArr = arr
 	}
@@ -130,7 +154,171 @@ func (s *SQL) generateWhereClause(ctx context.Context, q *goqu.SelectDataset, fi
 		query = query.Where(goqu.And(exps...))
 	}
 
-	return query, regexArr
+	return query, regexArr, nil
+}
+
+// validateSearchColumn makes sure the column has a full text index in the table, since it gets inserted in a raw sql expression
+func (s *SQL) validateSearchColumn(col, column string) error {
+	fieldType, ok := s.getFieldType(col, column)
+	if ok {
+		for _, indexInfo := range fieldType.IndexInfo {
+			if indexInfo.IsFullText {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("field (%s) of table (%s) does not have a full text index", column, col)
+}
+
+// generateSearchExpression generates the full text search clause for a column
+func (s *SQL) generateSearchExpression(col, column string, query interface{}) (goqu.Expression, error) {
+	if err := s.validateSearchColumn(col, column); err != nil {
+		return nil, err
+	}
+
+	switch model.DBType(s.dbType) {
+	case model.Postgres:
+		return goqu.L("to_tsvector('english', ?) @@ plainto_tsquery('english', ?)", goqu.I(column), query), nil
+	case model.MySQL:
+		return goqu.L("MATCH (?) AGAINST (? IN NATURAL LANGUAGE MODE)", goqu.I(column), query), nil
+	}
+	return nil, fmt.Errorf("_search not supported for database (%s)", s.dbType)
+}
+
+// generateSearchScoreExpression generates the expression which computes the relevance of a row for the full text search
+// clause present in the where clause
+func (s *SQL) generateSearchScoreExpression(col string, find map[string]interface{}) (exp.LiteralExpression, error) {
+	column, query, ok := getSearchClause(find)
+	if !ok {
+		return nil, fmt.Errorf("cannot sort by (%s) without a _search clause in the where clause", utils.SearchScoreField)
+	}
+	if err := s.validateSearchColumn(col, column); err != nil {
+		return nil, err
+	}
+
+	switch model.DBType(s.dbType) {
+	case model.Postgres:
+		return goqu.L("ts_rank(to_tsvector('english', ?), plainto_tsquery('english', ?))", goqu.I(column), query), nil
+	case model.MySQL:
+		return goqu.L("MATCH (?) AGAINST (? IN NATURAL LANGUAGE MODE)", goqu.I(column), query), nil
+	}
+	return nil, fmt.Errorf("_search not supported for database (%s)", s.dbType)
+}
+
+// getSearchClause returns the column and the query of the top level full text search clause
+func getSearchClause(find map[string]interface{}) (string, interface{}, bool) {
+	for k, v := range find {
+		if obj, ok := v.(map[string]interface{}); ok {
+			if query, p := obj["$search"]; p {
+				return k, query, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// validateGeoColumn makes sure the column is a geo field of the table, since it gets inserted in a raw sql expression
+func (s *SQL) validateGeoColumn(col, column string) error {
+	fieldType, ok := s.getFieldType(col, column)
+	if !ok || (fieldType.Kind != model.TypePoint && fieldType.Kind != model.TypeGeometry) {
+		return fmt.Errorf("field (%s) is not a geo field of table (%s)", column, col)
+	}
+	return nil
+}
+
+// generateGeoExpression generates the clause of a geo operator for a column. A near clause without a max distance
+// doesn't filter the rows, hence a nil expression is returned for it
+func (s *SQL) generateGeoExpression(col, column, operator string, value interface{}) (goqu.Expression, error) {
+	if err := s.validateGeoColumn(col, column); err != nil {
+		return nil, err
+	}
+
+	var center map[string]interface{}
+	var radius *float64
+	var polygon map[string]interface{}
+	switch operator {
+	case "$near":
+		near, err := utils.ParseGeoNear(value)
+		if err != nil {
+			return nil, err
+		}
+		center, radius = near.Point, near.MaxDistance
+	case "$within":
+		within, err := utils.ParseGeoWithin(value)
+		if err != nil {
+			return nil, err
+		}
+		center, radius, polygon = within.Center, &within.Radius, within.Polygon
+	}
+
+	if polygon != nil {
+		data, err := json.Marshal(polygon)
+		if err != nil {
+			return nil, err
+		}
+		switch model.DBType(s.dbType) {
+		case model.Postgres:
+			return goqu.L("ST_Covers(ST_GeomFromGeoJSON(?)::geography, ?)", string(data), goqu.I(column)), nil
+		case model.MySQL:
+			return goqu.L("ST_Within(?, ST_GeomFromGeoJSON(?, 1, 4326))", goqu.I(column), string(data)), nil
+		}
+		return nil, fmt.Errorf("geo queries are not supported for database (%s)", s.dbType)
+	}
+
+	if radius == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(center)
+	if err != nil {
+		return nil, err
+	}
+	switch model.DBType(s.dbType) {
+	case model.Postgres:
+		return goqu.L("ST_DWithin(?, ST_GeomFromGeoJSON(?)::geography, ?)", goqu.I(column), string(data), *radius), nil
+	case model.MySQL:
+		return goqu.L("ST_Distance_Sphere(?, ST_GeomFromGeoJSON(?, 1, 4326)) <= ?", goqu.I(column), string(data), *radius), nil
+	}
+	return nil, fmt.Errorf("geo queries are not supported for database (%s)", s.dbType)
+}
+
+// generateDistanceExpression generates the expression which computes the distance in meters of a row from the point
+// of the near clause present in the where clause
+func (s *SQL) generateDistanceExpression(col string, find map[string]interface{}) (exp.LiteralExpression, error) {
+	column, value, ok := getNearClause(find)
+	if !ok {
+		return nil, fmt.Errorf("cannot sort by (%s) without a _near clause in the where clause", utils.DistanceField)
+	}
+	if err := s.validateGeoColumn(col, column); err != nil {
+		return nil, err
+	}
+	near, err := utils.ParseGeoNear(value)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(near.Point)
+	if err != nil {
+		return nil, err
+	}
+
+	switch model.DBType(s.dbType) {
+	case model.Postgres:
+		return goqu.L("ST_Distance(?, ST_GeomFromGeoJSON(?)::geography)", goqu.I(column), string(data)), nil
+	case model.MySQL:
+		return goqu.L("ST_Distance_Sphere(?, ST_GeomFromGeoJSON(?, 1, 4326))", goqu.I(column), string(data)), nil
+	}
+	return nil, fmt.Errorf("geo queries are not supported for database (%s)", s.dbType)
+}
+
+// getNearClause returns the column and the value of the top level near clause
+func getNearClause(find map[string]interface{}) (string, interface{}, bool) {
+	for k, v := range find {
+		if obj, ok := v.(map[string]interface{}); ok {
+			if value, p := obj["$near"]; p {
+				return k, value, true
+			}
+		}
+	}
+	return "", nil, false
 }
 
 func generateRecord(temp interface{}) (goqu.Record, error) {
@@ -197,6 +385,18 @@ func mysqlTypeCheck(ctx context.Context, dbType model.DBType, types []*sql.Colum
 				if err := json.Unmarshal(v, &val); err == nil {
 					mapping[colType.Name()] = val
 				}
+			case "GEOMETRY":
+				if val, err := utils.GeoJSONFromMySQLGeometry(v); err == nil {
+					mapping[colType.Name()] = val
+				}
+			case "":
+				// Types of extensions, like the geography type of postgis, don't have a type name. Geometries are
+				// returned as hex encoded extended WKB
+				if dbType == model.Postgres {
+					if val, err := utils.GeoJSONFromEWKB(string(v)); err == nil {
+						mapping[colType.Name()] = val
+					}
+				}
 			case "VARCHAR", "CHAR", "TEXT", "NAME", "BPCHAR":
 				// NOTE: The NAME data type is only valid for Postgres database, as it exists for Postgres only (Name is a 63 byte (varchar) type used for storing system identifiers.)
 				val, ok := mapping[colType.Name()].([]byte)
@@ -256,7 +456,10 @@ func mysqlTypeCheck(ctx context.Context, dbType model.DBType, types []*sql.Colum
 
 func (s *SQL) processJoins(ctx context.Context, query *goqu.SelectDataset, join []*model.JoinOption, sel map[string]int32, isAggregate bool) (*goqu.SelectDataset, error) {
 	for _, j := range join {
-		on, _ := s.generator(ctx, j.On, true)
+		on, _, err := s.generator(ctx, j.Table, j.On, true)
+		if err != nil {
+			return nil, err
+		}
 		switch j.Type {
 		case "", "LEFT":
 			query = query.LeftJoin(goqu.T(s.getColName(j.Table)), goqu.On(on))
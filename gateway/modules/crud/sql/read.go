@@ -32,16 +32,30 @@ func (s *SQL) generateReadQuery(ctx context.Context, col string, req *model.Read
 	if req.Options == nil {
 		req.Options = &model.ReadOptions{}
 	}
-	if req.Options.Limit == nil {
-		req.Options.Limit = s.queryFetchLimit
-		req.Options.HasOptions = true
+
+	// Documents before a cursor are read in the reverse order and reversed back once read
+	sortOrder := req.Options.Sort
+	matchWhere := req.MatchWhere
+	if cursor, isBefore, ok := utils.GetCursor(req.Options); ok {
+		if cursor != "" {
+			clause, err := utils.GenerateCursorClause(sortOrder, cursor, isBefore, utils.NullsSortFirst(s.dbType))
+			if err != nil {
+				return "", nil, err
+			}
+			matchWhere = append(append(make([]map[string]interface{}, 0, len(matchWhere)+1), matchWhere...), clause)
+		}
+		if isBefore {
+			sortOrder = utils.ReverseSort(sortOrder)
+		}
 	}
 
 	dialect := goqu.Dialect(dbType)
 	query := dialect.From(s.getColName(col)).Prepared(true)
-	var regexArr []string
 	// Get the where clause from query object
-	query, regexArr = s.generateWhereClause(ctx, query, req.Find, req.MatchWhere)
+	query, regexArr, err := s.generateWhereClause(ctx, col, query, req.Find, matchWhere)
+	if err != nil {
+		return "", nil, err
+	}
 
 	selArray := make([]interface{}, 0)
 	if req.Options != nil {
@@ -61,15 +75,37 @@ func (s *SQL) generateReadQuery(ctx context.Context, col string, req *model.Read
 			query = query.Limit(uint(*req.Options.Limit))
 		}
 
-		if req.Options.Sort != nil {
+		if sortOrder != nil {
 			// Format the order array to a suitable type
-			orderBys := make([]exp.OrderedExpression, len(req.Options.Sort))
+			orderBys := make([]exp.OrderedExpression, len(sortOrder))
 
 			// Iterate over order array
-			for i, value := range req.Options.Sort {
+			for i, value := range sortOrder {
 				// Add order type based on type attribute of order element
 				var e exp.OrderedExpression
-				if strings.HasPrefix(value, "-") {
+				if strings.TrimPrefix(value, "-") == utils.SearchScoreField {
+					// Sort by the relevance of the full text search
+					score, err := s.generateSearchScoreExpression(col, req.Find)
+					if err != nil {
+						return "", nil, err
+					}
+					if strings.HasPrefix(value, "-") {
+						e = score.Desc()
+					} else {
+						e = score.Asc()
+					}
+				} else if strings.TrimPrefix(value, "-") == utils.DistanceField {
+					// Sort by the distance from the point of the near clause
+					distance, err := s.generateDistanceExpression(col, req.Find)
+					if err != nil {
+						return "", nil, err
+					}
+					if strings.HasPrefix(value, "-") {
+						e = distance.Desc()
+					} else {
+						e = distance.Asc()
+					}
+				} else if strings.HasPrefix(value, "-") {
 					e = goqu.I(strings.TrimPrefix(value, "-")).Desc()
 				} else {
 					e = goqu.I(value).Asc()
@@ -156,24 +192,32 @@ func (s *SQL) generateReadQuery(ctx context.Context, col string, req *model.Read
 		}
 	}
 
+	sqlString, err = s.replaceRegexOperators(ctx, sqlString, regexArr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if s.dbType == string(model.SQLServer) {
+		sqlString = s.generateQuerySQLServer(sqlString)
+	}
+	return sqlString, args, nil
+}
+
+// replaceRegexOperators replaces the equality operator generated for the $regex clauses with the regex operator of the database
+func (s *SQL) replaceRegexOperators(ctx context.Context, sqlString string, regexArr []string) (string, error) {
 	for _, v := range regexArr {
 		switch s.dbType {
-		case "mysql":
+		case "mysql", "sqlite":
 			vReplaced := strings.Replace(v, "=", "REGEXP", -1)
 			sqlString = strings.Replace(sqlString, v, vReplaced, -1)
 		case "postgres":
 			vReplaced := strings.Replace(v, "=", "~", -1)
 			sqlString = strings.Replace(sqlString, v, vReplaced, -1)
 		case "sqlserver":
-			return "", nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "SQL server doesn't support regex operation", nil, nil)
+			return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "SQL server doesn't support regex operation", nil, nil)
 		}
-
 	}
-
-	if s.dbType == string(model.SQLServer) {
-		sqlString = s.generateQuerySQLServer(sqlString)
-	}
-	return sqlString, args, nil
+	return sqlString, nil
 }
 
 func getAggregateColumnName(column string) string {
@@ -216,6 +260,14 @@ func (s *SQL) Read(ctx context.Context, col string, req *model.ReadRequest) (int
 }
 
 func (s *SQL) read(ctx context.Context, col string, req *model.ReadRequest, executor executor) (int64, interface{}, map[string]map[string]string, *model.SQLMetaData, error) {
+	if req.Options == nil {
+		req.Options = &model.ReadOptions{}
+	}
+	if req.Options.Limit == nil {
+		req.Options.Limit = s.queryFetchLimit
+		req.Options.HasOptions = true
+	}
+
 	sqlString, args, err := s.generateReadQuery(ctx, col, req)
 	if err != nil {
 		return 0, nil, nil, nil, err
@@ -252,7 +304,7 @@ func (s *SQL) readExec(ctx context.Context, col, sqlString string, args []interf
 	var rowTypes []*sql.ColumnType
 
 	switch s.GetDBType() {
-	case model.MySQL, model.Postgres, model.SQLServer:
+	case model.MySQL, model.Postgres, model.SQLServer, model.SQLite:
 		rowTypes, _ = rows.ColumnTypes()
 	}
 
@@ -271,6 +323,8 @@ func (s *SQL) readExec(ctx context.Context, col, sqlString string, args []interf
 		switch s.GetDBType() {
 		case model.MySQL, model.Postgres, model.SQLServer:
 			mysqlTypeCheck(ctx, s.GetDBType(), rowTypes, mapping)
+		case model.SQLite:
+			sqliteTypeCheck(rowTypes, mapping)
 		}
 
 		for _, v := range mapping {
@@ -298,6 +352,8 @@ func (s *SQL) readExec(ctx context.Context, col, sqlString string, args []interf
 			switch s.GetDBType() {
 			case model.MySQL, model.Postgres, model.SQLServer:
 				mysqlTypeCheck(ctx, s.GetDBType(), rowTypes, row)
+			case model.SQLite:
+				sqliteTypeCheck(rowTypes, row)
 			}
 
 			if req.Options == nil || req.Options.ReturnType == "table" || len(req.Options.Join) == 0 {
@@ -321,6 +377,10 @@ func (s *SQL) readExec(ctx context.Context, col, sqlString string, args []interf
 			return 1, array[0], jointMapping, metaData, nil
 		}
 
+		if _, isBefore, ok := utils.GetCursor(req.Options); ok && isBefore {
+			utils.ReverseArray(array)
+		}
+
 		return count, array, jointMapping, metaData, nil
 
 	default:
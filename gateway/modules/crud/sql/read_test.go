@@ -21,12 +21,21 @@ func TestSQL_generateReadQuery(t *testing.T) {
 		connection string
 		client     *sqlx.DB
 		dbType     string
+		schema     model.Collection
 	}
 	type args struct {
 		project string
 		col     string
 		req     *model.ReadRequest
 	}
+	tableSchema := model.Collection{"table": model.Fields{
+		"title": &model.FieldType{FieldName: "title", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{IsFullText: true, Group: "title_fulltext", Field: "title"}}},
+		"body":  &model.FieldType{FieldName: "body", Kind: model.TypeString},
+	}}
+	driversSchema := model.Collection{"drivers": model.Fields{
+		"name":     &model.FieldType{FieldName: "name", Kind: model.TypeString},
+		"location": &model.FieldType{FieldName: "location", Kind: model.TypePoint},
+	}}
 	tests := []struct {
 		name    string
 		fields  fields
@@ -564,6 +573,176 @@ func TestSQL_generateReadQuery(t *testing.T) {
 			want1:   []interface{}{int64(1)},
 			wantErr: false,
 		},
+		{
+			name:   "read after cursor",
+			fields: fields{dbType: "postgres"},
+			args: args{project: "test", col: "table",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"Column1": map[string]interface{}{"$eq": 1}},
+					Options:   &model.ReadOptions{Limit: iti(10), Sort: []string{"-id"}, After: str("WyJiIl0")},
+					Operation: "all"}},
+			want:    []string{"SELECT * FROM test.table WHERE ((id < $1) AND (Column1 = $2)) ORDER BY id DESC LIMIT $3"},
+			want1:   []interface{}{"b", int64(1), int64(10)},
+			wantErr: false,
+		},
+		{
+			name:   "read before cursor",
+			fields: fields{dbType: "mysql"},
+			args: args{project: "test", col: "table",
+				req: &model.ReadRequest{
+					Options:   &model.ReadOptions{Limit: iti(10), Sort: []string{"id"}, Before: str("WyJiIl0")},
+					Operation: "all"}},
+			want:    []string{"SELECT * FROM table WHERE ((id < ?) OR (id IS NULL)) ORDER BY id DESC LIMIT ?"},
+			want1:   []interface{}{"b", int64(10)},
+			wantErr: false,
+		},
+		{
+			name:   "read last page",
+			fields: fields{dbType: "mysql"},
+			args: args{project: "test", col: "table",
+				req: &model.ReadRequest{
+					Options:   &model.ReadOptions{Limit: iti(10), Sort: []string{"id"}, Before: str("")},
+					Operation: "all"}},
+			want:    []string{"SELECT * FROM table ORDER BY id DESC LIMIT ?"},
+			want1:   []interface{}{int64(10)},
+			wantErr: false,
+		},
+		{
+			name:   "cursor with a different sort order",
+			fields: fields{dbType: "mysql"},
+			args: args{project: "test", col: "table",
+				req: &model.ReadRequest{
+					Options:   &model.ReadOptions{Sort: []string{"age", "id"}, After: str("WyJiIl0")},
+					Operation: "all"}},
+			want:    []string{""},
+			wantErr: true,
+		},
+		{
+			name:   "full text search",
+			fields: fields{dbType: "mysql", schema: tableSchema},
+			args: args{project: "test", col: "table",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"title": map[string]interface{}{"$search": "space cloud"}},
+					Operation: "all"}},
+			want:    []string{"SELECT * FROM table WHERE MATCH (title) AGAINST (? IN NATURAL LANGUAGE MODE)"},
+			want1:   []interface{}{"space cloud"},
+			wantErr: false,
+		},
+		{
+			name:   "full text search on unsupported database",
+			fields: fields{dbType: "sqlserver", schema: tableSchema},
+			args: args{project: "test", col: "table",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"title": map[string]interface{}{"$search": "space cloud"}},
+					Operation: "all"}},
+			want:    []string{""},
+			wantErr: true,
+		},
+		{
+			name:   "full text search sorted by relevance",
+			fields: fields{dbType: "postgres", schema: tableSchema},
+			args: args{project: "test", col: "table",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"title": map[string]interface{}{"$search": "space cloud"}},
+					Options:   &model.ReadOptions{Sort: []string{"-_score"}},
+					Operation: "all"}},
+			want:    []string{"SELECT * FROM test.table WHERE to_tsvector('english', title) @@ plainto_tsquery('english', $1) ORDER BY ts_rank(to_tsvector('english', title), plainto_tsquery('english', $2)) DESC"},
+			want1:   []interface{}{"space cloud", "space cloud"},
+			wantErr: false,
+		},
+		{
+			name:   "full text search on a malicious key",
+			fields: fields{dbType: "mysql", schema: tableSchema},
+			args: args{project: "test", col: "table",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"title) AGAINST ('x') OR 1=1 OR MATCH (title": map[string]interface{}{"$search": "space cloud"}},
+					Operation: "all"}},
+			want:    []string{""},
+			wantErr: true,
+		},
+		{
+			name:   "full text search on a field without a full text index",
+			fields: fields{dbType: "postgres", schema: tableSchema},
+			args: args{project: "test", col: "table",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"body": map[string]interface{}{"$search": "space cloud"}},
+					Operation: "all"}},
+			want:    []string{""},
+			wantErr: true,
+		},
+		{
+			name:   "sort by relevance without full text search",
+			fields: fields{dbType: "postgres"},
+			args: args{project: "test", col: "table",
+				req: &model.ReadRequest{
+					Options:   &model.ReadOptions{Sort: []string{"-_score"}},
+					Operation: "all"}},
+			want:    []string{""},
+			wantErr: true,
+		},
+		{
+			name:   "near clause sorted by distance",
+			fields: fields{dbType: "postgres", schema: driversSchema},
+			args: args{project: "test", col: "drivers",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"location": map[string]interface{}{"$near": map[string]interface{}{"point": map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.87, 19.07}}, "maxDistance": float64(5000)}}},
+					Options:   &model.ReadOptions{Sort: []string{"_distance"}},
+					Operation: "all"}},
+			want:    []string{"SELECT * FROM test.drivers WHERE ST_DWithin(location, ST_GeomFromGeoJSON($1)::geography, $2) ORDER BY ST_Distance(location, ST_GeomFromGeoJSON($3)::geography) ASC"},
+			want1:   []interface{}{`{"coordinates":[72.87,19.07],"type":"Point"}`, float64(5000), `{"coordinates":[72.87,19.07],"type":"Point"}`},
+			wantErr: false,
+		},
+		{
+			name:   "within clause with a polygon",
+			fields: fields{dbType: "mysql", schema: driversSchema},
+			args: args{project: "test", col: "drivers",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"location": map[string]interface{}{"$within": map[string]interface{}{"polygon": map[string]interface{}{"type": "Polygon", "coordinates": []interface{}{[]interface{}{[]interface{}{0, 0}, []interface{}{0, 1}, []interface{}{1, 1}, []interface{}{0, 0}}}}}}},
+					Operation: "all"}},
+			want:    []string{"SELECT * FROM drivers WHERE ST_Within(location, ST_GeomFromGeoJSON(?, 1, 4326))"},
+			want1:   []interface{}{`{"coordinates":[[[0,0],[0,1],[1,1],[0,0]]],"type":"Polygon"}`},
+			wantErr: false,
+		},
+		{
+			name:   "within clause without a polygon or center",
+			fields: fields{dbType: "postgres", schema: driversSchema},
+			args: args{project: "test", col: "drivers",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"location": map[string]interface{}{"$within": map[string]interface{}{"radius": float64(100)}}},
+					Operation: "all"}},
+			want:    []string{""},
+			wantErr: true,
+		},
+		{
+			name:   "sort by distance without near clause",
+			fields: fields{dbType: "mysql"},
+			args: args{project: "test", col: "drivers",
+				req: &model.ReadRequest{
+					Options:   &model.ReadOptions{Sort: []string{"_distance"}},
+					Operation: "all"}},
+			want:    []string{""},
+			wantErr: true,
+		},
+		{
+			name:   "near clause on a malicious key",
+			fields: fields{dbType: "postgres", schema: driversSchema},
+			args: args{project: "test", col: "drivers",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"location, location) OR 1=1 OR ST_DWithin(location": map[string]interface{}{"$near": map[string]interface{}{"point": map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.87, 19.07}}, "maxDistance": float64(5000)}}},
+					Operation: "all"}},
+			want:    []string{""},
+			wantErr: true,
+		},
+		{
+			name:   "within clause on a field which is not a geo field",
+			fields: fields{dbType: "mysql", schema: driversSchema},
+			args: args{project: "test", col: "drivers",
+				req: &model.ReadRequest{
+					Find:      map[string]interface{}{"name": map[string]interface{}{"$within": map[string]interface{}{"center": map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.87, 19.07}}, "radius": float64(100)}}},
+					Operation: "all"}},
+			want:    []string{""},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -574,6 +753,7 @@ func TestSQL_generateReadQuery(t *testing.T) {
 				client:     tt.fields.client,
 				dbType:     tt.fields.dbType,
 				name:       tt.args.project,
+				schema:     tt.fields.schema,
 			}
 			got, got1, err := s.generateReadQuery(context.Background(), tt.args.col, tt.args.req)
 			if (err != nil) != tt.wantErr {
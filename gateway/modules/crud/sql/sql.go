@@ -32,6 +32,9 @@ type SQL struct {
 	driverConf          config.DriverConfig
 	connRetryCloserChan chan struct{}
 
+	// schema is used to validate the columns used in geo and full text search clauses
+	schema model.Collection
+
 	// 	Auth module
 	aesKey []byte
 }
@@ -50,6 +53,9 @@ func Init(dbType model.DBType, enabled bool, connection string, dbName string, d
 	case model.SQLServer:
 		s.dbType = "sqlserver"
 
+	case model.SQLite:
+		s.dbType = "sqlite"
+
 	default:
 		err = utils.ErrUnsupportedDatabase
 		return
@@ -113,6 +119,8 @@ func (s *SQL) GetDBType() model.DBType {
 		return model.MySQL
 	case "sqlserver":
 		return model.SQLServer
+	case "sqlite":
+		return model.SQLite
 	}
 
 	return model.MySQL
@@ -139,7 +147,12 @@ func (s *SQL) connect() error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeOut)
 	defer cancel()
 
-	sql, err := sqlx.Open(s.dbType, s.connection)
+	driverName := s.dbType
+	if model.DBType(s.dbType) == model.SQLite {
+		driverName = sqliteDriverName
+	}
+
+	sql, err := sqlx.Open(driverName, s.connection)
 	if err != nil {
 		return err
 	}
@@ -149,6 +162,11 @@ func (s *SQL) connect() error {
 	maxConn := s.driverConf.MaxConn
 	if maxConn == 0 {
 		maxConn = 100
+
+		// SQLite allows a single writer at a time. An in memory database is also private to the connection which created it
+		if model.DBType(s.dbType) == model.SQLite {
+			maxConn = 1
+		}
 	}
 
 	maxIdleConn := s.driverConf.MaxIdleConn
@@ -192,6 +210,27 @@ func (s *SQL) SetProjectAESKey(aesKey []byte) {
 	s.aesKey = aesKey
 }
 
+// SetSchema sets the schema of the database. Sql databases maintain their own indexes, the schema is only
+// stored to validate the columns which get inserted in raw sql expressions
+func (s *SQL) SetSchema(ctx context.Context, schema model.Collection) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.schema = schema
+	return nil
+}
+
+// getFieldType returns the schema of a column. The column may be prefixed with the name of its table
+func (s *SQL) getFieldType(col, column string) (*model.FieldType, bool) {
+	if arr := strings.SplitN(column, ".", 2); len(arr) == 2 {
+		col, column = arr[0], arr[1]
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	fieldType, ok := s.schema[col][column]
+	return fieldType, ok && fieldType != nil
+}
+
 func (s *SQL) setClient(c *sqlx.DB) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
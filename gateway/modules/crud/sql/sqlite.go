@@ -0,0 +1,235 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// sqliteDriverName is the name of the sqlite driver registered with the regexp function
+const sqliteDriverName = "sqlite3_space_cloud"
+
+func init() {
+	// SQLite provides the syntax for the REGEXP operator but leaves its implementation to the application
+	sql.Register(sqliteDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("regexp", sqliteRegexp, true)
+		},
+	})
+}
+
+func sqliteRegexp(pattern string, value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case string:
+		return regexp.MatchString(pattern, v)
+	case []byte:
+		if v == nil {
+			return false, nil
+		}
+		return regexp.Match(pattern, v)
+	default:
+		return regexp.MatchString(pattern, fmt.Sprintf("%v", v))
+	}
+}
+
+// sqliteTypeCheck converts the values returned by the sqlite driver based on the declared column type
+func sqliteTypeCheck(types []*sql.ColumnType, mapping map[string]interface{}) {
+	for _, colType := range types {
+		typeName, _ := splitSQLiteType(colType.DatabaseTypeName())
+		switch v := mapping[colType.Name()].(type) {
+		case string:
+			if typeName == "json" {
+				var val interface{}
+				if err := json.Unmarshal([]byte(v), &val); err == nil {
+					mapping[colType.Name()] = val
+				}
+			}
+		case []byte:
+			if typeName == "json" {
+				var val interface{}
+				if err := json.Unmarshal(v, &val); err == nil {
+					mapping[colType.Name()] = val
+				}
+			}
+		case time.Time:
+			if typeName == "date" {
+				mapping[colType.Name()] = v.Format("2006-01-02")
+				continue
+			}
+			mapping[colType.Name()] = v.UTC().Format(time.RFC3339Nano)
+		}
+	}
+}
+
+// splitSQLiteType splits a declared column type like numeric(10,2) into its name & arguments
+func splitSQLiteType(declaredType string) (string, []int) {
+	declaredType = strings.ToLower(strings.TrimSpace(declaredType))
+	index := strings.Index(declaredType, "(")
+	if index == -1 {
+		return declaredType, nil
+	}
+
+	args := make([]int, 0)
+	for _, arg := range strings.Split(strings.TrimSuffix(declaredType[index+1:], ")"), ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(arg))
+		if err != nil {
+			continue
+		}
+		args = append(args, v)
+	}
+	return strings.TrimSpace(declaredType[:index]), args
+}
+
+type sqliteColumn struct {
+	ID      int    `db:"cid"`
+	Name    string `db:"name"`
+	Type    string `db:"type"`
+	NotNull bool   `db:"notnull"`
+	Default string `db:"dflt_value"`
+	Primary int    `db:"pk"`
+}
+
+type sqliteForeignKey struct {
+	From     string `db:"from"`
+	Table    string `db:"table"`
+	To       string `db:"to"`
+	OnDelete string `db:"on_delete"`
+}
+
+type sqliteIndex struct {
+	Name     string `db:"name"`
+	IsUnique bool   `db:"unique"`
+}
+
+type sqliteIndexColumn struct {
+	Order  int    `db:"seqno"`
+	Name   string `db:"name"`
+	IsDesc bool   `db:"desc"`
+}
+
+// describeSQLiteTable describes a sqlite table using the pragma functions as sqlite doesn't have an information schema.
+// NOTE: The results of each query are fetched completely before firing the next one since the connection pool of sqlite
+// is limited to a single connection by default
+func (s *SQL) describeSQLiteTable(ctx context.Context, col string) ([]model.InspectorFieldType, []model.IndexType, error) {
+	client := s.getClient()
+
+	var createSQL string
+	if err := client.GetContext(ctx, &createSQL, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", col); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, errors.New(s.dbType + ":" + col + " not found during inspection")
+		}
+		return nil, nil, err
+	}
+	isAutoIncrement := strings.Contains(strings.ToUpper(createSQL), "AUTOINCREMENT")
+
+	columns := make([]sqliteColumn, 0)
+	if err := client.SelectContext(ctx, &columns, `SELECT cid, name, type, "notnull", coalesce(dflt_value, '') AS dflt_value, pk FROM pragma_table_info(?) ORDER BY cid`, col); err != nil {
+		return nil, nil, err
+	}
+
+	// The foreign keys are fetched separately since older versions of sqlite don't join pragma functions correctly
+	foreignKeys := make([]sqliteForeignKey, 0)
+	if err := client.SelectContext(ctx, &foreignKeys, `SELECT "from", "table", "to", on_delete FROM pragma_foreign_key_list(?)`, col); err != nil {
+		return nil, nil, err
+	}
+	foreignKeyMap := make(map[string]sqliteForeignKey, len(foreignKeys))
+	for _, foreignKey := range foreignKeys {
+		foreignKeyMap[foreignKey.From] = foreignKey
+	}
+
+	fields := make([]model.InspectorFieldType, 0)
+	indexes := make([]model.IndexType, 0)
+	for _, column := range columns {
+		field := model.InspectorFieldType{
+			TableSchema:     "main",
+			TableName:       col,
+			ColumnName:      column.Name,
+			FieldType:       strings.ToLower(column.Type),
+			FieldNull:       "YES",
+			OrdinalPosition: strconv.Itoa(column.ID + 1),
+			FieldDefault:    getSQLiteDefaultValue(column.Default),
+			AutoIncrement:   "false",
+		}
+		if column.NotNull {
+			field.FieldNull = "NO"
+		}
+		if foreignKey, ok := foreignKeyMap[column.Name]; ok {
+			field.RefTableSchema = "main"
+			field.RefTableName = foreignKey.Table
+			field.RefColumnName = foreignKey.To
+			field.DeleteRule = foreignKey.OnDelete
+		}
+
+		typeName, args := splitSQLiteType(column.Type)
+		switch typeName {
+		case "varchar", "character", "char":
+			field.VarcharSize = -1
+			if len(args) > 0 {
+				field.VarcharSize = args[0]
+			}
+		case "numeric", "decimal":
+			if len(args) > 0 {
+				field.NumericPrecision = args[0]
+			}
+			if len(args) > 1 {
+				field.NumericScale = args[1]
+			}
+		}
+
+		if column.Primary > 0 {
+			if isAutoIncrement && typeName == "integer" {
+				field.AutoIncrement = "true"
+			}
+			indexes = append(indexes, model.IndexType{TableSchema: "main", TableName: col, ColumnName: column.Name, IndexName: "PRIMARY", Order: column.Primary, Sort: "asc", IsPrimary: true})
+		}
+		fields = append(fields, field)
+	}
+
+	// Only the indexes created explicitly are of interest. Indexes created for unique & primary key constraints are skipped
+	indexList := make([]sqliteIndex, 0)
+	if err := client.SelectContext(ctx, &indexList, `SELECT name, "unique" FROM pragma_index_list(?) WHERE origin = 'c'`, col); err != nil {
+		return nil, nil, err
+	}
+
+	for _, index := range indexList {
+		indexColumns := make([]sqliteIndexColumn, 0)
+		if err := client.SelectContext(ctx, &indexColumns, `SELECT seqno, name, "desc" FROM pragma_index_xinfo(?) WHERE key = 1 ORDER BY seqno`, index.Name); err != nil {
+			return nil, nil, err
+		}
+
+		for _, indexColumn := range indexColumns {
+			sort := "asc"
+			if indexColumn.IsDesc {
+				sort = "desc"
+			}
+			indexes = append(indexes, model.IndexType{TableSchema: "main", TableName: col, ColumnName: indexColumn.Name, IndexName: index.Name, Order: indexColumn.Order + 1, Sort: sort, IsUnique: index.IsUnique})
+		}
+	}
+
+	return fields, indexes, nil
+}
+
+// getSQLiteDefaultValue converts the default expression stored by sqlite to the raw value
+func getSQLiteDefaultValue(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1)
+	}
+
+	switch strings.ToUpper(value) {
+	case "TRUE":
+		return "true"
+	case "FALSE":
+		return "false"
+	}
+	return value
+}
@@ -0,0 +1,78 @@
+package sql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func TestSQL_SQLite(t *testing.T) {
+	ctx := context.Background()
+	s, err := Init(model.SQLite, true, "file::memory:?_foreign_keys=1", "main", config.DriverConfig{})
+	if err != nil {
+		t.Fatal("unable to initialize sqlite", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	queries := []string{
+		"CREATE TABLE parents (id varchar(50) NOT NULL, PRIMARY KEY (id));",
+		"CREATE TABLE users (id varchar(50) NOT NULL, age integer DEFAULT 10, is_active boolean, info json, parent varchar(50), PRIMARY KEY (id), CONSTRAINT c_users_parent FOREIGN KEY (parent) REFERENCES parents (id) ON DELETE CASCADE);",
+		"CREATE UNIQUE INDEX index__users__age ON users (age desc)",
+		"INSERT INTO parents (id) VALUES ('p1')",
+	}
+	if err := s.RawBatch(ctx, queries); err != nil {
+		t.Fatal("unable to create tables", err)
+	}
+
+	if _, err := s.Create(ctx, "users", &model.CreateRequest{Operation: "all", Document: []interface{}{
+		map[string]interface{}{"id": "1", "age": 20, "is_active": true, "info": `{"city":"pune"}`, "parent": "p1"},
+		map[string]interface{}{"id": "2", "age": 30, "is_active": false, "info": "{}", "parent": "p1"},
+	}}); err != nil {
+		t.Fatal("unable to create documents", err)
+	}
+
+	if _, err := s.Update(ctx, "users", &model.UpdateRequest{Operation: "all", Find: map[string]interface{}{"id": "1"}, Update: map[string]interface{}{"$inc": map[string]interface{}{"age": 5}}}); err != nil {
+		t.Fatal("unable to update documents", err)
+	}
+
+	count, result, _, _, err := s.Read(ctx, "users", &model.ReadRequest{Operation: "all", Find: map[string]interface{}{"id": map[string]interface{}{"$regex": "^1"}}, Options: &model.ReadOptions{}})
+	if err != nil {
+		t.Fatal("unable to read documents", err)
+	}
+	want := []interface{}{map[string]interface{}{"id": "1", "age": int64(25), "is_active": true, "info": map[string]interface{}{"city": "pune"}, "parent": "p1"}}
+	if count != 1 || !reflect.DeepEqual(result, want) {
+		t.Errorf("Read() got = %v, want %v", result, want)
+	}
+
+	fields, indexes, err := s.DescribeTable(ctx, "users")
+	if err != nil {
+		t.Fatal("unable to describe table", err)
+	}
+	if len(fields) != 5 {
+		t.Fatalf("DescribeTable() got %d fields, want 5", len(fields))
+	}
+	if f := fields[1]; f.ColumnName != "age" || f.FieldType != "integer" || f.FieldDefault != "10" || f.FieldNull != "YES" {
+		t.Errorf("DescribeTable() got field = %v", f)
+	}
+	if f := fields[4]; f.RefTableName != "parents" || f.RefColumnName != "id" || f.DeleteRule != "CASCADE" {
+		t.Errorf("DescribeTable() got foreign field = %v", f)
+	}
+	wantIndexes := []model.IndexType{
+		{TableSchema: "main", TableName: "users", ColumnName: "id", IndexName: "PRIMARY", Order: 1, Sort: "asc", IsPrimary: true},
+		{TableSchema: "main", TableName: "users", ColumnName: "age", IndexName: "index__users__age", Order: 1, Sort: "desc", IsUnique: true},
+	}
+	if !reflect.DeepEqual(indexes, wantIndexes) {
+		t.Errorf("DescribeTable() got indexes = %v, want %v", indexes, wantIndexes)
+	}
+
+	collections, err := s.GetCollections(ctx)
+	if err != nil {
+		t.Fatal("unable to get collections", err)
+	}
+	if len(collections) != 2 {
+		t.Errorf("GetCollections() got = %v", collections)
+	}
+}
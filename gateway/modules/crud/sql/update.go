@@ -28,6 +28,7 @@ func (s *SQL) Update(ctx context.Context, col string, req *model.UpdateRequest)
 	}
 	count, err := s.update(ctx, col, req, tx)
 	if err != nil {
+		_ = tx.Rollback()
 		return 0, err
 	}
 	return count, tx.Commit() // commit the Batch
@@ -42,8 +43,20 @@ func (s *SQL) update(ctx context.Context, col string, req *model.UpdateRequest,
 	}
 	switch req.Operation {
 	case utils.All:
-		var count int64
+		// The version gets bumped by the $set operation. Hence it must be the last operation for the version check to
+		// hold true for the rest of the operations
+		ops := make([]string, 0, len(req.Update))
 		for k := range req.Update {
+			if k != "$set" {
+				ops = append(ops, k)
+			}
+		}
+		if _, p := req.Update["$set"]; p {
+			ops = append(ops, "$set")
+		}
+
+		var count int64
+		for _, k := range ops {
 			switch k {
 			case "$set", "$inc", "$mul", "$max", "$min", "$currentDate":
 				sqlQuery, args, err := s.generateUpdateQuery(ctx, col, req, k)
@@ -56,7 +69,12 @@ func (s *SQL) update(ctx context.Context, col string, req *model.UpdateRequest,
 					return 0, err
 				}
 
+				// Only the $set statement is guaranteed to change the matched rows since it bumps the version. Mysql
+				// doesn't count the rows which weren't changed by the other operations as affected
 				c, _ := res.RowsAffected()
+				if req.IsVersioned && k == "$set" && c == 0 {
+					return 0, utils.ErrVersionConflict
+				}
 				count += c
 
 			default: // (case "$push", "$unset", "$rename")
@@ -146,7 +164,11 @@ func (s *SQL) generateUpdateQuery(ctx context.Context, col string, req *model.Up
 
 	if req.Find != nil {
 		// Get the where clause from query object
-		query, _ = s.generateWhereClause(ctx, query, req.Find, nil)
+		var err error
+		query, _, err = s.generateWhereClause(ctx, col, query, req.Find, nil)
+		if err != nil {
+			return "", nil, err
+		}
 	}
 
 	if req.Update == nil {
@@ -193,7 +215,7 @@ func (s *SQL) generateUpdateQuery(ctx context.Context, col string, req *model.Up
 			if err != nil {
 				return "", nil, err
 			}
-			if s.dbType == string(model.MySQL) {
+			if s.dbType == string(model.MySQL) || s.dbType == string(model.SQLite) {
 				sqlString = strings.Replace(sqlString, k+"=?", k+"="+k+"+?", -1)
 			}
 			if s.dbType == string(model.Postgres) {
@@ -211,7 +233,7 @@ func (s *SQL) generateUpdateQuery(ctx context.Context, col string, req *model.Up
 			if err != nil {
 				return "", nil, err
 			}
-			if dbType == string(model.MySQL) {
+			if dbType == string(model.MySQL) || dbType == string(model.SQLite) {
 				sqlString = strings.Replace(sqlString, k+"=?", k+"="+k+"*?", -1)
 			}
 			if dbType == string(model.Postgres) {
@@ -232,6 +254,10 @@ func (s *SQL) generateUpdateQuery(ctx context.Context, col string, req *model.Up
 			if s.dbType == string(model.MySQL) {
 				sqlString = strings.Replace(sqlString, k+"=?", k+"=GREATEST("+k+","+"?"+")", -1)
 			}
+			if s.dbType == string(model.SQLite) {
+				// SQLite uses the multi argument max function in place of greatest
+				sqlString = strings.Replace(sqlString, k+"=?", k+"=MAX("+k+","+"?"+")", -1)
+			}
 			if s.dbType == string(model.Postgres) {
 				sqlString = strings.Replace(sqlString, k+"=$", k+"=GREATEST("+k+","+"$"+"", -1)
 			}
@@ -250,6 +276,10 @@ func (s *SQL) generateUpdateQuery(ctx context.Context, col string, req *model.Up
 			if dbType == string(model.MySQL) {
 				sqlString = strings.Replace(sqlString, k+"=?", k+"=LEAST("+k+","+"?"+")", -1)
 			}
+			if dbType == string(model.SQLite) {
+				// SQLite uses the multi argument min function in place of least
+				sqlString = strings.Replace(sqlString, k+"=?", k+"=MIN("+k+","+"?"+")", -1)
+			}
 			if dbType == string(model.Postgres) {
 				sqlString = strings.Replace(sqlString, k+"=$", k+"=LEAST("+k+","+"$", -1)
 			}
@@ -265,7 +295,7 @@ func (s *SQL) generateUpdateQuery(ctx context.Context, col string, req *model.Up
 			if !ok {
 				return "", nil, utils.ErrInvalidParams
 			}
-			if dbType == string(model.MySQL) {
+			if dbType == string(model.MySQL) || dbType == string(model.SQLite) {
 				sqlString = strings.Replace(sqlString, k+"=?", k+"="+val, -1)
 			}
 			if dbType == string(model.Postgres) {
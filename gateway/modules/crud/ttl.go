@@ -0,0 +1,208 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	ttlSweepInterval  = time.Minute
+	ttlSweepBatchSize = 100
+)
+
+// expiringTable holds the information required to delete the expired rows of a table
+type expiringTable struct {
+	project     string
+	dbAlias     string
+	dbType      string
+	col         string
+	field       *model.FieldType
+	primaryKeys []string
+	block       Crud
+}
+
+func (m *Module) routineSweepExpiredRows(done chan struct{}) {
+	ticker := time.NewTicker(ttlSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			m.sweepExpiredRows()
+		}
+	}
+}
+
+// sweepExpiredRows deletes the expired rows of the tables having a field with the ttl or expiresAt directive. Mongo
+// expires the documents on its own using ttl indexes. Only the leader gateway sweeps the tables so that the events of
+// the deleted rows are queued just once
+func (m *Module) sweepExpiredRows() {
+	ctx, cancel := context.WithTimeout(context.Background(), ttlSweepInterval)
+	defer cancel()
+
+	if m.admin == nil {
+		return
+	}
+	isLeader, err := m.admin.IsLeaderGateway()
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to check if gateway is the leader to delete expired rows", err, nil)
+		return
+	}
+	if !isLeader {
+		return
+	}
+
+	now := time.Now()
+	for _, table := range m.getExpiringTables(ctx) {
+		if err := m.sweepExpiredTable(ctx, table, now); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to delete expired rows of table (%s) of database (%s)", table.col, table.dbAlias), err, nil)
+		}
+	}
+}
+
+// getExpiringTables returns the tables whose expired rows need to be deleted by the gateway
+func (m *Module) getExpiringTables(ctx context.Context) []*expiringTable {
+	m.RLock()
+	defer m.RUnlock()
+
+	tables := make([]*expiringTable, 0)
+	for dbAlias, collection := range m.schemaDoc {
+		dbType, err := m.getDBType(dbAlias)
+		if err != nil || model.DBType(dbType) == model.Mongo {
+			continue
+		}
+		block, err := m.getCrudBlock(dbAlias)
+		if err != nil {
+			continue
+		}
+
+		for col, fields := range collection {
+			field, ok := schemaHelpers.GetTTLField(fields)
+			if !ok {
+				continue
+			}
+
+			primaryKeys := make([]string, 0)
+			for fieldName, fieldType := range fields {
+				if fieldType.IsPrimary {
+					primaryKeys = append(primaryKeys, fieldName)
+				}
+			}
+			if len(primaryKeys) == 0 && model.DBType(dbType) == model.EmbeddedDB {
+				primaryKeys = append(primaryKeys, "_id")
+			}
+			if len(primaryKeys) == 0 {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to delete expired rows of table (%s) as it doesn't have a primary key", col), nil, nil)
+				continue
+			}
+			sort.Strings(primaryKeys)
+
+			tables = append(tables, &expiringTable{project: m.project, dbAlias: dbAlias, dbType: dbType, col: col, field: field, primaryKeys: primaryKeys, block: block})
+		}
+	}
+	return tables
+}
+
+// sweepExpiredTable deletes the rows of a table which expired before the provided time in batches. The DB_DELETE events
+// are queued and the cache of the table is invalidated for every batch
+func (m *Module) sweepExpiredTable(ctx context.Context, table *expiringTable, now time.Time) error {
+	if err := table.block.IsClientSafe(ctx); err != nil {
+		return err
+	}
+
+	for {
+		limit := int64(ttlSweepBatchSize)
+		readRequest := &model.ReadRequest{Find: schemaHelpers.GenerateExpiredClause(table.field, table.dbType, now), Operation: utils.All, Options: &model.ReadOptions{Limit: &limit}}
+		_, result, _, _, err := table.block.Read(ctx, table.col, readRequest)
+		if err != nil {
+			return err
+		}
+		rows, _ := result.([]interface{})
+		if len(rows) == 0 {
+			return nil
+		}
+
+		// Only the rows which were read are deleted so that the events can be queued for them
+		find := generateRowsClause(table.primaryKeys, rows)
+		for k, v := range schemaHelpers.GenerateExpiredClause(table.field, table.dbType, now) {
+			find[k] = v
+		}
+		n, err := table.block.Delete(ctx, table.col, &model.DeleteRequest{Find: find, Operation: utils.All})
+		if err != nil {
+			return err
+		}
+
+		m.queueExpiredRowEvents(ctx, table, rows)
+		if m.metricHook != nil {
+			m.metricHook(table.project, table.dbAlias, table.col, n, model.Delete)
+		}
+
+		if n == 0 || len(rows) < ttlSweepBatchSize {
+			return nil
+		}
+	}
+}
+
+// queueExpiredRowEvents queues the DB_DELETE events of the deleted rows and invalidates the cache of their table
+func (m *Module) queueExpiredRowEvents(ctx context.Context, table *expiringTable, rows []interface{}) {
+	if m.eventing != nil {
+		events := make([]*model.QueueEventRequest, 0, len(rows))
+		for _, row := range rows {
+			doc, ok := row.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			events = append(events, &model.QueueEventRequest{
+				Type:    utils.EventDBDelete,
+				Payload: map[string]interface{}{"db": table.dbAlias, "col": table.col, "doc": doc, "find": doc},
+				Options: map[string]string{"db": table.dbAlias, "col": table.col},
+			})
+		}
+		if err := m.eventing.QueueAdminEvent(ctx, events); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to queue delete events for expired rows of table (%s)", table.col), err, nil)
+		}
+	}
+
+	if m.caching != nil {
+		if err := m.caching.InvalidateDatabaseCache(ctx, table.project, table.dbAlias, table.col, utils.EventDBDelete, nil); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to invalidate cache of table (%s) after deleting expired rows", table.col), err, nil)
+		}
+	}
+}
+
+// generateRowsClause returns the where clause which matches the provided rows on their primary keys
+func generateRowsClause(primaryKeys []string, rows []interface{}) map[string]interface{} {
+	if len(primaryKeys) == 1 {
+		values := make([]interface{}, 0, len(rows))
+		for _, row := range rows {
+			if doc, ok := row.(map[string]interface{}); ok {
+				values = append(values, doc[primaryKeys[0]])
+			}
+		}
+		return map[string]interface{}{primaryKeys[0]: map[string]interface{}{"$in": values}}
+	}
+
+	clauses := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		doc, ok := row.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		clause := map[string]interface{}{}
+		for _, key := range primaryKeys {
+			clause[key] = doc[key]
+		}
+		clauses = append(clauses, clause)
+	}
+	return map[string]interface{}{"$or": clauses}
+}
@@ -0,0 +1,38 @@
+package crud
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_generateRowsClause(t *testing.T) {
+	tests := []struct {
+		name        string
+		primaryKeys []string
+		rows        []interface{}
+		want        map[string]interface{}
+	}{
+		{
+			name:        "rows are matched on a single primary key",
+			primaryKeys: []string{"id"},
+			rows:        []interface{}{map[string]interface{}{"id": "1", "token": "a"}, map[string]interface{}{"id": "2", "token": "b"}},
+			want:        map[string]interface{}{"id": map[string]interface{}{"$in": []interface{}{"1", "2"}}},
+		},
+		{
+			name:        "rows are matched on a composite primary key",
+			primaryKeys: []string{"user_id", "device"},
+			rows:        []interface{}{map[string]interface{}{"user_id": "1", "device": "web", "token": "a"}, map[string]interface{}{"user_id": "1", "device": "ios", "token": "b"}},
+			want: map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"user_id": "1", "device": "web"},
+				map[string]interface{}{"user_id": "1", "device": "ios"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := generateRowsClause(tt.primaryKeys, tt.rows); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("generateRowsClause() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,4 +15,9 @@ type integrationManagerInterface interface {
 type cachingInterface interface {
 	SetDatabaseKey(ctx context.Context, projectID, dbAlias, col string, result *model.CacheDatabaseResult, dbCacheOptions *caching.CacheResult, cache *config.ReadCacheOptions, cacheJoinInfo map[string]map[string]string) error
 	GetDatabaseKey(ctx context.Context, projectID, dbAlias, tableName string, req *model.ReadRequest) (*caching.CacheResult, error)
+	InvalidateDatabaseCache(ctx context.Context, projectID, dbAlias, rootTable, opType string, doc map[string]interface{}) error
+}
+
+type eventingInterface interface {
+	QueueAdminEvent(ctx context.Context, reqs []*model.QueueEventRequest) error
 }
@@ -52,3 +52,15 @@ func (m *Module) GetEndpointContextTimeout(ctx context.Context, projectID, servi
 	}
 	return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Could not find endpoint (%s) for service (%s)", function, service), nil, nil)
 }
+
+// GetServices returns the config of all the remote services
+func (m *Module) GetServices() config.Services {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	services := make(config.Services, len(m.config))
+	for id, service := range m.config {
+		services[id] = service
+	}
+	return services
+}
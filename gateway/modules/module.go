@@ -1,6 +1,8 @@
 package modules
 
 import (
+	"os"
+
 	"github.com/spaceuptech/space-cloud/gateway/managers"
 	"github.com/spaceuptech/space-cloud/gateway/modules/auth"
 	"github.com/spaceuptech/space-cloud/gateway/modules/crud"
@@ -12,6 +14,7 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/modules/schema"
 	"github.com/spaceuptech/space-cloud/gateway/modules/userman"
 	"github.com/spaceuptech/space-cloud/gateway/utils/graphql"
+	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
 )
 
 // Module is an object that sets up the modules
@@ -53,6 +56,14 @@ func newModule(projectID, clusterID, nodeID string, managers *managers.Managers,
 	a := auth.Init(clusterID, nodeID, c, adminMan, integrationMan)
 	a.SetMakeHTTPRequest(syncMan.MakeHTTPRequest)
 
+	ps, err := pubsub.New(projectID, os.Getenv("REDIS_CONN"))
+	if err != nil {
+		return nil, err
+	}
+	if err := a.SetPubsubClient(ps); err != nil {
+		return nil, err
+	}
+
 	fn := functions.Init(clusterID, a, syncMan, integrationMan, metrics.AddFunctionOperation)
 	fn.SetCachingModule(globalMods.Caching())
 	f := filestore.Init(a, metrics.AddFileOperation)
@@ -64,6 +75,7 @@ func newModule(projectID, clusterID, nodeID string, managers *managers.Managers,
 	}
 
 	f.SetEventingModule(e)
+	c.SetEventingModule(e)
 
 	c.SetHooks(metrics.AddDBOperation)
 
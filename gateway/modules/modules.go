@@ -205,6 +205,15 @@ func (m *Modules) SetRemoteServiceConfig(ctx context.Context, projectID string,
 	return module.SetRemoteServiceConfig(ctx, projectID, services)
 }
 
+// SetGraphQLPersistedQueriesConfig set the allow list of graphql module
+func (m *Modules) SetGraphQLPersistedQueriesConfig(ctx context.Context, projectID string, queries config.GraphQLPersistedQueries) error {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return err
+	}
+	return module.SetGraphQLPersistedQueriesConfig(ctx, projectID, queries)
+}
+
 func (m *Modules) projects() *config.Config {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
@@ -77,6 +77,10 @@ func (m *Module) SetInitialProjectConfig(ctx context.Context, projects config.Pr
 
 		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of graphql module", nil)
 		m.graphql.SetConfig(projectID)
+		m.graphql.SetQueryLimits(project.ProjectConfig.GraphQLLimits)
+		m.graphql.SetEnforcePersistedQueries(project.ProjectConfig.EnforcePersistedQueries)
+		m.graphql.SetPublicIntrospection(project.ProjectConfig.PublicIntrospection)
+		m.graphql.SetPersistedQueries(ctx, project.GraphQLPersistedQueries)
 		if err := m.graphql.SetProjectAESKey(project.ProjectConfig.AESKey); err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set aes key for graphql module config", err, nil)
 		}
@@ -93,6 +97,7 @@ func (m *Module) SetInitialProjectConfig(ctx context.Context, projects config.Pr
 		m.GlobalMods.Routing().SetGlobalConfig(project.IngressGlobal)
 		m.eventing.SetInternalTriggersFromDbRules(project.DatabaseRules)
 		m.GlobalMods.Caching().AddDBRules(projectID, project.DatabaseRules)
+		m.db.SetDatabaseRules(project.DatabaseRules)
 	}
 	return nil
 }
@@ -108,6 +113,9 @@ func (m *Module) SetProjectConfig(ctx context.Context, p *config.ProjectConfig)
 	_ = m.user.SetProjectAESKey(p.AESKey)
 	_ = m.graphql.SetProjectAESKey(p.AESKey)
 	m.graphql.SetConfig(p.ID)
+	m.graphql.SetQueryLimits(p.GraphQLLimits)
+	m.graphql.SetEnforcePersistedQueries(p.EnforcePersistedQueries)
+	m.graphql.SetPublicIntrospection(p.PublicIntrospection)
 	return nil
 }
 
@@ -154,6 +162,7 @@ func (m *Module) SetDatabaseSchemaConfig(ctx context.Context, projectID string,
 		return err
 	}
 	m.realtime.SetDatabaseSchemas(schemaConfigs)
+	m.graphql.ResetSchema()
 	return nil
 }
 
@@ -161,9 +170,11 @@ func (m *Module) SetDatabaseSchemaConfig(ctx context.Context, projectID string,
 func (m *Module) SetDatabaseRulesConfig(ctx context.Context, projectID string, ruleConfigs config.DatabaseRules) error {
 	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of db rule in db module", nil)
 	m.auth.SetDatabaseRules(ruleConfigs)
+	m.db.SetDatabaseRules(ruleConfigs)
 	m.realtime.SetDatabaseRules(ruleConfigs)
 	m.eventing.SetInternalTriggersFromDbRules(ruleConfigs)
 	m.GlobalMods.Caching().AddDBRules(projectID, ruleConfigs)
+	m.graphql.ResetSchema()
 	return nil
 }
 
@@ -174,6 +185,7 @@ func (m *Module) SetDatabasePreparedQueryConfig(ctx context.Context, prepConfigs
 		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set db prepared query in db module", err, nil)
 	}
 	m.auth.SetDatabasePreparedQueryRules(prepConfigs)
+	m.graphql.ResetSchema()
 	return nil
 }
 
@@ -271,5 +283,16 @@ func (m *Module) SetRemoteServiceConfig(ctx context.Context, projectID string, s
 	m.auth.SetRemoteServiceConfig(services)
 
 	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of remote service module", nil)
-	return m.functions.SetConfig(projectID, services)
+	if err := m.functions.SetConfig(projectID, services); err != nil {
+		return err
+	}
+	m.graphql.ResetSchema()
+	return nil
+}
+
+// SetGraphQLPersistedQueriesConfig set the allow list of graphql module
+func (m *Module) SetGraphQLPersistedQueriesConfig(ctx context.Context, projectID string, queries config.GraphQLPersistedQueries) error {
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting persisted queries of graphql module", nil)
+	m.graphql.SetPersistedQueries(ctx, queries)
+	return nil
 }
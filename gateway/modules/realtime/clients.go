@@ -11,9 +11,10 @@ import (
 )
 
 type queryStub struct {
-	sendFeed model.SendFeed
-	whereObj map[string]interface{}
-	actions  *model.PostProcess
+	sendFeed       model.SendFeed
+	whereObj       map[string]interface{}
+	actions        *model.PostProcess
+	includeDeleted bool
 }
 
 type clientsStub struct {
@@ -22,7 +23,7 @@ type clientsStub struct {
 }
 
 // AddLiveQuery tracks a client for a live query
-func (m *Module) AddLiveQuery(id, _, dbAlias, group, clientID string, whereObj map[string]interface{}, actions *model.PostProcess, sendFeed model.SendFeed) {
+func (m *Module) AddLiveQuery(id, _, dbAlias, group, clientID string, whereObj map[string]interface{}, includeDeleted bool, actions *model.PostProcess, sendFeed model.SendFeed) {
 	// Load clients in a particular group
 	clients := new(clientsStub)
 	t, _ := m.groups.LoadOrStore(createGroupKey(dbAlias, group), clients)
@@ -34,7 +35,7 @@ func (m *Module) AddLiveQuery(id, _, dbAlias, group, clientID string, whereObj m
 	queries = t.(*sync.Map)
 
 	// Add the query
-	queries.Store(id, &queryStub{sendFeed, whereObj, actions})
+	queries.Store(id, &queryStub{sendFeed, whereObj, actions, includeDeleted})
 }
 
 // RemoveLiveQuery removes a particular live query
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
@@ -66,6 +68,28 @@ func (m *Module) prepareFindObject(db, col string, row map[string]interface{}) m
 	return find
 }
 
+// isSoftDeleted checks if the document of a feed has been marked as deleted
+func (m *Module) isSoftDeleted(data *model.FeedData) bool {
+	fields, _ := m.schema.GetSchema(data.DBType, data.Group)
+	field, ok := schemaHelpers.GetSoftDeleteField(fields)
+	if !ok {
+		return false
+	}
+
+	doc, ok := data.Payload.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	value, p := doc[field.FieldName]
+	if !p || value == nil {
+		return false
+	}
+	if isDeleted, ok := value.(bool); ok {
+		return isDeleted
+	}
+	return true
+}
+
 func generateEventRules(dbConfigs config.DatabaseConfigs, dbRules config.DatabaseRules, dbSchemas config.DatabaseSchemas, project, url string) []*config.EventingTrigger {
 
 	var eventingRules []*config.EventingTrigger
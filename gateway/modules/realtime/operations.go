@@ -11,6 +11,7 @@ import (
 
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	authHelpers "github.com/spaceuptech/space-cloud/gateway/modules/auth/helpers"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
@@ -37,8 +38,19 @@ func (m *Module) Subscribe(clientID string, data *model.RealtimeRequest, sendFee
 // DoRealtimeSubscribe makes the realtime query
 func (m *Module) DoRealtimeSubscribe(ctx context.Context, clientID string, data *model.RealtimeRequest, actions *model.PostProcess, reqParams model.RequestParams, sendFeed model.SendFeed) ([]*model.FeedData, error) {
 	readReq := &model.ReadRequest{Find: data.Where, Operation: utils.All}
+	if data.Options.IncludeDeleted {
+		readReq.Options = &model.ReadOptions{IncludeDeleted: true}
+	}
+
+	// Soft deleted documents must not match the live query either
+	whereObj := data.Where
+	if !data.Options.IncludeDeleted {
+		fields, _ := m.schema.GetSchema(data.DBType, data.Group)
+		whereObj = schemaHelpers.AddSoftDeleteClause(fields, "", data.Where)
+	}
+
 	if data.Options.SkipInitial {
-		m.AddLiveQuery(data.ID, data.Project, data.DBType, data.Group, clientID, data.Where, actions, sendFeed)
+		m.AddLiveQuery(data.ID, data.Project, data.DBType, data.Group, clientID, whereObj, data.Options.IncludeDeleted, actions, sendFeed)
 		return []*model.FeedData{}, nil
 	}
 
@@ -73,7 +85,7 @@ func (m *Module) DoRealtimeSubscribe(ctx context.Context, clientID string, data
 	}
 
 	// Add the live query
-	m.AddLiveQuery(data.ID, data.Project, data.DBType, data.Group, clientID, data.Where, actions, sendFeed)
+	m.AddLiveQuery(data.ID, data.Project, data.DBType, data.Group, clientID, whereObj, data.Options.IncludeDeleted, actions, sendFeed)
 
 	return feedData, nil
 }
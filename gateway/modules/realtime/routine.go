@@ -23,6 +23,7 @@ func (m *Module) helperSendFeed(ctx context.Context, data *model.FeedData) {
 	}
 
 	clients := clientsTemp.(*clientsStub)
+	isSoftDeleted := data.Type == utils.RealtimeUpdate && m.isSoftDeleted(data)
 	clients.clients.Range(func(key interface{}, value interface{}) bool {
 		queries := value.(*sync.Map)
 		queries.Range(func(id interface{}, value interface{}) bool {
@@ -44,6 +45,12 @@ func (m *Module) helperSendFeed(ctx context.Context, data *model.FeedData) {
 					_ = authHelpers.PostProcessMethod(ctx, m.aesKey, query.actions, dataPoint.Payload)
 					query.sendFeed(dataPoint)
 					m.metrics.AddDBOperation(m.project, data.DBType, data.Group, 1, model.Read)
+				} else if isSoftDeleted && !query.includeDeleted {
+					// The clients see a soft deleted document as a deleted one
+					dataPoint.Type = utils.RealtimeDelete
+					_ = authHelpers.PostProcessMethod(ctx, m.aesKey, query.actions, dataPoint.Payload)
+					query.sendFeed(dataPoint)
+					m.metrics.AddDBOperation(m.project, data.DBType, data.Group, 1, model.Read)
 				}
 
 			default:
@@ -33,16 +33,21 @@ func (s *Schema) SchemaCreation(ctx context.Context, dbAlias, tableName, logical
 		return nil
 	}
 
-	currentSchema, err := s.Inspector(ctx, dbAlias, dbType, logicalDBName, tableName, parsedSchema[dbAlias])
+	queries, err := s.planTableCreation(ctx, dbAlias, dbType, tableName, logicalDBName, parsedSchema)
 	if err != nil {
-		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Schema Inspector Error", map[string]interface{}{"error": err.Error()})
+		return err
 	}
+	return s.crud.RawBatch(ctx, dbAlias, queries)
+}
 
-	queries, err := s.generateCreationQueries(ctx, dbAlias, tableName, logicalDBName, parsedSchema, currentSchema)
+// planTableCreation returns the queries required to bring the table in sync with the parsed schema without running them
+func (s *Schema) planTableCreation(ctx context.Context, dbAlias, dbType, tableName, logicalDBName string, parsedSchema model.Type) ([]string, error) {
+	currentSchema, err := s.Inspector(ctx, dbAlias, dbType, logicalDBName, tableName, parsedSchema[dbAlias])
 	if err != nil {
-		return err
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Schema Inspector Error", map[string]interface{}{"error": err.Error()})
 	}
-	return s.crud.RawBatch(ctx, dbAlias, queries)
+
+	return s.generateCreationQueries(ctx, dbAlias, tableName, logicalDBName, parsedSchema, currentSchema)
 }
 
 func (s *Schema) generateCreationQueries(ctx context.Context, dbAlias, tableName, logicalDBName string, parsedSchema model.Type, currentSchema model.Collection) ([]string, error) {
@@ -69,6 +74,11 @@ func (s *Schema) generateCreationQueries(ctx context.Context, dbAlias, tableName
 		return nil, errors.New("Schema not provided for table: " + tableName)
 	}
 
+	// SQLite can only add columns to an existing table. Hence sqlite tables are rebuilt to apply other changes
+	if dbType == string(model.SQLite) {
+		return s.generateSQLiteCreationQueries(ctx, dbAlias, realTableName, logicalDBName, parsedSchema, realTableInfo, currentSchema)
+	}
+
 	// check if table exist in current schema
 	currentTableInfo, ok := currentSchema[realTableName]
 	if !ok {
@@ -146,9 +156,11 @@ func (s *Schema) generateCreationQueries(ctx context.Context, dbAlias, tableName
 		// Create the joint table first
 		if realColumnInfo.IsForeign {
 			if _, p := currentSchema[realColumnInfo.JointTable.Table]; !p {
-				if err := s.SchemaCreation(ctx, dbAlias, realColumnInfo.JointTable.Table, logicalDBName, parsedSchema); err != nil {
+				queries, err := s.planTableCreation(ctx, dbAlias, dbType, realColumnInfo.JointTable.Table, logicalDBName, parsedSchema)
+				if err != nil {
 					return nil, err
 				}
+				batchedQueries = append(queries, batchedQueries...)
 			}
 		}
 
@@ -201,6 +213,17 @@ func (s *Schema) generateCreationQueries(ctx context.Context, dbAlias, tableName
 		}
 	}
 
+	indexQueries, err := s.generateIndexQueries(ctx, dbType, dbAlias, logicalDBName, tableName, realIndexMap, currentIndexMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(batchedQueries, indexQueries...), nil
+}
+
+// generateIndexQueries returns the queries required to bring the current indexes in sync with the real ones
+func (s *Schema) generateIndexQueries(ctx context.Context, dbType, dbAlias, logicalDBName, tableName string, realIndexMap, currentIndexMap map[string]*indexStruct) ([]string, error) {
+	batchedQueries := []string{}
 	for indexName, currentFields := range currentIndexMap {
 		if _, ok := realIndexMap[indexName]; !ok {
 			batchedQueries = append(batchedQueries, s.removeIndex(dbType, dbAlias, logicalDBName, tableName, currentFields.IndexName))
@@ -208,16 +231,23 @@ func (s *Schema) generateCreationQueries(ctx context.Context, dbAlias, tableName
 	}
 
 	for indexName, fields := range realIndexMap {
-		if _, ok := currentIndexMap[indexName]; !ok {
-			batchedQueries = append(batchedQueries, s.addIndex(dbType, dbAlias, logicalDBName, tableName, indexName, fields.IsIndexUnique, fields.IndexTableProperties))
-			continue
+		if currentFields, ok := currentIndexMap[indexName]; ok {
+			if arr := deep.Equal(fields.IndexTableProperties, cleanIndexMap(currentFields.IndexTableProperties)); len(arr) == 0 {
+				continue
+			}
+			batchedQueries = append(batchedQueries, s.removeIndex(dbType, dbAlias, logicalDBName, tableName, currentFields.IndexName))
 		}
-		if arr := deep.Equal(fields.IndexTableProperties, cleanIndexMap(currentIndexMap[indexName].IndexTableProperties)); len(arr) > 0 {
-			batchedQueries = append(batchedQueries, s.removeIndex(dbType, dbAlias, logicalDBName, tableName, currentIndexMap[indexName].IndexName))
-			batchedQueries = append(batchedQueries, s.addIndex(dbType, dbAlias, logicalDBName, tableName, indexName, fields.IsIndexUnique, fields.IndexTableProperties))
+
+		if fields.IsIndexFullText {
+			query, err := s.addFullTextIndex(ctx, dbType, logicalDBName, tableName, indexName, fields.IndexTableProperties)
+			if err != nil {
+				return nil, err
+			}
+			batchedQueries = append(batchedQueries, query)
+			continue
 		}
+		batchedQueries = append(batchedQueries, s.addIndex(dbType, dbAlias, logicalDBName, tableName, indexName, fields.IsIndexUnique, fields.IndexTableProperties))
 	}
-
 	return batchedQueries, nil
 }
 
@@ -228,8 +258,11 @@ func cleanIndexMap(v []*model.TableProperties) []*model.TableProperties {
 	return v
 }
 
-// SchemaModifyAll modifies all the tables provided
+// SchemaModifyAll modifies all the tables provided. Each modification gets recorded as a schema migration.
 func (s *Schema) SchemaModifyAll(ctx context.Context, dbAlias, logicalDBName string, dbSchemas config.DatabaseSchemas) error {
+	s.migrationLock.Lock()
+	defer s.migrationLock.Unlock()
+
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
@@ -241,7 +274,14 @@ func (s *Schema) SchemaModifyAll(ctx context.Context, dbAlias, logicalDBName str
 		if dbSchema.Schema == "" {
 			continue
 		}
-		if err := s.SchemaCreation(ctx, dbAlias, dbSchema.Table, logicalDBName, parsedSchema); err != nil {
+		migration, err := s.planMigration(ctx, dbAlias, logicalDBName, dbSchema, parsedSchema)
+		if err != nil {
+			return err
+		}
+		if migration == nil || len(migration.Up) == 0 {
+			continue
+		}
+		if err := s.applyNewMigration(ctx, dbAlias, logicalDBName, migration); err != nil {
 			return err
 		}
 	}
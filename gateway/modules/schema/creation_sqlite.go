@@ -0,0 +1,255 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-test/deep"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// generateSQLiteCreationQueries generates the queries to create or alter a sqlite table. SQLite doesn't support altering
+// or adding constraints on existing columns. Hence the table gets rebuilt (rename, create, copy & drop) whenever the columns
+// of a table get modified.
+func (s *Schema) generateSQLiteCreationQueries(ctx context.Context, dbAlias, tableName, logicalDBName string, parsedSchema model.Type, realTableInfo model.Fields, currentSchema model.Collection) ([]string, error) {
+	jointTableQueries := []string{}
+	for _, realColumnInfo := range realTableInfo {
+		if realColumnInfo.IsLinked {
+			continue
+		}
+		if err := checkErrors(ctx, realColumnInfo); err != nil {
+			return nil, err
+		}
+
+		// Create the joint table first
+		if realColumnInfo.IsForeign && realColumnInfo.JointTable.Table != tableName {
+			if _, p := currentSchema[realColumnInfo.JointTable.Table]; !p {
+				queries, err := s.planTableCreation(ctx, dbAlias, string(model.SQLite), realColumnInfo.JointTable.Table, logicalDBName, parsedSchema)
+				if err != nil {
+					return nil, err
+				}
+				jointTableQueries = append(jointTableQueries, queries...)
+			}
+		}
+	}
+
+	queries, err := s.generateSQLiteTableQueries(ctx, dbAlias, tableName, logicalDBName, realTableInfo, currentSchema)
+	if err != nil {
+		return nil, err
+	}
+	return append(jointTableQueries, queries...), nil
+}
+
+func (s *Schema) generateSQLiteTableQueries(ctx context.Context, dbAlias, tableName, logicalDBName string, realTableInfo model.Fields, currentSchema model.Collection) ([]string, error) {
+	dbType := string(model.SQLite)
+
+	realIndexMap, err := getIndexMap(ctx, realTableInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	currentTableInfo, ok := currentSchema[tableName]
+	if !ok {
+		query, err := s.addNewSQLiteTable(ctx, dbAlias, tableName, realTableInfo)
+		if err != nil {
+			return nil, err
+		}
+		indexQueries, err := s.generateIndexQueries(ctx, dbType, dbAlias, logicalDBName, tableName, realIndexMap, map[string]*indexStruct{})
+		if err != nil {
+			return nil, err
+		}
+		return append([]string{query}, indexQueries...), nil
+	}
+
+	currentIndexMap, err := getIndexMap(ctx, currentTableInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	isModified, err := isSQLiteTableModified(ctx, realTableInfo, currentTableInfo)
+	if err != nil {
+		return nil, err
+	}
+	if !isModified {
+		return s.generateIndexQueries(ctx, dbType, dbAlias, logicalDBName, tableName, realIndexMap, currentIndexMap)
+	}
+
+	// Only the columns present in both the old & new table can be copied over
+	commonColumns := make([]string, 0)
+	for columnName, realColumnInfo := range realTableInfo {
+		if realColumnInfo.IsLinked {
+			continue
+		}
+		if currentColumnInfo, p := currentTableInfo[columnName]; p && !currentColumnInfo.IsLinked {
+			commonColumns = append(commonColumns, columnName)
+		}
+	}
+	sort.Strings(commonColumns)
+
+	createQuery, err := s.addNewSQLiteTable(ctx, dbAlias, tableName, realTableInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Legacy alter table prevents sqlite from rewriting the references of other tables to the renamed table
+	oldTableName := "sc_old__" + tableName
+	batchedQueries := []string{
+		"PRAGMA legacy_alter_table = ON",
+		"ALTER TABLE " + tableName + " RENAME TO " + oldTableName,
+		createQuery,
+	}
+	if len(commonColumns) > 0 {
+		columns := strings.Join(commonColumns, ", ")
+		batchedQueries = append(batchedQueries, "INSERT INTO "+tableName+" ("+columns+") SELECT "+columns+" FROM "+oldTableName)
+	}
+	batchedQueries = append(batchedQueries, "DROP TABLE "+oldTableName, "PRAGMA legacy_alter_table = OFF")
+
+	// The indexes get dropped along with the old table
+	indexQueries, err := s.generateIndexQueries(ctx, dbType, dbAlias, logicalDBName, tableName, realIndexMap, map[string]*indexStruct{})
+	if err != nil {
+		return nil, err
+	}
+	return append(batchedQueries, indexQueries...), nil
+}
+
+// isSQLiteTableModified checks if the columns of a sqlite table need to be changed
+func isSQLiteTableModified(ctx context.Context, realTableInfo, currentTableInfo model.Fields) (bool, error) {
+	isModified := false
+	for columnName, currentColumnInfo := range currentTableInfo {
+		if currentColumnInfo.IsLinked {
+			continue
+		}
+		realColumnInfo, ok := realTableInfo[columnName]
+		if !ok || realColumnInfo.IsLinked {
+			if currentColumnInfo.IsPrimary {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Field (%s) with primary key cannot be removed, Delete the table to change primary key", columnName), nil, nil)
+			}
+			isModified = true
+		}
+	}
+
+	for columnName, realColumnInfo := range realTableInfo {
+		if realColumnInfo.IsLinked {
+			continue
+		}
+		currentColumnInfo, ok := currentTableInfo[columnName]
+		if !ok || currentColumnInfo.IsLinked {
+			if realColumnInfo.IsPrimary {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf(`Cannot add primary key on field ("%s") of an existing table, Delete the table to change primary key`, columnName), nil, nil)
+			}
+			isModified = true
+			continue
+		}
+
+		if realColumnInfo.IsPrimary != currentColumnInfo.IsPrimary {
+			return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf(`Cannot change primary constraint on field ("%s"), Delete the table to change primary key`, columnName), nil, nil)
+		}
+
+		arr := deep.Equal(realColumnInfo.Args, currentColumnInfo.Args)
+		if realColumnInfo.Kind != currentColumnInfo.Kind || realColumnInfo.TypeIDSize != currentColumnInfo.TypeIDSize || (currentColumnInfo.Args != nil && len(arr) > 0) {
+			if realColumnInfo.IsPrimary {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf(`Cannot change type of field ("%s") primary key exists, Delete the table to change primary key`, columnName), nil, nil)
+			}
+			isModified = true
+		}
+
+		if realColumnInfo.IsFieldTypeRequired != currentColumnInfo.IsFieldTypeRequired || realColumnInfo.IsAutoIncrement != currentColumnInfo.IsAutoIncrement {
+			isModified = true
+		}
+
+		if realColumnInfo.IsDefault != currentColumnInfo.IsDefault || (realColumnInfo.IsDefault && fmt.Sprintf("%v", realColumnInfo.Default) != fmt.Sprintf("%v", currentColumnInfo.Default)) {
+			isModified = true
+		}
+
+		if realColumnInfo.IsForeign != currentColumnInfo.IsForeign {
+			isModified = true
+		} else if realColumnInfo.IsForeign {
+			realJointTable, currentJointTable := realColumnInfo.JointTable, currentColumnInfo.JointTable
+			if currentJointTable == nil || realJointTable.Table != currentJointTable.Table || realJointTable.To != currentJointTable.To || realJointTable.OnDelete != currentJointTable.OnDelete {
+				isModified = true
+			}
+		}
+	}
+	return isModified, nil
+}
+
+// addNewSQLiteTable generates the create table query for sqlite. All the constraints are specified inline since they
+// cannot be added to an existing table
+func (s *Schema) addNewSQLiteTable(ctx context.Context, dbAlias, tableName string, realTableInfo model.Fields) (string, error) {
+	dbType := string(model.SQLite)
+
+	columnNames := make([]string, 0)
+	for columnName, columnInfo := range realTableInfo {
+		// Ignore linked fields since these are virtual fields
+		if !columnInfo.IsLinked {
+			columnNames = append(columnNames, columnName)
+		}
+	}
+	sort.Strings(columnNames)
+
+	columns := make([]string, 0)
+	constraints := make([]string, 0)
+	compositePrimaryKeys := make(primaryKeyStore, 0)
+	hasAutoIncrement := false
+	for _, columnName := range columnNames {
+		columnInfo := realTableInfo[columnName]
+		if err := checkErrors(ctx, columnInfo); err != nil {
+			return "", err
+		}
+		sqlType, err := getSQLType(ctx, dbType, columnInfo)
+		if err != nil {
+			return "", err
+		}
+
+		// SQLite only supports auto increment on a column declared as integer primary key
+		if columnInfo.IsPrimary && columnInfo.IsAutoIncrement {
+			switch columnInfo.Kind {
+			case model.TypeInteger, model.TypeBigInteger, model.TypeSmallInteger:
+			default:
+				return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot add autoIncrement constraint on non integer column (%s)", columnName), nil, nil)
+			}
+			columns = append(columns, columnName+" integer NOT NULL PRIMARY KEY AUTOINCREMENT")
+			hasAutoIncrement = true
+			continue
+		}
+
+		column := columnName + " " + sqlType
+		if columnInfo.IsFieldTypeRequired || columnInfo.IsPrimary {
+			column += " NOT NULL"
+		}
+		if columnInfo.IsDefault {
+			c := creationModule{dbAlias: dbAlias, schemaModule: s, realColumnInfo: columnInfo}
+			column += " DEFAULT " + c.typeSwitch()
+		}
+		columns = append(columns, column)
+
+		if columnInfo.IsPrimary {
+			compositePrimaryKeys = append(compositePrimaryKeys, columnInfo)
+		}
+
+		if columnInfo.IsForeign {
+			constraint := "CONSTRAINT " + columnInfo.JointTable.ConstraintName + " FOREIGN KEY (" + columnName + ") REFERENCES " + columnInfo.JointTable.Table + " (" + columnInfo.JointTable.To + ")"
+			if columnInfo.JointTable.OnDelete == "CASCADE" {
+				constraint += " ON DELETE CASCADE"
+			}
+			constraints = append(constraints, constraint)
+		}
+	}
+
+	if hasAutoIncrement && len(compositePrimaryKeys) > 0 {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot use autoIncrement with a composite primary key in table (%s)", tableName), nil, nil)
+	}
+	if len(compositePrimaryKeys) > 0 {
+		primaryKeyQuery, err := getCompositePrimaryKeyQuery(ctx, compositePrimaryKeys)
+		if err != nil {
+			return "", err
+		}
+		constraints = append([]string{primaryKeyQuery}, constraints...)
+	}
+
+	return "CREATE TABLE " + tableName + " (" + strings.Join(append(columns, constraints...), ", ") + ");", nil
+}
@@ -0,0 +1,124 @@
+package schema
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud"
+	. "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+)
+
+func TestSchema_generateSQLiteCreationQueries(t *testing.T) {
+	crudSQLite := crud.Init()
+	crudSQLite.SetAdminManager(&admin.Manager{})
+	err := crudSQLite.SetConfig("test", config.DatabaseConfigs{config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseConfig, "sqlite"): &config.DatabaseConfig{DbAlias: "sqlite", Type: "sql-sqlite", Enabled: false}})
+	if err != nil {
+		t.Fatal("unable to initialize sqlite", err)
+	}
+
+	primaryField := func() *model.FieldType {
+		return &model.FieldType{FieldName: "id", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{Order: 1}, IsFieldTypeRequired: true}
+	}
+
+	tests := []struct {
+		name          string
+		parsedSchema  model.Type
+		currentSchema model.Collection
+		want          []string
+		wantErr       bool
+	}{
+		{
+			name: "create new table with default, foreign key & index",
+			parsedSchema: model.Type{"sqlite": model.Collection{
+				"table1": model.Fields{
+					"id":     primaryField(),
+					"name":   &model.FieldType{FieldName: "name", Kind: model.TypeString, IsFieldTypeRequired: true, IsDefault: true, Default: "foo", IndexInfo: []*model.TableProperties{{Field: "name", IsIndex: true, Group: "name", Order: 1, Sort: "asc"}}},
+					"parent": &model.FieldType{FieldName: "parent", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsForeign: true, JointTable: &model.TableProperties{Table: "table2", To: "id", ConstraintName: GetConstraintName("table1", "parent"), OnDelete: "CASCADE"}},
+				},
+			}},
+			currentSchema: model.Collection{"table2": model.Fields{"id": primaryField()}},
+			want: []string{
+				"CREATE TABLE table1 (id varchar(100) NOT NULL, name text NOT NULL DEFAULT 'foo', parent varchar(100), PRIMARY KEY (id), CONSTRAINT c_table1_parent FOREIGN KEY (parent) REFERENCES table2 (id) ON DELETE CASCADE);",
+				"CREATE INDEX index__table1__name ON table1 (name asc)",
+			},
+		},
+		{
+			name: "create new table with auto increment primary key",
+			parsedSchema: model.Type{"sqlite": model.Collection{
+				"table1": model.Fields{
+					"id": &model.FieldType{FieldName: "id", Kind: model.TypeInteger, IsPrimary: true, IsAutoIncrement: true, PrimaryKeyInfo: &model.TableProperties{Order: 1}, IsFieldTypeRequired: true},
+				},
+			}},
+			currentSchema: model.Collection{},
+			want:          []string{"CREATE TABLE table1 (id integer NOT NULL PRIMARY KEY AUTOINCREMENT);"},
+		},
+		{
+			name: "only index changes are applied when columns are unchanged",
+			parsedSchema: model.Type{"sqlite": model.Collection{
+				"table1": model.Fields{
+					"id":   primaryField(),
+					"name": &model.FieldType{FieldName: "name", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{Field: "name", IsUnique: true, Group: "name", Order: 1, Sort: "desc"}}},
+				},
+			}},
+			currentSchema: model.Collection{"table1": model.Fields{
+				"id":   primaryField(),
+				"name": &model.FieldType{FieldName: "name", Kind: model.TypeString},
+			}},
+			want: []string{"CREATE UNIQUE INDEX index__table1__name ON table1 (name desc)"},
+		},
+		{
+			name: "table is rebuilt when a column is modified",
+			parsedSchema: model.Type{"sqlite": model.Collection{
+				"table1": model.Fields{
+					"id":   primaryField(),
+					"age":  &model.FieldType{FieldName: "age", Kind: model.TypeInteger, IsFieldTypeRequired: true},
+					"name": &model.FieldType{FieldName: "name", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{Field: "name", IsIndex: true, Group: "name", Order: 1, Sort: "asc"}}},
+				},
+			}},
+			currentSchema: model.Collection{"table1": model.Fields{
+				"id":   primaryField(),
+				"age":  &model.FieldType{FieldName: "age", Kind: model.TypeInteger},
+				"old":  &model.FieldType{FieldName: "old", Kind: model.TypeBoolean},
+				"name": &model.FieldType{FieldName: "name", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{Field: "name", IsIndex: true, Group: "name", Order: 1, Sort: "asc", ConstraintName: "index__table1__name"}}},
+			}},
+			want: []string{
+				"PRAGMA legacy_alter_table = ON",
+				"ALTER TABLE table1 RENAME TO sc_old__table1",
+				"CREATE TABLE table1 (age integer NOT NULL, id varchar(100) NOT NULL, name text, PRIMARY KEY (id));",
+				"INSERT INTO table1 (age, id, name) SELECT age, id, name FROM sc_old__table1",
+				"DROP TABLE sc_old__table1",
+				"PRAGMA legacy_alter_table = OFF",
+				"CREATE INDEX index__table1__name ON table1 (name asc)",
+			},
+		},
+		{
+			name: "primary key cannot be removed",
+			parsedSchema: model.Type{"sqlite": model.Collection{
+				"table1": model.Fields{"name": &model.FieldType{FieldName: "name", Kind: model.TypeString}},
+			}},
+			currentSchema: model.Collection{"table1": model.Fields{
+				"id":   primaryField(),
+				"name": &model.FieldType{FieldName: "name", Kind: model.TypeString},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Schema{crud: crudSQLite, project: "test"}
+			got, err := s.generateCreationQueries(context.Background(), "sqlite", "table1", "test", tt.parsedSchema, tt.currentSchema)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Schema.generateCreationQueries() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Schema.generateCreationQueries()\n got = %v,\n want %v", got, tt.want)
+			}
+		})
+	}
+}
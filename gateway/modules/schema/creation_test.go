@@ -3250,6 +3250,84 @@ func TestSchema_generateCreationQueries(t *testing.T) {
 		},
 	}
 	var changingIndexKeyTestCases = []testGenerateCreationQueries{
+		{
+			name: "mysql adding fulltext index",
+			args: args{
+				dbAlias:       "mysql",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"mysql": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{Field: "col2", IsFullText: true, Group: "col2_fulltext", Order: 1, Sort: "asc"}}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeString}}},
+			},
+			fields:  fields{crud: crudMySQL, project: "test"},
+			want:    []string{"CREATE FULLTEXT INDEX index__table1__col2_fulltext ON table1 (col2)"},
+			wantErr: false,
+		},
+		{
+			name: "postgres adding fulltext index",
+			args: args{
+				dbAlias:       "postgres",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"postgres": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{Field: "col2", IsFullText: true, Group: "col2_fulltext", Order: 1, Sort: "asc"}}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeString}}},
+			},
+			fields:  fields{crud: crudPostgres, project: "test"},
+			want:    []string{"CREATE INDEX index__table1__col2_fulltext ON test.table1 USING GIN (to_tsvector('english', col2))"},
+			wantErr: false,
+		},
+		{
+			name: "mysql no queries generated when fulltext index exists",
+			args: args{
+				dbAlias:       "mysql",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"mysql": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{Field: "col2", IsFullText: true, Group: "col2_fulltext", Order: 1, Sort: "asc"}}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{Field: "col2", IsFullText: true, Group: "col2_fulltext", Order: 1, Sort: "asc", ConstraintName: "index__table1__col2_fulltext"}}}}},
+			},
+			fields:  fields{crud: crudMySQL, project: "test"},
+			want:    []string{},
+			wantErr: false,
+		},
+		{
+			name: "mysql removing fulltext index",
+			args: args{
+				dbAlias:       "mysql",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"mysql": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeString}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{Field: "col2", IsFullText: true, Group: "col2_fulltext", Order: 1, Sort: "asc", ConstraintName: "index__table1__col2_fulltext"}}}}},
+			},
+			fields:  fields{crud: crudMySQL, project: "test"},
+			want:    []string{"DROP INDEX index__table1__col2_fulltext ON table1"},
+			wantErr: false,
+		},
+		{
+			name: "sqlserver adding fulltext index",
+			args: args{
+				dbAlias:       "sqlserver",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"sqlserver": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeString, IndexInfo: []*model.TableProperties{{Field: "col2", IsFullText: true, Group: "col2_fulltext", Order: 1, Sort: "asc"}}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeString}}},
+			},
+			fields:  fields{crud: crudSQLServer, project: "test"},
+			want:    []string{},
+			wantErr: true,
+		},
+		{
+			name: "adding fulltext index on type integer",
+			args: args{
+				dbAlias:       "mysql",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"mysql": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeInteger, IndexInfo: []*model.TableProperties{{Field: "col2", IsFullText: true, Group: "col2_fulltext", Order: 1, Sort: "asc"}}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeInteger}}},
+			},
+			fields:  fields{crud: crudMySQL, project: "test"},
+			want:    []string{},
+			wantErr: true,
+		},
 		{
 			name: "adding index directive to type Json",
 			args: args{
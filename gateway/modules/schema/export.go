@@ -0,0 +1,281 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	// ExportFormatJSONSchema exports the schema of every table as a json schema document
+	ExportFormatJSONSchema = "jsonschema"
+	// ExportFormatOpenAPI exports the crud endpoints of every table as an openapi 3 spec
+	ExportFormatOpenAPI = "openapi"
+
+	jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+	openAPIVersion  = "3.0.3"
+)
+
+type jsonSchemaResponse struct {
+	DbAlias string                 `json:"dbAlias"`
+	Col     string                 `json:"col"`
+	Schema  map[string]interface{} `json:"schema"`
+}
+
+type exportTable struct {
+	dbAlias string
+	col     string
+	fields  model.Fields
+}
+
+// ExportSchemas returns the schemas of the tables as json schema documents or as an openapi spec of their crud endpoints
+// If * is provided for database or collection. It will export all the databases and collection
+func (s *Schema) ExportSchemas(ctx context.Context, dbAlias, col, format string) (interface{}, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	tables := make([]*exportTable, 0)
+	for alias, dbSchema := range s.SchemaDoc {
+		if dbAlias != "*" && dbAlias != alias {
+			continue
+		}
+		for table, fields := range dbSchema {
+			if col != "*" && col != table {
+				continue
+			}
+			tables = append(tables, &exportTable{dbAlias: alias, col: table, fields: fields})
+		}
+	}
+	if col != "*" && len(tables) == 0 {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Specified collection/table (%s) not present in config of dbAlias (%s)", col, dbAlias), nil, nil)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].dbAlias != tables[j].dbAlias {
+			return tables[i].dbAlias < tables[j].dbAlias
+		}
+		return tables[i].col < tables[j].col
+	})
+
+	switch format {
+	case ExportFormatJSONSchema:
+		arr := make([]interface{}, 0, len(tables))
+		for _, table := range tables {
+			schema := generateJSONSchema(table.col, table.fields)
+			schema["$schema"] = jsonSchemaDraft
+			arr = append(arr, jsonSchemaResponse{DbAlias: table.dbAlias, Col: table.col, Schema: schema})
+		}
+		return arr, nil
+	case ExportFormatOpenAPI:
+		return generateOpenAPISpec(s.project, tables), nil
+	default:
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid export format (%s) provided - wanted (%s) or (%s)", format, ExportFormatJSONSchema, ExportFormatOpenAPI), nil, nil)
+	}
+}
+
+// generateJSONSchema converts the fields of a table to a json schema document. Linked fields are skipped since they
+// aren't stored in the table, while fields populated by space cloud are marked read only and are never required
+func generateJSONSchema(title string, fields model.Fields) map[string]interface{} {
+	properties := make(map[string]interface{}, len(fields))
+	required := make([]string, 0)
+	for fieldName, field := range fields {
+		if field.IsLinked {
+			continue
+		}
+
+		properties[fieldName] = generateFieldJSONSchema(field)
+		if isFieldRequiredInRequest(field) {
+			required = append(required, fieldName)
+		}
+	}
+	sort.Strings(required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if title != "" {
+		schema["title"] = title
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func generateFieldJSONSchema(field *model.FieldType) map[string]interface{} {
+	var schema map[string]interface{}
+	switch field.Kind {
+	case model.TypeObject:
+		schema = generateJSONSchema("", field.NestedObject)
+	case model.TypeJSON:
+		schema = map[string]interface{}{"type": "object"}
+	case model.TypePoint, model.TypeGeometry:
+		// Geometries are provided as GeoJSON objects
+		schema = map[string]interface{}{"type": "object", "required": []string{"type", "coordinates"}, "properties": map[string]interface{}{
+			"type":        map[string]interface{}{"type": "string"},
+			"coordinates": map[string]interface{}{"type": "array"},
+		}}
+		if field.Kind == model.TypePoint {
+			schema["properties"].(map[string]interface{})["type"] = map[string]interface{}{"type": "string", "enum": []string{"Point"}}
+		}
+	case model.TypeInteger, model.TypeSmallInteger, model.TypeBigInteger:
+		schema = map[string]interface{}{"type": "integer"}
+	case model.TypeFloat, model.TypeDecimal:
+		schema = map[string]interface{}{"type": "number"}
+	case model.TypeBoolean:
+		schema = map[string]interface{}{"type": "boolean"}
+	case model.TypeDateTime, model.TypeDateTimeWithZone:
+		schema = map[string]interface{}{"type": "string", "format": "date-time"}
+	case model.TypeDate:
+		schema = map[string]interface{}{"type": "string", "format": "date"}
+	case model.TypeTime:
+		schema = map[string]interface{}{"type": "string", "format": "time"}
+	case model.TypeUUID:
+		schema = map[string]interface{}{"type": "string", "format": "uuid"}
+	case model.TypeID, model.TypeChar, model.TypeVarChar:
+		schema = map[string]interface{}{"type": "string"}
+		// A size of -1 denotes the maximum size allowed by the database
+		if field.TypeIDSize > 0 {
+			schema["maxLength"] = field.TypeIDSize
+		}
+	default:
+		schema = map[string]interface{}{"type": "string"}
+	}
+
+	if len(field.Enum) > 0 {
+		schema["enum"] = field.Enum
+	}
+	if field.IsDefault {
+		schema["default"] = field.Default
+	}
+	if field.IsCreatedAt || field.IsUpdatedAt || field.IsVersion || field.IsAutoIncrement {
+		schema["readOnly"] = true
+	}
+
+	if field.IsList {
+		schema = map[string]interface{}{"type": "array", "items": schema}
+	}
+	return schema
+}
+
+// isFieldRequiredInRequest mirrors the schema validator by not requiring the fields it can populate on its own
+func isFieldRequiredInRequest(field *model.FieldType) bool {
+	if !field.IsFieldTypeRequired || field.IsDefault || field.IsAutoIncrement || field.IsCreatedAt || field.IsUpdatedAt || field.IsVersion {
+		return false
+	}
+	if field.Kind == model.TypeID || (field.IsSoftDelete && field.Kind == model.TypeBoolean) {
+		return false
+	}
+	return true
+}
+
+// generateOpenAPISpec generates an openapi 3 spec for the crud endpoints of the tables provided
+func generateOpenAPISpec(project string, tables []*exportTable) map[string]interface{} {
+	schemas := map[string]interface{}{}
+	paths := map[string]interface{}{}
+	for _, table := range tables {
+		name := fmt.Sprintf("%s_%s", table.dbAlias, table.col)
+		schema := generateJSONSchema(table.col, table.fields)
+		schemas[name] = schema
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + name}
+		docs := map[string]interface{}{"oneOf": []interface{}{ref, map[string]interface{}{"type": "array", "items": ref}}}
+
+		// The fields of a table can be updated partially. Hence none of them are required in the update operators
+		setSchema := map[string]interface{}{"type": "object", "properties": schema["properties"]}
+		find := map[string]interface{}{"type": "object", "description": "The where clause to filter the rows on"}
+
+		prefix := fmt.Sprintf("/v1/api/%s/crud/%s/%s", project, table.dbAlias, table.col)
+		paths[prefix+"/create"] = openAPIOperation(table, "create", "Inserts rows in", map[string]interface{}{
+			"doc": docs,
+			"op":  map[string]interface{}{"type": "string", "enum": []string{utils.One, utils.All}},
+		}, []string{"doc", "op"}, nil)
+		paths[prefix+"/read"] = openAPIOperation(table, "read", "Reads rows from", map[string]interface{}{
+			"find":    find,
+			"op":      map[string]interface{}{"type": "string", "enum": []string{utils.One, utils.All, utils.Count, utils.Distinct}},
+			"options": map[string]interface{}{"type": "object", "description": "The select, sort, skip, limit and join options of the read"},
+		}, []string{"op"}, docs)
+		paths[prefix+"/update"] = openAPIOperation(table, "update", "Updates rows in", map[string]interface{}{
+			"find": find,
+			"op":   map[string]interface{}{"type": "string", "enum": []string{utils.One, utils.All, utils.Upsert}},
+			"update": map[string]interface{}{
+				"type":        "object",
+				"description": "The update operators to apply",
+				"properties":  map[string]interface{}{"$set": setSchema},
+			},
+		}, []string{"op", "update"}, nil)
+		paths[prefix+"/delete"] = openAPIOperation(table, "delete", "Deletes rows from", map[string]interface{}{
+			"find": find,
+			"op":   map[string]interface{}{"type": "string", "enum": []string{utils.One, utils.All}},
+		}, []string{"op"}, nil)
+		paths[prefix+"/aggr"] = openAPIOperation(table, "aggr", "Runs an aggregation pipeline on", map[string]interface{}{
+			"pipe": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}},
+			"op":   map[string]interface{}{"type": "string", "enum": []string{utils.One, utils.All}},
+		}, []string{"pipe", "op"}, map[string]interface{}{})
+	}
+
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":   fmt.Sprintf("Crud API of project %s", project),
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"responses": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"description": "The request could not be processed",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"type":       "object",
+								"properties": map[string]interface{}{"error": map[string]interface{}{"type": "string"}},
+							},
+						},
+					},
+				},
+			},
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+		"security": []interface{}{map[string]interface{}{"bearerAuth": []string{}}},
+	}
+}
+
+// openAPIOperation generates the path item of a crud endpoint. The result schema is only added for endpoints returning rows
+func openAPIOperation(table *exportTable, operation, summary string, properties map[string]interface{}, required []string, result map[string]interface{}) map[string]interface{} {
+	response := map[string]interface{}{"description": "The operation was successful"}
+	if result != nil {
+		response["content"] = map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"result": result},
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"operationId": fmt.Sprintf("%s_%s_%s", operation, table.dbAlias, table.col),
+			"summary":     fmt.Sprintf("%s table %s of database %s", summary, table.col, table.dbAlias),
+			"tags":        []string{table.dbAlias},
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{"type": "object", "properties": properties, "required": required},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200":     response,
+				"default": map[string]interface{}{"$ref": "#/components/responses/Error"},
+			},
+		},
+	}
+}
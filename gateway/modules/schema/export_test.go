@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func TestSchema_ExportSchemas(t *testing.T) {
+	const usersSchema = `type users {
+		id: ID! @primary
+		email: Varchar! @size(value: 50)
+		role: String! @enum(values: ["admin", "user"]) @default(value: "user")
+		age: Integer
+		tags: [String]
+		createdAt: DateTime! @createdAt
+		address: address
+	}
+	type address {
+		city: String!
+	}`
+
+	tests := []struct {
+		name    string
+		dbAlias string
+		col     string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "json schema of a table",
+			dbAlias: "db",
+			col:     "users",
+			format:  ExportFormatJSONSchema,
+			want: `[{"dbAlias":"db","col":"users","schema":{"$schema":"http://json-schema.org/draft-07/schema#","properties":{` +
+				`"address":{"properties":{"city":{"type":"string"}},"required":["city"],"type":"object"},` +
+				`"age":{"type":"integer"},` +
+				`"createdAt":{"format":"date-time","readOnly":true,"type":"string"},` +
+				`"email":{"maxLength":50,"type":"string"},` +
+				`"id":{"maxLength":100,"type":"string"},` +
+				`"role":{"default":"user","enum":["admin","user"],"type":"string"},` +
+				`"tags":{"items":{"type":"string"},"type":"array"}},` +
+				`"required":["email"],"title":"users","type":"object"}}]`,
+		},
+		{
+			name:    "unknown table",
+			dbAlias: "db",
+			col:     "posts",
+			format:  ExportFormatJSONSchema,
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			dbAlias: "*",
+			col:     "*",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Init("chicago", &mockCrudSchemaInterface{})
+			if err := s.SetDatabaseSchema(config.DatabaseSchemas{"users": &config.DatabaseSchema{Table: "users", DbAlias: "db", Schema: usersSchema}}, "myproject"); err != nil {
+				t.Fatalf("SetDatabaseSchema() error = %v", err)
+			}
+
+			got, err := s.ExportSchemas(context.Background(), tt.dbAlias, tt.col, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExportSchemas() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			data, _ := json.Marshal(got)
+			if string(data) != tt.want {
+				t.Errorf("ExportSchemas() got = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchema_ExportSchemas_OpenAPI(t *testing.T) {
+	s := Init("chicago", &mockCrudSchemaInterface{})
+	if err := s.SetDatabaseSchema(config.DatabaseSchemas{"users": &config.DatabaseSchema{Table: "users", DbAlias: "db", Schema: `type users { id: ID! @primary name: String! }`}}, "myproject"); err != nil {
+		t.Fatalf("SetDatabaseSchema() error = %v", err)
+	}
+
+	got, err := s.ExportSchemas(context.Background(), "*", "*", ExportFormatOpenAPI)
+	if err != nil {
+		t.Fatalf("ExportSchemas() error = %v", err)
+	}
+	spec := got.(map[string]interface{})
+
+	paths := spec["paths"].(map[string]interface{})
+	for _, op := range []string{"create", "read", "update", "delete", "aggr"} {
+		if _, ok := paths["/v1/api/myproject/crud/db/users/"+op]; !ok {
+			t.Errorf("ExportSchemas() path for operation (%s) not present in spec", op)
+		}
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	data, _ := json.Marshal(schemas["db_users"])
+	want := `{"properties":{"id":{"maxLength":100,"type":"string"},"name":{"type":"string"}},"required":["name"],"title":"users","type":"object"}`
+	if string(data) != want {
+		t.Errorf("ExportSchemas() got component = %s, want %s", data, want)
+	}
+}
@@ -21,6 +21,9 @@ func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldT
 		}
 		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "UUID type is only supported by postgres database", nil, nil)
 	case model.TypeTime:
+		if dbType == string(model.SQLite) {
+			return "time", nil
+		}
 		return fmt.Sprintf("time(%d)", realColumnInfo.Args.Precision), nil
 	case model.TypeDate:
 		return "date", nil
@@ -35,6 +38,11 @@ func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldT
 			return fmt.Sprintf("char(%d)", realColumnInfo.TypeIDSize), nil
 		case string(model.SQLServer):
 			return fmt.Sprintf("nchar(%d)", realColumnInfo.TypeIDSize), nil
+		case string(model.SQLite):
+			if realColumnInfo.TypeIDSize == -1 {
+				return "character", nil
+			}
+			return fmt.Sprintf("character(%d)", realColumnInfo.TypeIDSize), nil
 		}
 	case model.TypeVarChar, model.TypeID:
 		switch dbType {
@@ -47,6 +55,11 @@ func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldT
 			return fmt.Sprintf("varchar(%d)", realColumnInfo.TypeIDSize), nil
 		case string(model.SQLServer):
 			return fmt.Sprintf("nvarchar(%d)", realColumnInfo.TypeIDSize), nil
+		case string(model.SQLite):
+			if realColumnInfo.TypeIDSize == -1 {
+				return "varchar", nil
+			}
+			return fmt.Sprintf("varchar(%d)", realColumnInfo.TypeIDSize), nil
 		}
 	case model.TypeString:
 		switch dbType {
@@ -56,6 +69,8 @@ func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldT
 			return "longtext", nil
 		case string(model.SQLServer):
 			return "nvarchar(max)", nil
+		case string(model.SQLite):
+			return "text", nil
 		}
 	case model.TypeDateTime:
 		switch dbType {
@@ -65,6 +80,9 @@ func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldT
 			return fmt.Sprintf("datetime2(%d)", realColumnInfo.Args.Precision), nil
 		case string(model.Postgres):
 			return fmt.Sprintf("timestamp(%d) without time zone", realColumnInfo.Args.Precision), nil
+		case string(model.SQLite):
+			// The sqlite driver only parses columns declared exactly as datetime or timestamp
+			return "datetime", nil
 		}
 	case model.TypeDateTimeWithZone:
 		switch dbType {
@@ -74,6 +92,8 @@ func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldT
 			return fmt.Sprintf("datetimeoffset(%d)", realColumnInfo.Args.Precision), nil
 		case string(model.Postgres):
 			return fmt.Sprintf("timestamp(%d) with time zone", realColumnInfo.Args.Precision), nil
+		case string(model.SQLite):
+			return "timestamp", nil
 		}
 	case model.TypeBoolean:
 		switch dbType {
@@ -83,6 +103,8 @@ func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldT
 			return "tinyint(1)", nil
 		case string(model.SQLServer):
 			return "bit", nil
+		case string(model.SQLite):
+			return "boolean", nil
 		default:
 			return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("json not supported for database %s", dbType), nil, nil)
 		}
@@ -94,10 +116,12 @@ func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldT
 			return "double", nil
 		case string(model.SQLServer):
 			return "float", nil
+		case string(model.SQLite):
+			return "real", nil
 		}
 	case model.TypeDecimal:
 		switch dbType {
-		case string(model.Postgres):
+		case string(model.Postgres), string(model.SQLite):
 			return fmt.Sprintf("numeric(%d,%d)", realColumnInfo.Args.Precision, realColumnInfo.Args.Scale), nil
 		case string(model.MySQL), string(model.SQLServer):
 			return fmt.Sprintf("decimal(%d,%d)", realColumnInfo.Args.Precision, realColumnInfo.Args.Scale), nil
@@ -112,11 +136,21 @@ func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldT
 		switch dbType {
 		case string(model.Postgres):
 			return "jsonb", nil
-		case string(model.MySQL):
+		case string(model.MySQL), string(model.SQLite):
 			return "json", nil
 		case string(model.SQLServer):
 			return "nvarchar(max)", nil
 		}
+	case model.TypePoint, model.TypeGeometry:
+		// Geometries are stored in the WGS 84 spatial reference system so that distances are computed in meters
+		switch dbType {
+		case string(model.Postgres):
+			return fmt.Sprintf("geography(%s,4326)", realColumnInfo.Kind), nil
+		case string(model.MySQL):
+			return fmt.Sprintf("%s SRID 4326", strings.ToLower(realColumnInfo.Kind)), nil
+		default:
+			return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("%s type is only supported by postgres and mysql databases", realColumnInfo.Kind), nil, nil)
+		}
 	default:
 		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid schema type (%s) provided", realColumnInfo.Kind), fmt.Errorf("%s type not allowed", realColumnInfo.Kind), nil)
 	}
@@ -153,6 +187,13 @@ func checkErrors(ctx context.Context, realFieldStruct *model.FieldType) error {
 				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("cannot set index on field (%s) having type json", realFieldStruct.FieldName), nil, nil)
 			}
 		}
+		if indexInfo.IsFullText {
+			switch realFieldStruct.Kind {
+			case model.TypeString, model.TypeVarChar, model.TypeChar, model.TypeID:
+			default:
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("cannot set fulltext index on field (%s) having type %s", realFieldStruct.FieldName, realFieldStruct.Kind), nil, nil)
+			}
+		}
 	}
 
 	return nil
@@ -547,7 +588,7 @@ func (c *creationModule) removeDirectives(dbType string) []string {
 	// }
 
 	for _, indexInfo := range c.currentColumnInfo.IndexInfo {
-		if indexInfo.IsIndex || indexInfo.IsUnique {
+		if indexInfo.IsIndex || indexInfo.IsUnique || indexInfo.IsFullText {
 			if _, p := c.currentIndexMap[indexInfo.Group]; p {
 				queries = append(queries, c.schemaModule.removeIndex(dbType, c.dbAlias, c.logicalDBName, c.TableName, indexInfo.ConstraintName))
 				delete(c.currentIndexMap, indexInfo.Group)
@@ -586,6 +627,18 @@ func (s *Schema) addIndex(dbType, dbAlias, logicalDBName, tableName, indexName s
 	return p
 }
 
+// addFullTextIndex generates the query to create a full text index on a single column
+func (s *Schema) addFullTextIndex(ctx context.Context, dbType, logicalDBName, tableName, indexName string, mapArray []*model.TableProperties) (string, error) {
+	column := mapArray[0].Field
+	switch model.DBType(dbType) {
+	case model.Postgres:
+		return "CREATE INDEX " + getIndexName(tableName, indexName) + " ON " + s.getTableName(dbType, logicalDBName, tableName) + " USING GIN (to_tsvector('english', " + column + "))", nil
+	case model.MySQL:
+		return "CREATE FULLTEXT INDEX " + getIndexName(tableName, indexName) + " ON " + s.getTableName(dbType, logicalDBName, tableName) + " (" + column + ")", nil
+	}
+	return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Full text index is not supported for database (%s)", dbType), nil, nil)
+}
+
 func (s *Schema) removeIndex(dbType, dbAlias, logicalDBName, tableName, indexName string) string {
 
 	switch model.DBType(dbType) {
@@ -596,6 +649,8 @@ func (s *Schema) removeIndex(dbType, dbAlias, logicalDBName, tableName, indexNam
 	case model.Postgres:
 		indexname := indexName
 		return "DROP INDEX " + s.getTableName(dbType, logicalDBName, indexname)
+	case model.SQLite:
+		return "DROP INDEX " + indexName
 	}
 	return ""
 }
@@ -606,6 +661,7 @@ func getIndexName(tableName, indexName string) string {
 
 type indexStruct struct {
 	IsIndexUnique        bool
+	IsIndexFullText      bool
 	IndexTableProperties []*model.TableProperties
 	IndexName            string
 }
@@ -618,7 +674,7 @@ func getIndexMap(ctx context.Context, tableInfo model.Fields) (map[string]*index
 
 		for _, indexInfo := range columnInfo.IndexInfo {
 			// We are only interested in the columns which have an index on them
-			if indexInfo.IsIndex || indexInfo.IsUnique {
+			if indexInfo.IsIndex || indexInfo.IsUnique || indexInfo.IsFullText {
 				// Append the column to te index map. Make sure we create an empty array if no index by the provided name exists
 				value, ok := indexMap[indexInfo.Group]
 				if !ok {
@@ -632,6 +688,9 @@ func getIndexMap(ctx context.Context, tableInfo model.Fields) (map[string]*index
 				if indexInfo.IsUnique {
 					indexMap[indexInfo.Group].IsIndexUnique = true
 				}
+				if indexInfo.IsFullText {
+					indexMap[indexInfo.Group].IsIndexFullText = true
+				}
 			}
 		}
 	}
@@ -640,6 +699,9 @@ func getIndexMap(ctx context.Context, tableInfo model.Fields) (map[string]*index
 		var v indexStore = indexValue.IndexTableProperties
 		sort.Stable(v)
 		indexValue.IndexTableProperties = v
+		if indexValue.IsIndexFullText && len(indexValue.IndexTableProperties) > 1 {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Full text index (%s) can only be created on a single column", indexName), nil, nil)
+		}
 		for i, column := range indexValue.IndexTableProperties {
 			if i+1 != column.Order {
 				return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid order sequence proveded for index (%s)", indexName), nil, nil)
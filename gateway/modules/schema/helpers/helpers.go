@@ -16,13 +16,26 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
+// normaliseDateTime converts the timestamps stored in embedded databases to UTC. Embedded databases store timestamps as
+// strings, hence timestamps with different offsets can't be compared otherwise
+func normaliseDateTime(dbType string, t time.Time) time.Time {
+	if model.DBType(dbType) == model.EmbeddedDB {
+		return t.UTC()
+	}
+	return t
+}
+
 func checkType(ctx context.Context, dbAlias, dbType, col string, value interface{}, fieldValue *model.FieldType) (interface{}, error) {
+	if _, isList := value.([]interface{}); !isList && value != nil && len(fieldValue.Enum) > 0 && !isEnumValue(value, fieldValue.Enum) {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid value (%v) received for field %s in collection %s - wanted one of %v", value, fieldValue.FieldName, col, fieldValue.Enum), nil, nil)
+	}
+
 	switch v := value.(type) {
 	case int:
 		// TODO: int64
 		switch fieldValue.Kind {
 		case model.TypeDateTime, model.TypeDateTimeWithZone:
-			return time.Unix(int64(v)/1000, 0), nil
+			return normaliseDateTime(dbType, time.Unix(int64(v)/1000, 0)), nil
 		case model.TypeInteger, model.TypeBigInteger, model.TypeSmallInteger:
 			return value, nil
 		case model.TypeFloat, model.TypeDecimal:
@@ -38,7 +51,7 @@ func checkType(ctx context.Context, dbAlias, dbType, col string, value interface
 			if err != nil {
 				return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid datetime format recieved for field %s in collection %s - use RFC3339 fromat", fieldValue.FieldName, col), err, nil)
 			}
-			return unitTimeInRFC3339Nano, nil
+			return normaliseDateTime(dbType, unitTimeInRFC3339Nano), nil
 		case model.TypeID, model.TypeString, model.TypeTime, model.TypeDate, model.TypeUUID, model.TypeVarChar, model.TypeChar:
 			return value, nil
 		default:
@@ -48,7 +61,7 @@ func checkType(ctx context.Context, dbAlias, dbType, col string, value interface
 	case float32, float64:
 		switch fieldValue.Kind {
 		case model.TypeDateTime, model.TypeDateTimeWithZone:
-			return time.Unix(int64(v.(float64))/1000, 0), nil
+			return normaliseDateTime(dbType, time.Unix(int64(v.(float64))/1000, 0)), nil
 		case model.TypeFloat, model.TypeDecimal:
 			return value, nil
 		case model.TypeInteger, model.TypeSmallInteger, model.TypeBigInteger:
@@ -68,6 +81,9 @@ func checkType(ctx context.Context, dbAlias, dbType, col string, value interface
 		return v, nil
 
 	case map[string]interface{}:
+		if fieldValue.Kind == model.TypePoint || fieldValue.Kind == model.TypeGeometry {
+			return checkGeoType(ctx, dbType, col, v, fieldValue)
+		}
 		if fieldValue.Kind == model.TypeJSON {
 			if model.DBType(dbType) == model.Mongo {
 				return value, nil
@@ -107,6 +123,40 @@ func checkType(ctx context.Context, dbAlias, dbType, col string, value interface
 	}
 }
 
+// checkGeoType validates a GeoJSON geometry and converts it to the format stored by the database. Mongo stores GeoJSON
+// as is while the sql databases are provided the WKB representation of the geometry
+func checkGeoType(ctx context.Context, dbType, col string, value map[string]interface{}, fieldValue *model.FieldType) (interface{}, error) {
+	geo, err := utils.ValidateGeoJSON(value)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid geometry received for field %s in collection %s", fieldValue.FieldName, col), err, nil)
+	}
+	if fieldValue.Kind == model.TypePoint && geo["type"] != "Point" {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid geometry (%v) received for field %s in collection %s - wanted Point", geo["type"], fieldValue.FieldName, col), nil, nil)
+	}
+
+	switch model.DBType(dbType) {
+	case model.Mongo:
+		return geo, nil
+	case model.Postgres:
+		return utils.GeoJSONToEWKB(geo)
+	case model.MySQL:
+		return utils.GeoJSONToMySQLGeometry(geo)
+	default:
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("geo types are not supported for database (%s)", dbType), nil, nil)
+	}
+}
+
+// isEnumValue checks if the value is one of the values allowed by the enum directive. Values are compared
+// by their string representation since numbers received in json and in the schema can be of different types
+func isEnumValue(value interface{}, enum []interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprintf("%v", allowed) == fmt.Sprintf("%v", value) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateArrayOperations(ctx context.Context, dbAlias, dbType, col string, doc interface{}, SchemaDoc model.Fields) error {
 
 	v, ok := doc.(map[string]interface{})
@@ -219,7 +269,7 @@ func validateUnsetOperation(ctx context.Context, dbType, col string, doc interfa
 		return nil
 	}
 
-	if dbType == string(model.Postgres) || dbType == string(model.MySQL) || dbType == string(model.SQLServer) {
+	if dbType == string(model.Postgres) || dbType == string(model.MySQL) || dbType == string(model.SQLServer) || dbType == string(model.SQLite) {
 		for fieldName := range v {
 			columnInfo, ok := schemaDoc[strings.Split(fieldName, ".")[0]]
 			if ok {
@@ -356,6 +406,29 @@ func getCollectionSchema(doc *ast.Document, dbName, collectionName string) (mode
 						fieldTypeStuct.IsCreatedAt = true
 					case model.DirectiveUpdatedAt:
 						fieldTypeStuct.IsUpdatedAt = true
+					case model.DirectiveVersion:
+						fieldTypeStuct.IsVersion = true
+					case model.DirectiveSoftDelete:
+						fieldTypeStuct.IsSoftDelete = true
+					case model.DirectiveExpiresAt:
+						var expireAfter int64
+						fieldTypeStuct.ExpireAfter = &expireAfter
+					case model.DirectiveTTL:
+						for _, arg := range directive.Arguments {
+							switch arg.Name.Value {
+							case "seconds":
+								val, _ := utils.ParseGraphqlValue(arg.Value, nil)
+								seconds, ok := val.(int)
+								if !ok || seconds <= 0 {
+									return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Unexpected argument provided for field (%s) directive @(%s) argument (%s) got (%v) expected a positive integer", fieldTypeStuct.FieldName, directive.Name.Value, arg.Name.Value, val), nil, map[string]interface{}{"arg": arg.Name.Value})
+								}
+								expireAfter := int64(seconds)
+								fieldTypeStuct.ExpireAfter = &expireAfter
+							}
+						}
+						if fieldTypeStuct.ExpireAfter == nil {
+							return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "TTL directive must be accompanied with seconds field", nil, nil)
+						}
 					case model.DirectiveStringSize:
 						for _, arg := range directive.Arguments {
 							switch arg.Name.Value {
@@ -388,6 +461,21 @@ func getCollectionSchema(doc *ast.Document, dbName, collectionName string) (mode
 						if fieldTypeStuct.Default == nil {
 							return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Default directive must be accompanied with value field", nil, nil)
 						}
+					case model.DirectiveEnum:
+						for _, arg := range directive.Arguments {
+							switch arg.Name.Value {
+							case "values":
+								val, _ := utils.ParseGraphqlValue(arg.Value, nil)
+								values, ok := val.([]interface{})
+								if !ok {
+									return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Unexpected argument type provided for field (%s) directive @(%s) argument (%s) got (%v) expected array", fieldTypeStuct.FieldName, directive.Name.Value, arg.Name.Value, reflect.TypeOf(val)), nil, map[string]interface{}{"arg": arg.Name.Value})
+								}
+								fieldTypeStuct.Enum = values
+							}
+						}
+						if len(fieldTypeStuct.Enum) == 0 {
+							return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Enum directive must be accompanied with a non empty values field", nil, nil)
+						}
 					case model.DirectiveIndex, model.DirectiveUnique:
 						if fieldTypeStuct.IndexInfo == nil {
 							fieldTypeStuct.IndexInfo = make([]*model.TableProperties, 0)
@@ -426,6 +514,25 @@ func getCollectionSchema(doc *ast.Document, dbName, collectionName string) (mode
 						indexInfo.Field = field.Name.Value
 						fieldTypeStuct.IndexInfo = append(fieldTypeStuct.IndexInfo, indexInfo)
 
+					case model.DirectiveFullText:
+						if fieldTypeStuct.IndexInfo == nil {
+							fieldTypeStuct.IndexInfo = make([]*model.TableProperties, 0)
+						}
+						// Full text indexes are created on a single column. Hence the group defaults to a name which doesn't clash with other indexes
+						indexInfo := &model.TableProperties{Order: model.DefaultIndexOrder, Sort: model.DefaultIndexSort, IsFullText: true, Group: field.Name.Value + "_fulltext", Field: field.Name.Value}
+						for _, arg := range directive.Arguments {
+							switch arg.Name.Value {
+							case "name":
+								val, _ := utils.ParseGraphqlValue(arg.Value, nil)
+								group, ok := val.(string)
+								if !ok {
+									return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Unexpected argument type provided for field (%s) directive @(%s) argument (%s) got (%v) expected string", fieldTypeStuct.FieldName, directive.Name.Value, arg.Name.Value, reflect.TypeOf(val)), nil, map[string]interface{}{"arg": arg.Name.Value})
+								}
+								indexInfo.Group = group
+							}
+						}
+						fieldTypeStuct.IndexInfo = append(fieldTypeStuct.IndexInfo, indexInfo)
+
 					case model.DirectiveLink:
 						fieldTypeStuct.IsLinked = true
 						fieldTypeStuct.LinkedTable = &model.TableProperties{DBType: dbName}
@@ -525,6 +632,39 @@ func getCollectionSchema(doc *ast.Document, dbName, collectionName string) (mode
 					fieldTypeStuct.TypeIDSize = model.DefaultCharacterSize
 				}
 			}
+			if fieldTypeStuct.IsVersion {
+				switch kind {
+				case model.TypeInteger, model.TypeBigInteger:
+				default:
+					return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Version directive can only be added on field (%s) of type (%s) or (%s)", fieldTypeStuct.FieldName, model.TypeInteger, model.TypeBigInteger), nil, nil)
+				}
+				if fieldTypeStuct.IsList {
+					return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Version directive cannot be added on field (%s) with type lists", fieldTypeStuct.FieldName), nil, nil)
+				}
+			}
+			if fieldTypeStuct.IsSoftDelete {
+				switch kind {
+				case model.TypeDateTime, model.TypeDateTimeWithZone, model.TypeBoolean:
+				default:
+					return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Soft delete directive can only be added on field (%s) of type (%s), (%s) or (%s)", fieldTypeStuct.FieldName, model.TypeDateTime, model.TypeDateTimeWithZone, model.TypeBoolean), nil, nil)
+				}
+				if fieldTypeStuct.IsList || fieldTypeStuct.IsFieldTypeRequired && kind != model.TypeBoolean {
+					return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Soft delete directive cannot be added on field (%s) which is a list or a required timestamp", fieldTypeStuct.FieldName), nil, nil)
+				}
+			}
+			if fieldTypeStuct.ExpireAfter != nil {
+				switch kind {
+				case model.TypeDateTime, model.TypeDateTimeWithZone:
+				default:
+					return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("TTL directive can only be added on field (%s) of type (%s) or (%s)", fieldTypeStuct.FieldName, model.TypeDateTime, model.TypeDateTimeWithZone), nil, nil)
+				}
+				if fieldTypeStuct.IsList {
+					return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("TTL directive cannot be added on field (%s) with type lists", fieldTypeStuct.FieldName), nil, nil)
+				}
+				if ttlField, ok := GetTTLField(fieldMap); ok {
+					return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("TTL directive cannot be added on field (%s) as it is already added on field (%s) of table (%s)", fieldTypeStuct.FieldName, ttlField.FieldName, collectionName), nil, nil)
+				}
+			}
 			if _, ok := fieldMap[field.Name.Value]; ok {
 				return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Column (%s) already exists in the Collection/Table(%s). Duplicate column not allowed", field.Name.Value, collectionName), nil, nil)
 			}
@@ -588,6 +728,10 @@ func getFieldType(dbName string, fieldType ast.Type, fieldTypeStuct *model.Field
 			return model.TypeDate, nil
 		case model.TypeUUID:
 			return model.TypeUUID, nil
+		case model.TypePoint:
+			return model.TypePoint, nil
+		case model.TypeGeometry:
+			return model.TypeGeometry, nil
 
 		default:
 			if fieldTypeStuct.IsLinked {
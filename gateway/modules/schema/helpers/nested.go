@@ -0,0 +1,162 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// NestedCreate holds the callbacks used while splitting the documents nested under linked fields
+type NestedCreate struct {
+	// GetSchema returns the schema of a table
+	GetSchema func(dbAlias, col string) (model.Fields, bool)
+
+	// PrepareDoc is called for each document before its nested documents are split from it. It is optional
+	PrepareDoc func(doc map[string]interface{}, fields model.Fields)
+
+	// AuthoriseDoc is called for each document once its nested documents have been split from it. It is optional
+	AuthoriseDoc func(dbAlias, col string, doc map[string]interface{}) error
+}
+
+// SplitNestedDocs splits documents having documents nested under linked fields into one create request per table. The
+// requests are returned in the order they need to be executed and the foreign keys of the nested documents are filled
+// with the keys of their parents. A single request is returned if no nested documents are present. The documents to be
+// returned to the client are returned as well, with the nested documents placed under their linked fields.
+func (n *NestedCreate) SplitNestedDocs(ctx context.Context, dbAlias, col string, docs []interface{}) ([]*model.AllRequest, []interface{}, error) {
+	parentReq := &model.AllRequest{Type: string(model.Create), Col: col, Operation: utils.All, Document: docs, DBAlias: dbAlias}
+
+	fields, p := n.GetSchema(dbAlias, col)
+	if !p {
+		// Return the docs as is if no schema is available
+		return []*model.AllRequest{parentReq}, docs, nil
+	}
+
+	beforeReqs := make([]*model.AllRequest, 0)
+	afterReqs := make([]*model.AllRequest, 0)
+	returningDocs := make([]interface{}, len(docs))
+	for i, docTemp := range docs {
+		doc, ok := docTemp.(map[string]interface{})
+		if !ok {
+			return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid document type (%T) provided for table (%s)", docTemp, col), nil, nil)
+		}
+		if n.PrepareDoc != nil {
+			n.PrepareDoc(doc, fields)
+		}
+
+		returningDoc := make(map[string]interface{}, len(doc))
+		for fieldName, fieldValue := range doc {
+			field, p := fields[fieldName]
+			if !p || !field.IsLinked {
+				continue
+			}
+			link := field.LinkedTable
+
+			if link.Field != "" {
+				return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot insert nested documents for linked field (%s) of table (%s) as it links to a single field", fieldName, col), nil, nil)
+			}
+			fromField, p := fields[link.From]
+			if !p {
+				return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Field (%s) used by the link directive of field (%s) is not present in table (%s)", link.From, fieldName, col), nil, nil)
+			}
+			linkedFields, p := n.GetSchema(link.DBType, link.Table)
+			if !p {
+				return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("schema not provided for table (%s). Check the link directive for field (%s) in table (%s)", link.Table, fieldName, col), nil, nil)
+			}
+
+			var linkedDocs []interface{}
+			if field.IsList {
+				temp, ok := fieldValue.([]interface{})
+				if !ok {
+					return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid format provided for linked field %s - wanted array got object", fieldName), nil, nil)
+				}
+				linkedDocs = temp
+			} else {
+				temp, ok := fieldValue.(map[string]interface{})
+				if !ok {
+					return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid format provided for linked field %s - wanted object got array", fieldName), nil, nil)
+				}
+				linkedDocs = []interface{}{temp}
+			}
+			for _, linkedDocTemp := range linkedDocs {
+				if _, ok := linkedDocTemp.(map[string]interface{}); !ok {
+					return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid document type (%T) provided for linked field (%s)", linkedDocTemp, fieldName), nil, nil)
+				}
+			}
+
+			// The parent is inserted first if the link uses its primary key. Otherwise the parent refers to the nested
+			// document which hence needs to be inserted first.
+			if fromField.IsPrimary {
+				key, err := getLinkKey(ctx, col, fromField, doc)
+				if err != nil {
+					return nil, nil, err
+				}
+				for _, linkedDoc := range linkedDocs {
+					linkedDoc.(map[string]interface{})[link.To] = key
+				}
+			} else if len(linkedDocs) != 1 {
+				return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Exactly one nested document must be provided for linked field (%s) of table (%s)", fieldName, col), nil, nil)
+			}
+
+			linkedReqs, linkedReturningDocs, err := n.SplitNestedDocs(ctx, link.DBType, link.Table, linkedDocs)
+			if err != nil {
+				return nil, nil, err
+			}
+			if fromField.IsPrimary {
+				afterReqs = append(afterReqs, linkedReqs...)
+			} else {
+				toField, p := linkedFields[link.To]
+				if !p {
+					return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Field (%s) used by the link directive of field (%s) is not present in table (%s)", link.To, fieldName, link.Table), nil, nil)
+				}
+				key, err := getLinkKey(ctx, link.Table, toField, linkedDocs[0].(map[string]interface{}))
+				if err != nil {
+					return nil, nil, err
+				}
+				doc[link.From] = key
+				beforeReqs = append(beforeReqs, linkedReqs...)
+			}
+
+			// Delete the nested field. The schema module would throw an error otherwise
+			delete(doc, fieldName)
+			if field.IsList {
+				returningDoc[fieldName] = linkedReturningDocs
+			} else {
+				returningDoc[fieldName] = linkedReturningDocs[0]
+			}
+		}
+
+		if n.AuthoriseDoc != nil {
+			if err := n.AuthoriseDoc(dbAlias, col, doc); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		// The document is copied after it has been authorised since the security rules can modify it
+		for key, value := range doc {
+			returningDoc[key] = value
+		}
+		returningDocs[i] = returningDoc
+	}
+
+	return append(append(beforeReqs, parentReq), afterReqs...), returningDocs, nil
+}
+
+// getLinkKey returns the value of the field used to link a document with its nested documents. A new id is generated
+// if the field is an id which hasn't been provided.
+func getLinkKey(ctx context.Context, col string, field *model.FieldType, doc map[string]interface{}) (interface{}, error) {
+	if value, p := doc[field.FieldName]; p {
+		return value, nil
+	}
+
+	if field.IsAutoIncrement || field.Kind != model.TypeID {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Value of field (%s) of table (%s) must be provided to insert nested documents", field.FieldName, col), nil, nil)
+	}
+
+	doc[field.FieldName] = ksuid.New().String()
+	return doc[field.FieldName], nil
+}
@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func TestNestedCreate_SplitNestedDocs(t *testing.T) {
+	schemaDoc := model.Type{
+		"db": model.Collection{
+			"authors": model.Fields{
+				"id":         &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true},
+				"profile_id": &model.FieldType{FieldName: "profile_id", Kind: model.TypeID},
+				"books":      &model.FieldType{FieldName: "books", Kind: "books", IsList: true, IsLinked: true, LinkedTable: &model.TableProperties{Table: "books", From: "id", To: "author_id", DBType: "db"}},
+				"profile":    &model.FieldType{FieldName: "profile", Kind: "profiles", IsLinked: true, LinkedTable: &model.TableProperties{Table: "profiles", From: "profile_id", To: "id", DBType: "db"}},
+			},
+			"books": model.Fields{
+				"id":        &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true},
+				"author_id": &model.FieldType{FieldName: "author_id", Kind: model.TypeID},
+			},
+			"profiles": model.Fields{
+				"id": &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true},
+			},
+		},
+	}
+
+	authorised := make([]string, 0)
+	nested := &NestedCreate{
+		GetSchema: func(dbAlias, col string) (model.Fields, bool) {
+			fields, p := schemaDoc[dbAlias][col]
+			return fields, p
+		},
+		PrepareDoc: func(doc map[string]interface{}, fields model.Fields) {
+			if _, p := fields["author_id"]; p {
+				doc["kind"] = "book"
+			}
+		},
+		AuthoriseDoc: func(dbAlias, col string, doc map[string]interface{}) error {
+			authorised = append(authorised, col)
+			doc["checked"] = true
+			return nil
+		},
+	}
+
+	docs := []interface{}{map[string]interface{}{
+		"id":      "a1",
+		"books":   []interface{}{map[string]interface{}{"id": "b1"}},
+		"profile": map[string]interface{}{"id": "p1"},
+	}}
+	reqs, returningDocs, err := nested.SplitNestedDocs(context.Background(), "db", "authors", docs)
+	if err != nil {
+		t.Fatalf("SplitNestedDocs() error = %v", err)
+	}
+
+	cols := make([]string, len(reqs))
+	for i, r := range reqs {
+		cols[i] = r.Col
+	}
+	if !reflect.DeepEqual(cols, []string{"profiles", "authors", "books"}) {
+		t.Errorf("SplitNestedDocs() got requests for = %v, want [profiles authors books]", cols)
+	}
+
+	// The parent is authorised once its nested documents have been linked to it
+	if authorised[len(authorised)-1] != "authors" {
+		t.Errorf("SplitNestedDocs() authorised parent before its nested documents - %v", authorised)
+	}
+
+	want := []interface{}{map[string]interface{}{
+		"id":         "a1",
+		"profile_id": "p1",
+		"checked":    true,
+		"books":      []interface{}{map[string]interface{}{"id": "b1", "author_id": "a1", "kind": "book", "checked": true}},
+		"profile":    map[string]interface{}{"id": "p1", "checked": true},
+	}}
+	if !reflect.DeepEqual(returningDocs, want) {
+		t.Errorf("SplitNestedDocs() got returning docs = %v, want %v", returningDocs, want)
+	}
+}
@@ -60,6 +60,18 @@ func SchemaValidator(ctx context.Context, dbAlias, dbType, col string, collectio
 			continue
 		}
 
+		// Every document starts with the first version
+		if fieldValue.IsVersion {
+			mutatedDoc[fieldKey] = 1
+			continue
+		}
+
+		// Boolean soft delete markers must be set explicitly since null rows would not match the filter in sql
+		if fieldValue.IsSoftDelete && fieldValue.Kind == model.TypeBoolean && !ok {
+			mutatedDoc[fieldKey] = false
+			continue
+		}
+
 		if fieldValue.IsFieldTypeRequired {
 			if fieldValue.Kind == model.TypeID && !ok {
 				value = ksuid.New().String()
@@ -179,6 +191,191 @@ func ValidateUpdateOperation(ctx context.Context, dbAlias, dbType, col, op strin
 	return nil
 }
 
+// PrepareVersionedUpdate adds the optimistic concurrency check to an update request if the collection has a field with
+// the version directive. The expected version is picked from the $set operation or the where clause. The version gets
+// incremented on every update. It returns the where clause to be used along with whether the update must be rejected
+// if no documents match it.
+func PrepareVersionedUpdate(ctx context.Context, dbAlias, col, op string, updateDoc, find map[string]interface{}, schemaDoc model.Type) (map[string]interface{}, bool, error) {
+	if len(updateDoc) == 0 {
+		return find, false, nil
+	}
+	fields, ok := schemaDoc[dbAlias][col]
+	if !ok {
+		return find, false, nil
+	}
+
+	versionField := ""
+	for fieldName, fieldInfo := range fields {
+		if fieldInfo.IsVersion {
+			versionField = fieldName
+			break
+		}
+	}
+	if versionField == "" {
+		return find, false, nil
+	}
+
+	// The version can only be modified by space cloud
+	for operator, doc := range updateDoc {
+		if operator == "$set" {
+			continue
+		}
+		if obj, ok := doc.(map[string]interface{}); ok {
+			if _, p := obj[versionField]; p {
+				return nil, false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot use (%s) on version field (%s)", operator, versionField), nil, nil)
+			}
+		}
+	}
+
+	setDoc, _ := updateDoc["$set"].(map[string]interface{})
+	expectedVersion, isVersioned := setDoc[versionField]
+	if isVersioned {
+		delete(setDoc, versionField)
+	} else {
+		expectedVersion, isVersioned = getVersionFromWhereClause(find[versionField])
+	}
+
+	if !isVersioned {
+		// Simply increment the version when no version is expected
+		incDoc, ok := updateDoc["$inc"].(map[string]interface{})
+		if !ok {
+			incDoc = map[string]interface{}{}
+			updateDoc["$inc"] = incDoc
+		}
+		incDoc[versionField] = 1
+		return find, false, nil
+	}
+
+	if op == utils.Upsert {
+		return nil, false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot check version field (%s) in an upsert operation", versionField), nil, nil)
+	}
+
+	version, err := getVersionNumber(expectedVersion)
+	if err != nil {
+		return nil, false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid value provided for version field (%s)", versionField), err, nil)
+	}
+
+	// The version is bumped along with the rest of the fields being set
+	if find == nil {
+		find = map[string]interface{}{}
+	}
+	find[versionField] = version
+	if setDoc == nil {
+		setDoc = map[string]interface{}{}
+		updateDoc["$set"] = setDoc
+	}
+	setDoc[versionField] = version + 1
+	return find, true, nil
+}
+
+func getVersionFromWhereClause(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+	case map[string]interface{}:
+		version, ok := v["$eq"]
+		if !ok || len(v) != 1 {
+			return nil, false
+		}
+		return version, true
+	default:
+		return v, true
+	}
+}
+
+func getVersionNumber(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, fmt.Errorf("version (%v) is not an integer", v)
+		}
+		return int64(v), nil
+	}
+	return 0, fmt.Errorf("version of type (%T) is not an integer", value)
+}
+
+// GetSoftDeleteField returns the field of a table which has the soft delete directive
+func GetSoftDeleteField(fields model.Fields) (*model.FieldType, bool) {
+	for _, field := range fields {
+		if field.IsSoftDelete {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// GetTTLField returns the field of a table which has the ttl or expiresAt directive
+func GetTTLField(fields model.Fields) (*model.FieldType, bool) {
+	for _, field := range fields {
+		if field.ExpireAfter != nil {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// embeddedExpiryFormat is the format in which the expiry time is compared with the timestamps of embedded databases
+const embeddedExpiryFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// GenerateExpiredClause returns the where clause to find the rows of a table which have expired at the provided time.
+// Embedded databases store timestamps as strings in UTC, hence the time is compared as a string for them. The fraction
+// of a second is padded so that a row is never matched before it expires, irrespective of the digits stored for it.
+func GenerateExpiredClause(field *model.FieldType, dbType string, now time.Time) map[string]interface{} {
+	var expiredBefore interface{} = now.Add(-time.Duration(*field.ExpireAfter) * time.Second).UTC()
+	if model.DBType(dbType) == model.EmbeddedDB {
+		expiredBefore = expiredBefore.(time.Time).Format(embeddedExpiryFormat)
+	}
+	return map[string]interface{}{field.FieldName: map[string]interface{}{"$lt": expiredBefore}}
+}
+
+// GenerateSoftDeleteMarker returns the value to be stored in the soft delete field when a document is deleted or restored
+func GenerateSoftDeleteMarker(field *model.FieldType, isDeleted bool) interface{} {
+	if field.Kind == model.TypeBoolean {
+		return isDeleted
+	}
+	if isDeleted {
+		return time.Now().UTC()
+	}
+	return nil
+}
+
+// AddSoftDeleteClause adds the condition to skip soft deleted documents to the where clause. The soft delete field is
+// prefixed with the table name if one is provided. The where clause is left untouched if it already filters on the
+// soft delete field.
+func AddSoftDeleteClause(fields model.Fields, tableName string, find map[string]interface{}) map[string]interface{} {
+	field, ok := GetSoftDeleteField(fields)
+	if !ok {
+		return find
+	}
+
+	key := field.FieldName
+	if tableName != "" {
+		key = tableName + "." + key
+	}
+	if _, p := find[field.FieldName]; p {
+		return find
+	}
+	if _, p := find[key]; p {
+		return find
+	}
+
+	if find == nil {
+		find = map[string]interface{}{}
+	}
+	if field.Kind == model.TypeBoolean {
+		find[key] = map[string]interface{}{"$ne": true}
+	} else {
+		find[key] = nil
+	}
+	return find
+}
+
 type fieldsToPostProcess struct {
 	kind string
 	name string
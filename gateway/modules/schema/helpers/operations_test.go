@@ -603,6 +603,236 @@ func TestParseSchema(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:          "version directive on a non integer field",
+			schema:        nil,
+			IsErrExpected: true,
+			Data: config.DatabaseSchemas{
+				config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseSchema, "mongo", "post"): &config.DatabaseSchema{
+					Table:   "post",
+					DbAlias: "mongo",
+					Schema: `type post {
+						 id: ID! @primary
+						 version: String @version
+						}`,
+				},
+			},
+		},
+		{
+			name: "fulltext & version directives",
+			schema: model.Type{
+				"mongo": model.Collection{
+					"post": model.Fields{
+						"id": &model.FieldType{
+							FieldName:           "id",
+							IsFieldTypeRequired: true,
+							Kind:                model.TypeID,
+							TypeIDSize:          model.DefaultCharacterSize,
+							IsPrimary:           true,
+							PrimaryKeyInfo:      &model.TableProperties{},
+						},
+						"title": &model.FieldType{
+							FieldName: "title",
+							Kind:      model.TypeString,
+							IndexInfo: []*model.TableProperties{{IsFullText: true, Group: "title_fulltext", Order: 1, Sort: "asc", Field: "title"}},
+						},
+						"body": &model.FieldType{
+							FieldName: "body",
+							Kind:      model.TypeString,
+							IndexInfo: []*model.TableProperties{{IsFullText: true, Group: "post_body", Order: 1, Sort: "asc", Field: "body"}},
+						},
+						"version": &model.FieldType{
+							FieldName:           "version",
+							IsFieldTypeRequired: true,
+							Kind:                model.TypeInteger,
+							IsVersion:           true,
+						},
+					},
+				},
+			},
+			IsErrExpected: false,
+			Data: config.DatabaseSchemas{
+				config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseSchema, "mongo", "post"): &config.DatabaseSchema{
+					Table:   "post",
+					DbAlias: "mongo",
+					Schema: `type post {
+						 id: ID! @primary
+						 title: String @fulltext
+						 body: String @fulltext(name: "post_body")
+						 version: Integer! @version
+						}`,
+				},
+			},
+		},
+		{
+			name:          "soft delete directive on a non timestamp field",
+			schema:        nil,
+			IsErrExpected: true,
+			Data: config.DatabaseSchemas{
+				config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseSchema, "mongo", "post"): &config.DatabaseSchema{
+					Table:   "post",
+					DbAlias: "mongo",
+					Schema: `type post {
+						 id: ID! @primary
+						 deleted_at: String @softDelete
+						}`,
+				},
+			},
+		},
+		{
+			name: "soft delete directive",
+			schema: model.Type{
+				"mongo": model.Collection{
+					"post": model.Fields{
+						"id": &model.FieldType{
+							FieldName:           "id",
+							IsFieldTypeRequired: true,
+							Kind:                model.TypeID,
+							TypeIDSize:          model.DefaultCharacterSize,
+							IsPrimary:           true,
+							PrimaryKeyInfo:      &model.TableProperties{},
+						},
+						"deleted_at": &model.FieldType{
+							FieldName:    "deleted_at",
+							Kind:         model.TypeDateTime,
+							Args:         &model.FieldArgs{Precision: model.DefaultDateTimePrecision},
+							IsSoftDelete: true,
+						},
+					},
+				},
+			},
+			IsErrExpected: false,
+			Data: config.DatabaseSchemas{
+				config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseSchema, "mongo", "post"): &config.DatabaseSchema{
+					Table:   "post",
+					DbAlias: "mongo",
+					Schema: `type post {
+						 id: ID! @primary
+						 deleted_at: DateTime @softDelete
+						}`,
+				},
+			},
+		},
+		{
+			name:          "ttl directive on a non timestamp field",
+			schema:        nil,
+			IsErrExpected: true,
+			Data: config.DatabaseSchemas{
+				config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseSchema, "mongo", "session"): &config.DatabaseSchema{
+					Table:   "session",
+					DbAlias: "mongo",
+					Schema: `type session {
+						 id: ID! @primary
+						 created_at: String @ttl(seconds: 60)
+						}`,
+				},
+			},
+		},
+		{
+			name:          "ttl directive without seconds",
+			schema:        nil,
+			IsErrExpected: true,
+			Data: config.DatabaseSchemas{
+				config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseSchema, "mongo", "session"): &config.DatabaseSchema{
+					Table:   "session",
+					DbAlias: "mongo",
+					Schema: `type session {
+						 id: ID! @primary
+						 created_at: DateTime @ttl
+						}`,
+				},
+			},
+		},
+		{
+			name:          "ttl directive with non positive seconds",
+			schema:        nil,
+			IsErrExpected: true,
+			Data: config.DatabaseSchemas{
+				config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseSchema, "mongo", "session"): &config.DatabaseSchema{
+					Table:   "session",
+					DbAlias: "mongo",
+					Schema: `type session {
+						 id: ID! @primary
+						 created_at: DateTime @ttl(seconds: 0)
+						}`,
+				},
+			},
+		},
+		{
+			name:          "ttl directive on multiple fields",
+			schema:        nil,
+			IsErrExpected: true,
+			Data: config.DatabaseSchemas{
+				config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseSchema, "mongo", "session"): &config.DatabaseSchema{
+					Table:   "session",
+					DbAlias: "mongo",
+					Schema: `type session {
+						 id: ID! @primary
+						 created_at: DateTime @ttl(seconds: 60)
+						 expires_at: DateTime @expiresAt
+						}`,
+				},
+			},
+		},
+		{
+			name: "ttl and expires at directives",
+			schema: model.Type{
+				"mongo": model.Collection{
+					"session": model.Fields{
+						"id": &model.FieldType{
+							FieldName:           "id",
+							IsFieldTypeRequired: true,
+							Kind:                model.TypeID,
+							TypeIDSize:          model.DefaultCharacterSize,
+							IsPrimary:           true,
+							PrimaryKeyInfo:      &model.TableProperties{},
+						},
+						"created_at": &model.FieldType{
+							FieldName:           "created_at",
+							IsFieldTypeRequired: true,
+							Kind:                model.TypeDateTime,
+							Args:                &model.FieldArgs{Precision: model.DefaultDateTimePrecision},
+							ExpireAfter:         func() *int64 { v := int64(3600); return &v }(),
+						},
+					},
+					"otp": model.Fields{
+						"id": &model.FieldType{
+							FieldName:           "id",
+							IsFieldTypeRequired: true,
+							Kind:                model.TypeID,
+							TypeIDSize:          model.DefaultCharacterSize,
+							IsPrimary:           true,
+							PrimaryKeyInfo:      &model.TableProperties{},
+						},
+						"expires_at": &model.FieldType{
+							FieldName:   "expires_at",
+							Kind:        model.TypeDateTimeWithZone,
+							Args:        &model.FieldArgs{Precision: model.DefaultDateTimePrecision},
+							ExpireAfter: func() *int64 { v := int64(0); return &v }(),
+						},
+					},
+				},
+			},
+			IsErrExpected: false,
+			Data: config.DatabaseSchemas{
+				config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseSchema, "mongo", "session"): &config.DatabaseSchema{
+					Table:   "session",
+					DbAlias: "mongo",
+					Schema: `type session {
+						 id: ID! @primary
+						 created_at: DateTime! @ttl(seconds: 3600)
+						}`,
+				},
+				config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseSchema, "mongo", "otp"): &config.DatabaseSchema{
+					Table:   "otp",
+					DbAlias: "mongo",
+					Schema: `type otp {
+						 id: ID! @primary
+						 expires_at: DateTimeWithZone @expiresAt
+						}`,
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -1264,6 +1494,8 @@ var testQueries = `
 	isMale:Boolean
 	exp:Integer
 	spec: JSON
+	status: String @enum(values: ["draft", "published"])
+	position: Point
 	event: event_logs
 	person : sharad @link(table:sharad, from:Name, to:isMale)
    }
@@ -1307,6 +1539,158 @@ var Parsedata = config.DatabaseSchemas{
 	},
 }
 
+func TestPrepareVersionedUpdate(t *testing.T) {
+	schemaDoc := model.Type{
+		"mysql": model.Collection{
+			"post": model.Fields{
+				"id":      &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true},
+				"title":   &model.FieldType{FieldName: "title", Kind: model.TypeString},
+				"version": &model.FieldType{FieldName: "version", Kind: model.TypeInteger, IsVersion: true},
+			},
+			"comment": model.Fields{
+				"id": &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true},
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		col             string
+		op              string
+		update          map[string]interface{}
+		find            map[string]interface{}
+		wantUpdate      map[string]interface{}
+		wantFind        map[string]interface{}
+		wantIsVersioned bool
+		wantErr         bool
+	}{
+		{
+			name:       "table without version field",
+			col:        "comment",
+			op:         utils.All,
+			update:     map[string]interface{}{"$set": map[string]interface{}{"id": "1"}},
+			find:       map[string]interface{}{"id": "1"},
+			wantUpdate: map[string]interface{}{"$set": map[string]interface{}{"id": "1"}},
+			wantFind:   map[string]interface{}{"id": "1"},
+		},
+		{
+			name:       "version is incremented when no version is expected",
+			col:        "post",
+			op:         utils.All,
+			update:     map[string]interface{}{"$set": map[string]interface{}{"title": "hello"}},
+			find:       map[string]interface{}{"id": "1"},
+			wantUpdate: map[string]interface{}{"$set": map[string]interface{}{"title": "hello"}, "$inc": map[string]interface{}{"version": 1}},
+			wantFind:   map[string]interface{}{"id": "1"},
+		},
+		{
+			name:            "expected version provided in set",
+			col:             "post",
+			op:              utils.All,
+			update:          map[string]interface{}{"$set": map[string]interface{}{"title": "hello", "version": 3}},
+			find:            map[string]interface{}{"id": "1"},
+			wantUpdate:      map[string]interface{}{"$set": map[string]interface{}{"title": "hello", "version": int64(4)}},
+			wantFind:        map[string]interface{}{"id": "1", "version": int64(3)},
+			wantIsVersioned: true,
+		},
+		{
+			name:            "expected version provided in where clause",
+			col:             "post",
+			op:              utils.All,
+			update:          map[string]interface{}{"$inc": map[string]interface{}{"likes": 1}},
+			find:            map[string]interface{}{"id": "1", "version": map[string]interface{}{"$eq": float64(3)}},
+			wantUpdate:      map[string]interface{}{"$inc": map[string]interface{}{"likes": 1}, "$set": map[string]interface{}{"version": int64(4)}},
+			wantFind:        map[string]interface{}{"id": "1", "version": int64(3)},
+			wantIsVersioned: true,
+		},
+		{
+			name:    "version modified manually",
+			col:     "post",
+			op:      utils.All,
+			update:  map[string]interface{}{"$inc": map[string]interface{}{"version": 1}},
+			find:    map[string]interface{}{"id": "1"},
+			wantErr: true,
+		},
+		{
+			name:    "expected version provided in upsert",
+			col:     "post",
+			op:      utils.Upsert,
+			update:  map[string]interface{}{"$set": map[string]interface{}{"version": 3}},
+			find:    map[string]interface{}{"id": "1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			find, isVersioned, err := PrepareVersionedUpdate(context.Background(), "mysql", tt.col, tt.op, tt.update, tt.find, schemaDoc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PrepareVersionedUpdate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if isVersioned != tt.wantIsVersioned {
+				t.Errorf("PrepareVersionedUpdate() isVersioned = %v, want %v", isVersioned, tt.wantIsVersioned)
+			}
+			if arr := deep.Equal(find, tt.wantFind); len(arr) > 0 {
+				t.Errorf("PrepareVersionedUpdate() find differences = %v", arr)
+			}
+			if arr := deep.Equal(tt.update, tt.wantUpdate); len(arr) > 0 {
+				t.Errorf("PrepareVersionedUpdate() update differences = %v", arr)
+			}
+		})
+	}
+}
+
+func TestAddSoftDeleteClause(t *testing.T) {
+	tests := []struct {
+		name      string
+		fields    model.Fields
+		tableName string
+		find      map[string]interface{}
+		want      map[string]interface{}
+	}{
+		{
+			name:   "table without soft delete field",
+			fields: model.Fields{"id": &model.FieldType{FieldName: "id", Kind: model.TypeID}},
+			find:   map[string]interface{}{"id": "1"},
+			want:   map[string]interface{}{"id": "1"},
+		},
+		{
+			name:   "timestamp soft delete field",
+			fields: model.Fields{"deleted_at": &model.FieldType{FieldName: "deleted_at", Kind: model.TypeDateTime, IsSoftDelete: true}},
+			find:   map[string]interface{}{"id": "1"},
+			want:   map[string]interface{}{"id": "1", "deleted_at": nil},
+		},
+		{
+			name:   "boolean soft delete field with an empty where clause",
+			fields: model.Fields{"is_deleted": &model.FieldType{FieldName: "is_deleted", Kind: model.TypeBoolean, IsSoftDelete: true}},
+			want:   map[string]interface{}{"is_deleted": map[string]interface{}{"$ne": true}},
+		},
+		{
+			name:      "soft delete field prefixed with the table name",
+			fields:    model.Fields{"deleted_at": &model.FieldType{FieldName: "deleted_at", Kind: model.TypeDateTime, IsSoftDelete: true}},
+			tableName: "post",
+			find:      map[string]interface{}{"post.id": "1"},
+			want:      map[string]interface{}{"post.id": "1", "post.deleted_at": nil},
+		},
+		{
+			name:   "where clause already filters on the soft delete field",
+			fields: model.Fields{"deleted_at": &model.FieldType{FieldName: "deleted_at", Kind: model.TypeDateTime, IsSoftDelete: true}},
+			find:   map[string]interface{}{"deleted_at": map[string]interface{}{"$ne": nil}},
+			want:   map[string]interface{}{"deleted_at": map[string]interface{}{"$ne": nil}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AddSoftDeleteClause(tt.fields, tt.tableName, tt.find)
+			if arr := deep.Equal(got, tt.want); len(arr) > 0 {
+				t.Errorf("AddSoftDeleteClause() differences = %v", arr)
+			}
+		})
+	}
+}
+
 func TestSchema_ValidateCreateOperation(t *testing.T) {
 
 	testCases := []struct {
@@ -1556,6 +1940,18 @@ func TestSchema_CheckType(t *testing.T) {
 				"createdAt": "1999-10-19T11:45:26.371Z",
 			},
 		},
+		{
+			coll:          "tweet",
+			dbType:        string(model.EmbeddedDB),
+			dbAlias:       "mongo",
+			name:          "datetime field is converted to utc for embedded databases",
+			IsErrExpected: false,
+			IsSkipable:    false,
+			result:        time.Date(1999, 10, 19, 6, 15, 26, 371000000, time.UTC),
+			Document: map[string]interface{}{
+				"createdAt": "1999-10-19T11:45:26.371+05:30",
+			},
+		},
 		{
 			coll:          "tweet",
 			dbType:        string(model.Mongo),
@@ -1742,6 +2138,59 @@ func TestSchema_CheckType(t *testing.T) {
 				"spec": 1,
 			},
 		},
+		{
+			coll:          "tweet",
+			dbType:        string(model.Mongo),
+			dbAlias:       "mongo",
+			name:          "value allowed by enum",
+			IsErrExpected: false,
+			result:        "draft",
+			Document: map[string]interface{}{
+				"status": "draft",
+			},
+		},
+		{
+			coll:          "tweet",
+			dbType:        string(model.Mongo),
+			dbAlias:       "mongo",
+			name:          "value not allowed by enum",
+			IsErrExpected: true,
+			Document: map[string]interface{}{
+				"status": "archived",
+			},
+		},
+		{
+			coll:          "tweet",
+			dbType:        string(model.Mongo),
+			dbAlias:       "mongo",
+			name:          "valid point",
+			IsErrExpected: false,
+			result:        map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.87, 19.07}},
+			Document: map[string]interface{}{
+				"position": map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.87, 19.07}},
+			},
+		},
+		{
+			coll:          "tweet",
+			dbType:        string(model.Postgres),
+			dbAlias:       "mongo",
+			name:          "point stored as extended wkb in postgres",
+			IsErrExpected: false,
+			result:        "0101000020e61000000000000000001c400000000000002440",
+			Document: map[string]interface{}{
+				"position": map[string]interface{}{"type": "Point", "coordinates": []interface{}{7, 10}},
+			},
+		},
+		{
+			coll:          "tweet",
+			dbType:        string(model.Mongo),
+			dbAlias:       "mongo",
+			name:          "polygon provided for point",
+			IsErrExpected: true,
+			Document: map[string]interface{}{
+				"position": map[string]interface{}{"type": "Polygon", "coordinates": []interface{}{}},
+			},
+		},
 	}
 
 	schemaDoc, err := Parser(Parsedata)
@@ -1768,3 +2217,54 @@ func TestSchema_CheckType(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateExpiredClause(t *testing.T) {
+	now := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+	ttl := int64(3600)
+	expiresAt := int64(0)
+
+	tests := []struct {
+		name   string
+		field  *model.FieldType
+		dbType string
+		now    time.Time
+		want   map[string]interface{}
+	}{
+		{
+			name:   "rows expire the provided seconds after the timestamp",
+			field:  &model.FieldType{FieldName: "created_at", Kind: model.TypeDateTime, ExpireAfter: &ttl},
+			dbType: string(model.Postgres),
+			want:   map[string]interface{}{"created_at": map[string]interface{}{"$lt": now.Add(-time.Hour)}},
+		},
+		{
+			name:   "rows expire at the timestamp",
+			field:  &model.FieldType{FieldName: "expires_at", Kind: model.TypeDateTime, ExpireAfter: &expiresAt},
+			dbType: string(model.MySQL),
+			want:   map[string]interface{}{"expires_at": map[string]interface{}{"$lt": now}},
+		},
+		{
+			name:   "timestamps are compared as strings for embedded databases",
+			field:  &model.FieldType{FieldName: "created_at", Kind: model.TypeDateTime, ExpireAfter: &ttl},
+			dbType: string(model.EmbeddedDB),
+			want:   map[string]interface{}{"created_at": map[string]interface{}{"$lt": "2021-03-04T09:00:00.000000000Z"}},
+		},
+		{
+			name:   "fraction of a second is padded for embedded databases",
+			field:  &model.FieldType{FieldName: "expires_at", Kind: model.TypeDateTime, ExpireAfter: &expiresAt},
+			dbType: string(model.EmbeddedDB),
+			now:    now.Add(300 * time.Millisecond),
+			want:   map[string]interface{}{"expires_at": map[string]interface{}{"$lt": "2021-03-04T10:00:00.300000000Z"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.now.IsZero() {
+				tt.now = now
+			}
+			got := GenerateExpiredClause(tt.field, tt.dbType, tt.now)
+			if arr := deep.Equal(got, tt.want); len(arr) > 0 {
+				t.Errorf("GenerateExpiredClause() differences = %v", arr)
+			}
+		})
+	}
+}
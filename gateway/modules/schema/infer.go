@@ -0,0 +1,124 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// inferSampleSize is the number of rows read to infer the schema of a table
+const inferSampleSize int64 = 100
+
+// InferSchema generates the schema of a table from a sample of its rows. It is used for tables which don't have a
+// schema, like the collections of mongo. Fields whose values are of different kinds across the rows are inferred as JSON
+func (s *Schema) InferSchema(ctx context.Context, dbAlias, col string) (string, error) {
+	limit := inferSampleSize
+	readRequest := &model.ReadRequest{Find: map[string]interface{}{}, Operation: utils.All, Options: &model.ReadOptions{Limit: &limit}}
+	result, _, err := s.crud.Read(ctx, dbAlias, col, readRequest, model.RequestParams{})
+	if err != nil {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read rows of table (%s) to infer its schema", col), err, nil)
+	}
+
+	rows, _ := result.([]interface{})
+	docs := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if doc, ok := row.(map[string]interface{}); ok {
+			docs = append(docs, doc)
+		}
+	}
+	if len(docs) == 0 {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to infer schema of table (%s) as it has no rows", col), nil, nil)
+	}
+
+	return generateSDL(model.Collection{col: inferFields(docs)})
+}
+
+func inferFields(docs []map[string]interface{}) model.Fields {
+	fields := model.Fields{}
+	for _, doc := range docs {
+		for key, value := range doc {
+			kind := inferKind(value)
+			if kind == "" {
+				continue
+			}
+
+			field, p := fields[key]
+			if !p {
+				fields[key] = &model.FieldType{FieldName: key, Kind: kind}
+				continue
+			}
+			field.Kind = mergeInferredKinds(field.Kind, kind)
+		}
+	}
+
+	// The primary key is _id for mongo and id for the other databases
+	primary := "_id"
+	if _, p := fields[primary]; !p {
+		primary = "id"
+	}
+	if field, p := fields[primary]; p {
+		field.IsPrimary = true
+		field.IsFieldTypeRequired = true
+		if field.Kind == model.TypeString {
+			field.Kind = model.TypeID
+		}
+	}
+
+	for _, field := range fields {
+		if field.Kind == model.TypeID {
+			field.TypeIDSize = model.DefaultCharacterSize
+		}
+	}
+	return fields
+}
+
+func inferKind(value interface{}) string {
+	switch value.(type) {
+	case primitive.ObjectID:
+		return model.TypeID
+	case string:
+		return model.TypeString
+	case bool:
+		return model.TypeBoolean
+	case int, int32:
+		return model.TypeInteger
+	case int64:
+		return model.TypeBigInteger
+	case float32, float64, primitive.Decimal128:
+		return model.TypeFloat
+	case time.Time, *time.Time, primitive.DateTime, primitive.Timestamp:
+		return model.TypeDateTime
+	case nil:
+		return ""
+	default:
+		// Nested documents and arrays are stored as json
+		return model.TypeJSON
+	}
+}
+
+// mergeInferredKinds returns the kind which can store the values of both the kinds provided
+func mergeInferredKinds(a, b string) string {
+	if a == b {
+		return a
+	}
+
+	isNumeric := func(kind string) bool {
+		return kind == model.TypeInteger || kind == model.TypeBigInteger || kind == model.TypeFloat
+	}
+	switch {
+	case a == model.TypeFloat && isNumeric(b), b == model.TypeFloat && isNumeric(a):
+		return model.TypeFloat
+	case isNumeric(a) && isNumeric(b):
+		return model.TypeBigInteger
+	case (a == model.TypeID && b == model.TypeString) || (a == model.TypeString && b == model.TypeID):
+		return model.TypeString
+	default:
+		return model.TypeJSON
+	}
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_inferFields(t *testing.T) {
+	tests := []struct {
+		name string
+		docs []map[string]interface{}
+		want model.Fields
+	}{
+		{
+			name: "fields of a mongo collection",
+			docs: []map[string]interface{}{
+				{"_id": primitive.NewObjectID(), "name": "john", "age": int32(20), "joined": time.Now(), "address": map[string]interface{}{"city": "pune"}},
+				{"_id": primitive.NewObjectID(), "name": "jane", "age": 20.5, "tags": primitive.A{"a"}, "deleted": nil},
+			},
+			want: model.Fields{
+				"_id":     &model.FieldType{FieldName: "_id", Kind: model.TypeID, IsPrimary: true, IsFieldTypeRequired: true, TypeIDSize: model.DefaultCharacterSize},
+				"name":    &model.FieldType{FieldName: "name", Kind: model.TypeString},
+				"age":     &model.FieldType{FieldName: "age", Kind: model.TypeFloat},
+				"joined":  &model.FieldType{FieldName: "joined", Kind: model.TypeDateTime},
+				"address": &model.FieldType{FieldName: "address", Kind: model.TypeJSON},
+				"tags":    &model.FieldType{FieldName: "tags", Kind: model.TypeJSON},
+			},
+		},
+		{
+			name: "string primary key with conflicting kinds",
+			docs: []map[string]interface{}{
+				{"id": "1", "value": "a"},
+				{"id": "2", "value": true},
+			},
+			want: model.Fields{
+				"id":    &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true, IsFieldTypeRequired: true, TypeIDSize: model.DefaultCharacterSize},
+				"value": &model.FieldType{FieldName: "value", Kind: model.TypeJSON},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inferFields(tt.docs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("inferFields() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -69,6 +69,15 @@ func (s *Schema) Inspector(ctx context.Context, dbAlias, dbType, project, col st
 		if realColumnInfo.IsUpdatedAt {
 			currentTableInfo.IsUpdatedAt = true
 		}
+		if realColumnInfo.IsVersion {
+			currentTableInfo.IsVersion = true
+		}
+		if realColumnInfo.IsSoftDelete {
+			currentTableInfo.IsSoftDelete = true
+		}
+		if realColumnInfo.ExpireAfter != nil {
+			currentTableInfo.ExpireAfter = realColumnInfo.ExpireAfter
+		}
 	}
 
 	return currentSchema, nil
@@ -100,6 +109,10 @@ func generateInspection(dbType, col string, fields []model.InspectorFieldType, i
 			if err := inspectionSQLServerCheckFieldType(col, field, &fieldDetails); err != nil {
 				return nil, err
 			}
+		case model.SQLite:
+			if err := inspectionSQLiteCheckFieldType(col, field, &fieldDetails); err != nil {
+				return nil, err
+			}
 		}
 
 		// default key
@@ -144,6 +157,8 @@ func generateInspection(dbType, col string, fields []model.InspectorFieldType, i
 						Order: indexValue.Order,
 					}
 					continue
+				} else if indexValue.IsFullText {
+					temp.IsFullText = true
 				} else if indexValue.IsUnique {
 					temp.IsUnique = true
 				} else {
@@ -232,6 +247,10 @@ func inspectionMySQLCheckFieldType(col string, field model.InspectorFieldType, f
 		fieldDetails.Kind = model.TypeBoolean
 	case "json":
 		fieldDetails.Kind = model.TypeJSON
+	case "point":
+		fieldDetails.Kind = model.TypePoint
+	case "geometry":
+		fieldDetails.Kind = model.TypeGeometry
 	default:
 		return helpers.Logger.LogError("", fmt.Sprintf("Cannot track/inspect table (%s)", col), fmt.Errorf("table contains a column (%s) with type (%s) which is not supported by space cloud", fieldDetails.FieldName, result), nil)
 	}
@@ -360,6 +379,58 @@ func inspectionPostgresCheckFieldType(col string, field model.InspectorFieldType
 		fieldDetails.Kind = model.TypeBoolean
 	case "jsonb", "json":
 		fieldDetails.Kind = model.TypeJSON
+	case "geography":
+		// The type of geography columns is formatted as geography(Point,4326)
+		fieldDetails.Kind = model.TypeGeometry
+		if strings.HasPrefix(field.FieldType, "geography(Point") {
+			fieldDetails.Kind = model.TypePoint
+		}
+	default:
+		return helpers.Logger.LogError("", fmt.Sprintf("Cannot track/inspect table (%s)", col), fmt.Errorf("table contains a column (%s) with type (%s) which is not supported by space cloud", fieldDetails.FieldName, result), nil)
+	}
+	return nil
+}
+
+func inspectionSQLiteCheckFieldType(col string, field model.InspectorFieldType, fieldDetails *model.FieldType) error {
+	result := strings.Split(field.FieldType, "(")
+
+	switch strings.TrimSpace(result[0]) {
+	case "date":
+		fieldDetails.Kind = model.TypeDate
+	case "time":
+		fieldDetails.Kind = model.TypeTime
+	case "varchar", "character varying", "nvarchar":
+		fieldDetails.Kind = model.TypeVarChar
+		fieldDetails.TypeIDSize = field.VarcharSize
+	case "character", "char", "nchar":
+		fieldDetails.Kind = model.TypeChar
+		fieldDetails.TypeIDSize = field.VarcharSize
+	case "text", "clob":
+		fieldDetails.Kind = model.TypeString
+	case "integer", "int", "mediumint":
+		fieldDetails.Kind = model.TypeInteger
+	case "smallint", "tinyint":
+		fieldDetails.Kind = model.TypeSmallInteger
+	case "bigint":
+		fieldDetails.Kind = model.TypeBigInteger
+	case "numeric", "decimal":
+		fieldDetails.Kind = model.TypeDecimal
+		if field.NumericPrecision > 0 || field.NumericScale > 0 {
+			fieldDetails.Args = &model.FieldArgs{
+				Precision: field.NumericPrecision,
+				Scale:     field.NumericScale,
+			}
+		}
+	case "real", "double", "double precision", "float":
+		fieldDetails.Kind = model.TypeFloat
+	case "datetime":
+		fieldDetails.Kind = model.TypeDateTime
+	case "timestamp":
+		fieldDetails.Kind = model.TypeDateTimeWithZone
+	case "boolean":
+		fieldDetails.Kind = model.TypeBoolean
+	case "json":
+		fieldDetails.Kind = model.TypeJSON
 	default:
 		return helpers.Logger.LogError("", fmt.Sprintf("Cannot track/inspect table (%s)", col), fmt.Errorf("table contains a column (%s) with type (%s) which is not supported by space cloud", fieldDetails.FieldName, result), nil)
 	}
@@ -0,0 +1,454 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// PlanSchemaModification returns the migrations required to modify the tables provided without running them. Each table
+// is planned against the current state of the database.
+func (s *Schema) PlanSchemaModification(ctx context.Context, dbAlias, logicalDBName string, dbSchemas config.DatabaseSchemas) ([]*model.SchemaMigration, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	parsedSchema, err := schemaHelpers.Parser(dbSchemas)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable parse provided schema SDL", err, nil)
+	}
+
+	migrations := make([]*model.SchemaMigration, 0)
+	for _, dbSchema := range dbSchemas {
+		if dbSchema.Schema == "" {
+			continue
+		}
+		migration, err := s.planMigration(ctx, dbAlias, logicalDBName, dbSchema, parsedSchema)
+		if err != nil {
+			return nil, err
+		}
+		if migration != nil {
+			migrations = append(migrations, migration)
+		}
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Table < migrations[j].Table })
+	return migrations, nil
+}
+
+// GetSchemaMigrations returns the schema migrations recorded for the database sorted by their version
+func (s *Schema) GetSchemaMigrations(ctx context.Context, dbAlias, logicalDBName string) ([]*model.SchemaMigration, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if err := s.createMigrationsTable(ctx, dbAlias, logicalDBName); err != nil {
+		return nil, err
+	}
+	return s.readMigrations(ctx, dbAlias)
+}
+
+// ApplySchemaMigrations applies the rolled back migrations of the database till the version provided. The migrations
+// which were applied are returned in the order they were applied, even if a later migration fails.
+func (s *Schema) ApplySchemaMigrations(ctx context.Context, dbAlias, logicalDBName string, version int) ([]*model.SchemaMigration, error) {
+	s.migrationLock.Lock()
+	defer s.migrationLock.Unlock()
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if err := s.createMigrationsTable(ctx, dbAlias, logicalDBName); err != nil {
+		return nil, err
+	}
+	migrations, err := s.readMigrations(ctx, dbAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := getMigrationsToApply(migrations, version)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply schema migrations", err, nil)
+	}
+
+	for i, migration := range pending {
+		if err := s.runMigration(ctx, dbAlias, migration, migration.Up, model.MigrationApplied); err != nil {
+			return pending[:i], helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to apply schema migration (%d)", migration.Version), err, nil)
+		}
+		if err := s.setMigrationStatus(ctx, dbAlias, migration, model.MigrationApplied); err != nil {
+			return pending[:i+1], err
+		}
+	}
+	return pending, nil
+}
+
+// RollbackSchemaMigrations reverts the applied migrations of the database newer than the version provided. The migrations
+// which were rolled back are returned in the order they were rolled back, even if a later migration fails.
+func (s *Schema) RollbackSchemaMigrations(ctx context.Context, dbAlias, logicalDBName string, version int) ([]*model.SchemaMigration, error) {
+	s.migrationLock.Lock()
+	defer s.migrationLock.Unlock()
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if err := s.createMigrationsTable(ctx, dbAlias, logicalDBName); err != nil {
+		return nil, err
+	}
+	migrations, err := s.readMigrations(ctx, dbAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := getMigrationsToRollback(migrations, version)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to rollback schema migrations", err, nil)
+	}
+
+	for i, migration := range applied {
+		if err := s.runMigration(ctx, dbAlias, migration, migration.Down, model.MigrationRolledBack); err != nil {
+			return applied[:i], helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to rollback schema migration (%d)", migration.Version), err, nil)
+		}
+		if err := s.setMigrationStatus(ctx, dbAlias, migration, model.MigrationRolledBack); err != nil {
+			return applied[:i+1], err
+		}
+	}
+	return applied, nil
+}
+
+// planMigration generates the queries to modify a table along with the queries to revert the modification. Nothing is
+// returned for databases which don't have a schema.
+func (s *Schema) planMigration(ctx context.Context, dbAlias, logicalDBName string, dbSchema *config.DatabaseSchema, parsedSchema model.Type) (*model.SchemaMigration, error) {
+	dbType, err := s.crud.GetDBType(dbAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return gracefully if db type is mongo
+	if dbType == string(model.Mongo) || dbType == string(model.EmbeddedDB) {
+		return nil, nil
+	}
+
+	tableName := dbSchema.Table
+	currentSchema, err := s.Inspector(ctx, dbAlias, dbType, logicalDBName, tableName, parsedSchema[dbAlias])
+	if err != nil {
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Schema Inspector Error", map[string]interface{}{"error": err.Error()})
+	}
+	currentTableInfo, tableExists := currentSchema[tableName]
+
+	// The schema of the table before the migration is needed to revert it
+	previousSchema := ""
+	if tableExists {
+		previousSchema, err = s.getPreviousSchema(dbAlias, tableName, currentSchema)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	up, err := s.generateCreationQueries(ctx, dbAlias, tableName, logicalDBName, parsedSchema, currentSchema)
+	if err != nil {
+		return nil, err
+	}
+	migration := &model.SchemaMigration{DbAlias: dbAlias, Table: tableName, Schema: dbSchema.Schema, PreviousSchema: previousSchema, Up: []string{}, Down: []string{}}
+	if len(up) == 0 {
+		return migration, nil
+	}
+	migration.Up = up
+
+	// The up queries create the missing tables referenced by the foreign keys as well. These are dropped after the table
+	// referencing them
+	jointTables := s.getMissingJointTables(ctx, dbAlias, dbType, logicalDBName, tableName, parsedSchema)
+	dropJointTables := make([]string, len(jointTables))
+	for i, jointTable := range jointTables {
+		dropJointTables[i] = "DROP TABLE " + s.getTableName(dbType, logicalDBName, jointTable)
+	}
+
+	if !tableExists {
+		migration.Down = append([]string{"DROP TABLE " + s.getTableName(dbType, logicalDBName, tableName)}, dropJointTables...)
+		return migration, nil
+	}
+
+	// The down queries bring the table back from the new schema to the current one
+	down, err := s.generateCreationQueries(ctx, dbAlias, tableName, logicalDBName, model.Type{dbAlias: model.Collection{tableName: currentTableInfo}}, model.Collection{tableName: parsedSchema[dbAlias][tableName]})
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to generate queries to revert the schema of table (%s)", tableName), err, nil)
+	}
+	migration.Down = append(down, dropJointTables...)
+	return migration, nil
+}
+
+// getMissingJointTables returns the tables referenced by the foreign keys of a table which don't exist in the database
+// yet. A table is always placed before the tables it references.
+func (s *Schema) getMissingJointTables(ctx context.Context, dbAlias, dbType, logicalDBName, tableName string, parsedSchema model.Type) []string {
+	// The tables are collected with the referenced tables placed first and then reversed
+	tables := make([]string, 0)
+	visited := map[string]bool{tableName: true}
+	var visit func(table string)
+	visit = func(table string) {
+		fieldNames := make([]string, 0, len(parsedSchema[dbAlias][table]))
+		for fieldName := range parsedSchema[dbAlias][table] {
+			fieldNames = append(fieldNames, fieldName)
+		}
+		sort.Strings(fieldNames)
+
+		for _, fieldName := range fieldNames {
+			field := parsedSchema[dbAlias][table][fieldName]
+			if !field.IsForeign || field.IsLinked || field.JointTable == nil || visited[field.JointTable.Table] {
+				continue
+			}
+			jointTable := field.JointTable.Table
+			visited[jointTable] = true
+
+			currentSchema, err := s.Inspector(ctx, dbAlias, dbType, logicalDBName, jointTable, parsedSchema[dbAlias])
+			if err != nil {
+				helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Schema Inspector Error", map[string]interface{}{"error": err.Error()})
+			}
+			if _, p := currentSchema[jointTable]; p {
+				continue
+			}
+			visit(jointTable)
+			tables = append(tables, jointTable)
+		}
+	}
+	visit(tableName)
+
+	for i, j := 0, len(tables)-1; i < j; i, j = i+1, j-1 {
+		tables[i], tables[j] = tables[j], tables[i]
+	}
+	return tables
+}
+
+// getPreviousSchema returns the schema of the table tracked in the config. The schema is generated from the database
+// if the table isn't tracked.
+func (s *Schema) getPreviousSchema(dbAlias, tableName string, currentSchema model.Collection) (string, error) {
+	if dbSchema, p := s.dbSchemas[config.GenerateResourceID(s.clusterID, s.project, config.ResourceDatabaseSchema, dbAlias, tableName)]; p && dbSchema.Schema != "" {
+		return dbSchema.Schema, nil
+	}
+	return generateSDL(model.Collection{tableName: currentSchema[tableName]})
+}
+
+// applyNewMigration records a migration as the latest version of the database and then runs its queries. The row is
+// written with the pending status before the queries run so that a failure after changing the schema still leaves a
+// record of the migration. The primary key on the version makes sure two gateways can't record the same version. The
+// migrations which were rolled back get discarded since they no longer apply on top of the new one.
+// NOTE: the parent function should take the migration lock before calling this function
+func (s *Schema) applyNewMigration(ctx context.Context, dbAlias, logicalDBName string, migration *model.SchemaMigration) error {
+	if err := s.createMigrationsTable(ctx, dbAlias, logicalDBName); err != nil {
+		return err
+	}
+	migrations, err := s.readMigrations(ctx, dbAlias)
+	if err != nil {
+		return err
+	}
+
+	migration.Version = 1
+	if len(migrations) > 0 {
+		migration.Version = migrations[len(migrations)-1].Version + 1
+	}
+	migration.Status = model.MigrationPending
+
+	up, _ := json.Marshal(migration.Up)
+	down, _ := json.Marshal(migration.Down)
+	doc := map[string]interface{}{
+		"_id":                   strconv.Itoa(migration.Version),
+		"version":               migration.Version,
+		"table_name":            migration.Table,
+		"table_schema":          migration.Schema,
+		"previous_table_schema": migration.PreviousSchema,
+		"up_queries":            string(up),
+		"down_queries":          string(down),
+		"status":                migration.Status,
+	}
+	if err := s.crud.InternalCreate(ctx, dbAlias, s.project, utils.TableSchemaMigrations, &model.CreateRequest{Document: doc, Operation: utils.One}, true); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to record schema migration of table (%s)", migration.Table), err, nil)
+	}
+
+	if err := s.crud.RawBatch(ctx, dbAlias, migration.Up); err != nil {
+		// The queries run in a transaction, so the failed migration is discarded. It stays pending if that fails too
+		deleteRequest := &model.DeleteRequest{Find: map[string]interface{}{"_id": strconv.Itoa(migration.Version)}, Operation: utils.All}
+		if err := s.crud.InternalDelete(ctx, dbAlias, s.project, utils.TableSchemaMigrations, deleteRequest); err != nil {
+			helpers.Logger.LogWarn(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to discard pending schema migration (%d) - %v", migration.Version, err), nil)
+		}
+		return err
+	}
+
+	if err := s.setMigrationStatus(ctx, dbAlias, migration, model.MigrationApplied); err != nil {
+		return err
+	}
+
+	if err := s.crud.InternalDelete(ctx, dbAlias, s.project, utils.TableSchemaMigrations, &model.DeleteRequest{Find: map[string]interface{}{"status": model.MigrationRolledBack}, Operation: utils.All}); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to discard rolled back schema migrations", err, nil)
+	}
+	return nil
+}
+
+// runMigration marks a recorded migration as pending and runs the queries provided. The previous status of the
+// migration is restored if the queries fail.
+func (s *Schema) runMigration(ctx context.Context, dbAlias string, migration *model.SchemaMigration, queries []string, status string) error {
+	previousStatus := migration.Status
+	if err := s.setMigrationStatus(ctx, dbAlias, migration, model.MigrationPending); err != nil {
+		return err
+	}
+	if err := s.crud.RawBatch(ctx, dbAlias, queries); err != nil {
+		if err := s.setMigrationStatus(ctx, dbAlias, migration, previousStatus); err != nil {
+			helpers.Logger.LogWarn(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to restore status of schema migration (%d) - %v", migration.Version, err), nil)
+		}
+		return err
+	}
+	return nil
+}
+
+// createMigrationsTable creates the table storing the migrations of the database if it doesn't exist
+func (s *Schema) createMigrationsTable(ctx context.Context, dbAlias, logicalDBName string) error {
+	parsedSchema, err := schemaHelpers.Parser(config.DatabaseSchemas{utils.TableSchemaMigrations: &config.DatabaseSchema{Table: utils.TableSchemaMigrations, DbAlias: dbAlias, Schema: utils.SchemaSchemaMigrations}})
+	if err != nil {
+		return err
+	}
+	if err := s.SchemaCreation(ctx, dbAlias, utils.TableSchemaMigrations, logicalDBName, parsedSchema); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to create table for schema migrations", err, nil)
+	}
+	return nil
+}
+
+func (s *Schema) readMigrations(ctx context.Context, dbAlias string) ([]*model.SchemaMigration, error) {
+	readRequest := &model.ReadRequest{Find: map[string]interface{}{}, Operation: utils.All, Options: &model.ReadOptions{ReadYourWrites: true}}
+	result, _, err := s.crud.Read(ctx, dbAlias, utils.TableSchemaMigrations, readRequest, model.RequestParams{})
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read schema migrations", err, nil)
+	}
+
+	rows, _ := result.([]interface{})
+	migrations := make([]*model.SchemaMigration, 0, len(rows))
+	for _, row := range rows {
+		doc, ok := row.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		migration, err := parseMigration(doc)
+		if err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to parse schema migration", err, nil)
+		}
+		migration.DbAlias = dbAlias
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func (s *Schema) setMigrationStatus(ctx context.Context, dbAlias string, migration *model.SchemaMigration, status string) error {
+	updateRequest := &model.UpdateRequest{
+		Find:      map[string]interface{}{"_id": strconv.Itoa(migration.Version)},
+		Operation: utils.All,
+		Update:    map[string]interface{}{"$set": map[string]interface{}{"status": status}},
+	}
+	if err := s.crud.InternalUpdate(ctx, dbAlias, s.project, utils.TableSchemaMigrations, updateRequest); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to update status of schema migration (%d)", migration.Version), err, nil)
+	}
+	migration.Status = status
+	return nil
+}
+
+// getMigrationsToApply returns the rolled back migrations till the version provided in ascending order
+func getMigrationsToApply(migrations []*model.SchemaMigration, version int) ([]*model.SchemaMigration, error) {
+	if !containsMigration(migrations, version) {
+		return nil, fmt.Errorf("schema migration (%d) does not exist", version)
+	}
+
+	pending := make([]*model.SchemaMigration, 0)
+	for _, migration := range migrations {
+		if migration.Version > version {
+			break
+		}
+		if migration.Status == model.MigrationRolledBack {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// getMigrationsToRollback returns the applied migrations newer than the version provided in descending order. Version 0
+// rolls back all the migrations.
+func getMigrationsToRollback(migrations []*model.SchemaMigration, version int) ([]*model.SchemaMigration, error) {
+	if version != 0 && !containsMigration(migrations, version) {
+		return nil, fmt.Errorf("schema migration (%d) does not exist", version)
+	}
+
+	applied := make([]*model.SchemaMigration, 0)
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if migration.Version <= version {
+			break
+		}
+		if migration.Status == model.MigrationApplied {
+			applied = append(applied, migration)
+		}
+	}
+	return applied, nil
+}
+
+func containsMigration(migrations []*model.SchemaMigration, version int) bool {
+	for _, migration := range migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMigration converts a row of the migrations table into a migration. The types of the values returned depend on
+// the database.
+func parseMigration(doc map[string]interface{}) (*model.SchemaMigration, error) {
+	version, err := toInt(doc["version"])
+	if err != nil {
+		return nil, err
+	}
+
+	migration := &model.SchemaMigration{
+		Version:        version,
+		Table:          toString(doc["table_name"]),
+		Schema:         toString(doc["table_schema"]),
+		PreviousSchema: toString(doc["previous_table_schema"]),
+		Status:         toString(doc["status"]),
+	}
+	if err := json.Unmarshal([]byte(toString(doc["up_queries"])), &migration.Up); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(toString(doc["down_queries"])), &migration.Down); err != nil {
+		return nil, err
+	}
+	return migration, nil
+}
+
+func toInt(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int32:
+		return int(v), nil
+	case int64:
+		return int(v), nil
+	case float64:
+		return int(v), nil
+	case string:
+		return strconv.Atoi(v)
+	case []byte:
+		return strconv.Atoi(string(v))
+	default:
+		return 0, fmt.Errorf("invalid type (%T) provided for version", value)
+	}
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,224 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func TestSchema_PlanSchemaModification(t *testing.T) {
+	tests := []struct {
+		name    string
+		dbType  string
+		schemas config.DatabaseSchemas
+		want    []*model.SchemaMigration
+		wantErr bool
+	}{
+		{
+			name:   "new table is dropped on rollback",
+			dbType: string(model.Postgres),
+			schemas: config.DatabaseSchemas{
+				"users": &config.DatabaseSchema{Table: "users", DbAlias: "db", Schema: `type users { id: ID! @primary name: String }`},
+			},
+			want: []*model.SchemaMigration{
+				{
+					DbAlias: "db",
+					Table:   "users",
+					Schema:  `type users { id: ID! @primary name: String }`,
+					Up:      []string{"CREATE TABLE public.users (id character varying(100) NOT NULL , name text ,PRIMARY KEY (id));"},
+					Down:    []string{"DROP TABLE public.users"},
+				},
+			},
+		},
+		{
+			name:   "joint tables created with a table are dropped on rollback",
+			dbType: string(model.Postgres),
+			schemas: config.DatabaseSchemas{
+				"users": &config.DatabaseSchema{Table: "users", DbAlias: "db", Schema: `type users { id: ID! @primary team_id: ID @foreign(table: "teams", field: "id") }`},
+				"teams": &config.DatabaseSchema{Table: "teams", DbAlias: "db", Schema: `type teams { id: ID! @primary name: String }`},
+			},
+			want: []*model.SchemaMigration{
+				{
+					DbAlias: "db",
+					Table:   "teams",
+					Schema:  `type teams { id: ID! @primary name: String }`,
+					Up:      []string{"CREATE TABLE public.teams (id character varying(100) NOT NULL , name text ,PRIMARY KEY (id));"},
+					Down:    []string{"DROP TABLE public.teams"},
+				},
+				{
+					DbAlias: "db",
+					Table:   "users",
+					Schema:  `type users { id: ID! @primary team_id: ID @foreign(table: "teams", field: "id") }`,
+					Up: []string{
+						"CREATE TABLE public.teams (id character varying(100) NOT NULL , name text ,PRIMARY KEY (id));",
+						"CREATE TABLE public.users (id character varying(100) NOT NULL , team_id character varying(100) ,PRIMARY KEY (id));",
+						"ALTER TABLE public.users ADD CONSTRAINT c_users_team_id FOREIGN KEY (team_id) REFERENCES public.teams (id)",
+					},
+					Down: []string{"DROP TABLE public.users", "DROP TABLE public.teams"},
+				},
+			},
+		},
+		{
+			name:   "mongo has no migrations",
+			dbType: string(model.Mongo),
+			schemas: config.DatabaseSchemas{
+				"users": &config.DatabaseSchema{Table: "users", DbAlias: "db", Schema: `type users { id: ID! @primary name: String }`},
+			},
+			want: []*model.SchemaMigration{},
+		},
+		{
+			name:   "invalid schema",
+			dbType: string(model.Postgres),
+			schemas: config.DatabaseSchemas{
+				"users": &config.DatabaseSchema{Table: "users", DbAlias: "db", Schema: `type users { id: ID! @primary name: String`},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCrud := mockCrudSchemaInterface{}
+			mockCrud.On("GetDBType", "db").Return(tt.dbType)
+
+			s := &Schema{clusterID: "chicago", crud: &mockCrud}
+			got, err := s.PlanSchemaModification(context.Background(), "db", "public", tt.schemas)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PlanSchemaModification() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				a, _ := json.Marshal(got)
+				b, _ := json.Marshal(tt.want)
+				t.Errorf("PlanSchemaModification() got = %s, want %s", a, b)
+			}
+		})
+	}
+}
+
+func Test_getMigrationsToApplyAndRollback(t *testing.T) {
+	migrations := []*model.SchemaMigration{
+		{Version: 1, Status: model.MigrationApplied},
+		{Version: 2, Status: model.MigrationApplied},
+		{Version: 3, Status: model.MigrationRolledBack},
+		{Version: 4, Status: model.MigrationRolledBack},
+	}
+
+	tests := []struct {
+		name       string
+		isRollback bool
+		version    int
+		want       []int
+		wantErr    bool
+	}{
+		{name: "apply till a rolled back version", version: 3, want: []int{3}},
+		{name: "apply all the migrations", version: 4, want: []int{3, 4}},
+		{name: "apply an applied version", version: 2, want: []int{}},
+		{name: "apply an unknown version", version: 5, wantErr: true},
+		{name: "rollback till a version", isRollback: true, version: 1, want: []int{2}},
+		{name: "rollback all the migrations", isRollback: true, version: 0, want: []int{2, 1}},
+		{name: "rollback an unknown version", isRollback: true, version: 7, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []*model.SchemaMigration
+			var err error
+			if tt.isRollback {
+				got, err = getMigrationsToRollback(migrations, tt.version)
+			} else {
+				got, err = getMigrationsToApply(migrations, tt.version)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			versions := make([]int, 0)
+			for _, migration := range got {
+				versions = append(versions, migration.Version)
+			}
+			if !reflect.DeepEqual(versions, tt.want) {
+				t.Errorf("got versions = %v, want %v", versions, tt.want)
+			}
+		})
+	}
+}
+
+// migrationCrud records the writes made while running a schema migration
+type migrationCrud struct {
+	mockCrudSchemaInterface
+	ops      []string
+	rawError error
+}
+
+func (m *migrationCrud) RawBatch(ctx context.Context, dbAlias string, batchedQueries []string) error {
+	// The queries creating the migrations table aren't part of the migration
+	if len(batchedQueries) == 0 || strings.Contains(batchedQueries[0], utils.TableSchemaMigrations) {
+		return nil
+	}
+	m.ops = append(m.ops, "raw")
+	return m.rawError
+}
+
+func (m *migrationCrud) InternalCreate(ctx context.Context, dbAlias, project, col string, req *model.CreateRequest, isIgnoreMetrics bool) error {
+	m.ops = append(m.ops, "create "+req.Document.(map[string]interface{})["status"].(string))
+	return nil
+}
+
+func (m *migrationCrud) InternalUpdate(ctx context.Context, dbAlias, project, col string, req *model.UpdateRequest) error {
+	m.ops = append(m.ops, "update "+req.Update["$set"].(map[string]interface{})["status"].(string))
+	return nil
+}
+
+func (m *migrationCrud) InternalDelete(ctx context.Context, dbAlias, project, col string, req *model.DeleteRequest) error {
+	m.ops = append(m.ops, "delete")
+	return nil
+}
+
+func TestSchema_applyNewMigration(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawError error
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "migration is recorded as pending before running its queries",
+			want: []string{"create pending", "raw", "update applied", "delete"},
+		},
+		{
+			name:     "failed migration is discarded",
+			rawError: errors.New("syntax error"),
+			want:     []string{"create pending", "raw", "delete"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crud := &migrationCrud{rawError: tt.rawError}
+			crud.On("GetDBType", "db").Return(string(model.Postgres))
+
+			s := &Schema{clusterID: "chicago", project: "project", crud: crud}
+			migration := &model.SchemaMigration{DbAlias: "db", Table: "users", Up: []string{"CREATE TABLE public.users (id character varying(100) NOT NULL ,PRIMARY KEY (id));"}, Down: []string{"DROP TABLE public.users"}}
+			err := s.applyNewMigration(context.Background(), "db", "public", migration)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("applyNewMigration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(crud.ops, tt.want) {
+				t.Errorf("applyNewMigration() got operations = %v, want %v", crud.ops, tt.want)
+			}
+			if migration.Version != 1 {
+				t.Errorf("applyNewMigration() got version = %d, want 1", migration.Version)
+			}
+		})
+	}
+}
@@ -16,6 +16,9 @@ type Schema struct {
 	project   string
 	dbSchemas config.DatabaseSchemas
 	clusterID string
+
+	// migrationLock makes sure only one schema migration gets versioned and run at a time
+	migrationLock sync.Mutex
 }
 
 // Init creates a new instance of the schema object
@@ -59,6 +62,22 @@ func (s *Schema) GetSchema(dbAlias, col string) (model.Fields, bool) {
 	return fields, true
 }
 
+// GetSchemas returns the parsed schemas of all the tables
+func (s *Schema) GetSchemas() model.Type {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	schemas := make(model.Type, len(s.SchemaDoc))
+	for dbAlias, dbSchema := range s.SchemaDoc {
+		collection := make(model.Collection, len(dbSchema))
+		for col, fields := range dbSchema {
+			collection[col] = fields
+		}
+		schemas[dbAlias] = collection
+	}
+	return schemas
+}
+
 // parseSchema Initializes Schema field in Module struct
 func (s *Schema) parseSchema(crud config.DatabaseSchemas) error {
 	schema, err := schemaHelpers.Parser(crud)
@@ -70,6 +70,19 @@ func generateSDL(schemaCol model.Collection) (string, error) {
 		"{{if $fieldValue.IsUpdatedAt}}" +
 		"@updatedAt " +
 		"{{end}}" +
+		"{{if $fieldValue.IsVersion}}" +
+		"@version " +
+		"{{end}}" +
+		"{{if $fieldValue.IsSoftDelete}}" +
+		"@softDelete " +
+		"{{end}}" +
+		"{{if $fieldValue.ExpireAfter}}" +
+		"{{if eq (deref $fieldValue.ExpireAfter) 0}}" +
+		"@expiresAt " +
+		"{{else}}" +
+		"@ttl(seconds: {{deref $fieldValue.ExpireAfter}}) " +
+		"{{end}}" +
+		"{{end}}" +
 
 		// @unique or @index directive
 		"{{ range $i, $sequence :=  (repeat 2) }}" + // for loop indexInfo
@@ -84,6 +97,13 @@ func generateSDL(schemaCol model.Collection) (string, error) {
 		"{{end}}" +
 		"{{end}}" + // for loop indexInfo
 
+		// @fulltext directive
+		"{{range $k,$v := $fieldValue.IndexInfo }}" +
+		"{{if $v.IsFullText}}" +
+		"@fulltext(name: \"{{$v.Group}}\") " +
+		"{{end}}" +
+		"{{end}}" +
+
 		// @default directive
 		"{{if $fieldValue.IsDefault}}" +
 		"@default(value: {{$fieldValue.Default}}) " +
@@ -146,6 +166,9 @@ func generateSDL(schemaCol model.Collection) (string, error) {
 		"inc": func(n int) int {
 			return n + 1
 		},
+		"deref": func(n *int64) int64 {
+			return *n
+		},
 	}
 
 	buf := &bytes.Buffer{}
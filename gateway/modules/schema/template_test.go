@@ -163,6 +163,38 @@ func Test_generateSDL(t *testing.T) {
 				"\n}",
 			wantErr: false,
 		},
+		{
+			name: "Successful test with ttl directives",
+			args: args{
+				schemaCol: model.Collection{"session": model.Fields{
+					"created_at": &model.FieldType{
+						FieldName:   "created_at",
+						Kind:        model.TypeDateTime,
+						ExpireAfter: func() *int64 { v := int64(3600); return &v }(),
+					},
+				}},
+			},
+			want: "type session {" +
+				"\n\tcreated_at: DateTime @ttl(seconds: 3600)" +
+				"\n}",
+			wantErr: false,
+		},
+		{
+			name: "Successful test with expires at directive",
+			args: args{
+				schemaCol: model.Collection{"otp": model.Fields{
+					"expires_at": &model.FieldType{
+						FieldName:   "expires_at",
+						Kind:        model.TypeDateTime,
+						ExpireAfter: func() *int64 { v := int64(0); return &v }(),
+					},
+				}},
+			},
+			want: "type otp {" +
+				"\n\texpires_at: DateTime @expiresAt" +
+				"\n}",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -24,3 +24,19 @@ func (m *mockCrudSchemaInterface) DescribeTable(ctx context.Context, dbAlias, co
 func (m *mockCrudSchemaInterface) RawBatch(ctx context.Context, dbAlias string, batchedQueries []string) error {
 	return nil
 }
+
+func (m *mockCrudSchemaInterface) InternalCreate(ctx context.Context, dbAlias, project, col string, req *model.CreateRequest, isIgnoreMetrics bool) error {
+	return nil
+}
+
+func (m *mockCrudSchemaInterface) InternalUpdate(ctx context.Context, dbAlias, project, col string, req *model.UpdateRequest) error {
+	return nil
+}
+
+func (m *mockCrudSchemaInterface) InternalDelete(ctx context.Context, dbAlias, project, col string, req *model.DeleteRequest) error {
+	return nil
+}
+
+func (m *mockCrudSchemaInterface) Read(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error) {
+	return []interface{}{}, nil, nil
+}
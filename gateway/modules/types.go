@@ -23,4 +23,7 @@ type RealtimeInterface interface {
 type GraphQLInterface interface {
 	GetDBAlias(ctx context.Context, field *ast.Field, token string, store utils.M) (string, error)
 	ExecGraphQLQuery(ctx context.Context, req *model.GraphQLRequest, token string, cb model.GraphQLCallback)
+	GenerateSDL(ctx context.Context) (string, error)
+	CheckPersistedQuery(ctx context.Context, query string, doc *ast.Document, token string) error
+	CheckQueryLimits(ctx context.Context, doc *ast.Document, variables map[string]interface{}) error
 }
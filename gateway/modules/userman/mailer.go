@@ -0,0 +1,236 @@
+package userman
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	htmlTemplate "html/template"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Mailer sends emails to the users of a project
+type Mailer interface {
+	SendMail(ctx context.Context, mail *Mail) error
+}
+
+// Mail is an email to be sent to a user
+type Mail struct {
+	From    string `json:"from,omitempty"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	HTML    bool   `json:"html"`
+}
+
+const (
+	mailTypeSMTP    = "smtp"
+	mailTypeWebhook = "webhook"
+
+	mailVerifyEmail   = "verify-email"
+	mailResetPassword = "reset-password"
+)
+
+var defaultMailTemplates = map[string]*config.MailTemplate{
+	mailVerifyEmail: {
+		Subject: "Verify your email",
+		Body:    "Hi {{.user.name}},\n\nUse the token below to verify your email. The token expires at {{.expiresAt.Format \"Jan 2, 2006 15:04 MST\"}}.\n\n{{.token}}\n",
+	},
+	mailResetPassword: {
+		Subject: "Reset your password",
+		Body:    "Hi {{.user.name}},\n\nUse the token below to reset your password. The token expires at {{.expiresAt.Format \"Jan 2, 2006 15:04 MST\"}}. You can ignore this email if you did not ask to reset your password.\n\n{{.token}}\n",
+	},
+}
+
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// mailTemplate holds the parsed templates of an email
+type mailTemplate struct {
+	subject executor
+	body    executor
+	html    bool
+}
+
+// SetMailer sets a mailer which is used instead of the one created from the config of the email provider
+func (m *Module) SetMailer(mailer Mailer) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.customMailer = mailer
+}
+
+// setMailerConfig creates the mailer and parses the templates of the emails as per the config of the email provider
+func (m *Module) setMailerConfig(stub *config.AuthStub) error {
+	m.mailer = nil
+	m.mailTemplates = map[string]*mailTemplate{}
+
+	var templates map[string]*config.MailTemplate
+	if stub != nil && stub.Mailer != nil {
+		mailer, err := newMailer(stub.Mailer)
+		if err != nil {
+			return err
+		}
+		m.mailer = mailer
+		templates = stub.Mailer.Templates
+	}
+
+	for kind, defaultTmpl := range defaultMailTemplates {
+		tmpl, p := templates[kind]
+		if !p || tmpl == nil {
+			tmpl = defaultTmpl
+		}
+		parsed, err := parseMailTemplate(kind, tmpl)
+		if err != nil {
+			return err
+		}
+		m.mailTemplates[kind] = parsed
+	}
+	return nil
+}
+
+// sendMail sends an email of the provided kind to a user
+func (m *Module) sendMail(ctx context.Context, kind string, user map[string]interface{}, data map[string]interface{}) error {
+	m.RLock()
+	mailer, tmpl := m.mailer, m.mailTemplates[kind]
+	if m.customMailer != nil {
+		mailer = m.customMailer
+	}
+	m.RUnlock()
+
+	if mailer == nil || tmpl == nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Mailer has not been configured for the email provider", nil, nil)
+	}
+
+	data["user"] = user
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to execute subject template of email (%s)", kind), err, nil)
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to execute body template of email (%s)", kind), err, nil)
+	}
+
+	email, _ := user["email"].(string)
+	mail := &Mail{To: email, Subject: subject.String(), Body: body.String(), HTML: tmpl.html}
+	if err := mailer.SendMail(ctx, mail); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to send email (%s)", kind), err, nil)
+	}
+	return nil
+}
+
+func parseMailTemplate(kind string, tmpl *config.MailTemplate) (*mailTemplate, error) {
+	subject, err := template.New(kind + "-subject").Parse(tmpl.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subject template of email (%s) - %v", kind, err)
+	}
+
+	var body executor
+	if tmpl.HTML {
+		body, err = htmlTemplate.New(kind + "-body").Parse(tmpl.Body)
+	} else {
+		body, err = template.New(kind + "-body").Parse(tmpl.Body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template of email (%s) - %v", kind, err)
+	}
+	return &mailTemplate{subject: subject, body: body, html: tmpl.HTML}, nil
+}
+
+func newMailer(c *config.Mailer) (Mailer, error) {
+	switch c.Type {
+	case mailTypeSMTP:
+		if c.Host == "" || c.From == "" {
+			return nil, fmt.Errorf("host and from are required for the smtp mailer")
+		}
+		return &smtpMailer{config: c}, nil
+	case mailTypeWebhook:
+		if c.URL == "" {
+			return nil, fmt.Errorf("url is required for the webhook mailer")
+		}
+		return &webhookMailer{config: c}, nil
+	default:
+		return nil, fmt.Errorf("invalid mailer type (%s) provided", c.Type)
+	}
+}
+
+// smtpMailer sends emails through an smtp server. The connection is upgraded with STARTTLS if the server supports it
+type smtpMailer struct {
+	config *config.Mailer
+}
+
+func (s *smtpMailer) SendMail(ctx context.Context, mail *Mail) error {
+	port := s.config.Port
+	if port == 0 {
+		port = 587
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+	return smtp.SendMail(net.JoinHostPort(s.config.Host, strconv.Itoa(port)), auth, s.config.From, []string{mail.To}, buildMessage(s.config.From, mail))
+}
+
+// buildMessage returns the mail formatted as per RFC 5322. Line breaks are removed from the headers to prevent header
+// injection through the fields of the user
+func buildMessage(from string, mail *Mail) []byte {
+	contentType := "text/plain"
+	if mail.HTML {
+		contentType = "text/html"
+	}
+
+	replacer := strings.NewReplacer("\r", "", "\n", "")
+	var b strings.Builder
+	b.WriteString("From: " + replacer.Replace(from) + "\r\n")
+	b.WriteString("To: " + replacer.Replace(mail.To) + "\r\n")
+	b.WriteString("Subject: " + replacer.Replace(mail.Subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: " + contentType + "; charset=\"UTF-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(mail.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// webhookMailer posts the emails to a webhook which is responsible for delivering them
+type webhookMailer struct {
+	config *config.Mailer
+}
+
+func (w *webhookMailer) SendMail(ctx context.Context, mail *Mail) error {
+	mail.From = w.config.From
+	data, err := json.Marshal(mail)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	w.config.Headers.UpdateHeader(req.Header)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer utils.CloseTheCloser(res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook (%s) returned status code (%d)", w.config.URL, res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,475 @@
+package userman
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	totpDigits     = 6
+	totpPeriod     = 30
+	totpSkew       = 1
+	totpSecretSize = 20
+
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 10
+
+	// The number of attempts to verify a one time password allowed before the user is locked out for a while
+	mfaMaxAttempts   = 5
+	mfaLockoutPeriod = 15 * time.Minute
+
+	mfaSignIn            = "mfa-sign-in"
+	mfaSignInTokenExpiry = 5 * time.Minute
+
+	// The authentication methods of the amr claim as per RFC 8176
+	amrPassword  = "pwd"
+	amrFederated = "fed"
+	amrOTP       = "otp"
+	amrMFA       = "mfa"
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EnrollMFA creates a new totp secret for the user the token belongs to. The secret needs to be confirmed with a one
+// time password generated from it before multi factor authentication gets enabled. It returns the secret along with
+// the otpauth uri to be shown as a QR code to the user
+func (m *Module) EnrollMFA(ctx context.Context, token, dbAlias, project string) (int, map[string]interface{}, error) {
+	if !m.IsEnabled() {
+		return http.StatusNotFound, nil, errors.New("This feature isn't enabled")
+	}
+
+	userObj, status, err := m.getUserFromToken(ctx, token, dbAlias, project)
+	if err != nil {
+		return status, nil, err
+	}
+	if isMFAEnabled(userObj) {
+		return http.StatusConflict, nil, errors.New("Multi factor authentication is already enabled")
+	}
+
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to generate totp secret", err, nil)
+	}
+	encodedSecret := base32NoPadding.EncodeToString(secret)
+
+	encryptedSecret, err := m.encryptMFASecret(encodedSecret)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	if err := m.updateUser(ctx, dbAlias, project, userObj[idString], map[string]interface{}{"mfaSecret": encryptedSecret, "mfaEnabled": false, "mfaLastCounter": 0, "mfaAttempts": 0}); err != nil {
+		return http.StatusInternalServerError, nil, errors.New("Failed to enroll for multi factor authentication")
+	}
+
+	email, _ := userObj["email"].(string)
+	return http.StatusOK, map[string]interface{}{"secret": encodedSecret, "uri": generateOTPAuthURI(project, email, encodedSecret)}, nil
+}
+
+// EnableMFA enables multi factor authentication for the user the token belongs to once the one time password generated
+// from the enrolled secret is verified. It returns the recovery codes which can be used instead of a one time password
+func (m *Module) EnableMFA(ctx context.Context, token, dbAlias, project, code string) (int, map[string]interface{}, error) {
+	if !m.IsEnabled() {
+		return http.StatusNotFound, nil, errors.New("This feature isn't enabled")
+	}
+
+	userObj, status, err := m.getUserFromToken(ctx, token, dbAlias, project)
+	if err != nil {
+		return status, nil, err
+	}
+	if isMFAEnabled(userObj) {
+		return http.StatusConflict, nil, errors.New("Multi factor authentication is already enabled")
+	}
+
+	secret, err := m.getMFASecret(userObj)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	counter, ok := validateTOTP(secret, code, time.Now(), getInt64(userObj["mfaLastCounter"]))
+	if !ok {
+		return http.StatusUnauthorized, nil, errors.New("Invalid one time password provided")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to generate recovery codes", err, nil)
+	}
+
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	if err := m.updateUser(ctx, dbAlias, project, userObj[idString], map[string]interface{}{"mfaEnabled": true, "mfaRecoveryCodes": hashes, "mfaLastCounter": counter, "mfaAttempts": 0}); err != nil {
+		return http.StatusInternalServerError, nil, errors.New("Failed to enable multi factor authentication")
+	}
+	return http.StatusOK, map[string]interface{}{"recoveryCodes": codes}, nil
+}
+
+// DisableMFA disables multi factor authentication for the user the token belongs to. A one time password or a recovery
+// code needs to be provided. The number of attempts is limited like while signing in
+func (m *Module) DisableMFA(ctx context.Context, token, dbAlias, project, code string) (int, error) {
+	if !m.IsEnabled() {
+		return http.StatusNotFound, errors.New("This feature isn't enabled")
+	}
+
+	userObj, status, err := m.getUserFromToken(ctx, token, dbAlias, project)
+	if err != nil {
+		return status, err
+	}
+	if !isMFAEnabled(userObj) {
+		return http.StatusBadRequest, errors.New("Multi factor authentication is not enabled")
+	}
+
+	if status, err := m.verifyMFACode(ctx, dbAlias, project, userObj, code); err != nil {
+		return status, err
+	}
+
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if err := m.updateUser(ctx, dbAlias, project, userObj[idString], map[string]interface{}{"mfaEnabled": false, "mfaSecret": "", "mfaRecoveryCodes": ""}); err != nil {
+		return http.StatusInternalServerError, errors.New("Failed to disable multi factor authentication")
+	}
+	return http.StatusOK, nil
+}
+
+// MFASignIn completes the sign in of a user who has enabled multi factor authentication. The mfa token returned by the
+// first step of the sign in can be used only once, so a new sign in is required if an invalid code is provided
+func (m *Module) MFASignIn(ctx context.Context, dbAlias, project, mfaToken, code string) (int, map[string]interface{}, error) {
+	if !m.IsEnabled() {
+		return http.StatusNotFound, nil, errors.New("This feature isn't enabled")
+	}
+
+	tokenObj, err := m.useUserToken(ctx, dbAlias, project, mfaSignIn, mfaToken)
+	if err != nil {
+		return http.StatusUnauthorized, nil, err
+	}
+
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	readReq := &model.ReadRequest{Find: map[string]interface{}{idString: tokenObj["user_id"]}, Operation: utils.One}
+	user, _, err := m.crud.Read(ctx, dbAlias, "users", readReq, getRequestParams(project, dbAlias, "users", "db-read"))
+	if err != nil {
+		return http.StatusUnauthorized, nil, errors.New("User not found")
+	}
+	userObj := user.(map[string]interface{})
+
+	if status, err := m.verifyMFACode(ctx, dbAlias, project, userObj, code); err != nil {
+		return status, nil, err
+	}
+
+	amr, _ := tokenObj["amr"].(string)
+	return m.issueSession(ctx, dbAlias, project, userObj, append(strings.Fields(amr), amrOTP, amrMFA))
+}
+
+// completeSignIn issues the tokens for a user who has signed in with the provided authentication methods. If the user
+// has enabled multi factor authentication, a token to complete the sign in with a one time password is returned instead
+func (m *Module) completeSignIn(ctx context.Context, dbAlias, project string, userObj map[string]interface{}, amr []string) (int, map[string]interface{}, error) {
+	if !isMFAEnabled(userObj) {
+		return m.issueSession(ctx, dbAlias, project, userObj, amr)
+	}
+
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	expiresAt := time.Now().UTC().Add(mfaSignInTokenExpiry)
+	mfaToken, err := m.createUserToken(ctx, dbAlias, project, mfaSignIn, userObj[idString], expiresAt, map[string]interface{}{"amr": strings.Join(amr, " ")})
+	if err != nil {
+		return http.StatusInternalServerError, nil, errors.New("Failed to create multi factor authentication token")
+	}
+	return http.StatusOK, map[string]interface{}{"mfaRequired": true, "mfaToken": mfaToken}, nil
+}
+
+// issueSession creates a session for the user and returns the user along with the tokens of the session
+func (m *Module) issueSession(ctx context.Context, dbAlias, project string, userObj map[string]interface{}, amr []string) (int, map[string]interface{}, error) {
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	deleteSecretFields(userObj)
+
+	claims := map[string]interface{}{"email": userObj["email"], "id": userObj[idString], "role": userObj["role"], "amr": amr}
+	token, refreshToken, err := m.createSession(ctx, dbAlias, project, claims)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	return http.StatusOK, map[string]interface{}{"user": userObj, "token": token, "refreshToken": refreshToken}, nil
+}
+
+// verifyMFACode checks the one time password or the recovery code provided by a user. A one time password can't be
+// reused and a recovery code is removed once it has been used. Every attempt is recorded before the code is checked
+// with an update versioned on the earlier attempts, so that concurrent attempts can't exceed the allowed ones
+func (m *Module) verifyMFACode(ctx context.Context, dbAlias, project string, userObj map[string]interface{}, code string) (int, error) {
+	secret, err := m.getMFASecret(userObj)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	now := time.Now().UTC()
+	attempts := getInt64(userObj["mfaAttempts"])
+	if lastAttemptAt, ok := getTime(userObj["mfaLastAttemptAt"]); ok && now.Sub(lastAttemptAt) > mfaLockoutPeriod {
+		attempts = 0
+	}
+	if attempts >= mfaMaxAttempts {
+		return http.StatusTooManyRequests, errors.New("Too many attempts to verify the one time password. Try again later")
+	}
+
+	updateReq := &model.UpdateRequest{
+		Find:        map[string]interface{}{idString: userObj[idString], "mfaAttempts": userObj["mfaAttempts"]},
+		Operation:   utils.One,
+		Update:      map[string]interface{}{"$set": map[string]interface{}{"mfaAttempts": attempts + 1, "mfaLastAttemptAt": now}},
+		IsVersioned: true,
+	}
+	if err := m.crud.Update(ctx, dbAlias, "users", updateReq, getRequestParams(project, dbAlias, "users", "db-update")); err == utils.ErrVersionConflict {
+		return http.StatusConflict, errors.New("Another attempt to verify the one time password is in progress")
+	} else if err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to record attempt to verify one time password", err, nil)
+	}
+
+	set := map[string]interface{}{"mfaAttempts": 0}
+	if counter, ok := validateTOTP(secret, code, now, getInt64(userObj["mfaLastCounter"])); ok {
+		set["mfaLastCounter"] = counter
+	} else {
+		hashes, _ := userObj["mfaRecoveryCodes"].(string)
+		remaining, ok := useRecoveryCode(hashes, code)
+		if !ok {
+			return http.StatusUnauthorized, errors.New("Invalid one time password provided")
+		}
+		set["mfaRecoveryCodes"] = remaining
+	}
+
+	if err := m.updateUser(ctx, dbAlias, project, userObj[idString], set); err != nil {
+		return http.StatusInternalServerError, errors.New("Failed to verify one time password")
+	}
+	return http.StatusOK, nil
+}
+
+// getUserFromToken returns the user the token belongs to
+func (m *Module) getUserFromToken(ctx context.Context, token, dbAlias, project string) (map[string]interface{}, int, error) {
+	claims, err := m.auth.ParseToken(ctx, token)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+	id, ok := claims["id"]
+	if !ok {
+		return nil, http.StatusBadRequest, errors.New("Claim (id) not present in token")
+	}
+
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	readReq := &model.ReadRequest{Find: map[string]interface{}{idString: id}, Operation: utils.One}
+	user, _, err := m.crud.Read(ctx, dbAlias, "users", readReq, getRequestParams(project, dbAlias, "users", "db-read"))
+	if err != nil {
+		return nil, http.StatusNotFound, errors.New("User not found")
+	}
+	return user.(map[string]interface{}), http.StatusOK, nil
+}
+
+// encryptMFASecret encrypts the totp secret with the aes key of the project using AES-GCM. The random nonce is stored
+// as the prefix of the encrypted secret
+func (m *Module) encryptMFASecret(secret string) (string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if len(m.aesKey) == 0 {
+		return "", errors.New("AES key of the project has not been set")
+	}
+	gcm, err := newGCM(m.aesKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+// getMFASecret decrypts the totp secret of the user
+func (m *Module) getMFASecret(userObj map[string]interface{}) ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	encrypted, _ := userObj["mfaSecret"].(string)
+	if encrypted == "" {
+		return nil, errors.New("User has not enrolled for multi factor authentication")
+	}
+	if len(m.aesKey) == 0 {
+		return nil, errors.New("AES key of the project has not been set")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(m.aesKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) < gcm.NonceSize() {
+		return nil, errors.New("Invalid multi factor authentication secret stored for user")
+	}
+	decrypted, err := gcm.Open(nil, decoded[:gcm.NonceSize()], decoded[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, err
+	}
+	return base32NoPadding.DecodeString(string(decrypted))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// getInt64 returns the integer stored in a field as per the type returned by the database
+func getInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// isMFAEnabled checks the mfaEnabled field of a user as per the type returned by the database
+func isMFAEnabled(userObj map[string]interface{}) bool {
+	switch v := userObj["mfaEnabled"].(type) {
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	}
+	return false
+}
+
+// deleteSecretFields deletes the password and the multi factor authentication secrets from the user
+func deleteSecretFields(userObj map[string]interface{}) {
+	delete(userObj, "pass")
+	delete(userObj, "mfaSecret")
+	delete(userObj, "mfaRecoveryCodes")
+}
+
+// generateOTPAuthURI returns the uri used by authenticator apps to add an account
+func generateOTPAuthURI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", totpDigits))
+	query.Set("period", fmt.Sprintf("%d", totpPeriod))
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + query.Encode()
+}
+
+// generateTOTP generates the one time password for a time step as per RFC 6238
+func generateTOTP(secret []byte, counter uint64) string {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, counter)
+
+	h := hmac.New(sha1.New, secret)
+	_, _ = h.Write(buf)
+	sum := h.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%uint32(math.Pow10(totpDigits)))
+}
+
+// validateTOTP checks the one time password against the time steps adjacent to the current one to allow for clock skew.
+// The time steps up to the last one used are skipped so that a one time password can't be reused. It returns the time
+// step the one time password belongs to
+func validateTOTP(secret []byte, code string, now time.Time, lastCounter int64) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	counter := now.Unix() / totpPeriod
+	var matched int64
+	valid := false
+	for i := -totpSkew; i <= totpSkew; i++ {
+		c := counter + int64(i)
+		if c > lastCounter && subtle.ConstantTimeCompare([]byte(generateTOTP(secret, uint64(c))), []byte(code)) == 1 {
+			matched, valid = c, true
+		}
+	}
+	return matched, valid
+}
+
+// generateRecoveryCodes returns the recovery codes along with their hashes to be stored
+func generateRecoveryCodes() ([]string, string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(b); err != nil {
+			return nil, "", err
+		}
+		code := base32NoPadding.EncodeToString(b)[:recoveryCodeSize]
+		codes[i] = code[:recoveryCodeSize/2] + "-" + code[recoveryCodeSize/2:]
+		hashes[i] = utils.HashString(code)
+	}
+	return codes, strings.Join(hashes, ","), nil
+}
+
+// useRecoveryCode checks the recovery code against the stored hashes. It returns the hashes of the remaining codes
+func useRecoveryCode(hashes, code string) (string, bool) {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	if hashes == "" || code == "" {
+		return hashes, false
+	}
+
+	hash := utils.HashString(code)
+	remaining := make([]string, 0)
+	found := false
+	for _, h := range strings.Split(hashes, ",") {
+		if !found && subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+			found = true
+			continue
+		}
+		remaining = append(remaining, h)
+	}
+	return strings.Join(remaining, ","), found
+}
@@ -0,0 +1,177 @@
+package userman
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func Test_generateTOTP(t *testing.T) {
+	// Test vectors of RFC 6238 truncated to six digits
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := generateTOTP(secret, uint64(tt.unix/totpPeriod)); got != tt.want {
+				t.Errorf("generateTOTP() = %v, want %v", got, tt.want)
+			}
+			counter, ok := validateTOTP(secret, tt.want, time.Unix(tt.unix+totpPeriod, 0), 0)
+			if !ok || counter != tt.unix/totpPeriod {
+				t.Errorf("validateTOTP() rejected code of previous time step")
+			}
+			if _, ok := validateTOTP(secret, tt.want, time.Unix(tt.unix+totpPeriod, 0), counter); ok {
+				t.Errorf("validateTOTP() accepted code of used time step")
+			}
+			if _, ok := validateTOTP(secret, tt.want, time.Unix(tt.unix+3*totpPeriod, 0), 0); ok {
+				t.Errorf("validateTOTP() accepted code of expired time step")
+			}
+		})
+	}
+}
+
+func Test_useRecoveryCode(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("generateRecoveryCodes() error = %v", err)
+	}
+	if len(codes) != recoveryCodeCount || len(strings.Split(hashes, ",")) != recoveryCodeCount {
+		t.Fatalf("generateRecoveryCodes() codes = %v, hashes = %v", codes, hashes)
+	}
+
+	remaining, ok := useRecoveryCode(hashes, strings.ToLower(codes[3]))
+	if !ok || len(strings.Split(remaining, ",")) != recoveryCodeCount-1 {
+		t.Errorf("useRecoveryCode() ok = %v, remaining = %v", ok, remaining)
+	}
+	if _, ok := useRecoveryCode(remaining, codes[3]); ok {
+		t.Errorf("useRecoveryCode() accepted used code")
+	}
+	if _, ok := useRecoveryCode("", ""); ok {
+		t.Errorf("useRecoveryCode() accepted empty code")
+	}
+}
+
+func TestModule_MFA(t *testing.T) {
+	crud := &fakeCrud{users: map[string]map[string]interface{}{}}
+	m := Init(crud, &fakeAuth{})
+	m.SetConfig(config.Auths{"email": &config.AuthStub{ID: "email", Enabled: true}})
+	if err := m.SetProjectAESKey(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))); err != nil {
+		t.Fatalf("SetProjectAESKey() error = %v", err)
+	}
+	ctx := context.Background()
+
+	_, result, err := m.EmailSignUp(ctx, "db", "project", "john@example.com", "John", "pass", "user")
+	if err != nil {
+		t.Fatalf("EmailSignUp() error = %v", err)
+	}
+	token := result["token"].(string)
+
+	status, result, err := m.EnrollMFA(ctx, token, "db", "project")
+	if err != nil {
+		t.Fatalf("EnrollMFA() status = %v, error = %v", status, err)
+	}
+	secret, _ := base32NoPadding.DecodeString(result["secret"].(string))
+	uri, _ := url.Parse(result["uri"].(string))
+	if uri.Scheme != "otpauth" || uri.Query().Get("secret") != result["secret"] || uri.Query().Get("issuer") != "project" {
+		t.Errorf("EnrollMFA() uri = %v", uri)
+	}
+	if stored := crud.users["john@example.com"]["mfaSecret"]; stored == "" || stored == result["secret"] {
+		t.Errorf("EnrollMFA() stored secret = %v", stored)
+	}
+	code := func() string { return generateTOTP(secret, uint64(time.Now().Unix()/totpPeriod)) }
+	// A one time password can be used only once, hence the used time step is forgotten to simulate the next time step
+	nextStep := func() { crud.users["john@example.com"]["mfaLastCounter"] = int64(0) }
+
+	if status, _, _ := m.EnableMFA(ctx, token, "db", "project", "000000x"); status != http.StatusUnauthorized {
+		t.Errorf("EnableMFA() with invalid code status = %v, want %v", status, http.StatusUnauthorized)
+	}
+	status, result, err = m.EnableMFA(ctx, token, "db", "project", code())
+	if err != nil {
+		t.Fatalf("EnableMFA() status = %v, error = %v", status, err)
+	}
+	recoveryCodes := result["recoveryCodes"].([]string)
+
+	// Sign in becomes two step and the mfa token can be used only once
+	_, result, _ = m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	if _, p := result["token"]; p || result["mfaRequired"] != true {
+		t.Fatalf("EmailSignIn() with mfa enabled result = %v", result)
+	}
+	mfaToken := result["mfaToken"].(string)
+	nextStep()
+	if status, _, _ := m.MFASignIn(ctx, "db", "project", mfaToken, "000000"); status != http.StatusUnauthorized {
+		t.Errorf("MFASignIn() with invalid code status = %v, want %v", status, http.StatusUnauthorized)
+	}
+	if status, _, _ := m.MFASignIn(ctx, "db", "project", mfaToken, code()); status != http.StatusUnauthorized {
+		t.Errorf("MFASignIn() with used mfa token status = %v, want %v", status, http.StatusUnauthorized)
+	}
+
+	wantAMR := []interface{}{amrPassword, amrOTP, amrMFA}
+	_, result, _ = m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	status, result, err = m.MFASignIn(ctx, "db", "project", result["mfaToken"].(string), code())
+	if err != nil {
+		t.Fatalf("MFASignIn() status = %v, error = %v", status, err)
+	}
+	if user := result["user"].(map[string]interface{}); user["mfaSecret"] != nil || user["mfaRecoveryCodes"] != nil {
+		t.Errorf("MFASignIn() user contains mfa secrets = %v", user)
+	}
+	claims, _ := m.auth.ParseToken(ctx, result["token"].(string))
+	if !reflect.DeepEqual(claims["amr"], wantAMR) {
+		t.Errorf("MFASignIn() amr = %v, want %v", claims["amr"], wantAMR)
+	}
+	_, reused, _ := m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	if status, _, _ := m.MFASignIn(ctx, "db", "project", reused["mfaToken"].(string), code()); status != http.StatusUnauthorized {
+		t.Errorf("MFASignIn() with used one time password status = %v, want %v", status, http.StatusUnauthorized)
+	}
+
+	// The amr claim is kept while refreshing the token
+	_, result, _ = m.Refresh(ctx, "db", "project", result["refreshToken"].(string))
+	claims, _ = m.auth.ParseToken(ctx, result["token"].(string))
+	if !reflect.DeepEqual(claims["amr"], wantAMR) {
+		t.Errorf("Refresh() amr = %v, want %v", claims["amr"], wantAMR)
+	}
+
+	// Recovery codes can be used only once
+	_, result, _ = m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	if status, _, err := m.MFASignIn(ctx, "db", "project", result["mfaToken"].(string), recoveryCodes[0]); err != nil {
+		t.Errorf("MFASignIn() with recovery code status = %v, error = %v", status, err)
+	}
+	_, result, _ = m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	if status, _, _ := m.MFASignIn(ctx, "db", "project", result["mfaToken"].(string), recoveryCodes[0]); status != http.StatusUnauthorized {
+		t.Errorf("MFASignIn() with used recovery code status = %v, want %v", status, http.StatusUnauthorized)
+	}
+
+	// The attempts to verify a one time password are limited. The attempt with the used recovery code has already failed
+	for i := 1; i < mfaMaxAttempts; i++ {
+		if status, _ := m.DisableMFA(ctx, token, "db", "project", "000000"); status != http.StatusUnauthorized {
+			t.Errorf("DisableMFA() with invalid code status = %v, want %v", status, http.StatusUnauthorized)
+		}
+	}
+	nextStep()
+	if status, _ := m.DisableMFA(ctx, token, "db", "project", code()); status != http.StatusTooManyRequests {
+		t.Errorf("DisableMFA() after too many attempts status = %v, want %v", status, http.StatusTooManyRequests)
+	}
+	crud.users["john@example.com"]["mfaLastAttemptAt"] = time.Now().Add(-mfaLockoutPeriod - time.Minute)
+
+	if status, err := m.DisableMFA(ctx, token, "db", "project", code()); err != nil {
+		t.Fatalf("DisableMFA() status = %v, error = %v", status, err)
+	}
+	_, result, _ = m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	claims, _ = m.auth.ParseToken(ctx, result["token"].(string))
+	if !reflect.DeepEqual(claims["amr"], []interface{}{amrPassword}) {
+		t.Errorf("EmailSignIn() after disabling mfa amr = %v", claims["amr"])
+	}
+}
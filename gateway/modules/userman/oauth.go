@@ -0,0 +1,495 @@
+package userman
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/lestrrat-go/jwx/jwk"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	oauthSessionExpiry = 10 * time.Minute
+	defaultOAuthRole   = "user"
+)
+
+var defaultOAuthScopes = []string{"openid", "email", "profile"}
+
+// providerMetadata holds the endpoints of an OpenID Connect or OAuth2 provider
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// oauthSession holds the state of a sign in between the authorize and callback requests. It is stored in a signed cookie
+type oauthSession struct {
+	State    string `json:"state"`
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+	Redirect string `json:"redirect,omitempty"`
+	Expiry   int64  `json:"exp"`
+}
+
+// tokenResponse is the response of the token endpoint of a provider
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// IsOAuthProvider shows if a sign in method is an OpenID Connect or OAuth2 provider
+func IsOAuthProvider(stub *config.AuthStub) bool {
+	return stub.Issuer != "" || stub.AuthURL != ""
+}
+
+// OAuthAuthorize starts the authorization code flow with PKCE of a provider. It returns the url of the provider to send
+// the user to along with the session to be stored in a cookie till the provider calls back
+func (m *Module) OAuthAuthorize(ctx context.Context, provider, redirect string) (int, string, string, error) {
+	stub, err := m.getOAuthProvider(provider)
+	if err != nil {
+		return http.StatusNotFound, "", "", err
+	}
+	if stub.CallbackURL == "" {
+		return http.StatusBadRequest, "", "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Callback url not provided for sign in provider (%s)", provider), nil, nil)
+	}
+	if redirect != "" && !isRedirectAllowed(stub.RedirectURLs, redirect) {
+		return http.StatusBadRequest, "", "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Redirect url (%s) is not allowed for sign in provider (%s)", redirect, provider), nil, nil)
+	}
+
+	metadata, err := m.getProviderMetadata(ctx, stub)
+	if err != nil {
+		return http.StatusInternalServerError, "", "", err
+	}
+
+	session := &oauthSession{State: generateRandomString(), Verifier: generateRandomString(), Nonce: generateRandomString(), Redirect: redirect, Expiry: time.Now().Add(oauthSessionExpiry).Unix()}
+	cookie, err := m.encodeOAuthSession(session)
+	if err != nil {
+		return http.StatusInternalServerError, "", "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to create sign in session", err, nil)
+	}
+
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return http.StatusInternalServerError, "", "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid authorization endpoint (%s) of sign in provider (%s)", metadata.AuthorizationEndpoint, provider), err, nil)
+	}
+	scopes := stub.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultOAuthScopes
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", stub.ClientID)
+	query.Set("redirect_uri", stub.CallbackURL)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", session.State)
+	query.Set("nonce", session.Nonce)
+	query.Set("code_challenge", generateCodeChallenge(session.Verifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return http.StatusOK, authURL.String(), cookie, nil
+}
+
+// OAuthCallback completes the sign in with a provider. The authorization code is exchanged for the tokens of the user,
+// whose claims are used to create or update the user in the users table. It returns the user along with a JWT token, or
+// a token to complete the sign in if the user has enabled multi factor authentication, and the url of the app to send
+// the user to
+func (m *Module) OAuthCallback(ctx context.Context, dbAlias, project, provider, code, state, cookie string) (int, map[string]interface{}, string, error) {
+	stub, err := m.getOAuthProvider(provider)
+	if err != nil {
+		return http.StatusNotFound, nil, "", err
+	}
+
+	session, err := m.decodeOAuthSession(cookie)
+	if err != nil {
+		return http.StatusUnauthorized, nil, "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid sign in session provided", err, nil)
+	}
+	if subtle.ConstantTimeCompare([]byte(session.State), []byte(state)) != 1 {
+		return http.StatusUnauthorized, nil, "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "State returned by the sign in provider does not match the sign in session", nil, nil)
+	}
+
+	metadata, err := m.getProviderMetadata(ctx, stub)
+	if err != nil {
+		return http.StatusInternalServerError, nil, "", err
+	}
+
+	tokens, err := exchangeCode(ctx, stub, metadata, code, session.Verifier)
+	if err != nil {
+		return http.StatusUnauthorized, nil, "", err
+	}
+
+	var claims map[string]interface{}
+	switch {
+	case tokens.IDToken != "":
+		claims, err = verifyIDToken(ctx, stub, metadata, tokens.IDToken, session.Nonce)
+	case metadata.UserInfoEndpoint != "":
+		claims, err = fetchUserInfo(ctx, metadata.UserInfoEndpoint, tokens.AccessToken)
+	default:
+		err = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Sign in provider (%s) returned neither an id token nor has a user info endpoint", provider), nil, nil)
+	}
+	if err != nil {
+		return http.StatusUnauthorized, nil, "", err
+	}
+
+	fields, isVerified, err := mapClaims(stub.ClaimMapping, claims, stub.TrustEmail)
+	if err != nil {
+		return http.StatusUnauthorized, nil, "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to sign in with provider (%s)", provider), err, nil)
+	}
+
+	status, result, err := m.upsertOAuthUser(ctx, dbAlias, project, fields, isVerified)
+	if err != nil {
+		return status, nil, "", err
+	}
+	return status, result, session.Redirect, nil
+}
+
+// upsertOAuthUser creates the user signing in with a provider if a user with the same email doesn't exist. Else the
+// fields of the existing user are updated with the claims of the provider. A user is linked to an existing one only if
+// the provider has verified the email, since anyone could otherwise take over the account of that email
+func (m *Module) upsertOAuthUser(ctx context.Context, dbAlias, project string, fields map[string]interface{}, isVerified bool) (int, map[string]interface{}, error) {
+	actualDbType, err := m.crud.GetDBType(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	idString := "id"
+	if actualDbType == string(model.Mongo) || actualDbType == string(model.EmbeddedDB) {
+		idString = "_id"
+	}
+
+	// The provider has already verified the email of the user
+	if isVerified && m.isEmailVerificationRequired() {
+		fields["emailVerified"] = true
+	}
+
+	attr := map[string]string{"project": project, "db": dbAlias, "col": "users"}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr}
+	readReq := &model.ReadRequest{Find: map[string]interface{}{"email": fields["email"]}, Operation: utils.One}
+
+	var userObj map[string]interface{}
+	if user, _, err := m.crud.Read(ctx, dbAlias, "users", readReq, reqParams); err == nil {
+		if !isVerified {
+			return http.StatusConflict, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("User with email (%v) already exists and the provider has not verified the email", fields["email"]), nil, nil)
+		}
+		userObj = user.(map[string]interface{})
+		for k, v := range fields {
+			userObj[k] = v
+		}
+
+		reqParams.Resource = "db-update"
+		updateReq := &model.UpdateRequest{Find: map[string]interface{}{idString: userObj[idString]}, Operation: utils.One, Update: map[string]interface{}{"$set": fields}}
+		if err := m.crud.Update(ctx, dbAlias, "users", updateReq, reqParams); err != nil {
+			helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Error %v", err), nil)
+			return http.StatusInternalServerError, nil, errors.New("Failed to update user account")
+		}
+	} else {
+		userObj = fields
+		if _, p := userObj["role"]; !p {
+			userObj["role"] = defaultOAuthRole
+		}
+		userObj[idString] = uuid.NewV1().String()
+
+		reqParams.Resource = "db-create"
+		createReq := &model.CreateRequest{Operation: utils.One, Document: userObj}
+		if err := m.crud.Create(ctx, dbAlias, "users", createReq, reqParams); err != nil {
+			helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Error %v", err), nil)
+			return http.StatusInternalServerError, nil, errors.New("Failed to create user account")
+		}
+	}
+
+	return m.completeSignIn(ctx, dbAlias, project, userObj, []string{amrFederated})
+}
+
+func (m *Module) getOAuthProvider(provider string) (*config.AuthStub, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	stub, p := m.methods[provider]
+	if !p || !stub.Enabled || !IsOAuthProvider(stub) {
+		return nil, fmt.Errorf("sign in provider (%s) is not enabled", provider)
+	}
+	return stub, nil
+}
+
+// getProviderMetadata returns the endpoints of a provider. Endpoints which aren't provided in the config are discovered
+// from the issuer of the provider
+func (m *Module) getProviderMetadata(ctx context.Context, stub *config.AuthStub) (*providerMetadata, error) {
+	m.RLock()
+	metadata, p := m.providers[stub.ID]
+	m.RUnlock()
+	if p {
+		return metadata, nil
+	}
+
+	metadata = &providerMetadata{Issuer: stub.Issuer}
+	if stub.Issuer != "" && (stub.AuthURL == "" || stub.TokenURL == "") {
+		discoveryURL := strings.TrimSuffix(stub.Issuer, "/") + "/.well-known/openid-configuration"
+		if err := getJSON(ctx, discoveryURL, "", metadata); err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to discover the endpoints of sign in provider (%s)", stub.ID), err, nil)
+		}
+	}
+	if stub.AuthURL != "" {
+		metadata.AuthorizationEndpoint = stub.AuthURL
+	}
+	if stub.TokenURL != "" {
+		metadata.TokenEndpoint = stub.TokenURL
+	}
+	if stub.UserInfoURL != "" {
+		metadata.UserInfoEndpoint = stub.UserInfoURL
+	}
+	if stub.JwksURL != "" {
+		metadata.JwksURI = stub.JwksURL
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Authorization and token endpoints of sign in provider (%s) are not known", stub.ID), nil, nil)
+	}
+
+	m.Lock()
+	m.providers[stub.ID] = metadata
+	m.Unlock()
+	return metadata, nil
+}
+
+// exchangeCode exchanges the authorization code returned by a provider for the tokens of the user
+func exchangeCode(ctx context.Context, stub *config.AuthStub, metadata *providerMetadata, code, verifier string) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", stub.CallbackURL)
+	form.Set("client_id", stub.ClientID)
+	form.Set("client_secret", stub.ClientSecret)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to exchange authorization code with sign in provider (%s)", stub.ID), err, nil)
+	}
+	defer utils.CloseTheCloser(res.Body)
+
+	tokens := new(tokenResponse)
+	if err := json.NewDecoder(res.Body).Decode(tokens); err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid token response received from sign in provider (%s)", stub.ID), err, nil)
+	}
+	if res.StatusCode != http.StatusOK || tokens.Error != "" {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Sign in provider (%s) rejected the authorization code with error (%s) %s", stub.ID, tokens.Error, tokens.Description), nil, map[string]interface{}{"statusCode": res.StatusCode})
+	}
+	return tokens, nil
+}
+
+// verifyIDToken verifies the signature and claims of the id token returned by a provider. Asymmetrically signed tokens
+// are verified with the keys of the provider while symmetrically signed ones are verified with the client secret
+func verifyIDToken(ctx context.Context, stub *config.AuthStub, metadata *providerMetadata, idToken, nonce string) (map[string]interface{}, error) {
+	token, err := jwt.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if stub.ClientSecret == "" {
+				return nil, errors.New("client secret of the provider is required to verify a symmetrically signed id token")
+			}
+			return []byte(stub.ClientSecret), nil
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA, *jwt.SigningMethodRSAPSS:
+			if metadata.JwksURI == "" {
+				return nil, errors.New("jwks url of the provider is not known")
+			}
+			set := new(jwk.Set)
+			if err := getJSON(ctx, metadata.JwksURI, "", set); err != nil {
+				return nil, err
+			}
+			keys := set.Keys
+			if kid, ok := token.Header["kid"].(string); ok {
+				keys = set.LookupKeyID(kid)
+			}
+			if len(keys) == 0 {
+				return nil, errors.New("no key found in the jwks of the provider to verify the id token")
+			}
+			var raw interface{}
+			if err := keys[0].Raw(&raw); err != nil {
+				return nil, err
+			}
+			return raw, nil
+		default:
+			return nil, fmt.Errorf("invalid id token algorithm (%s) provided", token.Method.Alg())
+		}
+	})
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to verify the id token returned by sign in provider (%s)", stub.ID), err, nil)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid id token returned by sign in provider (%s)", stub.ID), nil, nil)
+	}
+	if metadata.Issuer != "" && claims["iss"] != metadata.Issuer {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Id token returned by sign in provider (%s) has an invalid issuer (%v)", stub.ID, claims["iss"]), nil, nil)
+	}
+	if !hasAudience(claims["aud"], stub.ClientID) {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Id token returned by sign in provider (%s) is not meant for client (%s)", stub.ID, stub.ClientID), nil, nil)
+	}
+	if claims["nonce"] != nonce {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Id token returned by sign in provider (%s) has an invalid nonce", stub.ID), nil, nil)
+	}
+	return claims, nil
+}
+
+// fetchUserInfo fetches the claims of the user from the user info endpoint of a provider
+func fetchUserInfo(ctx context.Context, userInfoURL, accessToken string) (map[string]interface{}, error) {
+	claims := map[string]interface{}{}
+	if err := getJSON(ctx, userInfoURL, accessToken, &claims); err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to fetch user info from sign in provider", err, nil)
+	}
+	return claims, nil
+}
+
+// mapClaims maps the claims of a provider to the fields of the users table. The email and name claims are mapped to
+// the fields of the same name if no mapping is provided. An email which the provider hasn't verified is rejected. It
+// also returns whether the email has been verified, which is assumed for providers trusted to verify emails if they
+// don't return the email_verified claim
+func mapClaims(mapping map[string]string, claims map[string]interface{}, trustEmail bool) (map[string]interface{}, bool, error) {
+	if len(mapping) == 0 {
+		mapping = map[string]string{"email": "email", "name": "name"}
+	}
+
+	fields := make(map[string]interface{}, len(mapping))
+	for field, claim := range mapping {
+		if value, p := claims[claim]; p {
+			fields[field] = value
+		}
+	}
+
+	email, ok := fields["email"].(string)
+	if !ok || email == "" {
+		return nil, false, errors.New("claims of the provider do not contain an email")
+	}
+	verified, p := claims["email_verified"]
+	if p && verified != true && verified != "true" {
+		return nil, false, fmt.Errorf("email (%s) has not been verified by the provider", email)
+	}
+	return fields, p || trustEmail, nil
+}
+
+func hasAudience(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		for _, item := range v {
+			if item == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRedirectAllowed checks if a url of the app matches one of the allowed redirect urls of a provider
+func isRedirectAllowed(allowed []string, redirect string) bool {
+	for _, prefix := range allowed {
+		if redirect == prefix || strings.HasPrefix(redirect, strings.TrimSuffix(prefix, "/")+"/") || strings.HasPrefix(redirect, prefix+"?") {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeOAuthSession serialises the session and signs it with the aes key of the project
+func (m *Module) encodeOAuthSession(session *oauthSession) (string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if len(m.aesKey) == 0 {
+		return "", errors.New("aes key of the project is not set")
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + signOAuthSession(m.aesKey, payload), nil
+}
+
+// decodeOAuthSession verifies the signature and expiry of a session
+func (m *Module) decodeOAuthSession(cookie string) (*oauthSession, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	arr := strings.Split(cookie, ".")
+	if len(arr) != 2 || len(m.aesKey) == 0 || !hmac.Equal([]byte(arr[1]), []byte(signOAuthSession(m.aesKey, arr[0]))) {
+		return nil, errors.New("session signature is invalid")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(arr[0])
+	if err != nil {
+		return nil, err
+	}
+	session := new(oauthSession)
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, err
+	}
+	if time.Now().Unix() > session.Expiry {
+		return nil, errors.New("session has expired")
+	}
+	return session, nil
+}
+
+func signOAuthSession(key []byte, payload string) string {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+func getJSON(ctx context.Context, url, accessToken string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer utils.CloseTheCloser(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("url (%s) returned status code (%d)", url, res.StatusCode)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func generateRandomString() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func generateCodeChallenge(verifier string) string {
+	h := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
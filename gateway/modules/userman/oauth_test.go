@@ -0,0 +1,387 @@
+package userman
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// mockOIDCServer is a local OpenID Connect provider which signs the id tokens with an rsa key
+type mockOIDCServer struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	clientID string
+	claims   map[string]interface{}
+
+	// Captured from the authorize request to validate the token request
+	nonce, challenge string
+}
+
+func newMockOIDCServer(t *testing.T, clientID string, claims map[string]interface{}) *mockOIDCServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unable to generate rsa key: %v", err)
+	}
+	s := &mockOIDCServer{key: key, clientID: clientID, claims: claims}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 s.URL,
+			"authorization_endpoint": s.URL + "/authorize",
+			"token_endpoint":         s.URL + "/token",
+			"jwks_uri":               s.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []interface{}{map[string]string{
+			"kty": "RSA",
+			"kid": "key1",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.PostForm.Get("code") != "valid-code" || generateCodeChallenge(r.PostForm.Get("code_verifier")) != s.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		claims := jwt.MapClaims{"iss": s.URL, "aud": s.clientID, "sub": "1234", "nonce": s.nonce, "exp": time.Now().Add(time.Hour).Unix()}
+		for k, v := range s.claims {
+			claims[k] = v
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key1"
+		idToken, _ := token.SignedString(key)
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "access", "id_token": idToken})
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// fakeCrud stores the rows of the users table by email and the rows of the other tables by id
+type fakeCrud struct {
+	users   map[string]map[string]interface{}
+	tables  map[string]map[string]map[string]interface{}
+	created int
+	updated int
+
+	// Invoked before an update to simulate a concurrent request
+	beforeUpdate func(col string)
+}
+
+func (c *fakeCrud) GetDBType(dbAlias string) (string, error) {
+	return string(model.Postgres), nil
+}
+
+func (c *fakeCrud) Read(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error) {
+	rows := make([]interface{}, 0)
+	for _, row := range c.table(col) {
+		if matchesFind(row, req.Find) {
+			rows = append(rows, copyDoc(row))
+		}
+	}
+	if req.Operation == utils.All {
+		return rows, nil, nil
+	}
+	if len(rows) == 0 {
+		return nil, nil, errors.New("no match found for specified find clause")
+	}
+	return rows[0], nil, nil
+}
+
+func (c *fakeCrud) Create(ctx context.Context, dbAlias, col string, req *model.CreateRequest, params model.RequestParams) error {
+	doc := req.Document.(map[string]interface{})
+	if col == "users" {
+		c.users[doc["email"].(string)] = copyDoc(doc)
+		c.created++
+		return nil
+	}
+	c.table(col)[doc["id"].(string)] = copyDoc(doc)
+	return nil
+}
+
+func (c *fakeCrud) Update(ctx context.Context, dbAlias, col string, req *model.UpdateRequest, params model.RequestParams) error {
+	if c.beforeUpdate != nil {
+		c.beforeUpdate(col)
+	}
+
+	matched := 0
+	for _, row := range c.table(col) {
+		if matchesFind(row, req.Find) {
+			for k, v := range req.Update["$set"].(map[string]interface{}) {
+				row[k] = v
+			}
+			matched++
+		}
+	}
+	if req.IsVersioned && matched == 0 {
+		return utils.ErrVersionConflict
+	}
+	if col == "users" {
+		c.updated++
+	}
+	return nil
+}
+
+func (c *fakeCrud) Delete(ctx context.Context, dbAlias, col string, req *model.DeleteRequest, params model.RequestParams) error {
+	table := c.table(col)
+	for id, row := range table {
+		if matchesFind(row, req.Find) {
+			delete(table, id)
+		}
+	}
+	return nil
+}
+
+func (c *fakeCrud) table(col string) map[string]map[string]interface{} {
+	if col == "users" {
+		return c.users
+	}
+	if c.tables == nil {
+		c.tables = map[string]map[string]map[string]interface{}{}
+	}
+	if _, p := c.tables[col]; !p {
+		c.tables[col] = map[string]map[string]interface{}{}
+	}
+	return c.tables[col]
+}
+
+func matchesFind(row, find map[string]interface{}) bool {
+	for k, v := range find {
+		if row[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func copyDoc(doc map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		copied[k] = v
+	}
+	return copied
+}
+
+type fakeAuth struct {
+	revoked []string
+}
+
+func (a *fakeAuth) IsReadOpAuthorised(ctx context.Context, project, dbType, col, token string, req *model.ReadRequest, stub model.ReturnWhereStub) (*model.PostProcess, model.RequestParams, error) {
+	return nil, model.RequestParams{}, nil
+}
+
+func (a *fakeAuth) CreateToken(ctx context.Context, tokenClaims model.TokenClaims) (string, error) {
+	data, err := json.Marshal(tokenClaims)
+	return string(data), err
+}
+
+func (a *fakeAuth) IsUpdateOpAuthorised(ctx context.Context, project, dbType, col, token string, req *model.UpdateRequest) (model.RequestParams, error) {
+	return model.RequestParams{}, nil
+}
+
+func (a *fakeAuth) ParseToken(ctx context.Context, token string) (map[string]interface{}, error) {
+	claims := map[string]interface{}{}
+	err := json.Unmarshal([]byte(token), &claims)
+	return claims, err
+}
+
+func (a *fakeAuth) RevokeSessions(ctx context.Context, ids []string) error {
+	a.revoked = append(a.revoked, ids...)
+	return nil
+}
+
+func TestModule_OAuthSignIn(t *testing.T) {
+	tests := []struct {
+		name        string
+		claims      map[string]interface{}
+		users       map[string]map[string]interface{}
+		code        string
+		redirect    string
+		trustEmail  bool
+		tamperState bool
+		wantStatus  int
+		wantRole    string
+		wantCreated int
+		wantUpdated int
+	}{
+		{
+			name:        "new user is created",
+			claims:      map[string]interface{}{"email": "john@example.com", "name": "John", "email_verified": true},
+			users:       map[string]map[string]interface{}{},
+			code:        "valid-code",
+			redirect:    "https://app.example.com/signed-in",
+			wantStatus:  http.StatusOK,
+			wantRole:    defaultOAuthRole,
+			wantCreated: 1,
+		},
+		{
+			name:        "existing user is updated",
+			claims:      map[string]interface{}{"email": "john@example.com", "name": "Johnny", "email_verified": true},
+			users:       map[string]map[string]interface{}{"john@example.com": {"id": "1", "email": "john@example.com", "role": "admin", "pass": "hash"}},
+			code:        "valid-code",
+			wantStatus:  http.StatusOK,
+			wantRole:    "admin",
+			wantUpdated: 1,
+		},
+		{
+			name:       "existing user is not linked without a verified email",
+			claims:     map[string]interface{}{"email": "john@example.com", "name": "Johnny"},
+			users:      map[string]map[string]interface{}{"john@example.com": {"id": "1", "email": "john@example.com", "role": "admin", "pass": "hash"}},
+			code:       "valid-code",
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:        "existing user is linked for a provider trusted to verify emails",
+			claims:      map[string]interface{}{"email": "john@example.com", "name": "Johnny"},
+			users:       map[string]map[string]interface{}{"john@example.com": {"id": "1", "email": "john@example.com", "role": "admin", "pass": "hash"}},
+			code:        "valid-code",
+			trustEmail:  true,
+			wantStatus:  http.StatusOK,
+			wantRole:    "admin",
+			wantUpdated: 1,
+		},
+		{
+			name:       "unverified email is rejected",
+			claims:     map[string]interface{}{"email": "john@example.com", "email_verified": false},
+			users:      map[string]map[string]interface{}{},
+			code:       "valid-code",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid code is rejected",
+			claims:     map[string]interface{}{"email": "john@example.com"},
+			users:      map[string]map[string]interface{}{},
+			code:       "invalid-code",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "mismatched state is rejected",
+			claims:      map[string]interface{}{"email": "john@example.com"},
+			users:       map[string]map[string]interface{}{},
+			code:        "valid-code",
+			tamperState: true,
+			wantStatus:  http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMockOIDCServer(t, "client", tt.claims)
+			defer server.Close()
+
+			crud := &fakeCrud{users: tt.users}
+			m := Init(crud, &fakeAuth{})
+			m.SetConfig(config.Auths{"oidc": &config.AuthStub{ID: "oidc", Enabled: true, Issuer: server.URL, ClientID: "client", ClientSecret: "secret", CallbackURL: "http://localhost:4122/callback", RedirectURLs: []string{"https://app.example.com"}, TrustEmail: tt.trustEmail}})
+			if err := m.SetProjectAESKey(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))); err != nil {
+				t.Fatalf("SetProjectAESKey() error = %v", err)
+			}
+
+			_, authURL, cookie, err := m.OAuthAuthorize(context.Background(), "oidc", tt.redirect)
+			if err != nil {
+				t.Fatalf("OAuthAuthorize() error = %v", err)
+			}
+			u, _ := url.Parse(authURL)
+			query := u.Query()
+			if query.Get("code_challenge_method") != "S256" || query.Get("redirect_uri") != "http://localhost:4122/callback" {
+				t.Fatalf("OAuthAuthorize() invalid authorization url = %s", authURL)
+			}
+			server.nonce, server.challenge = query.Get("nonce"), query.Get("code_challenge")
+
+			state := query.Get("state")
+			if tt.tamperState {
+				state = "tampered"
+			}
+			status, result, redirect, err := m.OAuthCallback(context.Background(), "db", "project", "oidc", tt.code, state, cookie)
+			if status != tt.wantStatus {
+				t.Fatalf("OAuthCallback() status = %v, want %v, error = %v", status, tt.wantStatus, err)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if redirect != tt.redirect {
+				t.Errorf("OAuthCallback() redirect = %v, want %v", redirect, tt.redirect)
+			}
+			user := result["user"].(map[string]interface{})
+			if user["role"] != tt.wantRole || user["name"] != tt.claims["name"] {
+				t.Errorf("OAuthCallback() user = %v", user)
+			}
+			if _, p := user["pass"]; p {
+				t.Errorf("OAuthCallback() user contains password")
+			}
+			if crud.created != tt.wantCreated || crud.updated != tt.wantUpdated {
+				t.Errorf("OAuthCallback() created = %v, updated = %v", crud.created, crud.updated)
+			}
+		})
+	}
+}
+
+func Test_verifyIDToken(t *testing.T) {
+	claims := jwt.MapClaims{"aud": "client", "nonce": "nonce", "email": "john@example.com", "exp": time.Now().Add(time.Hour).Unix()}
+
+	tests := []struct {
+		name         string
+		clientSecret string
+		signingKey   string
+		wantErr      bool
+	}{
+		{name: "token signed with the client secret is accepted", clientSecret: "secret", signingKey: "secret"},
+		{name: "token signed with another key is rejected", clientSecret: "secret", signingKey: "other", wantErr: true},
+		{name: "symmetrically signed token is rejected without a client secret", signingKey: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(tt.signingKey))
+			if err != nil {
+				t.Fatalf("Unable to sign id token: %v", err)
+			}
+
+			stub := &config.AuthStub{ID: "oidc", ClientID: "client", ClientSecret: tt.clientSecret}
+			_, err = verifyIDToken(context.Background(), stub, &providerMetadata{}, idToken, "nonce")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifyIDToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_isRedirectAllowed(t *testing.T) {
+	allowed := []string{"https://app.example.com", "https://other.example.com/auth/"}
+	tests := []struct {
+		redirect string
+		want     bool
+	}{
+		{redirect: "https://app.example.com", want: true},
+		{redirect: "https://app.example.com/signed-in", want: true},
+		{redirect: "https://other.example.com/auth/done", want: true},
+		{redirect: "https://app.example.com.evil.com", want: false},
+		{redirect: "https://other.example.com/home", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.redirect, func(t *testing.T) {
+			if got := isRedirectAllowed(allowed, tt.redirect); got != tt.want {
+				t.Errorf("isRedirectAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -52,8 +52,8 @@ func (m *Module) Profile(ctx context.Context, token, dbAlias, project, id string
 
 	_ = authHelpers.PostProcessMethod(ctx, m.aesKey, actions, res)
 
-	// Delete password from user object
-	delete(res.(map[string]interface{}), "pass")
+	// Delete the secrets from user object
+	deleteSecretFields(res.(map[string]interface{}))
 
 	return http.StatusOK, res.(map[string]interface{}), nil
 }
@@ -82,18 +82,19 @@ func (m *Module) Profiles(ctx context.Context, token, dbAlias, project string) (
 
 	_ = authHelpers.PostProcessMethod(ctx, m.aesKey, actions, res)
 
-	// Delete password from user object
+	// Delete the secrets from user object
 	if usersArray, ok := res.([]interface{}); ok {
 		for _, user := range usersArray {
 			userObj := user.(map[string]interface{})
-			delete(userObj, "pass")
+			deleteSecretFields(userObj)
 		}
 	}
 
 	return http.StatusOK, map[string]interface{}{"users": res}, nil
 }
 
-// EmailSignIn signins the user and returns a JWT token
+// EmailSignIn signins the user and returns a JWT token. A token to complete the sign in with a one time password is
+// returned instead if the user has enabled multi factor authentication
 func (m *Module) EmailSignIn(ctx context.Context, dbAlias, project, email, password string) (int, map[string]interface{}, error) {
 	// Allow this feature only if the email sign in function is enabled
 	if !m.IsActive("email") {
@@ -118,28 +119,11 @@ func (m *Module) EmailSignIn(ctx context.Context, dbAlias, project, email, passw
 		return http.StatusUnauthorized, nil, errors.New("Given credentials are not correct")
 	}
 
-	// Delete password from user
-	delete(userObj, "pass")
-
-	req := map[string]interface{}{}
-	req["email"] = email
-	actualDbType, err := m.crud.GetDBType(dbAlias)
-	if err != nil {
-		return 0, nil, err
-	}
-	// Create a token
-	if actualDbType == string(model.Mongo) || actualDbType == string(model.EmbeddedDB) {
-		req["id"] = userObj["_id"]
-	} else {
-		req["id"] = userObj["id"]
+	if m.isEmailVerificationRequired() && !isEmailVerified(userObj) {
+		return http.StatusForbidden, nil, errors.New("Email has not been verified")
 	}
-	req["role"] = userObj["role"]
 
-	token, err := m.auth.CreateToken(ctx, req)
-	if err != nil {
-		return http.StatusInternalServerError, nil, errors.New("Failed to create a JWT token")
-	}
-	return http.StatusOK, map[string]interface{}{"user": user, "token": token}, nil
+	return m.completeSignIn(ctx, dbAlias, project, userObj, []string{amrPassword})
 }
 
 // EmailSignUp signs up a user and return a JWT token
@@ -171,6 +155,10 @@ func (m *Module) EmailSignUp(ctx context.Context, dbAlias, project, email, name,
 	req["pass"] = password
 	req["name"] = name
 	req["role"] = role
+	verificationRequired := m.isEmailVerificationRequired()
+	if verificationRequired {
+		req["emailVerified"] = false
+	}
 	actualDbType, err := m.crud.GetDBType(dbAlias)
 	if err != nil {
 		return http.StatusInternalServerError, nil, err
@@ -193,17 +181,26 @@ func (m *Module) EmailSignUp(ctx context.Context, dbAlias, project, email, name,
 
 	delete(req, "pass")
 
+	// The user can sign in only after verifying the email
+	if verificationRequired {
+		if err := m.sendUserToken(ctx, dbAlias, project, mailVerifyEmail, req, verifyEmailTokenExpiry); err != nil {
+			return http.StatusInternalServerError, nil, errors.New("Failed to send verification email")
+		}
+		return http.StatusOK, map[string]interface{}{"user": req}, nil
+	}
+
 	// Create a new token Object
 	tokenObj := map[string]interface{}{
 		"email": email,
 		"role":  role,
-		"id":    id.String()}
+		"id":    id.String(),
+		"amr":   []string{amrPassword}}
 
-	token, err := m.auth.CreateToken(ctx, tokenObj)
+	token, refreshToken, err := m.createSession(ctx, dbAlias, project, tokenObj)
 	if err != nil {
-		return http.StatusInternalServerError, nil, errors.New("Failed to create a JWT token")
+		return http.StatusInternalServerError, nil, err
 	}
-	return http.StatusOK, map[string]interface{}{"user": req, "token": token}, nil
+	return http.StatusOK, map[string]interface{}{"user": req, "token": token, "refreshToken": refreshToken}, nil
 }
 
 // EmailEditProfile allows the user to edit a profile
@@ -254,6 +251,20 @@ func (m *Module) EmailEditProfile(ctx context.Context, token, dbAlias, project,
 		return http.StatusForbidden, nil, err
 	}
 
+	// A new email needs to be verified again before the user can sign in with it
+	isEmailChanged := false
+	if email != "" && m.isEmailVerificationRequired() {
+		readReq := &model.ReadRequest{Find: map[string]interface{}{idString: id}, Operation: utils.One}
+		user, _, err := m.crud.Read(ctx, dbAlias, "users", readReq, getRequestParams(project, dbAlias, "users", "db-read"))
+		if err != nil {
+			return http.StatusNotFound, nil, errors.New("User not found")
+		}
+		if user.(map[string]interface{})["email"] != email {
+			set["emailVerified"] = false
+			isEmailChanged = true
+		}
+	}
+
 	err = m.crud.Update(ctx, dbAlias, "users", req, reqParams)
 	if err != nil {
 		return http.StatusInternalServerError, nil, err
@@ -268,14 +279,31 @@ func (m *Module) EmailEditProfile(ctx context.Context, token, dbAlias, project,
 
 	userObj := user.(map[string]interface{})
 
-	// Delete password from user
-	delete(userObj, "pass")
+	// Delete the secrets from user
+	deleteSecretFields(userObj)
+
+	// The profile has already been updated, hence a failure to send the email is only logged. The user can ask for
+	// another verification email
+	if isEmailChanged {
+		if err := m.sendUserToken(ctx, dbAlias, project, mailVerifyEmail, userObj, verifyEmailTokenExpiry); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to send verification email", err, nil)
+		}
+	}
 
 	req1 := map[string]interface{}{}
 	req1["email"] = userObj["email"]
 	req1["id"] = userObj[idString]
 	req1["role"] = userObj["role"]
 
+	// Keep the new token in the session of the token used to edit the profile
+	if claims, err := m.auth.ParseToken(ctx, token); err == nil {
+		for _, key := range []string{"sid", "amr"} {
+			if v, p := claims[key]; p {
+				req1[key] = v
+			}
+		}
+	}
+
 	token1, err := m.auth.CreateToken(ctx, req1)
 	if err != nil {
 		return http.StatusInternalServerError, nil, errors.New("Failed to create a JWT token")
@@ -0,0 +1,298 @@
+package userman
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/spaceuptech/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	sessionsCollection = "sessions"
+	sessionExpiry      = 30 * 24 * time.Hour
+
+	// The number of rotated refresh tokens of a session which are remembered to detect their reuse
+	sessionRotatedTokens = 10
+)
+
+// Refresh returns a new token for the session of a refresh token. The refresh token is rotated on every use. Reusing a
+// refresh token which has already been rotated revokes the session since the token might have been stolen. The token
+// is rotated only if it hasn't been rotated by a concurrent request, which is treated as a reuse as well
+func (m *Module) Refresh(ctx context.Context, dbAlias, project, refreshToken string) (int, map[string]interface{}, error) {
+	if !m.IsEnabled() {
+		return http.StatusNotFound, nil, errors.New("This feature isn't enabled")
+	}
+
+	arr := strings.Split(refreshToken, ".")
+	if len(arr) != 2 {
+		return http.StatusUnauthorized, nil, errors.New("Invalid refresh token provided")
+	}
+	sid, secret := arr[0], arr[1]
+
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	reqParams := getRequestParams(project, dbAlias, sessionsCollection, "db-read")
+	session, _, err := m.crud.Read(ctx, dbAlias, sessionsCollection, &model.ReadRequest{Find: map[string]interface{}{idString: sid}, Operation: utils.One}, reqParams)
+	if err != nil {
+		return http.StatusUnauthorized, nil, errors.New("Invalid refresh token provided")
+	}
+	sessionObj := session.(map[string]interface{})
+
+	tokenHash, _ := sessionObj["token_hash"].(string)
+	rotatedHashes, _ := sessionObj["rotated_token_hashes"].(string)
+	if hash := utils.HashString(secret); subtle.ConstantTimeCompare([]byte(tokenHash), []byte(hash)) != 1 {
+		// Only a token which was issued for the session is considered to be reused. Any other token is just invalid
+		if !containsHash(strings.Fields(rotatedHashes), hash) {
+			return http.StatusUnauthorized, nil, errors.New("Invalid refresh token provided")
+		}
+		return http.StatusUnauthorized, nil, m.revokeReusedSession(ctx, dbAlias, project, idString, sid)
+	}
+	if expiresAt, ok := getTime(sessionObj["expires_at"]); !ok || time.Now().After(expiresAt) {
+		_ = m.deleteSessions(ctx, dbAlias, project, map[string]interface{}{idString: sid}, []string{sid})
+		return http.StatusUnauthorized, nil, errors.New("Session has expired")
+	}
+
+	reqParams.Attributes["col"] = "users"
+	user, _, err := m.crud.Read(ctx, dbAlias, "users", &model.ReadRequest{Find: map[string]interface{}{idString: sessionObj["user_id"]}, Operation: utils.One}, reqParams)
+	if err != nil {
+		return http.StatusUnauthorized, nil, errors.New("User not found")
+	}
+	userObj := user.(map[string]interface{})
+
+	// Rotate the refresh token. The update is versioned on the current token so that it fails if the token was rotated
+	// by another request in the meantime
+	hashes := append(strings.Fields(rotatedHashes), tokenHash)
+	if len(hashes) > sessionRotatedTokens {
+		hashes = hashes[len(hashes)-sessionRotatedTokens:]
+	}
+	secret = generateRandomString()
+	reqParams = getRequestParams(project, dbAlias, sessionsCollection, "db-update")
+	updateReq := &model.UpdateRequest{
+		Find:        map[string]interface{}{idString: sid, "token_hash": tokenHash},
+		Operation:   utils.One,
+		Update:      map[string]interface{}{"$set": map[string]interface{}{"token_hash": utils.HashString(secret), "rotated_token_hashes": strings.Join(hashes, " "), "expires_at": time.Now().UTC().Add(sessionExpiry)}},
+		IsVersioned: true,
+	}
+	if err := m.crud.Update(ctx, dbAlias, sessionsCollection, updateReq, reqParams); err == utils.ErrVersionConflict {
+		return http.StatusUnauthorized, nil, m.revokeReusedSession(ctx, dbAlias, project, idString, sid)
+	} else if err != nil {
+		helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Error %v", err), nil)
+		return http.StatusInternalServerError, nil, errors.New("Failed to rotate refresh token")
+	}
+
+	claims := map[string]interface{}{"email": userObj["email"], "id": userObj[idString], "role": userObj["role"], "sid": sid}
+	if amr, ok := sessionObj["amr"].(string); ok && amr != "" {
+		claims["amr"] = strings.Fields(amr)
+	}
+	token, err := m.auth.CreateToken(ctx, claims)
+	if err != nil {
+		return http.StatusInternalServerError, nil, errors.New("Failed to create a JWT token")
+	}
+	return http.StatusOK, map[string]interface{}{"token": token, "refreshToken": sid + "." + secret}, nil
+}
+
+// Logout ends the session of the provided token. The session is identified by the refresh token if one is provided,
+// which lets a client whose token has expired log out without refreshing it first
+func (m *Module) Logout(ctx context.Context, token, refreshToken, dbAlias, project string) (int, error) {
+	if !m.IsEnabled() {
+		return http.StatusNotFound, errors.New("This feature isn't enabled")
+	}
+
+	status, sid, _, err := m.getLogoutSession(ctx, token, refreshToken, dbAlias, project)
+	if err != nil {
+		return status, err
+	}
+	if sid == "" {
+		return http.StatusBadRequest, errors.New("Provided token does not belong to a session")
+	}
+
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if err := m.deleteSessions(ctx, dbAlias, project, map[string]interface{}{idString: sid}, []string{sid}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// LogoutAll ends all the sessions of the user the provided token belongs to. The user is identified by the refresh
+// token if one is provided
+func (m *Module) LogoutAll(ctx context.Context, token, refreshToken, dbAlias, project string) (int, error) {
+	if !m.IsEnabled() {
+		return http.StatusNotFound, errors.New("This feature isn't enabled")
+	}
+
+	status, _, userID, err := m.getLogoutSession(ctx, token, refreshToken, dbAlias, project)
+	if err != nil {
+		return status, err
+	}
+	if userID == nil {
+		return http.StatusBadRequest, errors.New("Claim (id) not present in token")
+	}
+
+	if err := m.endUserSessions(ctx, dbAlias, project, userID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// getLogoutSession returns the id of the session and the id of the user to be logged out. They are read from the
+// session of the refresh token if one is provided and from the claims of the token otherwise. The refresh token is
+// accepted even if its session has expired since the session is being ended anyway
+func (m *Module) getLogoutSession(ctx context.Context, token, refreshToken, dbAlias, project string) (int, string, interface{}, error) {
+	if refreshToken == "" {
+		claims, err := m.auth.ParseToken(ctx, token)
+		if err != nil {
+			return http.StatusUnauthorized, "", nil, err
+		}
+		sid, _ := claims["sid"].(string)
+		return http.StatusOK, sid, claims["id"], nil
+	}
+
+	arr := strings.Split(refreshToken, ".")
+	if len(arr) != 2 {
+		return http.StatusUnauthorized, "", nil, errors.New("Invalid refresh token provided")
+	}
+	sid, secret := arr[0], arr[1]
+
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, "", nil, err
+	}
+
+	reqParams := getRequestParams(project, dbAlias, sessionsCollection, "db-read")
+	session, _, err := m.crud.Read(ctx, dbAlias, sessionsCollection, &model.ReadRequest{Find: map[string]interface{}{idString: sid}, Operation: utils.One}, reqParams)
+	if err != nil {
+		return http.StatusUnauthorized, "", nil, errors.New("Invalid refresh token provided")
+	}
+	sessionObj := session.(map[string]interface{})
+
+	// Only the current refresh token of the session identifies it. Rotated tokens are rejected
+	tokenHash, _ := sessionObj["token_hash"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenHash), []byte(utils.HashString(secret))) != 1 {
+		return http.StatusUnauthorized, "", nil, errors.New("Invalid refresh token provided")
+	}
+	return http.StatusOK, sid, sessionObj["user_id"], nil
+}
+
+// endUserSessions deletes all the sessions of a user and revokes the tokens created for them
+func (m *Module) endUserSessions(ctx context.Context, dbAlias, project string, userID interface{}) error {
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return err
+	}
+
+	find := map[string]interface{}{"user_id": userID}
+	sessions, _, err := m.crud.Read(ctx, dbAlias, sessionsCollection, &model.ReadRequest{Find: find, Operation: utils.All}, getRequestParams(project, dbAlias, sessionsCollection, "db-read"))
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0)
+	sessionsArray, _ := sessions.([]interface{})
+	for _, session := range sessionsArray {
+		if id, ok := session.(map[string]interface{})[idString].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return m.deleteSessions(ctx, dbAlias, project, find, ids)
+}
+
+// createSession creates a session for a user with a refresh token which can be used to get new tokens for the session.
+// It returns the token and the refresh token of the session
+func (m *Module) createSession(ctx context.Context, dbAlias, project string, claims map[string]interface{}) (string, string, error) {
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return "", "", err
+	}
+
+	sid := uuid.NewV4().String()
+	secret := generateRandomString()
+	now := time.Now().UTC()
+	doc := map[string]interface{}{idString: sid, "user_id": claims["id"], "token_hash": utils.HashString(secret), "expires_at": now.Add(sessionExpiry), "created_at": now}
+	if amr, ok := claims["amr"].([]string); ok {
+		doc["amr"] = strings.Join(amr, " ")
+	}
+
+	createReq := &model.CreateRequest{Operation: utils.One, Document: doc}
+	if err := m.crud.Create(ctx, dbAlias, sessionsCollection, createReq, getRequestParams(project, dbAlias, sessionsCollection, "db-create")); err != nil {
+		helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Error %v", err), nil)
+		return "", "", errors.New("Failed to create session")
+	}
+
+	claims["sid"] = sid
+	token, err := m.auth.CreateToken(ctx, claims)
+	if err != nil {
+		return "", "", errors.New("Failed to create a JWT token")
+	}
+	return token, sid + "." + secret, nil
+}
+
+// revokeReusedSession ends a session whose refresh token has been reused. It returns the error to be sent to the client
+func (m *Module) revokeReusedSession(ctx context.Context, dbAlias, project, idString, sid string) error {
+	_ = m.deleteSessions(ctx, dbAlias, project, map[string]interface{}{idString: sid}, []string{sid})
+	return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Refresh token has already been used. Revoking the session", nil, map[string]interface{}{"sid": sid})
+}
+
+// deleteSessions deletes the sessions matching the find clause and revokes the tokens created for them
+func (m *Module) deleteSessions(ctx context.Context, dbAlias, project string, find map[string]interface{}, ids []string) error {
+	deleteReq := &model.DeleteRequest{Find: find, Operation: utils.All}
+	if err := m.crud.Delete(ctx, dbAlias, sessionsCollection, deleteReq, getRequestParams(project, dbAlias, sessionsCollection, "db-delete")); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to delete sessions", err, nil)
+	}
+	return m.auth.RevokeSessions(ctx, ids)
+}
+
+func (m *Module) getIDField(dbAlias string) (string, error) {
+	actualDbType, err := m.crud.GetDBType(dbAlias)
+	if err != nil {
+		return "", err
+	}
+	if actualDbType == string(model.Mongo) || actualDbType == string(model.EmbeddedDB) {
+		return "_id", nil
+	}
+	return "id", nil
+}
+
+func getRequestParams(project, dbAlias, col, resource string) model.RequestParams {
+	attr := map[string]string{"project": project, "db": dbAlias, "col": col}
+	return model.RequestParams{Resource: resource, Op: "access", Attributes: attr}
+}
+
+// getTime returns the time stored in a field as per the type returned by the database
+func getTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case primitive.DateTime:
+		return v.Time(), true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// containsHash checks if a hash is present in a list of hashes without leaking the position of a match
+func containsHash(hashes []string, hash string) bool {
+	found := 0
+	for _, h := range hashes {
+		found |= subtle.ConstantTimeCompare([]byte(h), []byte(hash))
+	}
+	return found == 1
+}
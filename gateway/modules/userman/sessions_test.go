@@ -0,0 +1,132 @@
+package userman
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func TestModule_Sessions(t *testing.T) {
+	crud := &fakeCrud{users: map[string]map[string]interface{}{}}
+	auth := &fakeAuth{}
+	m := Init(crud, auth)
+	m.SetConfig(config.Auths{"email": &config.AuthStub{ID: "email", Enabled: true}})
+	ctx := context.Background()
+
+	status, result, err := m.EmailSignUp(ctx, "db", "project", "john@example.com", "John", "pass", "user")
+	if err != nil {
+		t.Fatalf("EmailSignUp() status = %v, error = %v", status, err)
+	}
+	firstRefresh := result["refreshToken"].(string)
+	if len(crud.tables[sessionsCollection]) != 1 {
+		t.Fatalf("EmailSignUp() sessions = %v, want 1", len(crud.tables[sessionsCollection]))
+	}
+
+	// Refreshing rotates the refresh token and keeps the session of the token
+	status, result, err = m.Refresh(ctx, "db", "project", firstRefresh)
+	if err != nil {
+		t.Fatalf("Refresh() status = %v, error = %v", status, err)
+	}
+	secondRefresh := result["refreshToken"].(string)
+	if secondRefresh == firstRefresh {
+		t.Errorf("Refresh() did not rotate the refresh token")
+	}
+	claims, _ := auth.ParseToken(ctx, result["token"].(string))
+	if claims["email"] != "john@example.com" || claims["sid"] == nil {
+		t.Errorf("Refresh() invalid token claims = %v", claims)
+	}
+
+	// An invalid secret doesn't end the session since it was never issued for the session
+	sid := strings.Split(secondRefresh, ".")[0]
+	if status, _, _ := m.Refresh(ctx, "db", "project", sid+".invalid"); status != http.StatusUnauthorized {
+		t.Errorf("Refresh() with invalid secret status = %v, want %v", status, http.StatusUnauthorized)
+	}
+	if len(crud.tables[sessionsCollection]) != 1 || len(auth.revoked) != 0 {
+		t.Errorf("Refresh() with invalid secret sessions = %v, revoked = %v", crud.tables[sessionsCollection], auth.revoked)
+	}
+
+	// Reusing a rotated refresh token revokes the session
+	if status, _, _ := m.Refresh(ctx, "db", "project", firstRefresh); status != http.StatusUnauthorized {
+		t.Errorf("Refresh() with reused token status = %v, want %v", status, http.StatusUnauthorized)
+	}
+	if len(crud.tables[sessionsCollection]) != 0 || len(auth.revoked) != 1 || auth.revoked[0] != claims["sid"] {
+		t.Errorf("Refresh() with reused token sessions = %v, revoked = %v", crud.tables[sessionsCollection], auth.revoked)
+	}
+	if status, _, _ := m.Refresh(ctx, "db", "project", secondRefresh); status != http.StatusUnauthorized {
+		t.Errorf("Refresh() of revoked session status = %v, want %v", status, http.StatusUnauthorized)
+	}
+
+	// Refreshing concurrently with the same token revokes the session as well
+	auth.revoked = nil
+	_, result, _ = m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	refreshToken := result["refreshToken"].(string)
+	var concurrentStatus int
+	crud.beforeUpdate = func(col string) {
+		if col == sessionsCollection {
+			crud.beforeUpdate = nil
+			concurrentStatus, _, _ = m.Refresh(ctx, "db", "project", refreshToken)
+		}
+	}
+	if status, _, _ := m.Refresh(ctx, "db", "project", refreshToken); status != http.StatusUnauthorized || concurrentStatus != http.StatusOK {
+		t.Errorf("Refresh() concurrently status = %v, concurrent status = %v", status, concurrentStatus)
+	}
+	if len(crud.tables[sessionsCollection]) != 0 || len(auth.revoked) != 1 {
+		t.Errorf("Refresh() concurrently sessions = %v, revoked = %v", crud.tables[sessionsCollection], auth.revoked)
+	}
+
+	// Expired sessions can't be refreshed
+	_, result, _ = m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	for _, session := range crud.tables[sessionsCollection] {
+		session["expires_at"] = time.Now().Add(-time.Minute)
+	}
+	if status, _, _ := m.Refresh(ctx, "db", "project", result["refreshToken"].(string)); status != http.StatusUnauthorized {
+		t.Errorf("Refresh() of expired session status = %v, want %v", status, http.StatusUnauthorized)
+	}
+
+	// Logout ends a single session while logout all ends every session of the user
+	auth.revoked = nil
+	_, first, _ := m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	_, second, _ := m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	_, third, _ := m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	if status, err := m.Logout(ctx, first["token"].(string), "", "db", "project"); err != nil {
+		t.Fatalf("Logout() status = %v, error = %v", status, err)
+	}
+	if len(crud.tables[sessionsCollection]) != 2 || len(auth.revoked) != 1 {
+		t.Errorf("Logout() sessions = %v, revoked = %v", len(crud.tables[sessionsCollection]), auth.revoked)
+	}
+	if status, err := m.LogoutAll(ctx, second["token"].(string), "", "db", "project"); err != nil {
+		t.Fatalf("LogoutAll() status = %v, error = %v", status, err)
+	}
+	if len(crud.tables[sessionsCollection]) != 0 || len(auth.revoked) != 3 {
+		t.Errorf("LogoutAll() sessions = %v, revoked = %v", len(crud.tables[sessionsCollection]), auth.revoked)
+	}
+	if status, _, _ := m.Refresh(ctx, "db", "project", third["refreshToken"].(string)); status != http.StatusUnauthorized {
+		t.Errorf("Refresh() after logout all status = %v, want %v", status, http.StatusUnauthorized)
+	}
+
+	// The refresh token identifies the session if the token has expired. Rotated refresh tokens are rejected
+	auth.revoked = nil
+	_, first, _ = m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	_, second, _ = m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	_, rotated, _ := m.Refresh(ctx, "db", "project", first["refreshToken"].(string))
+	if status, _ := m.Logout(ctx, "expired", first["refreshToken"].(string), "db", "project"); status != http.StatusUnauthorized {
+		t.Errorf("Logout() with rotated refresh token status = %v, want %v", status, http.StatusUnauthorized)
+	}
+	if status, err := m.Logout(ctx, "expired", rotated["refreshToken"].(string), "db", "project"); err != nil {
+		t.Fatalf("Logout() with refresh token status = %v, error = %v", status, err)
+	}
+	if len(crud.tables[sessionsCollection]) != 1 || len(auth.revoked) != 1 {
+		t.Errorf("Logout() with refresh token sessions = %v, revoked = %v", len(crud.tables[sessionsCollection]), auth.revoked)
+	}
+	_, _, _ = m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass")
+	if status, err := m.LogoutAll(ctx, "expired", second["refreshToken"].(string), "db", "project"); err != nil {
+		t.Fatalf("LogoutAll() with refresh token status = %v, error = %v", status, err)
+	}
+	if len(crud.tables[sessionsCollection]) != 0 || len(auth.revoked) != 3 {
+		t.Errorf("LogoutAll() with refresh token sessions = %v, revoked = %v", len(crud.tables[sessionsCollection]), auth.revoked)
+	}
+}
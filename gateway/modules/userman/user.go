@@ -4,6 +4,8 @@ import (
 	"encoding/base64"
 	"sync"
 
+	"github.com/spaceuptech/helpers"
+
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 )
@@ -15,13 +17,21 @@ type Module struct {
 	crud    model.CrudUserInterface
 	auth    model.AuthUserInterface
 
+	// The discovered endpoints of the oauth providers
+	providers map[string]*providerMetadata
+
+	// The mailer used to verify emails and reset passwords
+	mailer        Mailer
+	customMailer  Mailer
+	mailTemplates map[string]*mailTemplate
+
 	// auth module
 	aesKey []byte
 }
 
 // Init creates a new instance of the user management object
 func Init(crud model.CrudUserInterface, auth model.AuthUserInterface) *Module {
-	return &Module{crud: crud, auth: auth}
+	return &Module{crud: crud, auth: auth, providers: map[string]*providerMetadata{}}
 }
 
 // SetConfig sets the config required by the user management module
@@ -30,10 +40,15 @@ func (m *Module) SetConfig(auth config.Auths) {
 	defer m.Unlock()
 
 	m.methods = make(map[string]*config.AuthStub, len(auth))
+	m.providers = map[string]*providerMetadata{}
 
 	for _, v := range auth {
 		m.methods[v.ID] = v
 	}
+
+	if err := m.setMailerConfig(m.methods["email"]); err != nil {
+		_ = helpers.Logger.LogError("userman-set-config", "Unable to set mailer of email provider", err, nil)
+	}
 }
 
 // IsActive shows if a given method is active
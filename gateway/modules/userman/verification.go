@@ -0,0 +1,248 @@
+package userman
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	userTokensCollection = "user_tokens"
+
+	verifyEmailTokenExpiry   = 24 * time.Hour
+	resetPasswordTokenExpiry = time.Hour
+)
+
+// SendVerificationEmail sends an email with a token to verify the email of a user. No error is returned if the user
+// doesn't exist so that the emails of the users can't be discovered
+func (m *Module) SendVerificationEmail(ctx context.Context, dbAlias, project, email string) (int, error) {
+	if !m.IsActive("email") {
+		return http.StatusNotFound, errors.New("Email sign in feature is not enabled")
+	}
+
+	userObj, err := m.getUserByEmail(ctx, dbAlias, project, email)
+	if err != nil || isEmailVerified(userObj) {
+		return http.StatusOK, nil
+	}
+
+	// The failure isn't returned since it would only be returned for the emails of existing users
+	if err := m.sendUserToken(ctx, dbAlias, project, mailVerifyEmail, userObj, verifyEmailTokenExpiry); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to send verification email", err, nil)
+	}
+	return http.StatusOK, nil
+}
+
+// VerifyEmail marks the email of the user the verification token was sent to as verified
+func (m *Module) VerifyEmail(ctx context.Context, dbAlias, project, token string) (int, error) {
+	if !m.IsActive("email") {
+		return http.StatusNotFound, errors.New("Email sign in feature is not enabled")
+	}
+
+	tokenObj, err := m.useUserToken(ctx, dbAlias, project, mailVerifyEmail, token)
+	if err != nil {
+		return http.StatusUnauthorized, err
+	}
+
+	if err := m.updateUser(ctx, dbAlias, project, tokenObj["user_id"], map[string]interface{}{"emailVerified": true}); err != nil {
+		return http.StatusInternalServerError, errors.New("Failed to verify email")
+	}
+	return http.StatusOK, nil
+}
+
+// ForgotPassword sends an email with a token to reset the password of a user. No error is returned if the user doesn't
+// exist so that the emails of the users can't be discovered
+func (m *Module) ForgotPassword(ctx context.Context, dbAlias, project, email string) (int, error) {
+	if !m.IsActive("email") {
+		return http.StatusNotFound, errors.New("Email sign in feature is not enabled")
+	}
+
+	userObj, err := m.getUserByEmail(ctx, dbAlias, project, email)
+	if err != nil {
+		return http.StatusOK, nil
+	}
+
+	// The failure isn't returned since it would only be returned for the emails of existing users
+	if err := m.sendUserToken(ctx, dbAlias, project, mailResetPassword, userObj, resetPasswordTokenExpiry); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to send password reset email", err, nil)
+	}
+	return http.StatusOK, nil
+}
+
+// ResetPassword sets the password of the user the reset token was sent to. All the sessions of the user are ended
+func (m *Module) ResetPassword(ctx context.Context, dbAlias, project, token, password string) (int, error) {
+	if !m.IsActive("email") {
+		return http.StatusNotFound, errors.New("Email sign in feature is not enabled")
+	}
+	if password == "" {
+		return http.StatusBadRequest, errors.New("Password cannot be empty")
+	}
+
+	tokenObj, err := m.useUserToken(ctx, dbAlias, project, mailResetPassword, token)
+	if err != nil {
+		return http.StatusUnauthorized, err
+	}
+	userID := tokenObj["user_id"]
+
+	password, err = hashPassword(password)
+	if err != nil {
+		helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Error %v", err), nil)
+		return http.StatusInternalServerError, errors.New("Failed to hash password")
+	}
+	if err := m.updateUser(ctx, dbAlias, project, userID, map[string]interface{}{"pass": password}); err != nil {
+		return http.StatusInternalServerError, errors.New("Failed to reset password")
+	}
+
+	if err := m.endUserSessions(ctx, dbAlias, project, userID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// sendUserToken creates a single use token of the provided kind for a user and sends it to the user by email
+func (m *Module) sendUserToken(ctx context.Context, dbAlias, project, kind string, userObj map[string]interface{}, expiry time.Duration) error {
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().UTC().Add(expiry)
+	token, err := m.createUserToken(ctx, dbAlias, project, kind, userObj[idString], expiresAt, nil)
+	if err != nil {
+		return err
+	}
+
+	// Delete the secrets from user
+	deleteSecretFields(userObj)
+	return m.sendMail(ctx, kind, userObj, map[string]interface{}{"token": token, "expiresAt": expiresAt})
+}
+
+// createUserToken creates a single use token of the provided kind for a user. The earlier tokens of the same kind are
+// deleted. The fields are stored along with the token
+func (m *Module) createUserToken(ctx context.Context, dbAlias, project, kind string, userID interface{}, expiresAt time.Time, fields map[string]interface{}) (string, error) {
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return "", err
+	}
+
+	deleteReq := &model.DeleteRequest{Find: map[string]interface{}{"user_id": userID, "type": kind}, Operation: utils.All}
+	if err := m.crud.Delete(ctx, dbAlias, userTokensCollection, deleteReq, getRequestParams(project, dbAlias, userTokensCollection, "db-delete")); err != nil {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to delete earlier tokens of user", err, nil)
+	}
+
+	id := uuid.NewV1().String()
+	secret := generateRandomString()
+	doc := map[string]interface{}{idString: id, "user_id": userID, "type": kind, "token_hash": utils.HashString(secret), "expires_at": expiresAt, "created_at": time.Now().UTC()}
+	for k, v := range fields {
+		doc[k] = v
+	}
+
+	createReq := &model.CreateRequest{Operation: utils.One, Document: doc}
+	if err := m.crud.Create(ctx, dbAlias, userTokensCollection, createReq, getRequestParams(project, dbAlias, userTokensCollection, "db-create")); err != nil {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to create token of user", err, nil)
+	}
+	return id + "." + secret, nil
+}
+
+// useUserToken validates a token of the provided kind and deletes it so that it can't be used again. The token is first
+// consumed with an update versioned on its hash so that concurrent requests can't use the same token. It returns the
+// stored token which holds the id of the user the token was created for
+func (m *Module) useUserToken(ctx context.Context, dbAlias, project, kind, token string) (map[string]interface{}, error) {
+	arr := strings.Split(token, ".")
+	if len(arr) != 2 {
+		return nil, errors.New("Invalid token provided")
+	}
+	id, secret := arr[0], arr[1]
+
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	readReq := &model.ReadRequest{Find: map[string]interface{}{idString: id}, Operation: utils.One}
+	result, _, err := m.crud.Read(ctx, dbAlias, userTokensCollection, readReq, getRequestParams(project, dbAlias, userTokensCollection, "db-read"))
+	if err != nil {
+		return nil, errors.New("Invalid token provided")
+	}
+	tokenObj := result.(map[string]interface{})
+
+	tokenHash, _ := tokenObj["token_hash"].(string)
+	if tokenObj["type"] != kind || subtle.ConstantTimeCompare([]byte(tokenHash), []byte(utils.HashString(secret))) != 1 {
+		return nil, errors.New("Invalid token provided")
+	}
+
+	updateReq := &model.UpdateRequest{
+		Find:        map[string]interface{}{idString: id, "token_hash": tokenHash},
+		Operation:   utils.One,
+		Update:      map[string]interface{}{"$set": map[string]interface{}{"token_hash": ""}},
+		IsVersioned: true,
+	}
+	if err := m.crud.Update(ctx, dbAlias, userTokensCollection, updateReq, getRequestParams(project, dbAlias, userTokensCollection, "db-update")); err == utils.ErrVersionConflict {
+		return nil, errors.New("Invalid token provided")
+	} else if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to use token of user", err, nil)
+	}
+
+	deleteReq := &model.DeleteRequest{Find: map[string]interface{}{idString: id}, Operation: utils.All}
+	if err := m.crud.Delete(ctx, dbAlias, userTokensCollection, deleteReq, getRequestParams(project, dbAlias, userTokensCollection, "db-delete")); err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to delete used token of user", err, nil)
+	}
+
+	if expiresAt, ok := getTime(tokenObj["expires_at"]); !ok || time.Now().After(expiresAt) {
+		return nil, errors.New("Token has expired")
+	}
+	return tokenObj, nil
+}
+
+func (m *Module) getUserByEmail(ctx context.Context, dbAlias, project, email string) (map[string]interface{}, error) {
+	readReq := &model.ReadRequest{Find: map[string]interface{}{"email": email}, Operation: utils.One}
+	user, _, err := m.crud.Read(ctx, dbAlias, "users", readReq, getRequestParams(project, dbAlias, "users", "db-read"))
+	if err != nil {
+		return nil, err
+	}
+	return user.(map[string]interface{}), nil
+}
+
+func (m *Module) updateUser(ctx context.Context, dbAlias, project string, userID interface{}, set map[string]interface{}) error {
+	idString, err := m.getIDField(dbAlias)
+	if err != nil {
+		return err
+	}
+
+	updateReq := &model.UpdateRequest{Find: map[string]interface{}{idString: userID}, Operation: utils.One, Update: map[string]interface{}{"$set": set}}
+	if err := m.crud.Update(ctx, dbAlias, "users", updateReq, getRequestParams(project, dbAlias, "users", "db-update")); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to update user", err, nil)
+	}
+	return nil
+}
+
+// isEmailVerificationRequired shows if users need to verify their email before signing in
+func (m *Module) isEmailVerificationRequired() bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	s, p := m.methods["email"]
+	return p && s.RequireEmailVerification
+}
+
+// isEmailVerified checks the emailVerified field of a user as per the type returned by the database
+func isEmailVerified(userObj map[string]interface{}) bool {
+	switch v := userObj["emailVerified"].(type) {
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package userman
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+type fakeMailer struct {
+	mails []*Mail
+	err   error
+}
+
+func (f *fakeMailer) SendMail(ctx context.Context, mail *Mail) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.mails = append(f.mails, mail)
+	return nil
+}
+
+// lastToken returns the token sent in the last email
+func (f *fakeMailer) lastToken() string {
+	fields := strings.Fields(f.mails[len(f.mails)-1].Body)
+	return fields[len(fields)-1]
+}
+
+func newVerificationModule() (*Module, *fakeCrud, *fakeAuth, *fakeMailer) {
+	crud := &fakeCrud{users: map[string]map[string]interface{}{}}
+	auth := &fakeAuth{}
+	mailer := &fakeMailer{}
+	m := Init(crud, auth)
+	m.SetConfig(config.Auths{"email": &config.AuthStub{ID: "email", Enabled: true, RequireEmailVerification: true}})
+	m.SetMailer(mailer)
+	return m, crud, auth, mailer
+}
+
+func TestModule_VerifyEmail(t *testing.T) {
+	m, crud, _, mailer := newVerificationModule()
+	ctx := context.Background()
+
+	status, result, err := m.EmailSignUp(ctx, "db", "project", "john@example.com", "John", "pass", "user")
+	if err != nil {
+		t.Fatalf("EmailSignUp() status = %v, error = %v", status, err)
+	}
+	if _, p := result["token"]; p {
+		t.Errorf("EmailSignUp() returned token before email was verified")
+	}
+	if len(mailer.mails) != 1 || mailer.mails[0].To != "john@example.com" || !strings.Contains(mailer.mails[0].Body, "Hi John") {
+		t.Fatalf("EmailSignUp() mails = %v", mailer.mails)
+	}
+	token := mailer.lastToken()
+
+	if status, _, _ := m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass"); status != http.StatusForbidden {
+		t.Errorf("EmailSignIn() before verification status = %v, want %v", status, http.StatusForbidden)
+	}
+	if status, _ := m.VerifyEmail(ctx, "db", "project", token+"x"); status != http.StatusUnauthorized {
+		t.Errorf("VerifyEmail() with invalid token status = %v, want %v", status, http.StatusUnauthorized)
+	}
+	if status, err := m.VerifyEmail(ctx, "db", "project", token); err != nil {
+		t.Fatalf("VerifyEmail() status = %v, error = %v", status, err)
+	}
+	if status, _ := m.VerifyEmail(ctx, "db", "project", token); status != http.StatusUnauthorized {
+		t.Errorf("VerifyEmail() with used token status = %v, want %v", status, http.StatusUnauthorized)
+	}
+	if crud.users["john@example.com"]["emailVerified"] != true {
+		t.Errorf("VerifyEmail() user = %v", crud.users["john@example.com"])
+	}
+	if status, _, err := m.EmailSignIn(ctx, "db", "project", "john@example.com", "pass"); err != nil {
+		t.Errorf("EmailSignIn() after verification status = %v, error = %v", status, err)
+	}
+
+	// No email is sent once the email has been verified or if the user doesn't exist
+	_, _ = m.SendVerificationEmail(ctx, "db", "project", "john@example.com")
+	if status, err := m.SendVerificationEmail(ctx, "db", "project", "jane@example.com"); err != nil || len(mailer.mails) != 1 {
+		t.Errorf("SendVerificationEmail() status = %v, error = %v, mails = %v", status, err, len(mailer.mails))
+	}
+}
+
+func TestModule_ResetPassword(t *testing.T) {
+	m, crud, auth, mailer := newVerificationModule()
+	ctx := context.Background()
+	crud.users["john@example.com"] = map[string]interface{}{"id": "1", "email": "john@example.com", "name": "John", "role": "user", "emailVerified": true}
+	_, _ = m.ForgotPassword(ctx, "db", "project", "jane@example.com")
+	if len(mailer.mails) != 0 {
+		t.Fatalf("ForgotPassword() sent email to user which does not exist")
+	}
+
+	// Requesting a new token invalidates the earlier one
+	if status, err := m.ForgotPassword(ctx, "db", "project", "john@example.com"); err != nil {
+		t.Fatalf("ForgotPassword() status = %v, error = %v", status, err)
+	}
+	oldToken := mailer.lastToken()
+	_, _ = m.ForgotPassword(ctx, "db", "project", "john@example.com")
+	token := mailer.lastToken()
+	if status, _ := m.ResetPassword(ctx, "db", "project", oldToken, "new-pass"); status != http.StatusUnauthorized {
+		t.Errorf("ResetPassword() with replaced token status = %v, want %v", status, http.StatusUnauthorized)
+	}
+
+	_, refreshToken, _ := m.createSession(ctx, "db", "project", map[string]interface{}{"email": "john@example.com", "id": "1", "role": "user"})
+	sid := strings.Split(refreshToken, ".")[0]
+	if status, err := m.ResetPassword(ctx, "db", "project", token, "new-pass"); err != nil {
+		t.Fatalf("ResetPassword() status = %v, error = %v", status, err)
+	}
+	if len(auth.revoked) != 1 || auth.revoked[0] != sid {
+		t.Errorf("ResetPassword() revoked = %v, want [%v]", auth.revoked, sid)
+	}
+	if status, _, err := m.EmailSignIn(ctx, "db", "project", "john@example.com", "new-pass"); err != nil {
+		t.Errorf("EmailSignIn() with new password status = %v, error = %v", status, err)
+	}
+	if status, _ := m.ResetPassword(ctx, "db", "project", token, "other-pass"); status != http.StatusUnauthorized {
+		t.Errorf("ResetPassword() with used token status = %v, want %v", status, http.StatusUnauthorized)
+	}
+
+	// A token can only be used by one of the requests using it concurrently
+	_, _ = m.ForgotPassword(ctx, "db", "project", "john@example.com")
+	token = mailer.lastToken()
+	var concurrentStatus int
+	crud.beforeUpdate = func(col string) {
+		if col == userTokensCollection {
+			crud.beforeUpdate = nil
+			concurrentStatus, _ = m.ResetPassword(ctx, "db", "project", token, "concurrent-pass")
+		}
+	}
+	if status, _ := m.ResetPassword(ctx, "db", "project", token, "other-pass"); status != http.StatusUnauthorized || concurrentStatus != http.StatusOK {
+		t.Errorf("ResetPassword() concurrently status = %v, concurrent status = %v", status, concurrentStatus)
+	}
+
+	// Expired tokens are rejected
+	_, _ = m.ForgotPassword(ctx, "db", "project", "john@example.com")
+	for _, row := range crud.tables[userTokensCollection] {
+		row["expires_at"] = time.Now().Add(-time.Minute)
+	}
+	if status, _ := m.ResetPassword(ctx, "db", "project", mailer.lastToken(), "other-pass"); status != http.StatusUnauthorized {
+		t.Errorf("ResetPassword() with expired token status = %v, want %v", status, http.StatusUnauthorized)
+	}
+}
+
+func TestModule_EmailEditProfile_Verification(t *testing.T) {
+	m, crud, _, mailer := newVerificationModule()
+	ctx := context.Background()
+	crud.users["john@example.com"] = map[string]interface{}{"id": "1", "email": "john@example.com", "name": "John", "role": "user", "emailVerified": true}
+
+	// Keeping the same email doesn't need another verification
+	if status, _, err := m.EmailEditProfile(ctx, "{}", "db", "project", "1", "john@example.com", "Johnny", ""); err != nil {
+		t.Fatalf("EmailEditProfile() status = %v, error = %v", status, err)
+	}
+	if crud.users["john@example.com"]["emailVerified"] != true || len(mailer.mails) != 0 {
+		t.Errorf("EmailEditProfile() with same email user = %v, mails = %v", crud.users["john@example.com"], len(mailer.mails))
+	}
+
+	if status, _, err := m.EmailEditProfile(ctx, "{}", "db", "project", "1", "johnny@example.com", "", ""); err != nil {
+		t.Fatalf("EmailEditProfile() status = %v, error = %v", status, err)
+	}
+	if crud.users["john@example.com"]["emailVerified"] != false {
+		t.Errorf("EmailEditProfile() with new email user = %v", crud.users["john@example.com"])
+	}
+	if len(mailer.mails) != 1 || mailer.mails[0].To != "johnny@example.com" {
+		t.Fatalf("EmailEditProfile() mails = %v", mailer.mails)
+	}
+	if status, err := m.VerifyEmail(ctx, "db", "project", mailer.lastToken()); err != nil {
+		t.Fatalf("VerifyEmail() status = %v, error = %v", status, err)
+	}
+	if crud.users["john@example.com"]["emailVerified"] != true {
+		t.Errorf("VerifyEmail() user = %v", crud.users["john@example.com"])
+	}
+}
+
+func TestModule_MailFailure(t *testing.T) {
+	m, crud, _, mailer := newVerificationModule()
+	ctx := context.Background()
+	crud.users["john@example.com"] = map[string]interface{}{"id": "1", "email": "john@example.com", "name": "John", "role": "user"}
+	mailer.err = errors.New("mail server unavailable")
+
+	// The response for an existing user must be the same as the one for an unknown user
+	for _, email := range []string{"john@example.com", "jane@example.com"} {
+		if status, err := m.SendVerificationEmail(ctx, "db", "project", email); status != http.StatusOK || err != nil {
+			t.Errorf("SendVerificationEmail(%s) status = %v, error = %v", email, status, err)
+		}
+		if status, err := m.ForgotPassword(ctx, "db", "project", email); status != http.StatusOK || err != nil {
+			t.Errorf("ForgotPassword(%s) status = %v, error = %v", email, status, err)
+		}
+	}
+}
+
+func Test_webhookMailer(t *testing.T) {
+	var got map[string]interface{}
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	mailer, err := newMailer(&config.Mailer{Type: mailTypeWebhook, URL: server.URL, From: "noreply@example.com", Headers: config.Headers{{Key: "Authorization", Value: "Bearer secret"}}})
+	if err != nil {
+		t.Fatalf("newMailer() error = %v", err)
+	}
+	if err := mailer.SendMail(context.Background(), &Mail{To: "john@example.com", Subject: "Hello", Body: "Body"}); err != nil {
+		t.Fatalf("SendMail() error = %v", err)
+	}
+	if auth != "Bearer secret" || got["from"] != "noreply@example.com" || got["to"] != "john@example.com" || got["subject"] != "Hello" {
+		t.Errorf("SendMail() auth = %v, payload = %v", auth, got)
+	}
+}
+
+func Test_buildMessage(t *testing.T) {
+	got := string(buildMessage("noreply@example.com", &Mail{To: "john@example.com", Subject: "Hi\r\nBcc: evil@example.com", Body: "line1\nline2", HTML: true}))
+	want := "From: noreply@example.com\r\nTo: john@example.com\r\nSubject: HiBcc: evil@example.com\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=\"UTF-8\"\r\n\r\nline1\r\nline2"
+	if got != want {
+		t.Errorf("buildMessage() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// HandleCopyTable is an endpoint handler which copies the rows of a table to a table of another database. The checkpoints
+// of the copy are streamed as new line delimited json objects
+func HandleCopyTable(adminMan *admin.Manager, modules *modules.Modules, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		dbAlias := vars["dbAlias"]
+		projectID := vars["project"]
+		col := vars["col"]
+
+		v := model.CopyRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&v)
+		defer utils.CloseTheCloser(r.Body)
+		if v.TargetCol == "" {
+			v.TargetCol = col
+		}
+
+		// A copy can run for long, so it is only cancelled once the client disconnects
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		// Check if the request is authorised to read the source table and modify the target table
+		if _, err := adminMan.IsTokenValid(ctx, token, "db-schema", "read", map[string]string{"project": projectID, "db": dbAlias, "col": col}); err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-schema", "modify", map[string]string{"project": projectID, "db": v.TargetDbAlias, "col": v.TargetCol})
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, v)
+
+		if v.CreateTable {
+			status, err := syncMan.SetCopyTableSchema(ctx, projectID, dbAlias, col, v.TargetDbAlias, v.TargetCol, reqParams)
+			if err != nil {
+				_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+				return
+			}
+		}
+
+		crud, err := modules.DB(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// The response is only started once the first checkpoint is received, so that errors can still be reported
+		started := false
+		encoder := json.NewEncoder(w)
+		flusher, _ := w.(http.Flusher)
+		err = crud.Copy(ctx, dbAlias, col, &v, reqParams, func(checkpoint *model.CopyCheckpoint) error {
+			if !started {
+				w.Header().Set("Content-Type", "application/x-ndjson")
+				w.WriteHeader(http.StatusOK)
+				started = true
+			}
+			if err := encoder.Encode(checkpoint); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return nil
+		})
+		if err != nil {
+			if !started {
+				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+				return
+			}
+			// The status has already been sent, so the error is reported as the last line of the stream
+			_ = encoder.Encode(map[string]string{"error": err.Error()})
+		}
+	}
+}
@@ -90,8 +90,9 @@ func HandleGetDatabaseConnectionState(adminMan *admin.Manager, modules *modules.
 		}
 
 		connState := crud.GetConnectionState(ctx, dbAlias)
+		replicasState := crud.GetReplicasConnectionState(ctx, dbAlias)
 
-		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: connState})
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": connState, "replicas": replicasState})
 	}
 }
 
@@ -440,6 +441,52 @@ func HandleGetSchemas(adminMan *admin.Manager, syncMan *syncman.Manager) http.Ha
 	}
 }
 
+// HandleExportSchemas returns handler to export schemas as json schema documents or as an openapi spec
+func HandleExportSchemas(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		// get project id and dbType from url
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := "*"
+		dbAliasQuery, exists := r.URL.Query()["dbAlias"]
+		if exists {
+			dbAlias = dbAliasQuery[0]
+		}
+		colQuery, exists := r.URL.Query()["col"]
+		col := "*"
+		if exists {
+			col = colQuery[0]
+		}
+		formatQuery, exists := r.URL.Query()["format"]
+		format := "openapi"
+		if exists {
+			format = formatQuery[0]
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-schema", "read", map[string]string{"project": projectID, "db": dbAlias, "col": col})
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
+
+		status, result, err := syncMan.ExportSchemas(ctx, projectID, dbAlias, col, format, reqParams)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: result})
+	}
+}
+
 // HandleSetTableRules is an endpoint handler which update database collection rules in config & creates collection if it doesn't exist
 func HandleSetTableRules(adminMan *admin.Manager, syncman *syncman.Manager) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
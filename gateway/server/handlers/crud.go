@@ -3,7 +3,11 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +20,12 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
+// exportFlushInterval is the number of exported documents after which the response is flushed to the client
+const exportFlushInterval = 1000
+
+// importBufferSize is the number of rows of a best effort import which are read before being inserted
+const importBufferSize = 1000
+
 type requestMetaData struct {
 	projectID, dbType, col, token string
 }
@@ -104,8 +114,25 @@ func HandleCrudCreate(modules *modules.Modules) http.HandlerFunc {
 		_ = json.NewDecoder(r.Body).Decode(&req)
 		defer utils.CloseTheCloser(r.Body)
 
-		// Check if the user is authenticated
-		reqParams, err := auth.IsCreateOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req)
+		// Documents nested under linked fields are inserted in their own tables
+		reqs, err := crud.SplitNestedCreate(ctx, meta.dbType, meta.col, &req)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Check if the user is authenticated. The nested documents are authorised against the rules of their tables.
+		var reqParams model.RequestParams
+		if len(reqs) == 1 {
+			reqParams, err = auth.IsCreateOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req)
+		} else {
+			for _, createReq := range reqs {
+				reqParams, err = auth.IsCreateOpAuthorised(ctx, meta.projectID, meta.dbType, createReq.Col, meta.token, &model.CreateRequest{Document: createReq.Document, Operation: createReq.Operation})
+				if err != nil {
+					break
+				}
+			}
+		}
 		if err != nil {
 			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
@@ -113,8 +140,13 @@ func HandleCrudCreate(modules *modules.Modules) http.HandlerFunc {
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, req)
 
-		// Perform the write operation
-		err = crud.Create(ctx, meta.dbType, meta.col, &req, reqParams)
+		// Perform the write operation. The parent and nested documents are inserted in a single transaction.
+		if len(reqs) == 1 {
+			err = crud.Create(ctx, meta.dbType, meta.col, &req, reqParams)
+		} else {
+			reqParams.Resource = "db-batch"
+			err = crud.Batch(ctx, meta.dbType, &model.BatchRequest{Requests: reqs}, reqParams)
+		}
 		if err != nil {
 
 			// Send http response
@@ -183,11 +215,254 @@ func HandleCrudRead(modules *modules.Modules) http.HandlerFunc {
 			return
 		}
 
+		// The cursor needs to be generated before post processing modifies the result
+		nextCursor, err := utils.GenerateNextCursor(req.Options, result)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
 		// function to do postProcessing on result
 		_ = authHelpers.PostProcessMethod(ctx, auth.GetAESKey(), actions, result)
 
 		// Give positive acknowledgement
-		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": result})
+		res := map[string]interface{}{"result": result}
+		if nextCursor != "" {
+			res["nextCursor"] = nextCursor
+		}
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, res)
+	}
+}
+
+// HandleCrudExport creates the export operation endpoint which streams all the documents of a collection as ndjson or csv
+func HandleCrudExport(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the path parameters
+		meta := getRequestMetaData(r)
+
+		// An export can run for long, so it is only cancelled once the client disconnects
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		auth, err := modules.Auth(meta.projectID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		crud, err := modules.DB(meta.projectID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		// Load the request from the body
+		req := model.ReadRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		defer utils.CloseTheCloser(r.Body)
+
+		// Create empty read options if it does not exist
+		if req.Options == nil {
+			req.Options = new(model.ReadOptions)
+		}
+
+		// Rest API is not allowed to do joins for security reasons
+		req.Options.Join = nil
+		req.Operation = utils.All
+
+		// Columns of the csv export are the selected fields if provided
+		var columns []string
+		for field := range req.Options.Select {
+			columns = append(columns, field)
+		}
+		sort.Strings(columns)
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = utils.ExportFormatNDJSON
+			if strings.Contains(r.Header.Get("Accept"), "text/csv") {
+				format = utils.ExportFormatCSV
+			}
+		}
+		writer, err := utils.NewExportWriter(w, format, columns)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		actions, reqParams, err := auth.IsReadOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req, model.ReturnWhereStub{})
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, req)
+
+		// The response is only started once the first document is received, so that errors can still be reported
+		var count int64
+		flusher, _ := w.(http.Flusher)
+		err = crud.Export(ctx, meta.dbType, meta.col, &req, reqParams, func(doc map[string]interface{}) error {
+			if count == 0 {
+				w.Header().Set("Content-Type", writer.ContentType())
+				w.WriteHeader(http.StatusOK)
+			}
+			count++
+
+			// function to do postProcessing on result
+			_ = authHelpers.PostProcessMethod(ctx, auth.GetAESKey(), actions, doc)
+			if err := writer.Write(doc); err != nil {
+				return err
+			}
+
+			if count%exportFlushInterval == 0 {
+				if err := writer.Flush(); err != nil {
+					return err
+				}
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			if count == 0 {
+				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+				return
+			}
+			// The status has already been sent, so the stream simply ends early
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Export stopped before all the documents were streamed", err, map[string]interface{}{"project": meta.projectID, "dbAlias": meta.dbType, "col": meta.col, "exported": count})
+			return
+		}
+
+		if count == 0 {
+			w.Header().Set("Content-Type", writer.ContentType())
+			w.WriteHeader(http.StatusOK)
+		}
+		_ = writer.Flush()
+	}
+}
+
+// HandleCrudImport creates the import operation endpoint which inserts the rows of an ndjson or csv file
+func HandleCrudImport(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the path parameters
+		meta := getRequestMetaData(r)
+
+		// An import can run for long, so it is only cancelled once the client disconnects
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		auth, err := modules.Auth(meta.projectID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		crud, err := modules.DB(meta.projectID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		defer utils.CloseTheCloser(r.Body)
+
+		query := r.URL.Query()
+		format := query.Get("format")
+		if format == "" {
+			format = utils.ExportFormatNDJSON
+			if strings.Contains(r.Header.Get("Content-Type"), "text/csv") {
+				format = utils.ExportFormatCSV
+			}
+		}
+		mode := query.Get("mode")
+		if mode == "" {
+			mode = model.ImportModeBestEffort
+		}
+		var chunkSize int
+		if v := query.Get("chunkSize"); v != "" {
+			chunkSize, err = strconv.Atoi(v)
+			if err != nil {
+				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		reader, err := utils.NewImportReader(r.Body, format)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		res := &model.ImportResponse{Errors: []*model.ImportRowError{}}
+		req := &model.ImportRequest{Mode: mode, ChunkSize: chunkSize, IsCSV: reader.IsCSV()}
+		var reqParams model.RequestParams
+
+		// importRows inserts the rows read so far and merges the report
+		importRows := func() error {
+			if len(req.Rows) == 0 {
+				return nil
+			}
+			result, err := crud.Import(ctx, meta.dbType, meta.col, req, reqParams)
+			if err != nil {
+				return err
+			}
+			res.Inserted += result.Inserted
+			res.Errors = append(res.Errors, result.Errors...)
+			req.Rows = nil
+			return nil
+		}
+
+		for {
+			doc, row, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				if _, ok := err.(*utils.ImportRowError); ok {
+					res.Errors = append(res.Errors, &model.ImportRowError{Row: row, Error: err.Error()})
+					continue
+				}
+				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+				return
+			}
+
+			// Every row is authorised individually since the rules may depend on the document
+			params, err := auth.IsCreateOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &model.CreateRequest{Operation: utils.One, Document: doc})
+			if err != nil {
+				res.Errors = append(res.Errors, &model.ImportRowError{Row: row, Error: err.Error()})
+				continue
+			}
+			reqParams = utils.ExtractRequestParams(r, params, req)
+			req.Rows = append(req.Rows, &model.ImportRow{Row: row, Document: doc})
+
+			// Rows of a best effort import are inserted as they are read
+			if mode != model.ImportModeAtomic && len(req.Rows) >= importBufferSize {
+				if err := importRows(); err != nil {
+					_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+					return
+				}
+			}
+		}
+
+		// Nothing gets inserted in the atomic mode if any of the rows is invalid
+		if mode != model.ImportModeAtomic || len(res.Errors) == 0 {
+			if err := importRows(); err != nil {
+				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		sort.Slice(res.Errors, func(i, j int) bool { return res.Errors[i].Row < res.Errors[j].Row })
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, res)
 	}
 }
 
@@ -234,7 +509,7 @@ func HandleCrudUpdate(modules *modules.Modules) http.HandlerFunc {
 		if err != nil {
 
 			// Send http response
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = helpers.Response.SendErrorResponse(ctx, w, getMutationErrorStatus(err), err)
 			return
 		}
 
@@ -294,6 +569,108 @@ func HandleCrudDelete(modules *modules.Modules) http.HandlerFunc {
 	}
 }
 
+// HandleCrudRestore creates the restore operation endpoint
+func HandleCrudRestore(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		meta := getRequestMetaData(r)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		auth, err := modules.Auth(meta.projectID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		crud, err := modules.DB(meta.projectID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		// Load the request from the body
+		req := model.RestoreRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		defer utils.CloseTheCloser(r.Body)
+
+		reqParams, err := auth.IsRestoreOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, req)
+
+		// Perform the restore operation
+		err = crud.Restore(ctx, meta.dbType, meta.col, &req, reqParams)
+		if err != nil {
+			// Send http response
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// Give positive acknowledgement
+		_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
+	}
+}
+
+// HandleCrudHistory creates the endpoint to fetch the change history of a row
+func HandleCrudHistory(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		meta := getRequestMetaData(r)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		auth, err := modules.Auth(meta.projectID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		crud, err := modules.DB(meta.projectID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		// Load the request from the body
+		req := model.HistoryRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		defer utils.CloseTheCloser(r.Body)
+
+		reqParams, err := auth.IsHistoryOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, req)
+
+		// Read the history of the row
+		result, err := crud.History(ctx, meta.dbType, meta.col, &req, reqParams)
+		if err != nil {
+			// Send http response
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// Give positive acknowledgement
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": result})
+	}
+}
+
 // HandleCrudAggregate creates the aggregate operation endpoint
 func HandleCrudAggregate(modules *modules.Modules) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -321,9 +698,20 @@ func HandleCrudAggregate(modules *modules.Modules) http.HandlerFunc {
 		}
 
 		// Load the request from the body
-		req := model.AggregateRequest{}
-		_ = json.NewDecoder(r.Body).Decode(&req)
 		defer utils.CloseTheCloser(r.Body)
+		body, _ := ioutil.ReadAll(r.Body)
+		req := model.AggregateRequest{}
+		_ = json.Unmarshal(body, &req)
+
+		// Keep the order of the fields provided to the sort stages
+		raw := struct {
+			Pipeline json.RawMessage `json:"pipe"`
+		}{}
+		_ = json.Unmarshal(body, &raw)
+		if err := utils.OrderPipelineSortStages(req.Pipeline, raw.Pipeline); err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
 
 		reqParams, err := auth.IsAggregateOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req)
 		if err != nil {
@@ -412,6 +800,10 @@ func HandleCrudBatch(modules *modules.Modules) http.HandlerFunc {
 				r := model.DeleteRequest{Find: req.Find, Operation: req.Operation}
 				reqParams, err = auth.IsDeleteOpAuthorised(ctx, meta.projectID, meta.dbType, req.Col, meta.token, &r)
 
+			case string(model.Restore):
+				r := model.RestoreRequest{Find: req.Find, Operation: req.Operation}
+				reqParams, err = auth.IsRestoreOpAuthorised(ctx, meta.projectID, meta.dbType, req.Col, meta.token, &r)
+
 			}
 
 			// Send error response
@@ -427,7 +819,7 @@ func HandleCrudBatch(modules *modules.Modules) http.HandlerFunc {
 
 		err = crud.Batch(ctx, meta.dbType, &txRequest, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = helpers.Response.SendErrorResponse(ctx, w, getMutationErrorStatus(err), err)
 			return
 		}
 
@@ -435,3 +827,11 @@ func HandleCrudBatch(modules *modules.Modules) http.HandlerFunc {
 		_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
 	}
 }
+
+// getMutationErrorStatus returns the http status code to be sent for an error which occurred while mutating documents
+func getMutationErrorStatus(err error) int {
+	if err == utils.ErrVersionConflict {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -3,7 +3,10 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -189,3 +192,469 @@ func HandleEmailEditProfile(modules *modules.Modules) http.HandlerFunc {
 		_ = helpers.Response.SendResponse(ctx, w, status, result)
 	}
 }
+
+// HandleSendVerificationEmail returns the handler which sends an email with a token to verify the email of a user
+func HandleSendVerificationEmail(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Load the request from the body
+		req := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		defer utils.CloseTheCloser(r.Body)
+
+		status, err := userManagement.SendVerificationEmail(ctx, dbAlias, projectID, req["email"])
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+// HandleVerifyEmail returns the handler which verifies the email of a user with the token sent to the user
+func HandleVerifyEmail(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Load the request from the body
+		req := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		defer utils.CloseTheCloser(r.Body)
+
+		status, err := userManagement.VerifyEmail(ctx, dbAlias, projectID, req["token"])
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+// HandleForgotPassword returns the handler which sends an email with a token to reset the password of a user
+func HandleForgotPassword(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Load the request from the body
+		req := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		defer utils.CloseTheCloser(r.Body)
+
+		status, err := userManagement.ForgotPassword(ctx, dbAlias, projectID, req["email"])
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+// HandleResetPassword returns the handler which resets the password of a user with the token sent to the user
+func HandleResetPassword(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Load the request from the body
+		req := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		defer utils.CloseTheCloser(r.Body)
+
+		status, err := userManagement.ResetPassword(ctx, dbAlias, projectID, req["token"], req["pass"])
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+// HandleEnrollMFA returns the handler which creates a totp secret for the user to enroll for multi factor authentication
+func HandleEnrollMFA(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		defer utils.CloseTheCloser(r.Body)
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		status, result, err := userManagement.EnrollMFA(ctx, token, dbAlias, projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, status, result)
+	}
+}
+
+// HandleEnableMFA returns the handler which enables multi factor authentication once the one time password of the enrolled secret is verified
+func HandleEnableMFA(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		// Load the request from the body
+		req := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		defer utils.CloseTheCloser(r.Body)
+
+		status, result, err := userManagement.EnableMFA(ctx, token, dbAlias, projectID, req["code"])
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, status, result)
+	}
+}
+
+// HandleDisableMFA returns the handler which disables multi factor authentication for the user
+func HandleDisableMFA(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		// Load the request from the body
+		req := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		defer utils.CloseTheCloser(r.Body)
+
+		status, err := userManagement.DisableMFA(ctx, token, dbAlias, projectID, req["code"])
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+// HandleMFASignIn returns the handler which completes the sign in of a user with a one time password or a recovery code
+func HandleMFASignIn(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Load the request from the body
+		req := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		defer utils.CloseTheCloser(r.Body)
+
+		status, result, err := userManagement.MFASignIn(ctx, dbAlias, projectID, req["mfaToken"], req["code"])
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, status, result)
+	}
+}
+
+// HandleOAuthAuthorize returns the handler which sends the user to the sign in page of an oauth provider
+func HandleOAuthAuthorize(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		provider := vars["provider"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+		defer utils.CloseTheCloser(r.Body)
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		status, authURL, session, err := userManagement.OAuthAuthorize(ctx, provider, r.URL.Query().Get("redirect"))
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: oauthSessionCookie, Value: session, Path: getOAuthCookiePath(r), MaxAge: int(oauthSessionCookieAge.Seconds()), HttpOnly: true, Secure: isSecureRequest(r), SameSite: http.SameSiteLaxMode})
+		http.Redirect(w, r, authURL, http.StatusFound)
+	}
+}
+
+// HandleOAuthCallback returns the handler which completes the sign in with an oauth provider. The user is sent back to
+// the app with the tokens in the fragment of the url if a redirect url was provided while authorizing
+func HandleOAuthCallback(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+		provider := vars["provider"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+		defer utils.CloseTheCloser(r.Body)
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		query := r.URL.Query()
+		if e := query.Get("error"); e != "" {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, fmt.Errorf("sign in provider (%s) returned error (%s) %s", provider, e, query.Get("error_description")))
+			return
+		}
+
+		var session string
+		if cookie, err := r.Cookie(oauthSessionCookie); err == nil {
+			session = cookie.Value
+		}
+		http.SetCookie(w, &http.Cookie{Name: oauthSessionCookie, Path: getOAuthCookiePath(r), MaxAge: -1, HttpOnly: true, Secure: isSecureRequest(r), SameSite: http.SameSiteLaxMode})
+
+		status, result, redirect, err := userManagement.OAuthCallback(ctx, dbAlias, projectID, provider, query.Get("code"), query.Get("state"), session)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		if redirect != "" {
+			fragment := url.Values{}
+			for _, key := range []string{"token", "refreshToken", "mfaToken"} {
+				if value, ok := result[key].(string); ok {
+					fragment.Set(key, value)
+				}
+			}
+			http.Redirect(w, r, redirect+"#"+fragment.Encode(), http.StatusFound)
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, status, result)
+	}
+}
+
+// HandleRefresh returns the handler which issues a new token for the session of a refresh token
+func HandleRefresh(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Load the request from the body
+		req := struct {
+			RefreshToken string `json:"refreshToken"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		defer utils.CloseTheCloser(r.Body)
+
+		status, result, err := userManagement.Refresh(ctx, dbAlias, projectID, req.RefreshToken)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, status, result)
+	}
+}
+
+// HandleLogout returns the handler which ends the session of the token or of the refresh token
+func HandleLogout(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+		defer utils.CloseTheCloser(r.Body)
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		// Load the refresh token from the body. It identifies the session if the token has expired
+		req := struct {
+			RefreshToken string `json:"refreshToken"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		status, err := userManagement.Logout(ctx, token, req.RefreshToken, dbAlias, projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+// HandleLogoutAll returns the handler which ends all the sessions of the user the token or the refresh token belongs to
+func HandleLogoutAll(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+		defer utils.CloseTheCloser(r.Body)
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		// Load the refresh token from the body. It identifies the session if the token has expired
+		req := struct {
+			RefreshToken string `json:"refreshToken"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		status, err := userManagement.LogoutAll(ctx, token, req.RefreshToken, dbAlias, projectID)
+		if err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+const (
+	oauthSessionCookie    = "sc-oauth-session"
+	oauthSessionCookieAge = 10 * time.Minute
+)
+
+// getOAuthCookiePath returns the path the session cookie of an oauth provider is scoped to
+func getOAuthCookiePath(r *http.Request) string {
+	return r.URL.Path[:strings.LastIndex(r.URL.Path, "/")]
+}
+
+func isSecureRequest(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
@@ -242,6 +242,18 @@ func HandleGraphqlSocket(modules WebsocketModulesInterface) http.HandlerFunc {
 					continue
 				}
 
+				// Subscriptions are subject to the allow list of persisted queries as well
+				if err := graph.CheckPersistedQuery(ctx, m.Payload.Query, doc, m.Payload.Token); err != nil {
+					channel <- &graphqlMessage{ID: m.ID, Type: utils.GqlError, Payload: payloadObject{Error: []gqlError{{Message: err.Error()}}}}
+					continue
+				}
+
+				// Reject the subscriptions exceeding the query limits of the project
+				if err := graph.CheckQueryLimits(ctx, doc, m.Payload.Variables); err != nil {
+					channel <- &graphqlMessage{ID: m.ID, Type: utils.GqlError, Payload: payloadObject{Error: []gqlError{{Message: err.Error()}}}}
+					continue
+				}
+
 				opDefinition, ok := doc.Definitions[0].(*ast.OperationDefinition)
 				if !ok {
 					channel <- &graphqlMessage{ID: m.ID, Type: utils.GqlError, Payload: payloadObject{Error: []gqlError{{Message: errors.New("erros in operation definition of schema").Error()}}}}
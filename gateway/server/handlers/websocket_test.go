@@ -490,6 +490,42 @@ subscription {
 				{Type: utils.GqlError, ID: "2"},
 			},
 		},
+		{
+			name: "start with a query which isn't persisted",
+			realtimeMockArgs: []mockArg{
+				{
+					method:        "RemoveClient",
+					args:          []interface{}{mock.Anything},
+					paramReturned: []interface{}{},
+				},
+			},
+			graphMockArgs: []mockArg{{method: "CheckPersistedQuery", args: []interface{}{mock.Anything, "abc"}, paramReturned: []interface{}{errors.New("query is not persisted")}}},
+			push:          []*model.FeedData{},
+			send: []*graphqlMessage{
+				{Type: utils.GqlStart, ID: "2", Payload: payloadObject{Token: "abc", Query: `subscription {	col(where: {foo: bar}) @db {find}}`}},
+			},
+			rcv: []*graphqlMessage{
+				{Type: utils.GqlError, ID: "2"},
+			},
+		},
+		{
+			name: "start with a query which exceeds the query limits",
+			realtimeMockArgs: []mockArg{
+				{
+					method:        "RemoveClient",
+					args:          []interface{}{mock.Anything},
+					paramReturned: []interface{}{},
+				},
+			},
+			graphMockArgs: []mockArg{{method: "CheckQueryLimits", args: []interface{}{map[string]interface{}{"limit": float64(1000)}}, paramReturned: []interface{}{errors.New("query cost exceeds the limit")}}},
+			push:          []*model.FeedData{},
+			send: []*graphqlMessage{
+				{Type: utils.GqlStart, ID: "2", Payload: payloadObject{Query: `subscription($limit: Int) {	col(where: {foo: bar}, limit: $limit) @db {find}}`, Variables: map[string]interface{}{"limit": float64(1000)}}},
+			},
+			rcv: []*graphqlMessage{
+				{Type: utils.GqlError, ID: "2"},
+			},
+		},
 		{
 			name: "invalid query string",
 			realtimeMockArgs: []mockArg{
@@ -578,6 +614,8 @@ subscription {
 			for _, m := range tt.graphMockArgs {
 				graph.On(m.method, m.args...).Return(m.paramReturned...)
 			}
+			graph.On("CheckPersistedQuery", mock.Anything, mock.Anything).Return(nil).Maybe()
+			graph.On("CheckQueryLimits", mock.Anything).Return(nil).Maybe()
 
 			// Create the mock server
 			s := httptest.NewServer(HandleGraphqlSocket(&mockWebsocketModules{&realtime, &graph}))
@@ -691,3 +729,16 @@ func (m *mockGraphQLModule) GetDBAlias(ctx context.Context, field *ast.Field, to
 func (m *mockGraphQLModule) ExecGraphQLQuery(ctx context.Context, req *model.GraphQLRequest, token string, cb model.GraphQLCallback) {
 	m.Called(ctx, req, token, cb)
 }
+
+func (m *mockGraphQLModule) GenerateSDL(ctx context.Context) (string, error) {
+	c := m.Called(ctx)
+	return c.String(0), c.Error(1)
+}
+
+func (m *mockGraphQLModule) CheckPersistedQuery(ctx context.Context, query string, doc *ast.Document, token string) error {
+	return m.Called(query, token).Error(0)
+}
+
+func (m *mockGraphQLModule) CheckQueryLimits(ctx context.Context, doc *ast.Document, variables map[string]interface{}) error {
+	return m.Called(variables).Error(0)
+}
@@ -44,6 +44,10 @@ func (s *Server) routes(profiler bool, staticPath string, restrictedHosts []stri
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/remote-service/service/{id}").HandlerFunc(handlers.HandleAddService(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/remote-service/service/{id}").HandlerFunc(handlers.HandleDeleteService(s.managers.Admin(), s.managers.Sync()))
 
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/graphql/persisted-queries").HandlerFunc(handlers.HandleGetGraphQLPersistedQueries(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/graphql/persisted-queries/{id}").HandlerFunc(handlers.HandleSetGraphQLPersistedQuery(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/graphql/persisted-queries/{id}").HandlerFunc(handlers.HandleDeleteGraphQLPersistedQuery(s.managers.Admin(), s.managers.Sync()))
+
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/user-management/provider").HandlerFunc(handlers.HandleGetUserManagement(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/user-management/provider/{id}").HandlerFunc(handlers.HandleSetUserManagement(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/user-management/provider/{id}").HandlerFunc(handlers.HandleDeleteUserManagement(s.managers.Admin(), s.managers.Sync()))
@@ -78,6 +82,7 @@ func (s *Server) routes(profiler bool, staticPath string, restrictedHosts []stri
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/database/collections/rules").HandlerFunc(handlers.HandleGetTableRules(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/database/config").HandlerFunc(handlers.HandleGetDatabaseConfig(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/database/collections/schema/mutate").HandlerFunc(handlers.HandleGetSchemas(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/database/collections/schema/export").HandlerFunc(handlers.HandleExportSchemas(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/rules").HandlerFunc(handlers.HandleSetTableRules(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/rules").HandlerFunc(handlers.HandleDeleteTableRules(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/config/{id}").HandlerFunc(handlers.HandleSetDatabaseConfig(s.managers.Admin(), s.managers.Sync()))
@@ -88,10 +93,16 @@ func (s *Server) routes(profiler bool, staticPath string, restrictedHosts []stri
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}").HandlerFunc(handlers.HandleDeleteTable(s.managers.Admin(), s.modules, s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/schema/mutate").HandlerFunc(handlers.HandleModifyAllSchema(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/schema/mutate").HandlerFunc(handlers.HandleModifySchema(s.managers.Admin(), s.modules, s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/schema/plan").HandlerFunc(handlers.HandlePlanModifySchema(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/copy").HandlerFunc(handlers.HandleCopyTable(s.managers.Admin(), s.modules, s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/database/{dbAlias}/schema/migrations").HandlerFunc(handlers.HandleGetSchemaMigrations(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/schema/migrations/apply").HandlerFunc(handlers.HandleApplySchemaMigrations(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/schema/migrations/rollback").HandlerFunc(handlers.HandleRollbackSchemaMigrations(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/schema/inspect").HandlerFunc(handlers.HandleReloadSchema(s.managers.Admin(), s.modules, s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/schema/track").HandlerFunc(handlers.HandleInspectCollectionSchema(s.managers.Admin(), s.modules, s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/schema/untrack").HandlerFunc(handlers.HandleUntrackCollectionSchema(s.managers.Admin(), s.modules, s.managers.Sync()))
 	router.Methods(http.MethodGet).Path("/v1/external/projects/{project}/database/{dbAlias}/schema/inspect").HandlerFunc(handlers.HandleInspectTrackedCollectionsSchema(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodGet).Path("/v1/external/projects/{project}/graphql/sdl").HandlerFunc(handlers.HandleGetGraphQLSDL(s.managers.Admin(), s.modules))
 
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/letsencrypt/config").HandlerFunc(handlers.HandleGetEncryptWhitelistedDomain(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/letsencrypt/config/{id}").HandlerFunc(handlers.HandleLetsEncryptWhitelistedDomain(s.managers.Admin(), s.managers.Sync()))
@@ -131,18 +142,35 @@ func (s *Server) routes(profiler bool, staticPath string, restrictedHosts []stri
 	router.Methods(http.MethodPost).Path("/v1/api/{project}/crud/{dbAlias}/prepared-queries/{id}").HandlerFunc(handlers.HandleCrudPreparedQuery(s.modules))
 	crudRouter := router.Methods(http.MethodPost).PathPrefix("/v1/api/{project}/crud/{dbAlias}/{col}").Subrouter()
 	crudRouter.HandleFunc("/create", handlers.HandleCrudCreate(s.modules))
+	crudRouter.HandleFunc("/import", handlers.HandleCrudImport(s.modules))
 	crudRouter.HandleFunc("/read", handlers.HandleCrudRead(s.modules))
+	crudRouter.HandleFunc("/export", handlers.HandleCrudExport(s.modules))
 	crudRouter.HandleFunc("/update", handlers.HandleCrudUpdate(s.modules))
 	crudRouter.HandleFunc("/delete", handlers.HandleCrudDelete(s.modules))
+	crudRouter.HandleFunc("/restore", handlers.HandleCrudRestore(s.modules))
+	crudRouter.HandleFunc("/history", handlers.HandleCrudHistory(s.modules))
 	crudRouter.HandleFunc("/aggr", handlers.HandleCrudAggregate(s.modules))
 
 	// Initialize the routes for the user management operations
 	userRouter := router.PathPrefix("/v1/api/{project}/auth/{dbAlias}").Subrouter()
 	userRouter.Methods(http.MethodPost).Path("/email/signin").HandlerFunc(handlers.HandleEmailSignIn(s.modules))
 	userRouter.Methods(http.MethodPost).Path("/email/signup").HandlerFunc(handlers.HandleEmailSignUp(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/email/verify/send").HandlerFunc(handlers.HandleSendVerificationEmail(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/email/verify").HandlerFunc(handlers.HandleVerifyEmail(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/email/forgot_password").HandlerFunc(handlers.HandleForgotPassword(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/email/reset_password").HandlerFunc(handlers.HandleResetPassword(s.modules))
 	userRouter.Methods(http.MethodGet).Path("/profile/{id}").HandlerFunc(handlers.HandleProfile(s.modules))
 	userRouter.Methods(http.MethodGet).Path("/profiles").HandlerFunc(handlers.HandleProfiles(s.modules))
 	userRouter.Methods(http.MethodPost).Path("/edit_profile/{id}").HandlerFunc(handlers.HandleEmailEditProfile(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/mfa/enroll").HandlerFunc(handlers.HandleEnrollMFA(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/mfa/enable").HandlerFunc(handlers.HandleEnableMFA(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/mfa/disable").HandlerFunc(handlers.HandleDisableMFA(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/mfa/signin").HandlerFunc(handlers.HandleMFASignIn(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/refresh").HandlerFunc(handlers.HandleRefresh(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/logout").HandlerFunc(handlers.HandleLogout(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/logout_all").HandlerFunc(handlers.HandleLogoutAll(s.modules))
+	userRouter.Methods(http.MethodGet).Path("/{provider}/authorize").HandlerFunc(handlers.HandleOAuthAuthorize(s.modules))
+	userRouter.Methods(http.MethodGet).Path("/{provider}/callback").HandlerFunc(handlers.HandleOAuthCallback(s.modules))
 
 	// Initialize the routes for the file management operations
 	router.Methods(http.MethodPost).Path("/v1/api/{project}/files").HandlerFunc(handlers.HandleCreateFile(s.modules))
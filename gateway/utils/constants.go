@@ -27,6 +27,12 @@ const (
 	Upsert string = "upsert"
 )
 
+// SearchScoreField is the sort field used to sort the documents by the relevance of a full text search
+const SearchScoreField = "_score"
+
+// DistanceField is the sort field used to sort the documents by their distance from the point of a near clause
+const DistanceField = "_distance"
+
 // Broker is the type of broker used by Space Cloud
 type Broker string
 
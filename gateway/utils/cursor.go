@@ -0,0 +1,261 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// ErrInvalidCursor is thrown when the cursor provided for keyset pagination cannot be decoded
+var ErrInvalidCursor = errors.New("Invalid cursor provided")
+
+// The types of the sort values which are restored while decoding a cursor since they don't survive a JSON round trip
+const (
+	cursorTypeDate     = "date"
+	cursorTypeObjectID = "objectId"
+)
+
+// cursorPayload is the JSON encoded form of a cursor having values which need to be restored while decoding. The types
+// hold a tag for every such value and are empty for the rest. Cursors without such values are encoded as an array
+type cursorPayload struct {
+	Values []interface{} `json:"v"`
+	Types  []string      `json:"t,omitempty"`
+}
+
+// EncodeCursor generates an opaque cursor from the values of the sort fields of a document
+func EncodeCursor(sort []string, doc interface{}) (string, error) {
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("cursor cannot be generated for document of type (%T)", doc)
+	}
+
+	payload := cursorPayload{Values: make([]interface{}, len(sort))}
+	types := make([]string, len(sort))
+	hasTypes := false
+	for i, field := range sort {
+		value, p := loadCursorValue(strings.TrimPrefix(field, "-"), obj)
+		if !p {
+			return "", fmt.Errorf("cursor cannot be generated as field (%s) is not present in the document", strings.TrimPrefix(field, "-"))
+		}
+
+		switch v := value.(type) {
+		case time.Time:
+			value, types[i] = v.UTC().Format(time.RFC3339Nano), cursorTypeDate
+		case primitive.DateTime:
+			value, types[i] = v.Time().UTC().Format(time.RFC3339Nano), cursorTypeDate
+		case primitive.ObjectID:
+			value, types[i] = v.Hex(), cursorTypeObjectID
+		}
+		if types[i] != "" {
+			hasTypes = true
+		}
+		payload.Values[i] = value
+	}
+	var encoded interface{} = payload.Values
+	if hasTypes {
+		payload.Types = types
+		encoded = payload
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor returns the values of the sort fields stored in a cursor
+func DecodeCursor(sort []string, cursor string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	payload := cursorPayload{}
+	if len(data) > 0 && data[0] == '[' {
+		err = json.Unmarshal(data, &payload.Values)
+	} else {
+		err = json.Unmarshal(data, &payload)
+	}
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	// The cursor is only valid for the sort order it was generated with
+	if len(payload.Values) != len(sort) || (len(payload.Types) != 0 && len(payload.Types) != len(sort)) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := payload.Values
+	for i, t := range payload.Types {
+		if t == "" {
+			continue
+		}
+		s, ok := values[i].(string)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+
+		switch t {
+		case cursorTypeDate:
+			v, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			values[i] = v
+		case cursorTypeObjectID:
+			v, err := primitive.ObjectIDFromHex(s)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			values[i] = v
+		default:
+			return nil, ErrInvalidCursor
+		}
+	}
+	return values, nil
+}
+
+// NullsSortFirst returns true if the database places documents having a null value before the rest when sorting in the
+// ascending order. Postgres considers nulls to be larger than any other value while the other databases consider them
+// to be smaller.
+func NullsSortFirst(dbType string) bool {
+	return model.DBType(dbType) != model.Postgres
+}
+
+// GenerateCursorClause generates the where clause which matches the documents lying after the cursor in the provided
+// sort order. The documents before the cursor are matched instead if isBefore is true. NullsFirst tells whether the
+// database sorts null values before the rest in the ascending order.
+//
+// For a sort order of [a, -b] the generated clause is equivalent to (a > :a) OR (a = :a AND b < :b). Documents having
+// a null value for a sort field lie either before or after all the other values of that field. Hence a comparison with
+// a null value is replaced by a check for null (IS NULL / IS NOT NULL) based on where the nulls lie.
+func GenerateCursorClause(sort []string, cursor string, isBefore, nullsFirst bool) (map[string]interface{}, error) {
+	if len(sort) == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	values, err := DecodeCursor(sort, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	or := make([]interface{}, 0, len(sort))
+	for i, field := range sort {
+		newClause := func(fieldClause map[string]interface{}) map[string]interface{} {
+			clause := make(map[string]interface{}, i+1)
+			for j := 0; j < i; j++ {
+				// A null value is matched with IS NULL
+				clause[strings.TrimPrefix(sort[j], "-")] = map[string]interface{}{"$eq": values[j]}
+			}
+			clause[strings.TrimPrefix(field, "-")] = fieldClause
+			return clause
+		}
+
+		op := "$gt"
+		if strings.HasPrefix(field, "-") != isBefore {
+			op = "$lt"
+		}
+
+		// Nulls are read first if they lie at the start of the direction in which the documents are read
+		nullsRead := (op == "$gt") == nullsFirst
+		if values[i] == nil {
+			// Every non null value lies after the nulls if they are read first. Nothing lies after them otherwise
+			if nullsRead {
+				or = append(or, newClause(map[string]interface{}{"$ne": nil}))
+			}
+			continue
+		}
+
+		or = append(or, newClause(map[string]interface{}{op: values[i]}))
+		if !nullsRead {
+			// Nulls lie after every non null value if they are read last
+			or = append(or, newClause(map[string]interface{}{"$eq": nil}))
+		}
+	}
+
+	if len(or) == 0 {
+		// No document lies after the cursor. A clause which doesn't match any document is returned
+		field := strings.TrimPrefix(sort[len(sort)-1], "-")
+		return map[string]interface{}{field: map[string]interface{}{"$eq": nil, "$ne": nil}}, nil
+	}
+	return map[string]interface{}{"$or": or}, nil
+}
+
+// GetCursor returns the cursor of a paginated read along with the direction in which the documents are to be read. An
+// empty cursor signifies the first page in that direction.
+func GetCursor(options *model.ReadOptions) (cursor string, isBefore, ok bool) {
+	if options == nil {
+		return "", false, false
+	}
+	if options.Before != nil {
+		return *options.Before, true, true
+	}
+	if options.After != nil {
+		return *options.After, false, true
+	}
+	return "", false, false
+}
+
+// GenerateNextCursor returns the cursor to be used for reading the next page of a cursor paginated read. The next page
+// lies in the direction of the cursor used for the read. An empty cursor is returned if no limit was provided or if the
+// limit wasn't reached, since no more documents are left in either case.
+func GenerateNextCursor(options *model.ReadOptions, result interface{}) (string, error) {
+	if options == nil || (options.After == nil && options.Before == nil) {
+		return "", nil
+	}
+
+	docs, ok := result.([]interface{})
+	if !ok || len(docs) == 0 || options.Limit == nil || int64(len(docs)) < *options.Limit {
+		return "", nil
+	}
+
+	if options.Before != nil {
+		return EncodeCursor(options.Sort, docs[0])
+	}
+	return EncodeCursor(options.Sort, docs[len(docs)-1])
+}
+
+// ReverseSort reverses the direction of each field in the sort order
+func ReverseSort(sort []string) []string {
+	reversed := make([]string, len(sort))
+	for i, field := range sort {
+		if strings.HasPrefix(field, "-") {
+			reversed[i] = strings.TrimPrefix(field, "-")
+			continue
+		}
+		reversed[i] = "-" + field
+	}
+	return reversed
+}
+
+// ReverseArray reverses the order of the elements of an array in place
+func ReverseArray(array []interface{}) {
+	for i, j := 0, len(array)-1; i < j; i, j = i+1, j-1 {
+		array[i], array[j] = array[j], array[i]
+	}
+}
+
+func loadCursorValue(field string, obj map[string]interface{}) (interface{}, bool) {
+	if value, p := obj[field]; p {
+		return value, true
+	}
+
+	// Nested fields are stored as objects by document databases
+	arr := strings.SplitN(field, ".", 2)
+	if len(arr) != 2 {
+		return nil, false
+	}
+	nested, ok := obj[arr[0]].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return loadCursorValue(arr[1], nested)
+}
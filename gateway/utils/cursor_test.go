@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func TestGenerateCursorClause(t *testing.T) {
+	sort := []string{"age", "-address.city", "id"}
+	cursor, err := EncodeCursor(sort, map[string]interface{}{"id": "1", "age": 20, "address": map[string]interface{}{"city": "pune"}})
+	if err != nil {
+		t.Fatal("unable to encode cursor", err)
+	}
+
+	nullCursor, err := EncodeCursor(sort, map[string]interface{}{"id": "1", "age": nil, "address": map[string]interface{}{"city": "pune"}})
+	if err != nil {
+		t.Fatal("unable to encode cursor", err)
+	}
+	onlyNullCursor, err := EncodeCursor([]string{"age"}, map[string]interface{}{"age": nil})
+	if err != nil {
+		t.Fatal("unable to encode cursor", err)
+	}
+
+	type args struct {
+		sort       []string
+		cursor     string
+		isBefore   bool
+		nullsFirst bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "after cursor",
+			args: args{sort: sort, cursor: cursor, nullsFirst: true},
+			want: map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"age": map[string]interface{}{"$gt": float64(20)}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": float64(20)}, "address.city": map[string]interface{}{"$lt": "pune"}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": float64(20)}, "address.city": map[string]interface{}{"$eq": nil}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": float64(20)}, "address.city": map[string]interface{}{"$eq": "pune"}, "id": map[string]interface{}{"$gt": "1"}},
+			}},
+		},
+		{
+			name: "before cursor",
+			args: args{sort: sort, cursor: cursor, isBefore: true, nullsFirst: true},
+			want: map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"age": map[string]interface{}{"$lt": float64(20)}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": nil}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": float64(20)}, "address.city": map[string]interface{}{"$gt": "pune"}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": float64(20)}, "address.city": map[string]interface{}{"$eq": "pune"}, "id": map[string]interface{}{"$lt": "1"}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": float64(20)}, "address.city": map[string]interface{}{"$eq": "pune"}, "id": map[string]interface{}{"$eq": nil}},
+			}},
+		},
+		{
+			name: "after cursor with nulls sorted last",
+			args: args{sort: sort, cursor: cursor},
+			want: map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"age": map[string]interface{}{"$gt": float64(20)}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": nil}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": float64(20)}, "address.city": map[string]interface{}{"$lt": "pune"}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": float64(20)}, "address.city": map[string]interface{}{"$eq": "pune"}, "id": map[string]interface{}{"$gt": "1"}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": float64(20)}, "address.city": map[string]interface{}{"$eq": "pune"}, "id": map[string]interface{}{"$eq": nil}},
+			}},
+		},
+		{
+			name: "after cursor having a null value with nulls sorted first",
+			args: args{sort: sort, cursor: nullCursor, nullsFirst: true},
+			want: map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"age": map[string]interface{}{"$ne": nil}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": nil}, "address.city": map[string]interface{}{"$lt": "pune"}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": nil}, "address.city": map[string]interface{}{"$eq": nil}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": nil}, "address.city": map[string]interface{}{"$eq": "pune"}, "id": map[string]interface{}{"$gt": "1"}},
+			}},
+		},
+		{
+			name: "after cursor having a null value with nulls sorted last",
+			args: args{sort: sort, cursor: nullCursor},
+			want: map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"age": map[string]interface{}{"$eq": nil}, "address.city": map[string]interface{}{"$lt": "pune"}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": nil}, "address.city": map[string]interface{}{"$eq": "pune"}, "id": map[string]interface{}{"$gt": "1"}},
+				map[string]interface{}{"age": map[string]interface{}{"$eq": nil}, "address.city": map[string]interface{}{"$eq": "pune"}, "id": map[string]interface{}{"$eq": nil}},
+			}},
+		},
+		{
+			name: "no document lies after a null value sorted last",
+			args: args{sort: []string{"age"}, cursor: onlyNullCursor},
+			want: map[string]interface{}{"age": map[string]interface{}{"$eq": nil, "$ne": nil}},
+		},
+		{
+			name:    "cursor generated for a different sort order",
+			args:    args{sort: []string{"id"}, cursor: cursor},
+			wantErr: true,
+		},
+		{
+			name:    "malformed cursor",
+			args:    args{sort: sort, cursor: "not-a-cursor"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateCursorClause(tt.args.sort, tt.args.cursor, tt.args.isBefore, tt.args.nullsFirst)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateCursorClause() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GenerateCursorClause() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateNextCursor(t *testing.T) {
+	empty := ""
+	limit := int64(2)
+	docs := []interface{}{map[string]interface{}{"id": "1"}, map[string]interface{}{"id": "2"}}
+	firstCursor, _ := EncodeCursor([]string{"id"}, docs[0])
+	lastCursor, _ := EncodeCursor([]string{"id"}, docs[1])
+
+	tests := []struct {
+		name    string
+		options *model.ReadOptions
+		result  interface{}
+		want    string
+	}{
+		{
+			name:    "not a paginated read",
+			options: &model.ReadOptions{Sort: []string{"id"}},
+			result:  docs,
+		},
+		{
+			name:    "next page after the last document",
+			options: &model.ReadOptions{Sort: []string{"id"}, After: &empty, Limit: &limit},
+			result:  docs,
+			want:    lastCursor,
+		},
+		{
+			name:    "previous page before the first document",
+			options: &model.ReadOptions{Sort: []string{"id"}, Before: &empty, Limit: &limit},
+			result:  docs,
+			want:    firstCursor,
+		},
+		{
+			name:    "no limit provided",
+			options: &model.ReadOptions{Sort: []string{"id"}, After: &empty},
+			result:  docs,
+		},
+		{
+			name:    "no more documents left",
+			options: &model.ReadOptions{Sort: []string{"id"}, After: &empty, Limit: &limit},
+			result:  docs[:1],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateNextCursor(tt.options, tt.result)
+			if err != nil {
+				t.Errorf("GenerateNextCursor() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GenerateNextCursor() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor_restoresTypes(t *testing.T) {
+	sort := []string{"createdAt", "-_id", "name"}
+	createdAt := time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.FixedZone("IST", 19800))
+	id := primitive.NewObjectID()
+
+	cursor, err := EncodeCursor(sort, map[string]interface{}{"createdAt": createdAt, "_id": id, "name": "ash"})
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+	got, err := DecodeCursor(sort, cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	want := []interface{}{createdAt.UTC(), id, "ash"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeCursor() got = %v, want %v", got, want)
+	}
+
+	// Mongo returns dates as primitive.DateTime
+	cursor, _ = EncodeCursor([]string{"createdAt"}, map[string]interface{}{"createdAt": primitive.NewDateTimeFromTime(createdAt)})
+	if got, _ := DecodeCursor([]string{"createdAt"}, cursor); !reflect.DeepEqual(got, []interface{}{createdAt.UTC().Truncate(time.Millisecond)}) {
+		t.Errorf("DecodeCursor() got = %v, want %v", got, createdAt.UTC())
+	}
+}
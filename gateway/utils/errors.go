@@ -5,6 +5,9 @@ import "errors"
 // ErrInvalidParams is thrown when the input parameters for an operation are invalid
 var ErrInvalidParams = errors.New("Invalid parameter provided")
 
+// ErrVersionConflict is thrown when the version of a document provided in an update doesn't match the one in the database
+var ErrVersionConflict = errors.New("Version conflict. The document has been modified by another request")
+
 // ErrDatabaseDisabled is thrown when an operation is requested on a disabled database
 var ErrDatabaseDisabled = errors.New("Database is disabled. Please enable it")
 
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// ExportFormatNDJSON exports every document as a json object on a new line
+	ExportFormatNDJSON string = "ndjson"
+
+	// ExportFormatCSV exports every document as a row of a csv file
+	ExportFormatCSV string = "csv"
+)
+
+// ExportWriter writes the exported documents to a stream in the requested format
+type ExportWriter struct {
+	format  string
+	columns []string
+
+	encoder       *json.Encoder
+	csvWriter     *csv.Writer
+	headerWritten bool
+}
+
+// NewExportWriter creates a writer for the provided export format. The columns are only used by the csv format.
+// They are derived from the first document written if not provided.
+func NewExportWriter(w io.Writer, format string, columns []string) (*ExportWriter, error) {
+	switch format {
+	case ExportFormatNDJSON:
+		return &ExportWriter{format: format, encoder: json.NewEncoder(w)}, nil
+	case ExportFormatCSV:
+		return &ExportWriter{format: format, columns: columns, csvWriter: csv.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("invalid export format (%s) provided", format)
+	}
+}
+
+// ContentType returns the content type of the exported stream
+func (e *ExportWriter) ContentType() string {
+	if e.format == ExportFormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// Write writes a single document to the stream
+func (e *ExportWriter) Write(doc map[string]interface{}) error {
+	if e.format == ExportFormatNDJSON {
+		return e.encoder.Encode(doc)
+	}
+
+	// Write the header before the first row
+	if e.columns == nil {
+		e.columns = make([]string, 0, len(doc))
+		for k := range doc {
+			e.columns = append(e.columns, k)
+		}
+		sort.Strings(e.columns)
+	}
+	if !e.headerWritten {
+		if err := e.csvWriter.Write(e.columns); err != nil {
+			return err
+		}
+		e.headerWritten = true
+	}
+
+	row := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		value, err := csvValue(doc[column])
+		if err != nil {
+			return err
+		}
+		row[i] = value
+	}
+	return e.csvWriter.Write(row)
+}
+
+// Flush flushes the buffered rows to the underlying stream
+func (e *ExportWriter) Flush() error {
+	if e.csvWriter == nil {
+		return nil
+	}
+	e.csvWriter.Flush()
+	return e.csvWriter.Error()
+}
+
+func csvValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int, int32, int64, float32, float64:
+		return fmt.Sprintf("%v", v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case []byte:
+		return string(v), nil
+	default:
+		// Nested objects and arrays are stored as json
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestExportWriter_Write(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	docs := []map[string]interface{}{
+		{"id": "1", "age": int64(20), "tags": []interface{}{"a", "b"}, "createdAt": ts},
+		{"id": "2", "age": nil, "extra": true},
+	}
+	tests := []struct {
+		name    string
+		format  string
+		columns []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "ndjson",
+			format: ExportFormatNDJSON,
+			want:   "{\"age\":20,\"createdAt\":\"2020-01-02T03:04:05Z\",\"id\":\"1\",\"tags\":[\"a\",\"b\"]}\n{\"age\":null,\"extra\":true,\"id\":\"2\"}\n",
+		},
+		{
+			name:   "csv with columns derived from the first document",
+			format: ExportFormatCSV,
+			want:   "age,createdAt,id,tags\n20,2020-01-02T03:04:05Z,1,\"[\"\"a\"\",\"\"b\"\"]\"\n,,2,\n",
+		},
+		{
+			name:    "csv with selected columns",
+			format:  ExportFormatCSV,
+			columns: []string{"id", "extra"},
+			want:    "id,extra\n1,\n2,true\n",
+		},
+		{
+			name:    "invalid format",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			e, err := NewExportWriter(buf, tt.format, tt.columns)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewExportWriter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			for _, doc := range docs {
+				if err := e.Write(doc); err != nil {
+					t.Errorf("Write() error = %v", err)
+					return
+				}
+			}
+			if err := e.Flush(); err != nil {
+				t.Errorf("Flush() error = %v", err)
+				return
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Write() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
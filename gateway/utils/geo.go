@@ -0,0 +1,449 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// EarthRadius is the mean radius of the earth in meters. Distances of geo queries are in meters
+const EarthRadius = 6378100.0
+
+// GeoSRID is the spatial reference system (WGS 84) of the geometries stored in sql databases
+const GeoSRID uint32 = 4326
+
+// ewkbSRIDFlag is set in the geometry type of an extended WKB geometry which is followed by its SRID
+const ewkbSRIDFlag uint32 = 0x20000000
+
+// geoTypeCodes maps the GeoJSON geometry types to their WKB type codes
+var geoTypeCodes = map[string]uint32{"Point": 1, "LineString": 2, "Polygon": 3, "MultiPoint": 4, "MultiLineString": 5, "MultiPolygon": 6}
+
+// GeoNear holds the arguments of the near operator. Documents are only filtered if the max distance is provided,
+// else the point is only used to sort the documents by their distance
+type GeoNear struct {
+	Point       map[string]interface{}
+	MaxDistance *float64
+}
+
+// GeoWithin holds the arguments of the within operator. The area is either a polygon or a circle of a radius around a point
+type GeoWithin struct {
+	Polygon map[string]interface{}
+	Center  map[string]interface{}
+	Radius  float64
+}
+
+// ParseGeoNear parses the value of a near clause
+func ParseGeoNear(value interface{}) (*GeoNear, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid value (%v) provided for near clause - expecting an object", value)
+	}
+
+	point, err := ValidateGeoJSON(obj["point"])
+	if err != nil {
+		return nil, err
+	}
+	if point["type"] != "Point" {
+		return nil, fmt.Errorf("invalid geometry (%v) provided for near clause - expecting a point", point["type"])
+	}
+
+	near := &GeoNear{Point: point}
+	if v, p := obj["maxDistance"]; p && v != nil {
+		distance, ok := toGeoFloat(v)
+		if !ok || distance < 0 {
+			return nil, fmt.Errorf("invalid max distance (%v) provided for near clause", v)
+		}
+		near.MaxDistance = &distance
+	}
+	return near, nil
+}
+
+// ParseGeoWithin parses the value of a within clause
+func ParseGeoWithin(value interface{}) (*GeoWithin, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid value (%v) provided for within clause - expecting an object", value)
+	}
+
+	if polygon, p := obj["polygon"]; p {
+		geo, err := ValidateGeoJSON(polygon)
+		if err != nil {
+			return nil, err
+		}
+		if geo["type"] != "Polygon" && geo["type"] != "MultiPolygon" {
+			return nil, fmt.Errorf("invalid geometry (%v) provided for within clause - expecting a polygon", geo["type"])
+		}
+		return &GeoWithin{Polygon: geo}, nil
+	}
+
+	center, err := ValidateGeoJSON(obj["center"])
+	if err != nil {
+		return nil, err
+	}
+	if center["type"] != "Point" {
+		return nil, fmt.Errorf("invalid geometry (%v) provided as the center of within clause - expecting a point", center["type"])
+	}
+	radius, ok := toGeoFloat(obj["radius"])
+	if !ok || radius < 0 {
+		return nil, fmt.Errorf("invalid radius (%v) provided for within clause", obj["radius"])
+	}
+	return &GeoWithin{Center: center, Radius: radius}, nil
+}
+
+// ValidateGeoJSON checks if the value is a GeoJSON geometry which can be stored in a geo field
+func ValidateGeoJSON(value interface{}) (map[string]interface{}, error) {
+	geo, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid geometry (%v) provided - expecting a GeoJSON object", value)
+	}
+	if err := writeWKB(new(bytes.Buffer), geo, false); err != nil {
+		return nil, err
+	}
+	return geo, nil
+}
+
+// GeoJSONToEWKB converts a GeoJSON geometry to the hex encoded extended WKB format accepted by postgis
+func GeoJSONToEWKB(geo map[string]interface{}) (string, error) {
+	buf := new(bytes.Buffer)
+	if err := writeWKB(buf, geo, true); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf.Bytes()), nil
+}
+
+// GeoJSONFromEWKB converts a hex encoded extended WKB geometry returned by postgis to GeoJSON
+func GeoJSONFromEWKB(data string) (map[string]interface{}, error) {
+	b, err := hex.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	return readWKB(bytes.NewReader(b))
+}
+
+// GeoJSONToMySQLGeometry converts a GeoJSON geometry to the internal format of mysql, which is the SRID followed by the WKB
+func GeoJSONToMySQLGeometry(geo map[string]interface{}) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	_ = binary.Write(buf, binary.LittleEndian, GeoSRID)
+	if err := writeWKB(buf, geo, false); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GeoJSONFromMySQLGeometry converts a geometry in the internal format of mysql to GeoJSON
+func GeoJSONFromMySQLGeometry(data []byte) (map[string]interface{}, error) {
+	if len(data) < 4 {
+		return nil, errors.New("invalid mysql geometry")
+	}
+	return readWKB(bytes.NewReader(data[4:]))
+}
+
+// GeoDistance returns the distance in meters between two points along the surface of the earth
+func GeoDistance(a, b map[string]interface{}) (float64, error) {
+	lng1, lat1, err := geoPoint(a)
+	if err != nil {
+		return 0, err
+	}
+	lng2, lat2, err := geoPoint(b)
+	if err != nil {
+		return 0, err
+	}
+
+	toRadians := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat, dLng := toRadians(lat2-lat1), toRadians(lng2-lng1)
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadius * math.Asin(math.Sqrt(h)), nil
+}
+
+// IsGeoPointWithin checks if a point lies within the area of a within clause. Polygons are treated as planar
+func IsGeoPointWithin(point map[string]interface{}, within *GeoWithin) (bool, error) {
+	if within.Polygon == nil {
+		distance, err := GeoDistance(point, within.Center)
+		if err != nil {
+			return false, err
+		}
+		return distance <= within.Radius, nil
+	}
+
+	lng, lat, err := geoPoint(point)
+	if err != nil {
+		return false, err
+	}
+	polygons, _ := toGeoSlice(within.Polygon["coordinates"])
+	if within.Polygon["type"] == "Polygon" {
+		polygons = []interface{}{within.Polygon["coordinates"]}
+	}
+	for _, polygon := range polygons {
+		rings, _ := toGeoSlice(polygon)
+		if len(rings) == 0 || !isPointInRing(lng, lat, rings[0]) {
+			continue
+		}
+
+		// The point must not lie in any of the holes of the polygon
+		inHole := false
+		for _, hole := range rings[1:] {
+			if isPointInRing(lng, lat, hole) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetGeoJSON returns the GeoJSON geometry of a value read from a database. Geometries of postgres are hex encoded extended WKB
+func GetGeoJSON(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		geo, err := ValidateGeoJSON(v)
+		return geo, err == nil
+	case string:
+		geo, err := GeoJSONFromEWKB(v)
+		return geo, err == nil
+	}
+	return nil, false
+}
+
+// isPointInRing checks if a point lies within a linear ring using the ray casting algorithm
+func isPointInRing(lng, lat float64, ring interface{}) bool {
+	positions, _ := toGeoSlice(ring)
+	inside := false
+	for i, j := 0, len(positions)-1; i < len(positions); j, i = i, i+1 {
+		xi, yi, _ := geoPosition(positions[i])
+		xj, yj, _ := geoPosition(positions[j])
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func geoPoint(geo map[string]interface{}) (float64, float64, error) {
+	if geo["type"] != "Point" {
+		return 0, 0, fmt.Errorf("invalid geometry (%v) provided - expecting a point", geo["type"])
+	}
+	return geoPosition(geo["coordinates"])
+}
+
+func geoPosition(value interface{}) (float64, float64, error) {
+	arr, ok := toGeoSlice(value)
+	if !ok || len(arr) != 2 {
+		return 0, 0, fmt.Errorf("invalid position (%v) provided - expecting [longitude, latitude]", value)
+	}
+	lng, ok1 := toGeoFloat(arr[0])
+	lat, ok2 := toGeoFloat(arr[1])
+	if !ok1 || !ok2 || lng < -180 || lng > 180 || lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("invalid position (%v) provided - expecting [longitude, latitude]", value)
+	}
+	return lng, lat, nil
+}
+
+func writeWKB(buf *bytes.Buffer, geo map[string]interface{}, withSRID bool) error {
+	geoType, _ := geo["type"].(string)
+	code, p := geoTypeCodes[geoType]
+	if !p {
+		return fmt.Errorf("invalid geometry type (%v) provided", geo["type"])
+	}
+
+	buf.WriteByte(1) // Little endian
+	if withSRID {
+		_ = binary.Write(buf, binary.LittleEndian, code|ewkbSRIDFlag)
+		_ = binary.Write(buf, binary.LittleEndian, GeoSRID)
+	} else {
+		_ = binary.Write(buf, binary.LittleEndian, code)
+	}
+
+	coordinates := geo["coordinates"]
+	switch geoType {
+	case "Point":
+		return writeWKBPositions(buf, coordinates, false)
+	case "LineString":
+		return writeWKBPositions(buf, coordinates, true)
+	case "Polygon":
+		rings, ok := toGeoSlice(coordinates)
+		if !ok {
+			return fmt.Errorf("invalid coordinates (%v) provided for polygon", coordinates)
+		}
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(rings)))
+		for _, ring := range rings {
+			if err := writeWKBPositions(buf, ring, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		// The members of multi geometries are complete WKB geometries themselves
+		members, ok := toGeoSlice(coordinates)
+		if !ok {
+			return fmt.Errorf("invalid coordinates (%v) provided for %s", coordinates, geoType)
+		}
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(members)))
+		for _, member := range members {
+			if err := writeWKB(buf, map[string]interface{}{"type": geoType[len("Multi"):], "coordinates": member}, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func writeWKBPositions(buf *bytes.Buffer, value interface{}, isList bool) error {
+	positions := []interface{}{value}
+	if isList {
+		arr, ok := toGeoSlice(value)
+		if !ok {
+			return fmt.Errorf("invalid coordinates (%v) provided", value)
+		}
+		positions = arr
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(positions)))
+	}
+
+	for _, position := range positions {
+		lng, lat, err := geoPosition(position)
+		if err != nil {
+			return err
+		}
+		_ = binary.Write(buf, binary.LittleEndian, lng)
+		_ = binary.Write(buf, binary.LittleEndian, lat)
+	}
+	return nil
+}
+
+func readWKB(r *bytes.Reader) (map[string]interface{}, error) {
+	order, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	var byteOrder binary.ByteOrder = binary.LittleEndian
+	if order == 0 {
+		byteOrder = binary.BigEndian
+	}
+
+	var code uint32
+	if err := binary.Read(r, byteOrder, &code); err != nil {
+		return nil, err
+	}
+	if code&ewkbSRIDFlag != 0 {
+		var srid uint32
+		if err := binary.Read(r, byteOrder, &srid); err != nil {
+			return nil, err
+		}
+		code &^= ewkbSRIDFlag
+	}
+
+	readUint32 := func() (int, error) {
+		var n uint32
+		err := binary.Read(r, byteOrder, &n)
+		// Guard against corrupted lengths since every element takes at least one byte
+		if err == nil && int(n) > r.Len() {
+			err = io.ErrUnexpectedEOF
+		}
+		return int(n), err
+	}
+	readPosition := func() (interface{}, error) {
+		var position [2]float64
+		if err := binary.Read(r, byteOrder, &position); err != nil {
+			return nil, err
+		}
+		return []interface{}{position[0], position[1]}, nil
+	}
+	readPositions := func() (interface{}, error) {
+		n, err := readUint32()
+		if err != nil {
+			return nil, err
+		}
+		positions := make([]interface{}, n)
+		for i := range positions {
+			if positions[i], err = readPosition(); err != nil {
+				return nil, err
+			}
+		}
+		return positions, nil
+	}
+
+	var geoType string
+	var coordinates interface{}
+	switch code {
+	case 1:
+		geoType = "Point"
+		coordinates, err = readPosition()
+	case 2:
+		geoType = "LineString"
+		coordinates, err = readPositions()
+	case 3:
+		geoType = "Polygon"
+		var n int
+		if n, err = readUint32(); err != nil {
+			return nil, err
+		}
+		rings := make([]interface{}, n)
+		for i := range rings {
+			if rings[i], err = readPositions(); err != nil {
+				return nil, err
+			}
+		}
+		coordinates = rings
+	case 4, 5, 6:
+		geoType = map[uint32]string{4: "MultiPoint", 5: "MultiLineString", 6: "MultiPolygon"}[code]
+		var n int
+		if n, err = readUint32(); err != nil {
+			return nil, err
+		}
+		members := make([]interface{}, n)
+		for i := range members {
+			member, err := readWKB(r)
+			if err != nil {
+				return nil, err
+			}
+			members[i] = member["coordinates"]
+		}
+		coordinates = members
+	default:
+		return nil, fmt.Errorf("unsupported wkb geometry type (%d)", code)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"type": geoType, "coordinates": coordinates}, nil
+}
+
+func toGeoSlice(value interface{}) ([]interface{}, bool) {
+	if arr, ok := value.([]interface{}); ok {
+		return arr, true
+	}
+
+	// Arrays decoded by the database drivers can be of other slice types
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		return nil, false
+	}
+	arr := make([]interface{}, v.Len())
+	for i := range arr {
+		arr[i] = v.Index(i).Interface()
+	}
+	return arr, true
+}
+
+func toGeoFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGeoJSONWKB(t *testing.T) {
+	tests := []struct {
+		name    string
+		geo     map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "point",
+			geo:  map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.87, 19.07}},
+		},
+		{
+			name: "polygon with a hole",
+			geo: map[string]interface{}{"type": "Polygon", "coordinates": []interface{}{
+				[]interface{}{[]interface{}{0.0, 0.0}, []interface{}{0.0, 10.0}, []interface{}{10.0, 10.0}, []interface{}{0.0, 0.0}},
+				[]interface{}{[]interface{}{1.0, 1.0}, []interface{}{1.0, 2.0}, []interface{}{2.0, 2.0}, []interface{}{1.0, 1.0}},
+			}},
+		},
+		{
+			name: "multi line string",
+			geo: map[string]interface{}{"type": "MultiLineString", "coordinates": []interface{}{
+				[]interface{}{[]interface{}{0.0, 0.0}, []interface{}{1.0, 1.0}},
+				[]interface{}{[]interface{}{2.0, 2.0}, []interface{}{3.0, 3.0}},
+			}},
+		},
+		{
+			name:    "unknown geometry type",
+			geo:     map[string]interface{}{"type": "Circle", "coordinates": []interface{}{0.0, 0.0}},
+			wantErr: true,
+		},
+		{
+			name:    "latitude out of range",
+			geo:     map[string]interface{}{"type": "Point", "coordinates": []interface{}{10.0, 100.0}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ewkb, err := GeoJSONToEWKB(tt.geo)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GeoJSONToEWKB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := GeoJSONFromEWKB(ewkb)
+			if err != nil || !reflect.DeepEqual(got, tt.geo) {
+				t.Errorf("GeoJSONFromEWKB() = %v, %v, want %v", got, err, tt.geo)
+			}
+
+			data, err := GeoJSONToMySQLGeometry(tt.geo)
+			if err != nil {
+				t.Errorf("GeoJSONToMySQLGeometry() error = %v", err)
+				return
+			}
+			got, err = GeoJSONFromMySQLGeometry(data)
+			if err != nil || !reflect.DeepEqual(got, tt.geo) {
+				t.Errorf("GeoJSONFromMySQLGeometry() = %v, %v, want %v", got, err, tt.geo)
+			}
+		})
+	}
+}
+
+func TestGeoDistance(t *testing.T) {
+	// Distance between two points a degree of longitude apart along the equator
+	a := map[string]interface{}{"type": "Point", "coordinates": []interface{}{0, 0}}
+	b := map[string]interface{}{"type": "Point", "coordinates": []interface{}{1, 0}}
+	got, err := GeoDistance(a, b)
+	if err != nil {
+		t.Fatalf("GeoDistance() error = %v", err)
+	}
+	if want := 2 * math.Pi * EarthRadius / 360; math.Abs(got-want) > 1e-6 {
+		t.Errorf("GeoDistance() = %v, want %v", got, want)
+	}
+}
+
+func TestParseGeoWithin(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr bool
+	}{
+		{
+			name:  "circle",
+			value: map[string]interface{}{"center": map[string]interface{}{"type": "Point", "coordinates": []interface{}{0, 0}}, "radius": 5000},
+		},
+		{
+			name:    "negative radius",
+			value:   map[string]interface{}{"center": map[string]interface{}{"type": "Point", "coordinates": []interface{}{0, 0}}, "radius": -1},
+			wantErr: true,
+		},
+		{
+			name:    "line instead of polygon",
+			value:   map[string]interface{}{"polygon": map[string]interface{}{"type": "LineString", "coordinates": []interface{}{[]interface{}{0, 0}, []interface{}{1, 1}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseGeoWithin(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("ParseGeoWithin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,15 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"strings"
 	"time"
 
 	"github.com/graphql-go/graphql/language/ast"
 	"github.com/segmentio/ksuid"
-	"github.com/spaceuptech/helpers"
 
 	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
@@ -42,6 +41,7 @@ func (graph *Module) prepareDocs(doc map[string]interface{}, schemaFields model.
 	// FieldDates is the array of fields for which the current time needs to be set.
 	fieldIDs := make([]string, 0)
 	fieldDates := make([]string, 0)
+	fieldVersions := make([]string, 0)
 	fieldDefaults := make(map[string]interface{})
 
 	for fieldName, fieldSchema := range schemaFields {
@@ -54,6 +54,10 @@ func (graph *Module) prepareDocs(doc map[string]interface{}, schemaFields model.
 			fieldDates = append(fieldDates, fieldName)
 		}
 
+		if fieldSchema.IsVersion {
+			fieldVersions = append(fieldVersions, fieldName)
+		}
+
 		if fieldSchema.IsDefault {
 			defaultStringValue, isString := fieldSchema.Default.(string)
 			if fieldSchema.Kind == model.TypeJSON && isString {
@@ -84,139 +88,30 @@ func (graph *Module) prepareDocs(doc map[string]interface{}, schemaFields model.
 	for _, field := range fieldDates {
 		doc[field] = time.Now().UTC()
 	}
-}
 
-func copyDoc(doc map[string]interface{}) map[string]interface{} {
-	newDoc := make(map[string]interface{}, len(doc))
-	for k, v := range doc {
-		newDoc[k] = v
+	// Every document starts with the first version
+	for _, field := range fieldVersions {
+		doc[field] = 1
 	}
-
-	return newDoc
 }
 
 func (graph *Module) processNestedFields(ctx context.Context, docs []interface{}, dbAlias, col, token string) (model.RequestParams, []*model.AllRequest, []interface{}, error) {
-	createRequests := make([]*model.AllRequest, 0)
-	afterRequests := make([]*model.AllRequest, 0)
-	var err error
 	var reqParams model.RequestParams
-	// Check if we can the schema for this collection
-	schemaFields, p := graph.schema.GetSchema(dbAlias, col)
-	if !p {
-		// Return the docs as is if no schema is available
-		return model.RequestParams{}, []*model.AllRequest{{Type: string(model.Create), Col: col, Operation: utils.All, Document: docs, DBAlias: dbAlias}}, docs, nil
+	nested := &schemaHelpers.NestedCreate{
+		GetSchema:  graph.schema.GetSchema,
+		PrepareDoc: graph.prepareDocs,
+		AuthoriseDoc: func(dbAlias, col string, doc map[string]interface{}) error {
+			var err error
+			reqParams, err = graph.auth.IsCreateOpAuthorised(ctx, graph.project, dbAlias, col, token, &model.CreateRequest{Document: []interface{}{doc}, Operation: utils.All})
+			return err
+		},
 	}
 
-	returningDocs := make([]interface{}, len(docs))
-
-	for i, docTemp := range docs {
-
-		// Each document is actually an object
-		doc := docTemp.(map[string]interface{})
-		graph.prepareDocs(doc, schemaFields)
-
-		newDoc := copyDoc(doc)
-
-		// Iterate over each field of the document to see if has any linked fields that are present
-		for fieldName, fieldValue := range doc {
-			fieldSchema, p := schemaFields[fieldName]
-			if !p || !fieldSchema.IsLinked {
-				// Simply ignore if the field does not have a corresponding schemaFields or it isn't linked
-				continue
-			}
-
-			// We are here means that the field is actually a linked value
-
-			fromFieldSchema, p := schemaFields[fieldSchema.LinkedTable.From]
-			if !p {
-				// Ignore if the `from` key is not present in the schema
-				continue
-			}
-
-			// Ignore if the field key is present in the linked table config. We don't support such operations.
-			if fieldSchema.LinkedTable.Field != "" {
-				continue
-			}
-
-			if fromFieldSchema.IsPrimary {
-				// Ignore if the from field doesn't exist in the document
-				if _, p := doc[fromFieldSchema.FieldName]; !p {
-					continue
-				}
-			}
-
-			// Lets populate an array of linked docs
-			var linkedDocs []interface{}
-			if !fieldSchema.IsList {
-				temp, ok := fieldValue.(map[string]interface{})
-				if !ok {
-					return model.RequestParams{}, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid format provided for linked field %s - wanted object got array", fieldName), nil, nil)
-				}
-
-				linkedDocs = []interface{}{temp}
-			} else {
-				temp, ok := fieldValue.([]interface{})
-				if !ok {
-					return model.RequestParams{}, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid format provided for linked field %s - wanted array got object", fieldName), nil, nil)
-				}
-				linkedDocs = temp
-			}
-
-			// Iterate over each linked doc
-			for _, linkedDocTemp := range linkedDocs {
-				// Each document is actually an object
-				linkedDoc := linkedDocTemp.(map[string]interface{})
-
-				linkedSchemaFields, p := graph.schema.GetSchema(fieldSchema.LinkedTable.DBType, fieldSchema.LinkedTable.Table)
-				if !p {
-					return model.RequestParams{}, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("schema not provided for table (%s). Check the link directive for field (%s) in table (%s)", fieldSchema.LinkedTable.Table, fieldSchema.FieldName, col), nil, nil)
-				}
-
-				graph.prepareDocs(linkedDoc, linkedSchemaFields)
-
-				// Check if the `from` field is a primary key. If it is, we need to set that value in the `to` field
-				// of the nested value. If it is not a primary key, we'll have to set it with the value of the `to`
-				// field of the nested value
-				if fromFieldSchema.IsPrimary {
-					linkedDoc[fieldSchema.LinkedTable.To] = doc[fieldSchema.LinkedTable.From]
-				} else {
-					// The nested docs need to be inserted first in this case
-					doc[fieldSchema.LinkedTable.From] = linkedDoc[fieldSchema.LinkedTable.To]
-				}
-			}
-
-			_, linkedCreateRequests, returningLinkedDocs, err := graph.processNestedFields(ctx, linkedDocs, fieldSchema.LinkedTable.DBType, fieldSchema.LinkedTable.Table, token)
-			if err != nil {
-				return model.RequestParams{}, nil, nil, err
-			}
-
-			if fromFieldSchema.IsPrimary {
-				// It the from field is primary, it means that the nested docs need to be inserted after the parent docs have been inserted
-				afterRequests = append(afterRequests, linkedCreateRequests...)
-			} else {
-				// If the from field is not primary, it means that the nested docs need to be inserted before the parent docs
-				createRequests = append(createRequests, linkedCreateRequests...)
-			}
-
-			// Delete the nested field. The schema module would throw an error otherwise
-			delete(doc, fieldName)
-
-			newDoc[fieldName] = returningLinkedDocs
-		}
-		r := &model.CreateRequest{Document: []interface{}{doc}, Operation: utils.All}
-		reqParams, err = graph.auth.IsCreateOpAuthorised(ctx, graph.project, dbAlias, col, token, r)
-		if err != nil {
-			return model.RequestParams{}, nil, nil, err
-		}
-		// Store the mutated doc because of security rules in returning docs
-		for key, value := range doc {
-			newDoc[key] = value
-		}
-
-		returningDocs[i] = newDoc
+	reqs, returningDocs, err := nested.SplitNestedDocs(ctx, dbAlias, col, docs)
+	if err != nil {
+		return model.RequestParams{}, nil, nil, err
 	}
-	createRequests = append(createRequests, &model.AllRequest{Type: string(model.Create), Col: col, Operation: utils.All, Document: docs, DBAlias: dbAlias})
-	return reqParams, append(createRequests, afterRequests...), returningDocs, nil
+	return reqParams, reqs, returningDocs, nil
 }
 
 func extractDocs(ctx context.Context, args []*ast.Argument, store utils.M) ([]interface{}, error) {
@@ -7,11 +7,15 @@ import (
 	"fmt"
 	"sync"
 
+	gql "github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
 	"github.com/graphql-go/graphql/language/kinds"
 	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/graphql/language/source"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/spaceuptech/helpers"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
@@ -26,16 +30,42 @@ type Module struct {
 
 	// 	Auth module
 	aesKey []byte
+
+	// Limits on the depth, size and cost of the queries
+	limits *config.GraphQLLimits
+
+	// Introspection queries are only allowed for admins unless they are public
+	publicIntrospection bool
+
+	// The schema used to resolve introspection queries. It is built on the first introspection query after a config change
+	schemaLock   sync.Mutex
+	schemaObject *gql.Schema
+
+	// Persisted queries
+	persistedLock           sync.RWMutex
+	persistedQueries        map[string]string // Key here is the sha256 hash of the query
+	normalizedQueries       map[string]bool   // Key here is the sha256 hash of the normalized query
+	enforcePersistedQueries bool
+	apqCache                *lru.Cache
 }
 
 // New creates a new GraphQL module
 func New(a AuthInterface, c CrudInterface, f FunctionInterface, s SchemaInterface) *Module {
-	return &Module{auth: a, crud: c, functions: f, schema: s}
+	apqCache, _ := lru.New(apqCacheSize)
+	return &Module{auth: a, crud: c, functions: f, schema: s, apqCache: apqCache}
 }
 
 // SetConfig sets the project configuration
 func (graph *Module) SetConfig(project string) {
 	graph.project = project
+	graph.ResetSchema()
+}
+
+// ResetSchema drops the cached graphql schema of the project so that it gets built again from the latest config
+func (graph *Module) ResetSchema() {
+	graph.schemaLock.Lock()
+	defer graph.schemaLock.Unlock()
+	graph.schemaObject = nil
 }
 
 // SetProjectAESKey sets aes key
@@ -48,6 +78,16 @@ func (graph *Module) SetProjectAESKey(aesKey string) error {
 	return nil
 }
 
+// SetQueryLimits sets the limits on the depth, size and cost of the queries
+func (graph *Module) SetQueryLimits(limits *config.GraphQLLimits) {
+	graph.limits = limits
+}
+
+// SetPublicIntrospection sets whether the introspection queries of the users other than admins are allowed
+func (graph *Module) SetPublicIntrospection(public bool) {
+	graph.publicIntrospection = public
+}
+
 // GetProjectID sets the project configuration
 func (graph *Module) GetProjectID() string {
 	return graph.project
@@ -56,6 +96,13 @@ func (graph *Module) GetProjectID() string {
 // ExecGraphQLQuery executes the provided graphql query
 func (graph *Module) ExecGraphQLQuery(ctx context.Context, req *model.GraphQLRequest, token string, cb model.GraphQLCallback) {
 
+	// Load the query if only the hash of a persisted query is provided
+	hash, err := graph.loadPersistedQuery(ctx, req)
+	if err != nil {
+		cb(nil, err)
+		return
+	}
+
 	s := source.NewSource(&source.Source{
 		Body: []byte(req.Query),
 		Name: req.OperationName,
@@ -67,7 +114,31 @@ func (graph *Module) ExecGraphQLQuery(ctx context.Context, req *model.GraphQLReq
 		return
 	}
 
-	graph.execGraphQLDocument(ctx, doc, token, utils.M{"vars": req.Variables, "path": "", "_query": utils.NewArray(0), "directive": ""}, nil, createCallback(cb))
+	if err := graph.checkPersistedQuery(ctx, req, hash, doc, token); err != nil {
+		cb(nil, err)
+		return
+	}
+
+	// Reject the queries exceeding the limits before executing them. This applies to introspection queries as well
+	if err := graph.CheckQueryLimits(ctx, doc, req.Variables); err != nil {
+		cb(nil, err)
+		return
+	}
+
+	// Introspection queries are resolved against the schema generated from the config of the project
+	if isIntrospectionQuery(doc, req.OperationName) {
+		if !graph.publicIntrospection {
+			if err := graph.auth.IsTokenAdmin(ctx, token); err != nil {
+				cb(nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Introspection queries are only allowed for admins", nil, nil))
+				return
+			}
+		}
+		graph.execIntrospectionQuery(ctx, req, createCallback(cb))
+		return
+	}
+
+	store := utils.M{"vars": req.Variables, "path": "", "_query": utils.NewArray(0), "directive": ""}
+	graph.execGraphQLDocument(ctx, doc, token, store, nil, createCallback(cb))
 }
 
 type dbCallback func(dbAlias, col string, op interface{}, err error)
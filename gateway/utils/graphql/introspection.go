@@ -0,0 +1,625 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Custom scalars used by the generated schema
+var (
+	dateTimeScalar = newScalar("DateTime", "Date and time in the RFC3339 format")
+	dateScalar     = newScalar("Date", "Date in the YYYY-MM-DD format")
+	timeScalar     = newScalar("Time", "Time in the HH:MM:SS format")
+	jsonScalar     = newScalar("JSON", "Any json value")
+	geoJSONScalar  = newScalar("GeoJSON", "A GeoJSON geometry with coordinates in the [longitude, latitude] order")
+
+	geoNearInput = gql.NewInputObject(gql.InputObjectConfig{
+		Name:        "geo_near_input",
+		Description: "Matches the geometries within the max distance in meters of a point. The rows can be sorted by their distance using the _distance sort field",
+		Fields: gql.InputObjectConfigFieldMap{
+			"point":       &gql.InputObjectFieldConfig{Type: gql.NewNonNull(geoJSONScalar)},
+			"maxDistance": &gql.InputObjectFieldConfig{Type: gql.Float},
+		},
+	})
+	geoWithinInput = gql.NewInputObject(gql.InputObjectConfig{
+		Name:        "geo_within_input",
+		Description: "Matches the geometries within a polygon or within the radius in meters of a center point",
+		Fields: gql.InputObjectConfigFieldMap{
+			"polygon": &gql.InputObjectFieldConfig{Type: geoJSONScalar},
+			"center":  &gql.InputObjectFieldConfig{Type: geoJSONScalar},
+			"radius":  &gql.InputObjectFieldConfig{Type: gql.Float},
+		},
+	})
+
+	sortOrderEnum = gql.NewEnum(gql.EnumConfig{
+		Name:   "sort_order",
+		Values: gql.EnumValueConfigMap{"asc": &gql.EnumValueConfig{Value: "asc"}, "desc": &gql.EnumValueConfig{Value: "desc"}},
+	})
+)
+
+// Suffixes of the names of the types generated for every table
+var tableTypeSuffixes = []string{"", "_where", "_sort", "_insert_input", "_set_input", "_mutation_response"}
+
+func newScalar(name, description string) *gql.Scalar {
+	identity := func(value interface{}) interface{} { return value }
+	return gql.NewScalar(gql.ScalarConfig{
+		Name:         name,
+		Description:  description,
+		Serialize:    identity,
+		ParseValue:   identity,
+		ParseLiteral: func(valueAST ast.Value) interface{} { return valueAST.GetValue() },
+	})
+}
+
+// isIntrospectionQuery checks if the operation to be executed only selects introspection fields like __schema and __type
+func isIntrospectionQuery(doc *ast.Document, operationName string) bool {
+	for _, definition := range doc.Definitions {
+		op, ok := definition.(*ast.OperationDefinition)
+		if !ok || (operationName != "" && (op.Name == nil || op.Name.Value != operationName)) {
+			continue
+		}
+
+		if op.Operation != ast.OperationTypeQuery || op.SelectionSet == nil || len(op.SelectionSet.Selections) == 0 {
+			return false
+		}
+		for _, selection := range op.SelectionSet.Selections {
+			field, ok := selection.(*ast.Field)
+			if !ok || !strings.HasPrefix(field.Name.Value, "__") {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// execIntrospectionQuery resolves the introspection query against the schema generated for the project
+func (graph *Module) execIntrospectionQuery(ctx context.Context, req *model.GraphQLRequest, cb model.GraphQLCallback) {
+	schema, err := graph.getSchema(ctx)
+	if err != nil {
+		cb(nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to generate graphql schema of the project", err, nil))
+		return
+	}
+
+	result := gql.Do(gql.Params{Schema: schema, RequestString: req.Query, VariableValues: req.Variables, OperationName: req.OperationName, Context: ctx})
+	if result.HasErrors() {
+		cb(nil, errors.New(result.Errors[0].Message))
+		return
+	}
+	cb(result.Data, nil)
+}
+
+// GenerateSDL returns the graphql schema of the project in the schema definition language
+func (graph *Module) GenerateSDL(ctx context.Context) (string, error) {
+	schema, err := graph.getSchema(ctx)
+	if err != nil {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to generate graphql schema of the project", err, nil)
+	}
+	return printSchema(schema), nil
+}
+
+// getSchema returns the cached graphql schema of the project. The schema is built if it isn't cached
+func (graph *Module) getSchema(ctx context.Context) (gql.Schema, error) {
+	graph.schemaLock.Lock()
+	defer graph.schemaLock.Unlock()
+
+	if graph.schemaObject != nil {
+		return *graph.schemaObject, nil
+	}
+
+	schema, err := graph.buildSchema(ctx)
+	if err != nil {
+		return gql.Schema{}, err
+	}
+	graph.schemaObject = &schema
+	return schema, nil
+}
+
+// buildSchema generates the graphql schema of the database tables, prepared queries and remote services of the project.
+// Tables on which all operations are denied are left out since they can't be queried anyway.
+func (graph *Module) buildSchema(ctx context.Context) (gql.Schema, error) {
+	schemas := model.Type{}
+	for dbAlias, tables := range graph.schema.GetSchemas() {
+		schemas[dbAlias] = model.Collection{}
+		for table, fields := range tables {
+			if !graph.auth.IsTableDenied(ctx, graph.project, dbAlias, table) {
+				schemas[dbAlias][table] = fields
+			}
+		}
+	}
+
+	b := &schemaBuilder{
+		schemas:      schemas,
+		typeNames:    map[string]bool{"Query": true, "Mutation": true, sortOrderEnum.Name(): true, geoNearInput.Name(): true, geoWithinInput.Name(): true},
+		tables:       map[string]*tableTypes{},
+		comparisons:  map[string]*gql.InputObject{},
+		queryFields:  gql.Fields{},
+		mutateFields: gql.Fields{},
+	}
+	for _, scalar := range []*gql.Scalar{gql.String, gql.Int, gql.Float, gql.Boolean, gql.ID, dateTimeScalar, dateScalar, timeScalar, jsonScalar, geoJSONScalar} {
+		b.typeNames[scalar.Name()] = true
+		b.typeNames[scalar.Name()+"_comparison"] = true
+	}
+	return b.build(graph.crud.GetPreparedQueries(), graph.functions.GetServices())
+}
+
+// tableTypes holds the graphql types generated for a table
+type tableTypes struct {
+	dbAlias, table, name string
+	fields               model.Fields
+
+	object      *gql.Object
+	where       *gql.InputObject
+	sort        *gql.InputObject
+	insertInput *gql.InputObject
+	setInput    *gql.InputObject
+	response    *gql.Object
+}
+
+type schemaBuilder struct {
+	schemas   model.Type
+	typeNames map[string]bool
+
+	tables      map[string]*tableTypes // key is dbAlias:table
+	comparisons map[string]*gql.InputObject
+
+	queryFields  gql.Fields
+	mutateFields gql.Fields
+	directives   map[string]gql.FieldConfigArgument
+}
+
+func (b *schemaBuilder) build(queries config.DatabasePreparedQueries, services config.Services) (gql.Schema, error) {
+	b.directives = map[string]gql.FieldConfigArgument{
+		"aggregate": {"op": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)}, "field": &gql.ArgumentConfig{Type: gql.String}},
+		"template":  {"value": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)}},
+	}
+
+	// Tables of databases are registered in a sorted order so that the first database gets the name of the table when
+	// two databases have a table with the same name
+	dbAliases := make([]string, 0, len(b.schemas))
+	for dbAlias := range b.schemas {
+		dbAliases = append(dbAliases, dbAlias)
+	}
+	sort.Strings(dbAliases)
+
+	for _, dbAlias := range dbAliases {
+		b.addDirective(dbAlias, gql.FieldConfigArgument{"col": &gql.ArgumentConfig{Type: gql.String}, "cache": &gql.ArgumentConfig{Type: jsonScalar}})
+
+		tables := make([]string, 0, len(b.schemas[dbAlias]))
+		for table := range b.schemas[dbAlias] {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		for _, table := range tables {
+			b.addTable(dbAlias, table, b.schemas[dbAlias][table])
+		}
+	}
+
+	for _, dbAlias := range dbAliases {
+		for _, t := range b.tables {
+			if t.dbAlias == dbAlias {
+				b.addTableFields(t)
+			}
+		}
+	}
+
+	b.addPreparedQueries(queries)
+	b.addRemoteServices(services)
+
+	// The query type cannot be empty. Hence the field used to fetch the metadata of the queries is always added
+	b.queryFields["_query"] = &gql.Field{Type: gql.NewList(jsonScalar), Description: "Metadata of the database queries made with the debug argument"}
+
+	directives := append([]*gql.Directive{}, gql.SpecifiedDirectives...)
+	for name, args := range b.directives {
+		directives = append(directives, gql.NewDirective(gql.DirectiveConfig{Name: name, Locations: []string{gql.DirectiveLocationField}, Args: args}))
+	}
+
+	// Tables whose names are taken by the tables of another database aren't reachable from the root types. Hence all
+	// the tables are added explicitly.
+	keys := make([]string, 0, len(b.tables))
+	for key := range b.tables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	types := make([]gql.Type, 0, len(keys))
+	for _, key := range keys {
+		types = append(types, b.tables[key].object)
+	}
+
+	schemaConfig := gql.SchemaConfig{
+		Query:      gql.NewObject(gql.ObjectConfig{Name: "Query", Fields: b.queryFields}),
+		Types:      types,
+		Directives: directives,
+	}
+	if len(b.mutateFields) > 0 {
+		schemaConfig.Mutation = gql.NewObject(gql.ObjectConfig{Name: "Mutation", Fields: b.mutateFields})
+	}
+	return gql.NewSchema(schemaConfig)
+}
+
+// addDirective declares the directive used to query a database or remote service
+func (b *schemaBuilder) addDirective(name string, args gql.FieldConfigArgument) {
+	if !gql.NameRegExp.MatchString(name) {
+		return
+	}
+	for _, directive := range gql.SpecifiedDirectives {
+		if directive.Name == name {
+			return
+		}
+	}
+	if existing, p := b.directives[name]; p {
+		for argName, arg := range args {
+			existing[argName] = arg
+		}
+		return
+	}
+	b.directives[name] = args
+}
+
+// addTable reserves the names of the types of a table. The name of the database is added as a prefix to the names of
+// the types if the name of the table is already taken.
+func (b *schemaBuilder) addTable(dbAlias, table string, fields model.Fields) {
+	hasValidField := false
+	for fieldName := range fields {
+		if gql.NameRegExp.MatchString(fieldName) {
+			hasValidField = true
+			break
+		}
+	}
+	if !hasValidField {
+		return
+	}
+
+	name := ""
+	for _, candidate := range []string{table, dbAlias + "_" + table} {
+		if b.isNameAvailable(candidate) {
+			name = candidate
+			break
+		}
+	}
+	if name == "" {
+		return
+	}
+	for _, suffix := range tableTypeSuffixes {
+		b.typeNames[name+suffix] = true
+	}
+
+	t := &tableTypes{dbAlias: dbAlias, table: table, name: name, fields: fields}
+	t.object = gql.NewObject(gql.ObjectConfig{Name: name, Fields: gql.FieldsThunk(func() gql.Fields { return b.objectFields(t) })})
+	t.where = gql.NewInputObject(gql.InputObjectConfig{Name: name + "_where", Fields: gql.InputObjectConfigFieldMapThunk(func() gql.InputObjectConfigFieldMap { return b.whereFields(t) })})
+	t.insertInput = gql.NewInputObject(gql.InputObjectConfig{Name: name + "_insert_input", Fields: gql.InputObjectConfigFieldMapThunk(func() gql.InputObjectConfigFieldMap { return b.inputFields(t, true) })})
+	t.setInput = gql.NewInputObject(gql.InputObjectConfig{Name: name + "_set_input", Fields: gql.InputObjectConfigFieldMapThunk(func() gql.InputObjectConfigFieldMap { return b.inputFields(t, false) })})
+	t.response = gql.NewObject(gql.ObjectConfig{Name: name + "_mutation_response", Fields: gql.Fields{
+		"status":    &gql.Field{Type: gql.Int},
+		"error":     &gql.Field{Type: gql.String},
+		"returning": &gql.Field{Type: gql.NewList(t.object)},
+	}})
+
+	sortFields := gql.InputObjectConfigFieldMap{}
+	for fieldName, field := range fields {
+		if gql.NameRegExp.MatchString(fieldName) && !field.IsLinked && field.Kind != model.TypeObject {
+			sortFields[fieldName] = &gql.InputObjectFieldConfig{Type: sortOrderEnum}
+		}
+		// Rows of tables with geo fields can be sorted by their distance from the point of a _near clause
+		if field.Kind == model.TypePoint || field.Kind == model.TypeGeometry {
+			sortFields[utils.DistanceField] = &gql.InputObjectFieldConfig{Type: sortOrderEnum}
+		}
+	}
+	if len(sortFields) > 0 {
+		t.sort = gql.NewInputObject(gql.InputObjectConfig{Name: name + "_sort", Fields: sortFields})
+	}
+
+	b.tables[dbAlias+":"+table] = t
+}
+
+func (b *schemaBuilder) isNameAvailable(name string) bool {
+	if !gql.NameRegExp.MatchString(name) || strings.HasPrefix(name, "__") {
+		return false
+	}
+	for _, suffix := range tableTypeSuffixes {
+		if b.typeNames[name+suffix] {
+			return false
+		}
+	}
+	return true
+}
+
+// uniqueName returns a name for a type which isn't taken yet
+func (b *schemaBuilder) uniqueName(name string) string {
+	for b.typeNames[name] {
+		name += "_"
+	}
+	b.typeNames[name] = true
+	return name
+}
+
+// addTableFields adds the fields used to query and mutate a table. Only the first database with the table gets the fields.
+func (b *schemaBuilder) addTableFields(t *tableTypes) {
+	if !gql.NameRegExp.MatchString(t.table) {
+		return
+	}
+
+	if _, p := b.queryFields[t.table]; !p {
+		args := gql.FieldConfigArgument{
+			"where":          &gql.ArgumentConfig{Type: t.where},
+			"skip":           &gql.ArgumentConfig{Type: gql.Int},
+			"limit":          &gql.ArgumentConfig{Type: gql.Int},
+			"op":             &gql.ArgumentConfig{Type: gql.String, Description: "Either (all) or (one)"},
+			"distinct":       &gql.ArgumentConfig{Type: gql.String},
+			"group":          &gql.ArgumentConfig{Type: gql.NewList(gql.NewNonNull(gql.String))},
+			"after":          &gql.ArgumentConfig{Type: gql.String},
+			"before":         &gql.ArgumentConfig{Type: gql.String},
+			"join":           &gql.ArgumentConfig{Type: jsonScalar},
+			"returnType":     &gql.ArgumentConfig{Type: gql.String},
+			"includeDeleted": &gql.ArgumentConfig{Type: gql.Boolean},
+			"readYourWrites": &gql.ArgumentConfig{Type: gql.Boolean},
+			"debug":          &gql.ArgumentConfig{Type: gql.Boolean},
+		}
+		if t.sort != nil {
+			args["sort"] = &gql.ArgumentConfig{Type: gql.NewList(gql.NewNonNull(t.sort))}
+		}
+		b.queryFields[t.table] = &gql.Field{Type: gql.NewList(t.object), Args: args}
+	}
+
+	if _, p := b.mutateFields["insert_"+t.table]; p {
+		return
+	}
+	b.mutateFields["insert_"+t.table] = &gql.Field{Type: t.response, Args: gql.FieldConfigArgument{
+		"docs": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(t.insertInput)))},
+	}}
+
+	updateArgs := gql.FieldConfigArgument{
+		"where": &gql.ArgumentConfig{Type: t.where},
+		"op":    &gql.ArgumentConfig{Type: gql.String, Description: "Either (all) or (upsert)"},
+		"set":   &gql.ArgumentConfig{Type: t.setInput},
+	}
+	for _, op := range []string{"inc", "mul", "max", "min", "currentTimestamp", "currentDate", "push", "rename", "unset"} {
+		updateArgs[op] = &gql.ArgumentConfig{Type: jsonScalar}
+	}
+	b.mutateFields["update_"+t.table] = &gql.Field{Type: t.response, Args: updateArgs}
+	b.mutateFields["delete_"+t.table] = &gql.Field{Type: t.response, Args: gql.FieldConfigArgument{"where": &gql.ArgumentConfig{Type: t.where}}}
+
+	for _, field := range t.fields {
+		if field.IsSoftDelete {
+			b.mutateFields["restore_"+t.table] = &gql.Field{Type: t.response, Args: gql.FieldConfigArgument{"where": &gql.ArgumentConfig{Type: t.where}}}
+			break
+		}
+	}
+}
+
+func (b *schemaBuilder) objectFields(t *tableTypes) gql.Fields {
+	fields := gql.Fields{}
+	for fieldName, field := range t.fields {
+		if !gql.NameRegExp.MatchString(fieldName) {
+			continue
+		}
+		fields[fieldName] = &gql.Field{Type: b.outputType(t.dbAlias, t.name+"_"+fieldName, field)}
+	}
+	return fields
+}
+
+func (b *schemaBuilder) outputType(dbAlias, nestedName string, field *model.FieldType) gql.Output {
+	var fieldType gql.Output = jsonScalar
+	switch {
+	case field.IsLinked:
+		if linked := b.linkedTable(dbAlias, field); linked != nil {
+			fieldType = linked.object
+		} else if scalar, ok := scalarType(field.Kind); ok {
+			fieldType = scalar
+		}
+
+	case field.Kind == model.TypeObject:
+		fields := gql.Fields{}
+		for nestedFieldName, nestedField := range field.NestedObject {
+			if gql.NameRegExp.MatchString(nestedFieldName) {
+				fields[nestedFieldName] = &gql.Field{Type: b.outputType(dbAlias, nestedName+"_"+nestedFieldName, nestedField)}
+			}
+		}
+		if len(fields) > 0 {
+			fieldType = gql.NewObject(gql.ObjectConfig{Name: b.uniqueName(nestedName), Fields: fields})
+		}
+
+	default:
+		fieldType, _ = scalarType(field.Kind)
+	}
+
+	if field.IsList {
+		fieldType = gql.NewList(fieldType)
+	}
+	if field.IsFieldTypeRequired && !field.IsLinked {
+		fieldType = gql.NewNonNull(fieldType)
+	}
+	return fieldType
+}
+
+// linkedTable returns the types of the table a field is linked to
+func (b *schemaBuilder) linkedTable(dbAlias string, field *model.FieldType) *tableTypes {
+	if field.LinkedTable == nil {
+		return nil
+	}
+	if field.LinkedTable.DBType != "" {
+		dbAlias = field.LinkedTable.DBType
+	}
+
+	// The kind of the field is the type of the linked table unless a field of the linked table is being fetched
+	if field.LinkedTable.Field != "" {
+		return nil
+	}
+	if t, p := b.tables[dbAlias+":"+field.Kind]; p {
+		return t
+	}
+	return b.tables[dbAlias+":"+field.LinkedTable.Table]
+}
+
+func (b *schemaBuilder) whereFields(t *tableTypes) gql.InputObjectConfigFieldMap {
+	fields := gql.InputObjectConfigFieldMap{
+		"_or": &gql.InputObjectFieldConfig{Type: gql.NewList(gql.NewNonNull(t.where))},
+	}
+	for fieldName, field := range t.fields {
+		if !gql.NameRegExp.MatchString(fieldName) || field.IsLinked || field.Kind == model.TypeObject {
+			continue
+		}
+		scalar, _ := scalarType(field.Kind)
+		fields[fieldName] = &gql.InputObjectFieldConfig{Type: b.comparison(scalar)}
+	}
+	return fields
+}
+
+// comparison returns the input type holding the operators which can be used to filter a field
+func (b *schemaBuilder) comparison(scalar *gql.Scalar) *gql.InputObject {
+	if input, p := b.comparisons[scalar.Name()]; p {
+		return input
+	}
+
+	fields := gql.InputObjectConfigFieldMap{}
+	if scalar == geoJSONScalar {
+		// Geometries can only be filtered by the geo operators
+		fields["_near"] = &gql.InputObjectFieldConfig{Type: geoNearInput}
+		fields["_within"] = &gql.InputObjectFieldConfig{Type: geoWithinInput}
+		input := gql.NewInputObject(gql.InputObjectConfig{Name: scalar.Name() + "_comparison", Fields: fields})
+		b.comparisons[scalar.Name()] = input
+		return input
+	}
+	for _, op := range []string{"_eq", "_ne", "_gt", "_gte", "_lt", "_lte"} {
+		fields[op] = &gql.InputObjectFieldConfig{Type: scalar}
+	}
+	fields["_in"] = &gql.InputObjectFieldConfig{Type: gql.NewList(scalar)}
+	fields["_nin"] = &gql.InputObjectFieldConfig{Type: gql.NewList(scalar)}
+	switch scalar {
+	case gql.String, gql.ID:
+		fields["_regex"] = &gql.InputObjectFieldConfig{Type: gql.String}
+	case jsonScalar:
+		fields["_contains"] = &gql.InputObjectFieldConfig{Type: jsonScalar}
+	}
+
+	input := gql.NewInputObject(gql.InputObjectConfig{Name: scalar.Name() + "_comparison", Fields: fields})
+	b.comparisons[scalar.Name()] = input
+	return input
+}
+
+// inputFields returns the fields which can be provided while inserting or updating the rows of a table. Documents of
+// linked tables can be provided while inserting a row to insert them in the same transaction.
+func (b *schemaBuilder) inputFields(t *tableTypes, isInsert bool) gql.InputObjectConfigFieldMap {
+	fields := gql.InputObjectConfigFieldMap{}
+	for fieldName, field := range t.fields {
+		if !gql.NameRegExp.MatchString(fieldName) {
+			continue
+		}
+
+		var fieldType gql.Input = jsonScalar
+		switch {
+		case field.IsLinked:
+			linked := b.linkedTable(t.dbAlias, field)
+			if !isInsert || linked == nil {
+				continue
+			}
+			fieldType = linked.insertInput
+		case field.Kind != model.TypeObject:
+			fieldType, _ = scalarType(field.Kind)
+		}
+
+		if field.IsList {
+			fieldType = gql.NewList(fieldType)
+		}
+		fields[fieldName] = &gql.InputObjectFieldConfig{Type: fieldType}
+	}
+	return fields
+}
+
+// addPreparedQueries adds a query field for every prepared query. The arguments of the prepared query are the root
+// keys of the arguments used by its sql statement.
+func (b *schemaBuilder) addPreparedQueries(queries config.DatabasePreparedQueries) {
+	keys := make([]string, 0, len(queries))
+	for key := range queries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		query := queries[key]
+		b.addDirective(query.DbAlias, gql.FieldConfigArgument{"cache": &gql.ArgumentConfig{Type: jsonScalar}})
+		if _, p := b.queryFields[query.ID]; p || !gql.NameRegExp.MatchString(query.ID) {
+			continue
+		}
+
+		args := gql.FieldConfigArgument{
+			"readYourWrites": &gql.ArgumentConfig{Type: gql.Boolean},
+			"debug":          &gql.ArgumentConfig{Type: gql.Boolean},
+		}
+		for _, arg := range query.Arguments {
+			name := strings.Split(strings.TrimPrefix(arg, "args."), ".")[0]
+			if gql.NameRegExp.MatchString(name) {
+				args[name] = &gql.ArgumentConfig{Type: jsonScalar}
+			}
+		}
+		b.queryFields[query.ID] = &gql.Field{Type: jsonScalar, Args: args, Description: "Prepared query of database " + query.DbAlias}
+	}
+}
+
+// addRemoteServices adds a query field for every endpoint of the remote services. The arguments of a field are sent as
+// the body of the request made to the endpoint.
+func (b *schemaBuilder) addRemoteServices(services config.Services) {
+	ids := make([]string, 0, len(services))
+	for id := range services {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		service := services[id]
+		b.addDirective(service.ID, gql.FieldConfigArgument{
+			"func":    &gql.ArgumentConfig{Type: gql.String},
+			"timeout": &gql.ArgumentConfig{Type: gql.Int},
+			"cache":   &gql.ArgumentConfig{Type: jsonScalar},
+		})
+
+		endpoints := make([]string, 0, len(service.Endpoints))
+		for endpoint := range service.Endpoints {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+		for _, endpoint := range endpoints {
+			if _, p := b.queryFields[endpoint]; p || !gql.NameRegExp.MatchString(endpoint) {
+				continue
+			}
+			b.queryFields[endpoint] = &gql.Field{Type: jsonScalar, Description: "Endpoint of remote service " + service.ID}
+		}
+	}
+}
+
+// scalarType returns the graphql scalar used for a kind of field. The JSON scalar is returned for unknown kinds.
+func scalarType(kind string) (*gql.Scalar, bool) {
+	switch kind {
+	case model.TypeID, model.TypeUUID:
+		return gql.ID, true
+	case model.TypeString, model.TypeChar, model.TypeVarChar:
+		return gql.String, true
+	case model.TypeInteger, model.TypeSmallInteger, model.TypeBigInteger:
+		return gql.Int, true
+	case model.TypeFloat, model.TypeDecimal:
+		return gql.Float, true
+	case model.TypeBoolean:
+		return gql.Boolean, true
+	case model.TypeDateTime, model.TypeDateTimeWithZone:
+		return dateTimeScalar, true
+	case model.TypeDate:
+		return dateScalar, true
+	case model.TypeTime:
+		return timeScalar, true
+	case model.TypeJSON:
+		return jsonScalar, true
+	case model.TypePoint, model.TypeGeometry:
+		return geoJSONScalar, true
+	}
+	return jsonScalar, false
+}
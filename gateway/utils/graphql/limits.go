@@ -0,0 +1,311 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Default weights of the cost model
+const (
+	defaultDBCost       = 1
+	defaultJoinCost     = 2
+	defaultFuncCost     = 10
+	defaultQueryLimit   = 100
+	queryLimitsMaxValue = math.MaxInt64
+)
+
+// queryCost holds the weights used to compute the cost of a query
+type queryCost struct {
+	db, join, funcCall, defaultLimit int64
+}
+
+func newQueryCost(limits *config.GraphQLLimits) *queryCost {
+	withDefault := func(value, def int) int64 {
+		if value <= 0 {
+			return int64(def)
+		}
+		return int64(value)
+	}
+
+	return &queryCost{
+		db:           withDefault(limits.DBCost, defaultDBCost),
+		join:         withDefault(limits.JoinCost, defaultJoinCost),
+		funcCall:     withDefault(limits.FuncCost, defaultFuncCost),
+		defaultLimit: withDefault(limits.DefaultLimit, defaultQueryLimit),
+	}
+}
+
+// CheckQueryLimits rejects the query if its depth, the number of fields it selects or its cost exceed the limits
+// configured for the project. It is used for the subscriptions received over websockets as well
+func (graph *Module) CheckQueryLimits(ctx context.Context, doc *ast.Document, variables map[string]interface{}) error {
+	limits := graph.limits
+	if limits == nil || (limits.MaxDepth <= 0 && limits.MaxFields <= 0 && limits.MaxCost <= 0) || len(doc.Definitions) == 0 {
+		return nil
+	}
+	store := utils.M{"vars": variables, "path": "", "_query": utils.NewArray(0), "directive": ""}
+
+	op, ok := doc.Definitions[0].(*ast.OperationDefinition)
+	if !ok {
+		return nil
+	}
+
+	if limits.MaxDepth > 0 {
+		if depth := getSelectionDepth(op.SelectionSet); depth > limits.MaxDepth {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Query depth (%d) exceeds the maximum allowed depth (%d)", depth, limits.MaxDepth), nil, nil)
+		}
+	}
+
+	if limits.MaxFields > 0 {
+		if fields := countSelectedFields(op.SelectionSet); fields > limits.MaxFields {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Query selects %d fields which exceeds the maximum allowed fields (%d)", fields, limits.MaxFields), nil, nil)
+		}
+	}
+
+	if limits.MaxCost > 0 {
+		cost, err := graph.getQueryCost(ctx, op, store, newQueryCost(limits))
+		if err != nil {
+			return err
+		}
+		if cost > int64(limits.MaxCost) {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Query cost (%d) exceeds the maximum allowed cost (%d)", cost, limits.MaxCost), nil, map[string]interface{}{"cost": cost})
+		}
+	}
+
+	return nil
+}
+
+// getSelectionDepth returns the maximum nesting of the fields in the selection set
+func getSelectionDepth(selectionSet *ast.SelectionSet) int {
+	if selectionSet == nil {
+		return 0
+	}
+
+	depth := 0
+	for _, selection := range selectionSet.Selections {
+		field, ok := selection.(*ast.Field)
+		if !ok {
+			continue
+		}
+		if d := 1 + getSelectionDepth(field.SelectionSet); d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// countSelectedFields returns the total number of fields in the selection set
+func countSelectedFields(selectionSet *ast.SelectionSet) int {
+	if selectionSet == nil {
+		return 0
+	}
+
+	count := 0
+	for _, selection := range selectionSet.Selections {
+		field, ok := selection.(*ast.Field)
+		if !ok {
+			continue
+		}
+		count += 1 + countSelectedFields(field.SelectionSet)
+	}
+	return count
+}
+
+// getQueryCost computes the cost of the operation. Each mutation costs a single database request while the cost of the
+// fields of a query depend on the kind of request they make.
+func (graph *Module) getQueryCost(ctx context.Context, op *ast.OperationDefinition, store utils.M, weights *queryCost) (int64, error) {
+	var cost int64
+	for _, selection := range op.SelectionSet.Selections {
+		field, ok := selection.(*ast.Field)
+		if !ok {
+			continue
+		}
+
+		if op.Operation == ast.OperationTypeMutation {
+			cost = addCost(cost, weights.db)
+			continue
+		}
+
+		fieldCost, err := graph.getFieldCost(ctx, field, store, nil, 1, weights)
+		if err != nil {
+			return 0, err
+		}
+		cost = addCost(cost, fieldCost)
+	}
+	return cost, nil
+}
+
+// getFieldCost computes the cost of a field and the fields nested in it. The multiplier is the number of times the field
+// is expected to be resolved which is the product of the number of rows returned by its parents.
+func (graph *Module) getFieldCost(ctx context.Context, field *ast.Field, store utils.M, schema model.Fields, multiplier int64, weights *queryCost) (int64, error) {
+	var cost, rows int64 = 0, 1
+	var childSchema model.Fields
+
+	switch {
+	case len(field.Directives) > 0 && field.Directives[0].Name.Value != "aggregate":
+		directive := field.Directives[0].Name.Value
+
+		// The database of a template directive is only known once the template is executed
+		kind := "read"
+		if directive != "template" {
+			kind = graph.getQueryKind(directive, field.Name.Value)
+		}
+
+		switch kind {
+		case "read":
+			cost = mulCost(weights.db, multiplier)
+
+			var err error
+			rows, err = getExpectedRows(ctx, field, store, weights)
+			if err != nil {
+				return 0, err
+			}
+
+			joins, err := countJoins(field, store)
+			if err != nil {
+				return 0, err
+			}
+			cost = addCost(cost, mulCost(mulCost(weights.join, joins), multiplier))
+
+			if directive != "template" {
+				if col, err := getCollection(field); err == nil {
+					childSchema, _ = graph.schema.GetSchema(directive, col)
+				}
+			}
+
+		case "prepared-queries":
+			cost = mulCost(weights.db, multiplier)
+			rows = weights.defaultLimit
+
+		default:
+			cost = mulCost(weights.funcCall, multiplier)
+		}
+
+	case schema != nil && schema[field.Name.Value] != nil && schema[field.Name.Value].IsLinked:
+		// Linked fields trigger another read request for each row of the parent
+		fieldStruct := schema[field.Name.Value]
+		cost = mulCost(weights.join, multiplier)
+		if fieldStruct.IsList {
+			var err error
+			rows, err = getExpectedRows(ctx, field, store, weights)
+			if err != nil {
+				return 0, err
+			}
+		}
+		childSchema, _ = graph.schema.GetSchema(fieldStruct.LinkedTable.DBType, fieldStruct.LinkedTable.Table)
+	}
+
+	if field.SelectionSet == nil {
+		return cost, nil
+	}
+
+	childMultiplier := mulCost(multiplier, rows)
+	for _, selection := range field.SelectionSet.Selections {
+		child, ok := selection.(*ast.Field)
+		if !ok {
+			continue
+		}
+
+		childCost, err := graph.getFieldCost(ctx, child, store, childSchema, childMultiplier, weights)
+		if err != nil {
+			return 0, err
+		}
+		cost = addCost(cost, childCost)
+	}
+	return cost, nil
+}
+
+// getExpectedRows returns the number of rows a read request is expected to return
+func getExpectedRows(ctx context.Context, field *ast.Field, store utils.M, weights *queryCost) (int64, error) {
+	op, err := extractQueryOp(ctx, field.Arguments, store)
+	if err != nil {
+		return 0, err
+	}
+	if op == utils.One {
+		return 1, nil
+	}
+
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+
+		temp, err := utils.ParseGraphqlValue(arg.Value, store)
+		if err != nil {
+			return 0, err
+		}
+		var limit int64
+		switch v := temp.(type) {
+		case int:
+			limit = int64(v)
+		case int64:
+			limit = v
+		case float64:
+			limit = int64(v)
+		default:
+			return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid type (%T) provided for limit", temp), nil, nil)
+		}
+
+		// Some databases treat a limit of zero as no limit at all
+		if limit > 0 {
+			return limit, nil
+		}
+		break
+	}
+	return weights.defaultLimit, nil
+}
+
+// countJoins returns the number of tables joined by a read request
+func countJoins(field *ast.Field, store utils.M) (int64, error) {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "join" {
+			continue
+		}
+
+		temp, err := utils.ParseGraphqlValue(arg.Value, store)
+		if err != nil {
+			return 0, err
+		}
+		join := make([]*model.JoinOption, 0)
+		if err := mapstructure.Decode(temp, &join); err != nil {
+			return 0, err
+		}
+		return countJoinOptions(join), nil
+	}
+	return 0, nil
+}
+
+func countJoinOptions(join []*model.JoinOption) int64 {
+	count := int64(len(join))
+	for _, j := range join {
+		count += countJoinOptions(j.Join)
+	}
+	return count
+}
+
+// addCost adds two costs without overflowing
+func addCost(a, b int64) int64 {
+	if a > queryLimitsMaxValue-b {
+		return queryLimitsMaxValue
+	}
+	return a + b
+}
+
+// mulCost multiplies two costs without overflowing
+func mulCost(a, b int64) int64 {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	if a > queryLimitsMaxValue/b {
+		return queryLimitsMaxValue
+	}
+	return a * b
+}
@@ -37,6 +37,20 @@ func (graph *Module) generateAllReq(ctx context.Context, field *ast.Field, dbAli
 			return reqParams, []*model.AllRequest{result}, nil, nil
 		}
 
+		// Restore query function
+		if strings.HasPrefix(field.Name.Value, "restore_") {
+			col := strings.TrimPrefix(field.Name.Value, "restore_")
+
+			reqParams, result, err := graph.generateRestoreReq(ctx, field, token, store)
+			if err != nil {
+				return model.RequestParams{}, nil, nil, err
+			}
+			result.Type = string(model.Restore)
+			result.Col = col
+			result.DBAlias = dbAlias
+			return reqParams, []*model.AllRequest{result}, nil, nil
+		}
+
 		// Update query function
 		if strings.HasPrefix(field.Name.Value, "update_") {
 			col := strings.TrimPrefix(field.Name.Value, "update_")
@@ -68,6 +82,11 @@ func (graph *Module) execAllReq(ctx context.Context, dbAlias, project string, re
 			t := model.DeleteRequest{Operation: r.Operation, Find: r.Find}
 			return map[string]interface{}{"status": 200, "error": nil}, graph.crud.Delete(ctx, dbAlias, r.Col, &t, params)
 
+		case string(model.Restore):
+
+			t := model.RestoreRequest{Operation: r.Operation, Find: r.Find}
+			return map[string]interface{}{"status": 200, "error": nil}, graph.crud.Restore(ctx, dbAlias, r.Col, &t, params)
+
 		case string(model.Update):
 
 			t := model.UpdateRequest{Operation: r.Operation, Find: r.Find, Update: r.Update}
@@ -131,6 +150,9 @@ func (graph *Module) handleMutation(ctx context.Context, node ast.Node, token st
 		if err != nil {
 			obj["error"] = err.Error()
 			obj["status"] = 500
+			if err == utils.ErrVersionConflict {
+				obj["status"] = 409
+			}
 		}
 
 		queryResults[dbAlias] = obj
@@ -0,0 +1,146 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// apqCacheSize is the maximum number of automatic persisted queries cached by the module
+const apqCacheSize = 1000
+
+// errPersistedQueryNotFound is the error clients implementing automatic persisted queries expect when the hash of the
+// query is unknown. The clients retry the request with the entire query on receiving it.
+const errPersistedQueryNotFound = "PersistedQueryNotFound"
+
+// SetPersistedQueries sets the queries in the allow list of the project
+func (graph *Module) SetPersistedQueries(ctx context.Context, queries config.GraphQLPersistedQueries) {
+	persistedQueries := make(map[string]string, len(queries))
+	normalizedQueries := make(map[string]bool, len(queries))
+	for _, query := range queries {
+		persistedQueries[hashQuery(query.Query)] = query.Query
+
+		// Queries sent by clients are formatted differently than the ones registered. Hence we also store the hash of
+		// the normalized query
+		doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(query.Query)})})
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to parse persisted query (%s)", query.ID), err, nil)
+			continue
+		}
+		normalizedQueries[hashQuery(normalizeQuery(doc))] = true
+	}
+
+	graph.persistedLock.Lock()
+	defer graph.persistedLock.Unlock()
+	graph.persistedQueries = persistedQueries
+	graph.normalizedQueries = normalizedQueries
+}
+
+// SetEnforcePersistedQueries sets whether the queries which aren't persisted are rejected
+func (graph *Module) SetEnforcePersistedQueries(enforce bool) {
+	graph.persistedLock.Lock()
+	defer graph.persistedLock.Unlock()
+	graph.enforcePersistedQueries = enforce
+}
+
+// loadPersistedQuery sets the query of a request which only provides the hash of a persisted query. The hash of the
+// query is returned if the request uses the persisted query extension.
+func (graph *Module) loadPersistedQuery(ctx context.Context, req *model.GraphQLRequest) (string, error) {
+	if req.Extensions == nil || req.Extensions.PersistedQuery == nil {
+		return "", nil
+	}
+
+	persistedQuery := req.Extensions.PersistedQuery
+	if persistedQuery.Version != 1 {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unsupported persisted query version (%d)", persistedQuery.Version), nil, nil)
+	}
+	hash := strings.ToLower(persistedQuery.Sha256Hash)
+
+	if req.Query != "" {
+		if hashQuery(req.Query) != hash {
+			return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "Provided sha256 hash does not match the query", nil, nil)
+		}
+		return hash, nil
+	}
+
+	graph.persistedLock.RLock()
+	defer graph.persistedLock.RUnlock()
+
+	if query, p := graph.persistedQueries[hash]; p {
+		req.Query = query
+		return hash, nil
+	}
+
+	// Queries registered automatically can only be used if the allow list isn't enforced
+	if !graph.enforcePersistedQueries {
+		if query, p := graph.apqCache.Get(hash); p {
+			req.Query = query.(string)
+			return hash, nil
+		}
+	}
+
+	return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), errPersistedQueryNotFound, nil, map[string]interface{}{"hash": hash})
+}
+
+// CheckPersistedQuery rejects the queries which aren't persisted if the allow list is enforced. It is used for the
+// queries which aren't executed by ExecGraphQLQuery, like subscriptions.
+func (graph *Module) CheckPersistedQuery(ctx context.Context, query string, doc *ast.Document, token string) error {
+	return graph.checkPersistedQuery(ctx, &model.GraphQLRequest{Query: query}, "", doc, token)
+}
+
+// checkPersistedQuery rejects the queries which aren't persisted if the allow list is enforced. Admins are allowed to
+// run any query. The query is registered as an automatic persisted query otherwise.
+func (graph *Module) checkPersistedQuery(ctx context.Context, req *model.GraphQLRequest, hash string, doc *ast.Document, token string) error {
+	graph.persistedLock.RLock()
+	enforce := graph.enforcePersistedQueries
+	graph.persistedLock.RUnlock()
+
+	if !enforce {
+		if hash != "" {
+			graph.apqCache.Add(hash, req.Query)
+		}
+		return nil
+	}
+
+	if graph.isQueryPersisted(req.Query, doc) {
+		return nil
+	}
+
+	if err := graph.auth.IsTokenAdmin(ctx, token); err == nil {
+		return nil
+	}
+	return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Query is not present in the persisted queries of the project", nil, nil)
+}
+
+func (graph *Module) isQueryPersisted(query string, doc *ast.Document) bool {
+	graph.persistedLock.RLock()
+	defer graph.persistedLock.RUnlock()
+
+	if _, p := graph.persistedQueries[hashQuery(query)]; p {
+		return true
+	}
+	return graph.normalizedQueries[hashQuery(normalizeQuery(doc))]
+}
+
+// normalizeQuery prints the query in a standard format so that queries differing only in whitespaces and commas have
+// the same hash
+func normalizeQuery(doc *ast.Document) string {
+	printed, _ := printer.Print(doc).(string)
+	return printed
+}
+
+func hashQuery(query string) string {
+	hash := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(hash[:])
+}
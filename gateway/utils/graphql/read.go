@@ -147,6 +147,12 @@ func (graph *Module) execReadRequest(ctx context.Context, field *ast.Field, toke
 			val.(*utils.Array).Append(structs.Map(metaData))
 		}
 
+		// The cursors need to be generated before post processing modifies the result
+		if err := setDocumentCursors(field, req.Options, result); err != nil {
+			cb("", "", nil, err)
+			return
+		}
+
 		// Post process only if joins were not enabled
 		if isPostProcessingEnabled(req.PostProcess) && len(req.Options.Join) == 0 {
 			_ = authHelpers.PostProcessMethod(ctx, graph.aesKey, req.PostProcess[col], result)
@@ -156,6 +162,35 @@ func (graph *Module) execReadRequest(ctx context.Context, field *ast.Field, toke
 	}()
 }
 
+// setDocumentCursors stores the cursor of each document of a paginated read in the _cursor field, if it was requested
+func setDocumentCursors(field *ast.Field, options *model.ReadOptions, result interface{}) error {
+	if _, _, ok := utils.GetCursor(options); !ok || field.SelectionSet == nil {
+		return nil
+	}
+
+	isCursorSelected := false
+	for _, selection := range field.SelectionSet.Selections {
+		if v, ok := selection.(*ast.Field); ok && v.Name.Value == "_cursor" {
+			isCursorSelected = true
+			break
+		}
+	}
+
+	docs, ok := result.([]interface{})
+	if !isCursorSelected || !ok {
+		return nil
+	}
+
+	for _, doc := range docs {
+		cursor, err := utils.EncodeCursor(options.Sort, doc)
+		if err != nil {
+			return err
+		}
+		doc.(map[string]interface{})["_cursor"] = cursor
+	}
+	return nil
+}
+
 func isDataLoaderDisabled(ctx context.Context, field *ast.Field, store utils.M) (bool, error) {
 	for _, arg := range field.Arguments {
 		switch arg.Name.Value {
@@ -218,7 +253,13 @@ func (graph *Module) execPreparedQueryRequest(ctx context.Context, field *ast.Fi
 		return
 	}
 
-	req := model.PreparedQueryRequest{Params: params, Debug: isDebug}
+	readYourWrites, err := getReadYourWritesParam(field.Arguments, store)
+	if err != nil {
+		cb("", "", nil, err)
+		return
+	}
+
+	req := model.PreparedQueryRequest{Params: params, Debug: isDebug, ReadYourWrites: readYourWrites}
 	// Check if PreparedQuery op is authorised
 	actions, reqParams, err := graph.auth.IsPreparedQueryAuthorised(ctx, graph.project, dbAlias, id, token, &req)
 	if err != nil {
@@ -288,7 +329,7 @@ func generateArguments(ctx context.Context, field *ast.Field, store utils.M) map
 	obj := map[string]interface{}{}
 	for _, arg := range field.Arguments {
 		switch arg.Name.Value {
-		case "where", "group", "skip", "limit", "sort", "distinct": // read & delete
+		case "where", "group", "skip", "limit", "sort", "distinct", "after", "before", "includeDeleted", "readYourWrites": // read & delete
 			continue
 		case "op", "set", "inc", "mul", "max", "min", "currentTimestamp", "currentDate", "push", "rename", "unset": // update
 			continue
@@ -360,8 +401,8 @@ func (graph *Module) extractSelectionSet(ctx context.Context, field *ast.Field,
 	for _, selection := range field.SelectionSet.Selections {
 		v := selection.(*ast.Field)
 
-		// Skip dbFetchTs fields
-		if v.Name.Value == "_dbFetchTs" {
+		// Skip dbFetchTs & cursor fields
+		if v.Name.Value == "_dbFetchTs" || v.Name.Value == "_cursor" {
 			continue
 		}
 
@@ -752,27 +793,32 @@ func generateOptions(ctx context.Context, args []*ast.Argument, store utils.M) (
 		case "sort":
 			hasOptions = true // Set the flag to true
 
+			sortArray, err := generateSortArray(ctx, v.Value, store)
+			if err != nil {
+				return nil, hasOptions, err
+			}
+
+			options.Sort = sortArray
+
+		case "after", "before":
+			hasOptions = true // Set the flag to true
+
 			temp, err := utils.ParseGraphqlValue(v.Value, store)
 			if err != nil {
 				return nil, hasOptions, err
 			}
 
-			tempInt, ok := temp.([]interface{})
+			cursor, ok := temp.(string)
 			if !ok {
-				return nil, hasOptions, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid type provided for (sort) expecting array got (%v)", reflect.TypeOf(temp)), nil, nil)
+				return nil, hasOptions, fmt.Errorf("invalid type provided for %s expecting string got (%s)", v.Name.Value, reflect.TypeOf(temp))
 			}
 
-			sortArray := make([]string, len(tempInt))
-			for i, value := range tempInt {
-				valueString, ok := value.(string)
-				if !ok {
-					return nil, hasOptions, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid type provided for (sort) each value in array should be string got (%v)", reflect.TypeOf(value)), nil, nil)
-				}
-				sortArray[i] = valueString
+			if v.Name.Value == "after" {
+				options.After = &cursor
+			} else {
+				options.Before = &cursor
 			}
 
-			options.Sort = sortArray
-
 		case "distinct":
 			hasOptions = true // Set the flag to true
 
@@ -787,6 +833,29 @@ func generateOptions(ctx context.Context, args []*ast.Argument, store utils.M) (
 			}
 
 			options.Distinct = &tempString
+		case "readYourWrites":
+			hasOptions = true // Set the flag to true
+
+			readYourWrites, err := getReadYourWritesParam([]*ast.Argument{v}, store)
+			if err != nil {
+				return nil, hasOptions, err
+			}
+
+			options.ReadYourWrites = readYourWrites
+		case "includeDeleted":
+			hasOptions = true // Set the flag to true
+
+			temp, err := utils.ParseGraphqlValue(v.Value, store)
+			if err != nil {
+				return nil, hasOptions, err
+			}
+
+			includeDeleted, ok := temp.(bool)
+			if !ok {
+				return nil, hasOptions, fmt.Errorf("invalid type (%s) for includeDeleted", reflect.TypeOf(temp))
+			}
+
+			options.IncludeDeleted = includeDeleted
 		case "debug":
 			hasOptions = true // Set the flag to true
 
@@ -819,6 +888,24 @@ func getDebugParam(ctx context.Context, args []*ast.Argument, store utils.M) (bo
 	return false, nil
 }
 
+func getReadYourWritesParam(args []*ast.Argument, store utils.M) (bool, error) {
+	for _, v := range args {
+		if v.Name.Value == "readYourWrites" {
+			temp, err := utils.ParseGraphqlValue(v.Value, store)
+			if err != nil {
+				return false, err
+			}
+
+			tempBool, ok := temp.(bool)
+			if !ok {
+				return false, fmt.Errorf("invalid type (%s) for readYourWrites", reflect.TypeOf(temp))
+			}
+			return tempBool, nil
+		}
+	}
+	return false, nil
+}
+
 func isJointTable(table string, join []*model.JoinOption) (*model.JoinOption, bool) {
 	for _, j := range join {
 		if j.Table == table {
@@ -828,3 +915,80 @@ func isJointTable(table string, join []*model.JoinOption) (*model.JoinOption, bo
 
 	return nil, false
 }
+
+// generateSortArray returns the sort order provided as an array of fields or objects like {name: asc, age: desc}. The
+// fields of an object are sorted in the order in which they are written in the query. The order of the keys of an
+// object provided in the variables is lost while decoding them, hence such objects can only have a single field.
+func generateSortArray(ctx context.Context, value ast.Value, store utils.M) ([]string, error) {
+	sortArray := make([]string, 0)
+	addField := func(field string, order interface{}) error {
+		switch order {
+		case "asc":
+			sortArray = append(sortArray, field)
+		case "desc":
+			sortArray = append(sortArray, "-"+field)
+		default:
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid sort order (%v) provided for field (%s) expecting either (asc) or (desc)", order, field), nil, nil)
+		}
+		return nil
+	}
+	addValue := func(value interface{}) error {
+		switch v := value.(type) {
+		case string:
+			sortArray = append(sortArray, v)
+		case map[string]interface{}:
+			if len(v) != 1 {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Each sort object provided in the variables must contain a single field", nil, nil)
+			}
+			for field, order := range v {
+				return addField(field, order)
+			}
+		default:
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid type provided for (sort) each value in array should be string or object got (%v)", reflect.TypeOf(value)), nil, nil)
+		}
+		return nil
+	}
+
+	list, ok := value.(*ast.ListValue)
+	if !ok {
+		temp, err := utils.ParseGraphqlValue(value, store)
+		if err != nil {
+			return nil, err
+		}
+		arr, ok := temp.([]interface{})
+		if !ok {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid type provided for (sort) expecting array got (%v)", reflect.TypeOf(temp)), nil, nil)
+		}
+		for _, item := range arr {
+			if err := addValue(item); err != nil {
+				return nil, err
+			}
+		}
+		return sortArray, nil
+	}
+
+	for _, item := range list.Values {
+		// The fields of an object written in the query are read from the ast to preserve their order
+		if obj, ok := item.(*ast.ObjectValue); ok {
+			for _, field := range obj.Fields {
+				order, err := utils.ParseGraphqlValue(field.Value, store)
+				if err != nil {
+					return nil, err
+				}
+				if err := addField(field.Name.Value, order); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		temp, err := utils.ParseGraphqlValue(item, store)
+		if err != nil {
+			return nil, err
+		}
+		if err := addValue(temp); err != nil {
+			return nil, err
+		}
+	}
+	return sortArray, nil
+}
@@ -0,0 +1,44 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func (graph *Module) generateRestoreReq(ctx context.Context, field *ast.Field, token string, store map[string]interface{}) (model.RequestParams, *model.AllRequest, error) {
+	dbAlias, err := graph.GetDBAlias(ctx, field, token, store)
+	if err != nil {
+		return model.RequestParams{}, nil, err
+	}
+	col := strings.TrimPrefix(field.Name.Value, "restore_")
+
+	req, err := generateRestoreRequest(field, store)
+	if err != nil {
+		return model.RequestParams{}, nil, err
+	}
+
+	reqParams, err := graph.auth.IsRestoreOpAuthorised(ctx, graph.project, dbAlias, col, token, req)
+	if err != nil {
+		return model.RequestParams{}, nil, err
+	}
+	return reqParams, &model.AllRequest{Operation: req.Operation, Find: req.Find}, nil
+}
+
+func generateRestoreRequest(field *ast.Field, store utils.M) (*model.RestoreRequest, error) {
+	var err error
+
+	// Create a restore request object
+	restoreRequest := model.RestoreRequest{Operation: utils.All}
+
+	restoreRequest.Find, err = ExtractWhereClause(field.Arguments, store)
+	if err != nil {
+		return nil, err
+	}
+
+	return &restoreRequest, nil
+}
@@ -0,0 +1,116 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+// printSchema prints the schema in the graphql schema definition language. The types, fields and arguments are printed
+// in a sorted order so that the output is stable.
+func printSchema(schema gql.Schema) string {
+	var b strings.Builder
+
+	specified := map[string]bool{}
+	for _, directive := range gql.SpecifiedDirectives {
+		specified[directive.Name] = true
+	}
+	directives := make([]*gql.Directive, 0)
+	for _, directive := range schema.Directives() {
+		if !specified[directive.Name] {
+			directives = append(directives, directive)
+		}
+	}
+	sort.Slice(directives, func(i, j int) bool { return directives[i].Name < directives[j].Name })
+	for _, directive := range directives {
+		printDescription(&b, "", directive.Description)
+		fmt.Fprintf(&b, "directive @%s%s on %s\n\n", directive.Name, printArgs(directive.Args), strings.Join(directive.Locations, " | "))
+	}
+
+	typeMap := schema.TypeMap()
+	names := make([]string, 0, len(typeMap))
+	for name := range typeMap {
+		switch name {
+		case gql.String.Name(), gql.Int.Name(), gql.Float.Name(), gql.Boolean.Name(), gql.ID.Name():
+			continue
+		}
+		if !strings.HasPrefix(name, "__") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch t := typeMap[name].(type) {
+		case *gql.Scalar:
+			printDescription(&b, "", t.Description())
+			fmt.Fprintf(&b, "scalar %s\n\n", name)
+
+		case *gql.Enum:
+			printDescription(&b, "", t.Description())
+			fmt.Fprintf(&b, "enum %s {\n", name)
+			for _, value := range t.Values() {
+				fmt.Fprintf(&b, "  %s\n", value.Name)
+			}
+			b.WriteString("}\n\n")
+
+		case *gql.InputObject:
+			printDescription(&b, "", t.Description())
+			fmt.Fprintf(&b, "input %s {\n", name)
+			fields := t.Fields()
+			fieldNames := make([]string, 0, len(fields))
+			for fieldName := range fields {
+				fieldNames = append(fieldNames, fieldName)
+			}
+			sort.Strings(fieldNames)
+			for _, fieldName := range fieldNames {
+				printDescription(&b, "  ", fields[fieldName].Description())
+				fmt.Fprintf(&b, "  %s: %s\n", fieldName, fields[fieldName].Type.String())
+			}
+			b.WriteString("}\n\n")
+
+		case *gql.Object:
+			printDescription(&b, "", t.Description())
+			fmt.Fprintf(&b, "type %s {\n", name)
+			fields := t.Fields()
+			fieldNames := make([]string, 0, len(fields))
+			for fieldName := range fields {
+				fieldNames = append(fieldNames, fieldName)
+			}
+			sort.Strings(fieldNames)
+			for _, fieldName := range fieldNames {
+				field := fields[fieldName]
+				printDescription(&b, "  ", field.Description)
+				fmt.Fprintf(&b, "  %s%s: %s\n", fieldName, printArgs(field.Args), field.Type.String())
+			}
+			b.WriteString("}\n\n")
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func printArgs(args []*gql.Argument) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	sorted := append([]*gql.Argument{}, args...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name() < sorted[j].Name() })
+	printed := make([]string, len(sorted))
+	for i, arg := range sorted {
+		printed[i] = fmt.Sprintf("%s: %s", arg.Name(), arg.Type.String())
+	}
+	return "(" + strings.Join(printed, ", ") + ")"
+}
+
+func printDescription(b *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	data, _ := json.Marshal(description)
+	fmt.Fprintf(b, "%s%s\n", indent, data)
+}
@@ -3,6 +3,7 @@ package graphql
 import (
 	"context"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 )
 
@@ -12,19 +13,24 @@ type CrudInterface interface {
 	Read(ctx context.Context, dbAlias, collection string, request *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error)
 	Update(ctx context.Context, dbAlias, collection string, request *model.UpdateRequest, params model.RequestParams) error
 	Delete(ctx context.Context, dbAlias, collection string, request *model.DeleteRequest, params model.RequestParams) error
+	Restore(ctx context.Context, dbAlias, collection string, request *model.RestoreRequest, params model.RequestParams) error
 	Batch(ctx context.Context, dbAlias string, req *model.BatchRequest, params model.RequestParams) error
 	GetDBType(dbAlias string) (string, error)
 	IsPreparedQueryPresent(directive, fieldName string) bool
 	ExecPreparedQuery(ctx context.Context, dbAlias, id string, req *model.PreparedQueryRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error)
+	GetPreparedQueries() config.DatabasePreparedQueries
 }
 
 // AuthInterface is an interface consisting of functions of auth module used by graphql module
 type AuthInterface interface {
 	ParseToken(ctx context.Context, token string) (map[string]interface{}, error)
+	IsTokenAdmin(ctx context.Context, token string) error
+	IsTableDenied(ctx context.Context, project, dbAlias, col string) bool
 	IsCreateOpAuthorised(ctx context.Context, project, dbAlias, col, token string, req *model.CreateRequest) (model.RequestParams, error)
 	IsReadOpAuthorised(ctx context.Context, project, dbAlias, col, token string, req *model.ReadRequest, stub model.ReturnWhereStub) (*model.PostProcess, model.RequestParams, error)
 	IsUpdateOpAuthorised(ctx context.Context, project, dbAlias, col, token string, req *model.UpdateRequest) (model.RequestParams, error)
 	IsDeleteOpAuthorised(ctx context.Context, project, dbAlias, col, token string, req *model.DeleteRequest) (model.RequestParams, error)
+	IsRestoreOpAuthorised(ctx context.Context, project, dbAlias, col, token string, req *model.RestoreRequest) (model.RequestParams, error)
 	IsFuncCallAuthorised(ctx context.Context, project, service, function, token string, params interface{}) (*model.PostProcess, model.RequestParams, error)
 	IsPreparedQueryAuthorised(ctx context.Context, project, dbAlias, id, token string, req *model.PreparedQueryRequest) (*model.PostProcess, model.RequestParams, error)
 }
@@ -32,9 +38,11 @@ type AuthInterface interface {
 // FunctionInterface is an interface consisting of functions of function module used by graphql module
 type FunctionInterface interface {
 	CallWithContext(ctx context.Context, service, function, token string, reqParams model.RequestParams, req *model.FunctionsRequest) (int, interface{}, error)
+	GetServices() config.Services
 }
 
 // SchemaInterface is an interface consisting of functions of schema module used by graphql module
 type SchemaInterface interface {
 	GetSchema(dbAlias, col string) (model.Fields, bool)
+	GetSchemas() model.Type
 }
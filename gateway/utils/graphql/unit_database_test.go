@@ -11,6 +11,7 @@ import (
 
 var distinct = "type"
 var number int64 = 5
+var cursor = "WyIwIl0"
 
 var queryTestCases = []tests{
 	{
@@ -1890,6 +1891,146 @@ var queryTestCases = []tests{
 		wantErr:    false,
 		wantResult: map[string]interface{}{"caught_pokemons": []interface{}{map[string]interface{}{"id": "1", "name": "ash", "caught_on": "2019-06-01"}, map[string]interface{}{"id": "2", "name": "james", "caught_on": "2019-06-01"}}},
 	},
+	{
+		name: "Query: Sorting by multiple fields provided as objects",
+		crudMockArgs: []mockArgs{
+			{
+				method:         "GetDBType",
+				args:           []interface{}{"db"},
+				paramsReturned: []interface{}{"postgres", nil},
+			},
+			{
+				method:         "IsPreparedQueryPresent",
+				args:           []interface{}{"db", "caught_pokemons"},
+				paramsReturned: []interface{}{false},
+			},
+			{
+				method:         "GetDBType",
+				args:           []interface{}{"db"},
+				paramsReturned: []interface{}{"postgres", nil},
+			},
+			{
+				method: "Read",
+				args: []interface{}{mock.Anything, "db", "caught_pokemons", &model.ReadRequest{
+					Extras:    map[string]interface{}{},
+					Find:      map[string]interface{}{},
+					Aggregate: map[string][]string{},
+					GroupBy:   []interface{}{},
+					Operation: utils.All,
+					Options: &model.ReadOptions{
+						Select:     map[string]int32{"caught_pokemons.id": 1, "caught_pokemons.name": 1, "caught_pokemons.caught_on": 1},
+						Sort:       []string{"name", "-caught_on"},
+						HasOptions: true,
+					},
+					IsBatch:     true,
+					PostProcess: map[string]*model.PostProcess{"caught_pokemons": &model.PostProcess{}},
+				}, model.RequestParams{}},
+				paramsReturned: []interface{}{[]interface{}{map[string]interface{}{"id": "1", "name": "ash", "caught_on": "2019-06-01"}, map[string]interface{}{"id": "2", "name": "james", "caught_on": "2019-06-01"}}, new(model.SQLMetaData), nil},
+			},
+		},
+		schemaMockArgs: []mockArgs{
+			{
+				method:         "GetSchema",
+				args:           []interface{}{"db", "caught_pokemons"},
+				paramsReturned: []interface{}{model.Fields{}, true},
+			},
+		},
+		authMockArgs: []mockArgs{
+			{
+				method:         "IsReadOpAuthorised",
+				args:           []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything},
+				paramsReturned: []interface{}{&model.PostProcess{}, model.RequestParams{}, nil},
+			},
+		},
+		args: args{
+			req: &model.GraphQLRequest{
+				OperationName: "query",
+				Query: `query {
+								caught_pokemons(
+									sort : [{name: asc}, {caught_on: desc}]
+								) @db {
+									id
+									name
+									caught_on
+								}
+							}`,
+				Variables: nil,
+			},
+			token: "",
+		},
+		wantErr:    false,
+		wantResult: map[string]interface{}{"caught_pokemons": []interface{}{map[string]interface{}{"id": "1", "name": "ash", "caught_on": "2019-06-01"}, map[string]interface{}{"id": "2", "name": "james", "caught_on": "2019-06-01"}}},
+	},
+	{
+		name: "Query: Sorting by multiple fields of an object in the order they are written",
+		crudMockArgs: []mockArgs{
+			{
+				method:         "GetDBType",
+				args:           []interface{}{"db"},
+				paramsReturned: []interface{}{"postgres", nil},
+			},
+			{
+				method:         "IsPreparedQueryPresent",
+				args:           []interface{}{"db", "caught_pokemons"},
+				paramsReturned: []interface{}{false},
+			},
+			{
+				method:         "GetDBType",
+				args:           []interface{}{"db"},
+				paramsReturned: []interface{}{"postgres", nil},
+			},
+			{
+				method: "Read",
+				args: []interface{}{mock.Anything, "db", "caught_pokemons", &model.ReadRequest{
+					Extras:    map[string]interface{}{},
+					Find:      map[string]interface{}{},
+					Aggregate: map[string][]string{},
+					GroupBy:   []interface{}{},
+					Operation: utils.All,
+					Options: &model.ReadOptions{
+						Select:     map[string]int32{"caught_pokemons.id": 1, "caught_pokemons.name": 1, "caught_pokemons.caught_on": 1},
+						Sort:       []string{"name", "-caught_on"},
+						HasOptions: true,
+					},
+					IsBatch:     true,
+					PostProcess: map[string]*model.PostProcess{"caught_pokemons": &model.PostProcess{}},
+				}, model.RequestParams{}},
+				paramsReturned: []interface{}{[]interface{}{map[string]interface{}{"id": "1", "name": "ash", "caught_on": "2019-06-01"}, map[string]interface{}{"id": "2", "name": "james", "caught_on": "2019-06-01"}}, new(model.SQLMetaData), nil},
+			},
+		},
+		schemaMockArgs: []mockArgs{
+			{
+				method:         "GetSchema",
+				args:           []interface{}{"db", "caught_pokemons"},
+				paramsReturned: []interface{}{model.Fields{}, true},
+			},
+		},
+		authMockArgs: []mockArgs{
+			{
+				method:         "IsReadOpAuthorised",
+				args:           []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything},
+				paramsReturned: []interface{}{&model.PostProcess{}, model.RequestParams{}, nil},
+			},
+		},
+		args: args{
+			req: &model.GraphQLRequest{
+				OperationName: "query",
+				Query: `query {
+								caught_pokemons(
+									sort : [{name: asc, caught_on: desc}]
+								) @db {
+									id
+									name
+									caught_on
+								}
+							}`,
+				Variables: nil,
+			},
+			token: "",
+		},
+		wantErr:    false,
+		wantResult: map[string]interface{}{"caught_pokemons": []interface{}{map[string]interface{}{"id": "1", "name": "ash", "caught_on": "2019-06-01"}, map[string]interface{}{"id": "2", "name": "james", "caught_on": "2019-06-01"}}},
+	},
 	// {
 	// 	name: "Query: Sorting nested fields",
 	// 	crudMockArgs: []mockArgs{
@@ -2241,6 +2382,80 @@ var queryTestCases = []tests{
 		wantErr:    false,
 		wantResult: map[string]interface{}{"trainers": []interface{}{map[string]interface{}{"id": "1", "name": "ash"}, map[string]interface{}{"id": "2", "name": "james"}}},
 	},
+	{
+		name: "Query: Pagination after cursor",
+		crudMockArgs: []mockArgs{
+			{
+				method:         "GetDBType",
+				args:           []interface{}{"db"},
+				paramsReturned: []interface{}{"postgres", nil},
+			},
+			{
+				method:         "IsPreparedQueryPresent",
+				args:           []interface{}{"db", "trainers"},
+				paramsReturned: []interface{}{false},
+			},
+			{
+				method:         "GetDBType",
+				args:           []interface{}{"db"},
+				paramsReturned: []interface{}{"postgres", nil},
+			},
+			{
+				method: "Read",
+				args: []interface{}{mock.Anything, "db", "trainers", &model.ReadRequest{
+					Extras:    map[string]interface{}{},
+					Find:      map[string]interface{}{},
+					Aggregate: map[string][]string{},
+					GroupBy:   []interface{}{},
+					Operation: utils.All,
+					Options: &model.ReadOptions{
+						Select:     map[string]int32{"trainers.id": 1, "trainers.name": 1},
+						Sort:       []string{"id"},
+						Limit:      &number,
+						After:      &cursor,
+						HasOptions: true,
+					},
+					IsBatch:     true,
+					PostProcess: map[string]*model.PostProcess{"trainers": &model.PostProcess{}},
+				}, model.RequestParams{}},
+				paramsReturned: []interface{}{[]interface{}{map[string]interface{}{"id": "1", "name": "ash"}, map[string]interface{}{"id": "2", "name": "james"}}, new(model.SQLMetaData), nil},
+			},
+		},
+		schemaMockArgs: []mockArgs{
+			{
+				method:         "GetSchema",
+				args:           []interface{}{"db", "trainers"},
+				paramsReturned: []interface{}{model.Fields{}, true},
+			},
+		},
+		authMockArgs: []mockArgs{
+			{
+				method:         "IsReadOpAuthorised",
+				args:           []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything},
+				paramsReturned: []interface{}{&model.PostProcess{}, model.RequestParams{}, nil},
+			},
+		},
+		args: args{
+			req: &model.GraphQLRequest{
+				OperationName: "query",
+				Query: `query {
+								trainers(
+									sort : ["id"]
+									limit : 5
+									after : "WyIwIl0"
+								) @db {
+									id
+									name
+									_cursor
+								}
+							}`,
+				Variables: nil,
+			},
+			token: "",
+		},
+		wantErr:    false,
+		wantResult: map[string]interface{}{"trainers": []interface{}{map[string]interface{}{"id": "1", "name": "ash", "_cursor": "WyIxIl0"}, map[string]interface{}{"id": "2", "name": "james", "_cursor": "WyIyIl0"}}},
+	},
 	// {
 	// 	name: "Query: Pagination skip & limit on nested queries",
 	// 	crudMockArgs: []mockArgs{
@@ -4373,6 +4588,57 @@ var deleteTestCases = []tests{
 		wantErr:    false,
 		wantResult: map[string]interface{}{"delete_pokemons": map[string]interface{}{"error": nil, "status": 200}},
 	},
+	{
+		name: "Mutation: Restore operation with the params returned by the auth module",
+		crudMockArgs: []mockArgs{
+			{
+				method:         "GetDBType",
+				args:           []interface{}{"db"},
+				paramsReturned: []interface{}{"postgres", nil},
+			},
+			{
+				method: "Restore",
+				args: []interface{}{mock.Anything, "db", "pokemons", &model.RestoreRequest{
+					Find: map[string]interface{}{
+						"id": map[string]interface{}{
+							"$eq": "1",
+						},
+					},
+					Operation: utils.All,
+				}, model.RequestParams{Resource: "db-restore", Op: "access", Claims: map[string]interface{}{"id": "admin"}}},
+				paramsReturned: []interface{}{nil},
+			},
+		},
+		schemaMockArgs: []mockArgs{},
+		authMockArgs: []mockArgs{
+			{
+				method:         "IsRestoreOpAuthorised",
+				args:           []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything},
+				paramsReturned: []interface{}{model.RequestParams{Resource: "db-restore", Op: "access", Claims: map[string]interface{}{"id": "admin"}}, nil},
+			},
+		},
+		args: args{
+			req: &model.GraphQLRequest{
+				OperationName: "query",
+				Query: `mutation {
+								  restore_pokemons(
+								    where : {
+										id: {
+											_eq: "1"
+											}
+										},
+								  ) @db {
+									error
+								    status
+								  }
+							}`,
+				Variables: nil,
+			},
+			token: "",
+		},
+		wantErr:    false,
+		wantResult: map[string]interface{}{"restore_pokemons": map[string]interface{}{"error": nil, "status": 200}},
+	},
 	{
 		name: "Mutation: Delete operation error not authorized",
 		crudMockArgs: []mockArgs{
@@ -0,0 +1,158 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils/graphql"
+)
+
+func newIntrospectionModule(t *testing.T) (*graphql.Module, *mockGraphQLSchemaInterface) {
+	schemas, err := schemaHelpers.Parser(config.DatabaseSchemas{
+		"users": &config.DatabaseSchema{DbAlias: "db", Table: "users", Schema: `type users {
+			id: ID! @primary
+			name: String
+			age: Integer
+			address: address
+			deleted_at: DateTime @softDelete
+			posts: [posts] @link(table: "posts", from: "id", to: "author_id")
+		}
+		type address {
+			city: String
+		}`},
+		"posts":     &config.DatabaseSchema{DbAlias: "db", Table: "posts", Schema: `type posts { id: ID! @primary title: String! author_id: ID }`},
+		"db2-users": &config.DatabaseSchema{DbAlias: "db2", Table: "users", Schema: `type users { id: ID! @primary meta: JSON }`},
+		"history":   &config.DatabaseSchema{DbAlias: "db", Table: "posts_history", Schema: `type posts_history { _id: ID! @primary row_key: String! }`},
+	})
+	if err != nil {
+		t.Fatal("error parsing schema", err)
+	}
+
+	mockCrud := mockGraphQLCrudInterface{}
+	mockCrud.On("GetPreparedQueries").Return(config.DatabasePreparedQueries{
+		"db--getUser": &config.DatbasePreparedQuery{ID: "getUser", DbAlias: "db", SQL: "select * from users where id = $1", Arguments: []string{"args.id"}},
+	})
+	mockFunction := mockGraphQLFunctionInterface{}
+	mockFunction.On("GetServices").Return(config.Services{
+		"payments": &config.Service{ID: "payments", Endpoints: map[string]*config.Endpoint{"charge": {}}},
+	})
+	mockSchema := &mockGraphQLSchemaInterface{}
+	mockSchema.On("GetSchemas").Return(schemas)
+
+	mockAuth := mockGraphQLAuthInterface{}
+	mockAuth.On("IsTableDenied", mock.Anything, mock.Anything, "db", "posts_history").Return(true)
+	mockAuth.On("IsTableDenied", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false)
+	mockAuth.On("IsTokenAdmin", mock.Anything, "admin").Return(nil)
+	mockAuth.On("IsTokenAdmin", mock.Anything, mock.Anything).Return(errors.New("token is not an admin token"))
+
+	return graphql.New(&mockAuth, &mockCrud, &mockFunction, mockSchema), mockSchema
+}
+
+func TestModule_GenerateSDL(t *testing.T) {
+	graph, _ := newIntrospectionModule(t)
+	sdl, err := graph.GenerateSDL(context.Background())
+	if err != nil {
+		t.Fatal("GenerateSDL() error", err)
+	}
+
+	want := []string{
+		"directive @db(cache: JSON, col: String) on FIELD",
+		"directive @payments(cache: JSON, func: String, timeout: Int) on FIELD",
+		"type users {\n  address: users_address\n  age: Int\n  deleted_at: DateTime\n  id: ID!\n  name: String\n  posts: [posts]\n}",
+		"type db2_users {\n  id: ID!\n  meta: JSON\n}",
+		"input users_where {\n  _or: [users_where!]\n  age: Int_comparison\n  deleted_at: DateTime_comparison\n  id: ID_comparison\n  name: String_comparison\n}",
+		"input users_sort {\n  age: sort_order\n  deleted_at: sort_order\n  id: sort_order\n  name: sort_order\n}",
+		"input users_insert_input {\n  address: JSON\n  age: Int\n  deleted_at: DateTime\n  id: ID\n  name: String\n  posts: [posts_insert_input]\n}",
+		"  users(after: String, before: String, debug: Boolean, distinct: String, group: [String!], includeDeleted: Boolean, join: JSON, limit: Int, op: String, readYourWrites: Boolean, returnType: String, skip: Int, sort: [users_sort!], where: users_where): [users]\n",
+		"  getUser(debug: Boolean, id: JSON, readYourWrites: Boolean): JSON\n",
+		"  charge: JSON\n",
+		"  insert_users(docs: [users_insert_input!]!): users_mutation_response\n",
+		"  restore_users(where: users_where): users_mutation_response\n",
+	}
+	for _, w := range want {
+		if !strings.Contains(sdl, w) {
+			t.Errorf("GenerateSDL() does not contain %q", w)
+		}
+	}
+	if strings.Contains(sdl, "restore_posts") {
+		t.Error("GenerateSDL() added restore mutation for a table without soft delete")
+	}
+	if strings.Contains(sdl, "posts_history") {
+		t.Error("GenerateSDL() added a table on which all operations are denied")
+	}
+}
+
+func TestModule_ExecGraphQLQuery_Introspection(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		token      string
+		public     bool
+		wantResult interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "root types",
+			query:      `query { __schema { queryType { name } mutationType { name } } }`,
+			token:      "admin",
+			wantResult: map[string]interface{}{"__schema": map[string]interface{}{"queryType": map[string]interface{}{"name": "Query"}, "mutationType": map[string]interface{}{"name": "Mutation"}}},
+		},
+		{
+			name:       "type of a table",
+			query:      `query { __type(name: "posts") { kind fields { name } } }`,
+			token:      "admin",
+			wantResult: map[string]interface{}{"__type": map[string]interface{}{"kind": "OBJECT", "fields": []interface{}{map[string]interface{}{"name": "author_id"}, map[string]interface{}{"name": "id"}, map[string]interface{}{"name": "title"}}}},
+		},
+		{
+			name:    "unknown introspection field",
+			query:   `query { __schema { unknown } }`,
+			token:   "admin",
+			wantErr: true,
+		},
+		{
+			name:    "introspection is only allowed for admins by default",
+			query:   `query { __schema { queryType { name } } }`,
+			token:   "user",
+			wantErr: true,
+		},
+		{
+			name:       "public introspection",
+			query:      `query { __schema { queryType { name } } }`,
+			token:      "user",
+			public:     true,
+			wantResult: map[string]interface{}{"__schema": map[string]interface{}{"queryType": map[string]interface{}{"name": "Query"}}},
+		},
+	}
+	graph, mockSchema := newIntrospectionModule(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph.SetPublicIntrospection(tt.public)
+			var result interface{}
+			var err error
+			graph.ExecGraphQLQuery(context.Background(), &model.GraphQLRequest{Query: tt.query}, tt.token, func(op interface{}, e error) {
+				result, err = op, e
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecGraphQLQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(result, tt.wantResult) {
+				t.Errorf("ExecGraphQLQuery() got = %v, want %v", result, tt.wantResult)
+			}
+		})
+	}
+
+	// The schema is built once and reused until the config changes
+	mockSchema.AssertNumberOfCalls(t, "GetSchemas", 1)
+	graph.ResetSchema()
+	graph.ExecGraphQLQuery(context.Background(), &model.GraphQLRequest{Query: `query { __schema { queryType { name } } }`}, "admin", func(interface{}, error) {})
+	mockSchema.AssertNumberOfCalls(t, "GetSchemas", 2)
+}
@@ -0,0 +1,221 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils/graphql"
+)
+
+func TestModule_ExecGraphQLQuery_Limits(t *testing.T) {
+	usersSchema := model.Fields{
+		"id":    &model.FieldType{FieldName: "id", Kind: model.TypeID},
+		"posts": &model.FieldType{FieldName: "posts", Kind: "posts", IsList: true, IsLinked: true, LinkedTable: &model.TableProperties{DBType: "db", Table: "posts", From: "id", To: "author_id"}},
+	}
+	postsSchema := model.Fields{"id": &model.FieldType{FieldName: "id", Kind: model.TypeID}}
+	dbMockArgs := []mockArgs{
+		{method: "GetDBType", args: []interface{}{"db"}, paramsReturned: []interface{}{string(model.Postgres), nil}},
+		{method: "IsPreparedQueryPresent", args: []interface{}{"db", "users"}, paramsReturned: []interface{}{false}},
+		{method: "GetDBType", args: []interface{}{"arithmetic"}, paramsReturned: []interface{}{"", errors.New("invalid db alias provided")}},
+	}
+	schemaMockArgs := []mockArgs{
+		{method: "GetSchema", args: []interface{}{"db", "users"}, paramsReturned: []interface{}{usersSchema, true}},
+		{method: "GetSchema", args: []interface{}{"db", "posts"}, paramsReturned: []interface{}{postsSchema, true}},
+	}
+
+	tests := []struct {
+		name             string
+		limits           *config.GraphQLLimits
+		req              *model.GraphQLRequest
+		crudMockArgs     []mockArgs
+		functionMockArgs []mockArgs
+		authMockArgs     []mockArgs
+		schemaMockArgs   []mockArgs
+		wantResult       interface{}
+		wantErr          string
+	}{
+		{
+			name:    "depth exceeds the limit",
+			limits:  &config.GraphQLLimits{MaxDepth: 2},
+			req:     &model.GraphQLRequest{Query: `query { users @db { posts { id } } }`},
+			wantErr: "Query depth (3) exceeds the maximum allowed depth (2)",
+		},
+		{
+			name:    "introspection query exceeding the depth limit",
+			limits:  &config.GraphQLLimits{MaxDepth: 2},
+			req:     &model.GraphQLRequest{Query: `query { __schema { types { fields { name } } } }`},
+			wantErr: "Query depth (4) exceeds the maximum allowed depth (2)",
+		},
+		{
+			name:    "number of fields exceeds the limit",
+			limits:  &config.GraphQLLimits{MaxFields: 3},
+			req:     &model.GraphQLRequest{Query: `query { users @db { id name posts { id } } }`},
+			wantErr: "Query selects 5 fields which exceeds the maximum allowed fields (3)",
+		},
+		{
+			name:           "cost of linked fields is multiplied by the limit",
+			limits:         &config.GraphQLLimits{MaxCost: 20},
+			req:            &model.GraphQLRequest{Query: `query { users(limit: 10) @db { id posts { id } } }`},
+			crudMockArgs:   dbMockArgs,
+			schemaMockArgs: schemaMockArgs,
+			wantErr:        "Query cost (21) exceeds the maximum allowed cost (20)",
+		},
+		{
+			name:           "default limit is used for queries without a limit",
+			limits:         &config.GraphQLLimits{MaxCost: 100, DefaultLimit: 50},
+			req:            &model.GraphQLRequest{Query: `query { users @db { id posts { id } } }`},
+			crudMockArgs:   dbMockArgs,
+			schemaMockArgs: schemaMockArgs,
+			wantErr:        "Query cost (101) exceeds the maximum allowed cost (100)",
+		},
+		{
+			name:           "default limit is used for queries with a limit of zero",
+			limits:         &config.GraphQLLimits{MaxCost: 100, DefaultLimit: 50},
+			req:            &model.GraphQLRequest{Query: `query { users(limit: 0) @db { id posts { id } } }`},
+			crudMockArgs:   dbMockArgs,
+			schemaMockArgs: schemaMockArgs,
+			wantErr:        "Query cost (101) exceeds the maximum allowed cost (100)",
+		},
+		{
+			name:   "joins and limit provided as variable",
+			limits: &config.GraphQLLimits{MaxCost: 10, JoinCost: 3},
+			req: &model.GraphQLRequest{
+				Query:     `query($limit: Int) { users(limit: $limit, join: [{type: "LEFT", table: "posts", on: {users__id: "posts.author_id"}, join: [{type: "LEFT", table: "comments", on: {posts__id: "comments.post_id"}}]}]) @db { id posts { id } } }`,
+				Variables: map[string]interface{}{"limit": float64(2)},
+			},
+			crudMockArgs:   dbMockArgs,
+			schemaMockArgs: schemaMockArgs,
+			wantErr:        "Query cost (13) exceeds the maximum allowed cost (10)",
+		},
+		{
+			name:           "remote service calls nested in a single row",
+			limits:         &config.GraphQLLimits{MaxCost: 10},
+			req:            &model.GraphQLRequest{Query: `query { users(op: "one") @db { id adder @arithmetic(func: "adder") { sum } } }`},
+			crudMockArgs:   dbMockArgs,
+			schemaMockArgs: schemaMockArgs,
+			wantErr:        "Query cost (11) exceeds the maximum allowed cost (10)",
+		},
+		{
+			name:   "query within the limits is executed",
+			limits: &config.GraphQLLimits{MaxDepth: 2, MaxFields: 2, MaxCost: 10},
+			req:    &model.GraphQLRequest{Query: `query { adder(num1: 10, num2: 20) @arithmetic(timeout: 10, func: "adder") { sum } }`},
+			crudMockArgs: []mockArgs{
+				{method: "GetDBType", args: []interface{}{"arithmetic"}, paramsReturned: []interface{}{"", errors.New("invalid db alias provided")}},
+			},
+			functionMockArgs: []mockArgs{
+				{
+					method:         "CallWithContext",
+					args:           []interface{}{mock.Anything, "arithmetic", "adder", "", model.RequestParams{Payload: map[string]interface{}{"num1": 10, "num2": 20}}, &model.FunctionsRequest{Timeout: 10, Params: map[string]interface{}{"num1": 10, "num2": 20}}},
+					paramsReturned: []interface{}{map[string]interface{}{"sum": 30}, nil},
+				},
+			},
+			authMockArgs: []mockArgs{
+				{
+					method:         "IsFuncCallAuthorised",
+					args:           []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything},
+					paramsReturned: []interface{}{&model.PostProcess{}, model.RequestParams{}, nil},
+				},
+			},
+			wantResult: map[string]interface{}{"adder": map[string]interface{}{"sum": 30}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCrud := mockGraphQLCrudInterface{}
+			for _, m := range tt.crudMockArgs {
+				mockCrud.On(m.method, m.args...).Return(m.paramsReturned...)
+			}
+			mockAuth := mockGraphQLAuthInterface{}
+			for _, m := range tt.authMockArgs {
+				mockAuth.On(m.method, m.args...).Return(m.paramsReturned...)
+			}
+			mockFunction := mockGraphQLFunctionInterface{}
+			for _, m := range tt.functionMockArgs {
+				mockFunction.On(m.method, m.args...).Return(m.paramsReturned...)
+			}
+			mockSchema := mockGraphQLSchemaInterface{}
+			for _, m := range tt.schemaMockArgs {
+				mockSchema.On(m.method, m.args...).Return(m.paramsReturned...)
+			}
+
+			graph := graphql.New(&mockAuth, &mockCrud, &mockFunction, &mockSchema)
+			graph.SetQueryLimits(tt.limits)
+
+			var result interface{}
+			var err error
+			graph.ExecGraphQLQuery(context.Background(), tt.req, "", func(op interface{}, e error) {
+				result, err = op, e
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ExecGraphQLQuery() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ExecGraphQLQuery() unexpected error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(result, tt.wantResult) {
+				t.Errorf("ExecGraphQLQuery() got = %v, want %v", result, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestModule_CheckQueryLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  *config.GraphQLLimits
+		query   string
+		wantErr string
+	}{
+		{
+			name:    "subscription exceeding the depth limit",
+			limits:  &config.GraphQLLimits{MaxDepth: 1},
+			query:   `subscription { users @db { payload { id } } }`,
+			wantErr: "Query depth (3) exceeds the maximum allowed depth (1)",
+		},
+		{
+			name:    "subscription exceeding the fields limit",
+			limits:  &config.GraphQLLimits{MaxFields: 2},
+			query:   `subscription { users @db { type payload { id } } }`,
+			wantErr: "Query selects 4 fields which exceeds the maximum allowed fields (2)",
+		},
+		{
+			name:   "subscription within the limits",
+			limits: &config.GraphQLLimits{MaxDepth: 3, MaxFields: 4},
+			query:  `subscription { users @db { type payload { id } } }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := graphql.New(&mockGraphQLAuthInterface{}, &mockGraphQLCrudInterface{}, &mockGraphQLFunctionInterface{}, &mockGraphQLSchemaInterface{})
+			graph.SetQueryLimits(tt.limits)
+
+			doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(tt.query)})})
+			if err != nil {
+				t.Fatalf("Unable to parse query - %v", err)
+			}
+
+			err = graph.CheckQueryLimits(context.Background(), doc, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckQueryLimits() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("CheckQueryLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
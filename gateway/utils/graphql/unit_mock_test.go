@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/mock"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 )
 
@@ -28,6 +29,10 @@ func (m *mockGraphQLCrudInterface) Delete(ctx context.Context, dbAlias, collecti
 	args := m.Called(ctx, dbAlias, collection, request, params)
 	return args.Error(0)
 }
+func (m *mockGraphQLCrudInterface) Restore(ctx context.Context, dbAlias, collection string, request *model.RestoreRequest, params model.RequestParams) error {
+	args := m.Called(ctx, dbAlias, collection, request, params)
+	return args.Error(0)
+}
 func (m *mockGraphQLCrudInterface) Batch(ctx context.Context, dbAlias string, req *model.BatchRequest, params model.RequestParams) error {
 	args := m.Called(ctx, dbAlias, req, params)
 	return args.Error(0)
@@ -44,6 +49,10 @@ func (m *mockGraphQLCrudInterface) ExecPreparedQuery(ctx context.Context, dbAlia
 	args := m.Called(ctx, dbAlias, id, req, params)
 	return args.Get(0), args.Get(1).(*model.SQLMetaData), args.Error(2)
 }
+func (m *mockGraphQLCrudInterface) GetPreparedQueries() config.DatabasePreparedQueries {
+	args := m.Called()
+	return args.Get(0).(config.DatabasePreparedQueries)
+}
 
 type mockGraphQLAuthInterface struct {
 	mock.Mock
@@ -54,6 +63,14 @@ func (m *mockGraphQLAuthInterface) ParseToken(ctx context.Context, token string)
 	return args.Get(0).(map[string]interface{}), args.Error(1)
 }
 
+func (m *mockGraphQLAuthInterface) IsTokenAdmin(ctx context.Context, token string) error {
+	return m.Called(ctx, token).Error(0)
+}
+
+func (m *mockGraphQLAuthInterface) IsTableDenied(ctx context.Context, project, dbAlias, col string) bool {
+	return m.Called(ctx, project, dbAlias, col).Bool(0)
+}
+
 func (m *mockGraphQLAuthInterface) IsCreateOpAuthorised(ctx context.Context, project, dbAlias, col, token string, req *model.CreateRequest) (model.RequestParams, error) {
 	args := m.Called(ctx, project, dbAlias, col, token, req)
 	return args.Get(0).(model.RequestParams), args.Error(1)
@@ -70,6 +87,11 @@ func (m *mockGraphQLAuthInterface) IsDeleteOpAuthorised(ctx context.Context, pro
 	args := m.Called(ctx, project, dbAlias, col, token, req)
 	return args.Get(0).(model.RequestParams), args.Error(1)
 }
+func (m *mockGraphQLAuthInterface) IsRestoreOpAuthorised(ctx context.Context, project, dbAlias, col, token string, req *model.RestoreRequest) (model.RequestParams, error) {
+	args := m.Called(ctx, project, dbAlias, col, token, req)
+	return args.Get(0).(model.RequestParams), args.Error(1)
+}
+
 func (m *mockGraphQLAuthInterface) IsFuncCallAuthorised(ctx context.Context, project, service, function, token string, params interface{}) (*model.PostProcess, model.RequestParams, error) {
 	args := m.Called(ctx, project, service, function, token, params)
 	return args.Get(0).(*model.PostProcess), args.Get(1).(model.RequestParams), args.Error(2)
@@ -91,6 +113,10 @@ func (m *mockGraphQLFunctionInterface) CallWithContext(ctx context.Context, serv
 	args := m.Called(ctx, service, function, token, reqParams, req)
 	return 0, args.Get(0).(interface{}), args.Error(1)
 }
+func (m *mockGraphQLFunctionInterface) GetServices() config.Services {
+	args := m.Called()
+	return args.Get(0).(config.Services)
+}
 
 type mockGraphQLSchemaInterface struct {
 	mock.Mock
@@ -100,3 +126,7 @@ func (m *mockGraphQLSchemaInterface) GetSchema(dbAlias, col string) (model.Field
 	args := m.Called(dbAlias, col)
 	return args.Get(0).(model.Fields), args.Bool(1)
 }
+func (m *mockGraphQLSchemaInterface) GetSchemas() model.Type {
+	args := m.Called()
+	return args.Get(0).(model.Type)
+}
@@ -0,0 +1,152 @@
+package graphql_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils/graphql"
+)
+
+func TestModule_ExecGraphQLQuery_PersistedQueries(t *testing.T) {
+	const (
+		adderQuery      = `query { adder(num1: 10, num2: 20) @arithmetic(timeout: 10, func: "adder") { sum } }`
+		unknownHash     = "0000000000000000000000000000000000000000000000000000000000000000"
+		registeredQuery = `query {
+			adder(num1: 10, num2: 20) @arithmetic(timeout: 10, func: "adder") {
+				sum
+			}
+		}`
+	)
+	adderResult := map[string]interface{}{"adder": map[string]interface{}{"sum": 30}}
+
+	type request struct {
+		req        *model.GraphQLRequest
+		wantResult interface{}
+		wantErr    string
+	}
+	tests := []struct {
+		name         string
+		enforce      bool
+		persisted    config.GraphQLPersistedQueries
+		isAdmin      bool
+		requests     []request
+		executeCount int
+	}{
+		{
+			name: "unknown hash",
+			requests: []request{
+				{req: &model.GraphQLRequest{Extensions: persistedQueryExtension(unknownHash)}, wantErr: "PersistedQueryNotFound"},
+			},
+		},
+		{
+			name: "hash does not match the query",
+			requests: []request{
+				{req: &model.GraphQLRequest{Query: adderQuery, Extensions: persistedQueryExtension(unknownHash)}, wantErr: "Provided sha256 hash does not match the query"},
+			},
+		},
+		{
+			name: "query is registered automatically",
+			requests: []request{
+				{req: &model.GraphQLRequest{Extensions: persistedQueryExtension(hashOf(adderQuery))}, wantErr: "PersistedQueryNotFound"},
+				{req: &model.GraphQLRequest{Query: adderQuery, Extensions: persistedQueryExtension(hashOf(adderQuery))}, wantResult: adderResult},
+				{req: &model.GraphQLRequest{Extensions: persistedQueryExtension(hashOf(adderQuery))}, wantResult: adderResult},
+			},
+			executeCount: 2,
+		},
+		{
+			name:    "queries are not registered automatically when allow list is enforced",
+			enforce: true,
+			isAdmin: true,
+			requests: []request{
+				{req: &model.GraphQLRequest{Query: adderQuery, Extensions: persistedQueryExtension(hashOf(adderQuery))}, wantResult: adderResult},
+				{req: &model.GraphQLRequest{Extensions: persistedQueryExtension(hashOf(adderQuery))}, wantErr: "PersistedQueryNotFound"},
+			},
+			executeCount: 1,
+		},
+		{
+			name:    "query which isn't persisted is rejected for non admins",
+			enforce: true,
+			requests: []request{
+				{req: &model.GraphQLRequest{Query: adderQuery}, wantErr: "Query is not present in the persisted queries of the project"},
+			},
+		},
+		{
+			name:    "query which isn't persisted is allowed for admins",
+			enforce: true,
+			isAdmin: true,
+			requests: []request{
+				{req: &model.GraphQLRequest{Query: adderQuery}, wantResult: adderResult},
+			},
+			executeCount: 1,
+		},
+		{
+			name:      "persisted query is allowed for non admins",
+			enforce:   true,
+			persisted: config.GraphQLPersistedQueries{"adder": &config.GraphQLPersistedQuery{ID: hashOf(registeredQuery), Query: registeredQuery}},
+			requests: []request{
+				{req: &model.GraphQLRequest{Extensions: persistedQueryExtension(hashOf(registeredQuery))}, wantResult: adderResult},
+				{req: &model.GraphQLRequest{Query: registeredQuery}, wantResult: adderResult},
+				{req: &model.GraphQLRequest{Query: adderQuery}, wantResult: adderResult},
+			},
+			executeCount: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCrud := mockGraphQLCrudInterface{}
+			mockCrud.On("GetDBType", "arithmetic").Return("", errors.New("invalid db alias provided"))
+			mockAuth := mockGraphQLAuthInterface{}
+			mockAuth.On("IsFuncCallAuthorised", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&model.PostProcess{}, model.RequestParams{}, nil)
+			if tt.isAdmin {
+				mockAuth.On("IsTokenAdmin", mock.Anything, mock.Anything).Return(nil)
+			} else {
+				mockAuth.On("IsTokenAdmin", mock.Anything, mock.Anything).Return(errors.New("invalid admin token"))
+			}
+			mockFunction := mockGraphQLFunctionInterface{}
+			mockFunction.On("CallWithContext", mock.Anything, "arithmetic", "adder", "", mock.Anything, mock.Anything).Return(map[string]interface{}{"sum": 30}, nil)
+
+			graph := graphql.New(&mockAuth, &mockCrud, &mockFunction, &mockGraphQLSchemaInterface{})
+			graph.SetEnforcePersistedQueries(tt.enforce)
+			graph.SetPersistedQueries(context.Background(), tt.persisted)
+
+			for i, r := range tt.requests {
+				var result interface{}
+				var err error
+				graph.ExecGraphQLQuery(context.Background(), r.req, "", func(op interface{}, e error) {
+					result, err = op, e
+				})
+				if r.wantErr != "" {
+					if err == nil || err.Error() != r.wantErr {
+						t.Errorf("ExecGraphQLQuery() request %d error = %v, wantErr %v", i, err, r.wantErr)
+					}
+					continue
+				}
+				if err != nil {
+					t.Errorf("ExecGraphQLQuery() request %d unexpected error = %v", i, err)
+					continue
+				}
+				if !reflect.DeepEqual(result, r.wantResult) {
+					t.Errorf("ExecGraphQLQuery() request %d got = %v, want %v", i, result, r.wantResult)
+				}
+			}
+			mockFunction.AssertNumberOfCalls(t, "CallWithContext", tt.executeCount)
+		})
+	}
+}
+
+func persistedQueryExtension(hash string) *model.GraphQLExtensions {
+	return &model.GraphQLExtensions{PersistedQuery: &model.PersistedQuery{Version: 1, Sha256Hash: hash}}
+}
+
+func hashOf(query string) string {
+	hash := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,23 @@
+package utils
+
+import "fmt"
+
+// HistoryTable returns the name of the companion table storing the change history of the rows of a table
+func HistoryTable(table string) string {
+	return table + "_history"
+}
+
+// HistorySchema returns the schema of the companion table storing the change history of the rows of a table
+func HistorySchema(table string) string {
+	return fmt.Sprintf(`type %s {
+		_id: ID! @primary
+		row_key: ID! @index @size(value: 255)
+		operation: ID! @size(value: 10)
+		old_doc: JSON
+		new_doc: JSON
+		user_id: ID @size(value: 255)
+		claims: JSON
+		request_id: ID @size(value: 255)
+		ts: DateTime! @createdAt
+	  }`, HistoryTable(table))
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ImportReader reads the documents of an import one row at a time
+type ImportReader struct {
+	format string
+	row    int
+
+	lineReader *bufio.Reader
+	csvReader  *csv.Reader
+	header     []string
+}
+
+// NewImportReader creates a reader for the provided import format. The first line of a csv file must be the header.
+func NewImportReader(r io.Reader, format string) (*ImportReader, error) {
+	switch format {
+	case ExportFormatNDJSON:
+		return &ImportReader{format: format, lineReader: bufio.NewReader(r)}, nil
+	case ExportFormatCSV:
+		csvReader := csv.NewReader(r)
+		csvReader.ReuseRecord = true
+		header, err := csvReader.Read()
+		if err == io.EOF {
+			return &ImportReader{format: format, csvReader: csvReader}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the header of the csv file - %v", err)
+		}
+		return &ImportReader{format: format, csvReader: csvReader, header: append([]string{}, header...)}, nil
+	default:
+		return nil, fmt.Errorf("invalid import format (%s) provided", format)
+	}
+}
+
+// IsCSV returns true if the values of the documents being read are strings
+func (i *ImportReader) IsCSV() bool {
+	return i.format == ExportFormatCSV
+}
+
+// Next returns the next document along with its row number. Rows are numbered from 1 excluding the csv header and
+// empty lines. A row which cannot be parsed is returned as an ImportRowError so that the import can continue with the
+// rest of the rows. io.EOF is returned once all the rows have been read.
+func (i *ImportReader) Next() (map[string]interface{}, int, error) {
+	if i.format == ExportFormatCSV {
+		if i.header == nil {
+			return nil, 0, io.EOF
+		}
+
+		record, err := i.csvReader.Read()
+		if err == io.EOF {
+			return nil, 0, io.EOF
+		}
+		i.row++
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, i.row, &ImportRowError{err: parseErr.Err}
+			}
+			return nil, i.row, err
+		}
+
+		doc := make(map[string]interface{}, len(i.header))
+		for index, column := range i.header {
+			doc[column] = record[index]
+		}
+		return doc, i.row, nil
+	}
+
+	for {
+		line, err := i.lineReader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, i.row, err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err == io.EOF {
+				return nil, 0, io.EOF
+			}
+			continue
+		}
+
+		i.row++
+		doc := map[string]interface{}{}
+		if err := json.Unmarshal(line, &doc); err != nil {
+			return nil, i.row, &ImportRowError{err: err}
+		}
+		return doc, i.row, nil
+	}
+}
+
+// ImportRowError is returned when a single row of an import cannot be parsed
+type ImportRowError struct {
+	err error
+}
+
+func (e *ImportRowError) Error() string {
+	return fmt.Sprintf("unable to parse row - %v", e.err)
+}
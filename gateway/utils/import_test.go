@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportReader_Next(t *testing.T) {
+	type result struct {
+		Doc    map[string]interface{}
+		Row    int
+		RowErr bool
+	}
+	tests := []struct {
+		name    string
+		format  string
+		data    string
+		want    []result
+		wantErr bool
+	}{
+		{
+			name:   "ndjson with empty lines and an invalid row",
+			format: ExportFormatNDJSON,
+			data:   "{\"id\":\"1\",\"age\":20}\n\n{invalid}\n{\"id\":\"3\"}",
+			want: []result{
+				{Doc: map[string]interface{}{"id": "1", "age": float64(20)}, Row: 1},
+				{Row: 2, RowErr: true},
+				{Doc: map[string]interface{}{"id": "3"}, Row: 3},
+			},
+		},
+		{
+			name:   "csv with a row having extra fields",
+			format: ExportFormatCSV,
+			data:   "id,age\n1,20\n2,30,extra\n3,\n",
+			want: []result{
+				{Doc: map[string]interface{}{"id": "1", "age": "20"}, Row: 1},
+				{Row: 2, RowErr: true},
+				{Doc: map[string]interface{}{"id": "3", "age": ""}, Row: 3},
+			},
+		},
+		{
+			name:   "empty csv",
+			format: ExportFormatCSV,
+			data:   "",
+			want:   []result{},
+		},
+		{
+			name:    "invalid format",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewImportReader(strings.NewReader(tt.data), tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewImportReader() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := []result{}
+			for {
+				doc, row, err := reader.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					if _, ok := err.(*ImportRowError); !ok {
+						t.Errorf("Next() unexpected error = %v", err)
+						return
+					}
+					got = append(got, result{Row: row, RowErr: true})
+					continue
+				}
+				got = append(got, result{Doc: doc, Row: row})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Next() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
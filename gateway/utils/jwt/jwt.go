@@ -21,6 +21,9 @@ type JWT struct {
 	jwkSecrets           map[string]*jwkSecret
 	closeJwkRoutineChan  chan struct{}
 	mapJwkKidToSecretKid map[string]string
+
+	// The revoked sessions along with the time till which the tokens of the session remain valid
+	revokedSessions map[string]time.Time
 }
 
 type jwkSecret struct {
@@ -31,7 +34,12 @@ type jwkSecret struct {
 	set         *jwk.Set
 }
 
-const defaultRefreshTime = 1 * time.Hour
+const (
+	defaultRefreshTime = 1 * time.Hour
+
+	// TokenExpiry is the time after which the tokens created by space cloud expire
+	TokenExpiry = 30 * time.Minute
+)
 
 // New initializes the package
 func New() *JWT {
@@ -41,6 +49,7 @@ func New() *JWT {
 		jwkSecrets:           map[string]*jwkSecret{},
 		mapJwkKidToSecretKid: map[string]string{},
 		closeJwkRoutineChan:  ch,
+		revokedSessions:      map[string]time.Time{},
 	}
 	go func() {
 		tick := time.NewTicker(defaultRefreshTime)
@@ -13,11 +13,42 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/model"
 )
 
-// ParseToken verifies the token
+// ParseToken verifies the token. Tokens belonging to a revoked session are rejected
 func (j *JWT) ParseToken(ctx context.Context, token string) (map[string]interface{}, error) {
 	j.lock.RLock()
 	defer j.lock.RUnlock()
 
+	claims, err := j.parseToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if sid, ok := claims["sid"].(string); ok {
+		if _, p := j.revokedSessions[sid]; p {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Token belongs to a session which has been revoked", nil, map[string]interface{}{"sid": sid})
+		}
+	}
+	return claims, nil
+}
+
+// RevokeSessions adds sessions to the revocation list. A session is kept in the list till the tokens created for it
+// before the revocation have expired
+func (j *JWT) RevokeSessions(ids []string) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	now := time.Now()
+	for id, until := range j.revokedSessions {
+		if now.After(until) {
+			delete(j.revokedSessions, id)
+		}
+	}
+	for _, id := range ids {
+		j.revokedSessions[id] = now.Add(TokenExpiry)
+	}
+}
+
+func (j *JWT) parseToken(ctx context.Context, token string) (map[string]interface{}, error) {
 	parser := jwt.Parser{}
 	parsedToken, _, err := parser.ParseUnverified(token, jwt.MapClaims{})
 	if err != nil {
@@ -78,7 +109,7 @@ func (j *JWT) CreateToken(ctx context.Context, tokenClaims model.TokenClaims) (s
 	var tokenString string
 	var err error
 	// Add expiry of one week
-	claims["exp"] = time.Now().Add(30 * time.Minute).Unix()
+	claims["exp"] = time.Now().Add(TokenExpiry).Unix()
 	for _, s := range j.staticSecrets {
 		if s.IsPrimary {
 			switch s.Alg {
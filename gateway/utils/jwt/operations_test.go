@@ -0,0 +1,39 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func TestJWT_RevokeSessions(t *testing.T) {
+	j := &JWT{
+		staticSecrets:   map[string]*config.Secret{"1": {IsPrimary: true, Alg: config.HS256, KID: "1", Secret: "some-secret"}},
+		revokedSessions: map[string]time.Time{},
+	}
+
+	tests := []struct {
+		name    string
+		claims  map[string]interface{}
+		wantErr bool
+	}{
+		{name: "token without a session", claims: map[string]interface{}{"id": "1"}},
+		{name: "token of an active session", claims: map[string]interface{}{"id": "1", "sid": "active"}},
+		{name: "token of a revoked session", claims: map[string]interface{}{"id": "1", "sid": "revoked"}, wantErr: true},
+	}
+
+	j.RevokeSessions([]string{"revoked"})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := j.CreateToken(context.Background(), tt.claims)
+			if err != nil {
+				t.Fatalf("CreateToken() error = %v", err)
+			}
+			if _, err := j.ParseToken(context.Background(), token); (err != nil) != tt.wantErr {
+				t.Errorf("ParseToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
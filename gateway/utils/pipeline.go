@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OrderPipelineSortStages replaces the object provided to each $sort stage of the pipeline with an ordered document.
+// Decoding the pipeline to maps loses the order of the keys, which decides the order of a sort on multiple fields.
+// The raw pipeline is the json which was decoded to get the pipeline
+func OrderPipelineSortStages(pipeline interface{}, raw json.RawMessage) error {
+	stages, ok := pipeline.([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil
+	}
+
+	rawStages := make([]json.RawMessage, 0)
+	if err := json.Unmarshal(raw, &rawStages); err != nil {
+		return err
+	}
+	if len(rawStages) != len(stages) {
+		return fmt.Errorf("pipeline does not match the raw pipeline provided")
+	}
+
+	for i, item := range stages {
+		stage, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, p := stage["$sort"]; !p {
+			continue
+		}
+
+		rawStage := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(rawStages[i], &rawStage); err != nil {
+			return err
+		}
+
+		sort, err := decodeOrderedObject(rawStage["$sort"])
+		if err != nil {
+			return err
+		}
+		if sort != nil {
+			stage["$sort"] = sort
+		}
+	}
+	return nil
+}
+
+// decodeOrderedObject decodes a json object to a document which keeps the order of its keys. It returns nil
+// if the raw value isn't an object
+func decodeOrderedObject(raw json.RawMessage) (primitive.D, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, nil
+	}
+
+	doc := primitive.D{}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid key (%v) provided in object", token)
+		}
+
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		doc = append(doc, primitive.E{Key: key, Value: value})
+	}
+	return doc, nil
+}
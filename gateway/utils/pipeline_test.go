@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestOrderPipelineSortStages(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want interface{}
+	}{
+		{
+			name: "sort keys keep the order of the raw pipeline",
+			raw:  `[{"$match": {"age": 10}}, {"$sort": {"name": 1, "age": -1, "city": 1}}]`,
+			want: []interface{}{
+				map[string]interface{}{"$match": map[string]interface{}{"age": float64(10)}},
+				map[string]interface{}{"$sort": primitive.D{{Key: "name", Value: float64(1)}, {Key: "age", Value: float64(-1)}, {Key: "city", Value: float64(1)}}},
+			},
+		},
+		{
+			name: "sort stages in the array format are left as is",
+			raw:  `[{"$sort": ["name", "-age"]}]`,
+			want: []interface{}{
+				map[string]interface{}{"$sort": []interface{}{"name", "-age"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pipeline interface{}
+			if err := json.Unmarshal([]byte(tt.raw), &pipeline); err != nil {
+				t.Fatalf("OrderPipelineSortStages() unable to unmarshal pipeline - %v", err)
+			}
+
+			if err := OrderPipelineSortStages(pipeline, json.RawMessage(tt.raw)); err != nil {
+				t.Fatalf("OrderPipelineSortStages() error = %v", err)
+			}
+			if !reflect.DeepEqual(pipeline, tt.want) {
+				t.Errorf("OrderPipelineSortStages() got = %v, want %v", pipeline, tt.want)
+			}
+		})
+	}
+}
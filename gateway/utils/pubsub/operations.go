@@ -50,6 +50,16 @@ func (m *Module) Send(ctx context.Context, topic string, value interface{}) erro
 	return nil
 }
 
+// Publish broadcasts a message on a topic without waiting for an acknowledgement
+func (m *Module) Publish(ctx context.Context, topic string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return m.client.Publish(ctx, m.getTopicName(topic), string(data)).Err()
+}
+
 // SendAck acknowledges the receipt of a message
 func (m *Module) SendAck(ctx context.Context, replyTo string, ack bool) error {
 	// Prepare response message
@@ -0,0 +1,17 @@
+package utils
+
+const (
+	// TableSchemaMigrations is the table storing the schema migrations applied on a database
+	TableSchemaMigrations string = "schema_migrations"
+	// SchemaSchemaMigrations is the schema of the table storing the schema migrations
+	SchemaSchemaMigrations string = `type schema_migrations {
+		_id: ID! @primary
+		version: Integer! @unique
+		table_name: ID! @size(value: 255)
+		table_schema: String
+		previous_table_schema: String
+		up_queries: String!
+		down_queries: String!
+		status: ID! @size(value: 20)
+	  }`
+)
@@ -95,6 +95,18 @@ func adjustValTypes(v1, v2 interface{}) (interface{}, interface{}) {
 	return v1, v2
 }
 
+// isNullMatched checks if a null value satisfies the condition of the where clause
+func isNullMatched(cond interface{}) bool {
+	switch c := cond.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		v, p := c["$ne"]
+		return p && len(c) == 1 && v != nil
+	}
+	return false
+}
+
 // Validate checks if the provided document matches with the where clause
 func Validate(dbType string, where map[string]interface{}, obj interface{}) bool {
 	if res, ok := obj.(map[string]interface{}); ok {
@@ -121,6 +133,10 @@ func Validate(dbType string, where map[string]interface{}, obj interface{}) bool
 			if !p {
 				tempObj, err := LoadValue(k, res)
 				if err != nil {
+					// A missing field is treated as null
+					if isNullMatched(temp) {
+						continue
+					}
 					return false
 				}
 				val = tempObj
@@ -139,8 +155,9 @@ func Validate(dbType string, where map[string]interface{}, obj interface{}) bool
 			// match condition
 			for k2, v2 := range cond {
 				v2, val = adjustValTypes(v2, val)
-				if k2 != "$in" && k2 != "$nin" {
-					// In case of in and not in, the value of v2 will be an array
+				if k2 != "$in" && k2 != "$nin" && k2 != "$near" && k2 != "$within" {
+					// In case of in and not in, the value of v2 will be an array. The geo operators are provided an object
+					// irrespective of the value
 					if reflect.TypeOf(val) != reflect.TypeOf(v2) {
 						return false
 					}
@@ -273,6 +290,50 @@ func Validate(dbType string, where map[string]interface{}, obj interface{}) bool
 						return false
 					}
 					return r.MatchString(vString)
+				case "$search":
+					// Every word of the search query must be present in the text
+					query, ok1 := v2.(string)
+					text, ok2 := val.(string)
+					if !ok1 || !ok2 {
+						return false
+					}
+					text = strings.ToLower(text)
+					for _, word := range strings.Fields(strings.ToLower(query)) {
+						if !strings.Contains(text, word) {
+							return false
+						}
+					}
+				case "$near":
+					// A near clause without a max distance only sorts the documents
+					near, err := ParseGeoNear(v2)
+					if err != nil {
+						_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Invalid value provided for $near clause", err, nil)
+						return false
+					}
+					if near.MaxDistance == nil {
+						continue
+					}
+					point, ok := GetGeoJSON(val)
+					if !ok {
+						return false
+					}
+					distance, err := GeoDistance(point, near.Point)
+					if err != nil || distance > *near.MaxDistance {
+						return false
+					}
+				case "$within":
+					within, err := ParseGeoWithin(v2)
+					if err != nil {
+						_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Invalid value provided for $within clause", err, nil)
+						return false
+					}
+					point, ok := GetGeoJSON(val)
+					if !ok {
+						return false
+					}
+					if isWithin, err := IsGeoPointWithin(point, within); err != nil || !isWithin {
+						return false
+					}
 				default:
 					log.Printf("Invalid operator (%s) provided\n", k2)
 					return false
@@ -575,6 +575,87 @@ func TestValidate(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "valid search",
+			args: args{
+				dbType: string(model.EmbeddedDB),
+				where:  map[string]interface{}{"op2": map[string]interface{}{"$search": "Space cloud"}},
+				obj:    map[string]interface{}{"op2": "Introducing space Cloud"},
+			},
+			want: true,
+		},
+		{
+			name: "invalid search",
+			args: args{
+				dbType: string(model.EmbeddedDB),
+				where:  map[string]interface{}{"op2": map[string]interface{}{"$search": "space cloud"}},
+				obj:    map[string]interface{}{"op2": "introducing space"},
+			},
+			want: false,
+		},
+		{
+			name: "point near another point",
+			args: args{
+				dbType: string(model.Mongo),
+				where:  map[string]interface{}{"location": map[string]interface{}{"$near": map[string]interface{}{"point": map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.8777, 19.076}}, "maxDistance": 5000}}},
+				obj:    map[string]interface{}{"location": map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.88, 19.08}}},
+			},
+			want: true,
+		},
+		{
+			name: "point far from another point",
+			args: args{
+				dbType: string(model.Mongo),
+				where:  map[string]interface{}{"location": map[string]interface{}{"$near": map[string]interface{}{"point": map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.8777, 19.076}}, "maxDistance": 5000}}},
+				obj:    map[string]interface{}{"location": map[string]interface{}{"type": "Point", "coordinates": []interface{}{73.8567, 18.5204}}},
+			},
+			want: false,
+		},
+		{
+			name: "point within polygon",
+			args: args{
+				dbType: string(model.Postgres),
+				where:  map[string]interface{}{"location": map[string]interface{}{"$within": map[string]interface{}{"polygon": map[string]interface{}{"type": "Polygon", "coordinates": []interface{}{[]interface{}{[]interface{}{0, 0}, []interface{}{0, 10}, []interface{}{10, 10}, []interface{}{10, 0}, []interface{}{0, 0}}}}}}},
+				obj:    map[string]interface{}{"location": map[string]interface{}{"type": "Point", "coordinates": []interface{}{5, 5}}},
+			},
+			want: true,
+		},
+		{
+			name: "point outside radius",
+			args: args{
+				dbType: string(model.Postgres),
+				where:  map[string]interface{}{"location": map[string]interface{}{"$within": map[string]interface{}{"center": map[string]interface{}{"type": "Point", "coordinates": []interface{}{0, 0}}, "radius": 1000}}},
+				obj:    map[string]interface{}{"location": map[string]interface{}{"type": "Point", "coordinates": []interface{}{5, 5}}},
+			},
+			want: false,
+		},
+		{
+			name: "missing field matches null",
+			args: args{
+				dbType: string(model.EmbeddedDB),
+				where:  map[string]interface{}{"deleted_at": nil},
+				obj:    map[string]interface{}{"op1": "value"},
+			},
+			want: true,
+		},
+		{
+			name: "missing field matches not equal to",
+			args: args{
+				dbType: string(model.EmbeddedDB),
+				where:  map[string]interface{}{"is_deleted": map[string]interface{}{"$ne": true}},
+				obj:    map[string]interface{}{"op1": "value"},
+			},
+			want: true,
+		},
+		{
+			name: "soft deleted document does not match not equal to",
+			args: args{
+				dbType: string(model.EmbeddedDB),
+				where:  map[string]interface{}{"is_deleted": map[string]interface{}{"$ne": true}},
+				obj:    map[string]interface{}{"is_deleted": true},
+			},
+			want: false,
+		},
 		{
 			name: "valid contains single field match",
 			args: args{
@@ -11,7 +11,9 @@ import (
 	"github.com/spaceuptech/space-cloud/space-cli/cmd/modules"
 	"github.com/spaceuptech/space-cloud/space-cli/cmd/modules/accounts"
 	"github.com/spaceuptech/space-cloud/space-cli/cmd/modules/addons"
+	"github.com/spaceuptech/space-cloud/space-cli/cmd/modules/database"
 	"github.com/spaceuptech/space-cloud/space-cli/cmd/modules/deploy"
+	"github.com/spaceuptech/space-cloud/space-cli/cmd/modules/graphql"
 	"github.com/spaceuptech/space-cloud/space-cli/cmd/modules/login"
 	"github.com/spaceuptech/space-cloud/space-cli/cmd/modules/logs"
 	"github.com/spaceuptech/space-cloud/space-cli/cmd/modules/operations"
@@ -114,6 +116,8 @@ func GetRootCommand() *cobra.Command {
 	rootCmd.AddCommand(login.Commands()...)
 	rootCmd.AddCommand(accounts.Commands()...)
 	rootCmd.AddCommand(logs.GetSubCommands()...)
+	rootCmd.AddCommand(database.DBCommands()...)
+	rootCmd.AddCommand(graphql.Commands()...)
 	rootCmd.AddCommand(completionCmd)
 	return rootCmd
 }
@@ -6,6 +6,18 @@ import (
 	"github.com/spaceuptech/space-cloud/space-cli/cmd/utils"
 )
 
+// DBCommands is the list of commands the database module exposes under the db command
+func DBCommands() []*cobra.Command {
+	var dbCmd = &cobra.Command{
+		Use:           "db",
+		Short:         "Manage the databases of a project",
+		SilenceErrors: true,
+	}
+
+	dbCmd.AddCommand(migrateCommand(), exportSchemaCommand(), copyCommand(), importCommand())
+	return []*cobra.Command{dbCmd}
+}
+
 // GenerateSubCommands is the list of commands the database module exposes
 func GenerateSubCommands() []*cobra.Command {
 
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/spaceuptech/space-cloud/space-cli/cmd/utils"
+)
+
+// CopyCheckpoint is the progress of a copy sent by space cloud after every chunk
+type CopyCheckpoint struct {
+	Cursor    string `json:"cursor"`
+	Processed int64  `json:"processed"`
+	Copied    int64  `json:"copied"`
+	Errors    []struct {
+		Row   int    `json:"row"`
+		Error string `json:"error"`
+	} `json:"errors"`
+	Done  bool   `json:"done"`
+	Error string `json:"error"`
+}
+
+func copyCommand() *cobra.Command {
+	var copyCmd = &cobra.Command{
+		Use:     "copy [db-alias] [collection] [target-db-alias] [target-collection]",
+		Short:   "Copies the rows of a collection to a collection of another database",
+		Example: "1) space-cli db copy mongo users postgres --create-table --project myproject\n2) space-cli db copy mongo users postgres users --resume --project myproject",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			for _, flag := range []string{"chunk-size", "create-table", "checkpoint", "resume"} {
+				if err := viper.BindPFlag(flag, cmd.Flags().Lookup(flag)); err != nil {
+					_ = utils.LogError(fmt.Sprintf("Unable to bind the flag ('%s')", flag), nil)
+				}
+			}
+		},
+		RunE: actionCopy,
+	}
+
+	copyCmd.Flags().IntP("chunk-size", "", 0, "The number of rows copied at once")
+	copyCmd.Flags().BoolP("create-table", "", false, "Create the target collection from the schema of the source collection if it doesn't have a schema")
+	copyCmd.Flags().StringP("checkpoint", "", "", "The file to store the checkpoint of the copy in. Defaults to [db-alias]-[collection].checkpoint")
+	copyCmd.Flags().BoolP("resume", "", false, "Resume the copy from the cursor stored in the checkpoint file")
+	return copyCmd
+}
+
+func actionCopy(cmd *cobra.Command, args []string) error {
+	project, check := utils.GetProjectID()
+	if !check {
+		return utils.LogError("Project not specified in flag", nil)
+	}
+	if len(args) < 3 || len(args) > 4 {
+		return utils.LogError("Database alias, collection and target database alias must be provided", nil)
+	}
+	targetCol := args[1]
+	if len(args) == 4 {
+		targetCol = args[3]
+	}
+
+	checkpointFile := viper.GetString("checkpoint")
+	if checkpointFile == "" {
+		checkpointFile = fmt.Sprintf("%s-%s.checkpoint", args[0], args[1])
+	}
+
+	var cursor string
+	if viper.GetBool("resume") {
+		data, err := ioutil.ReadFile(checkpointFile)
+		if err != nil {
+			return utils.LogError(fmt.Sprintf("Unable to read checkpoint file (%s)", checkpointFile), err)
+		}
+		cursor = string(bytes.TrimSpace(data))
+		if cursor == "" {
+			return utils.LogError(fmt.Sprintf("No checkpoint stored in file (%s)", checkpointFile), nil)
+		}
+		utils.LogInfo(fmt.Sprintf("Resuming copy from checkpoint stored in file (%s)", checkpointFile))
+	}
+
+	body := map[string]interface{}{
+		"targetDbAlias": args[2],
+		"targetCol":     targetCol,
+		"chunkSize":     viper.GetInt("chunk-size"),
+		"cursor":        cursor,
+		"createTable":   viper.GetBool("create-table"),
+	}
+	var copied int64
+	err := CopyTable(project, args[0], args[1], body, func(checkpoint *CopyCheckpoint) error {
+		copied = checkpoint.Copied
+		for _, rowErr := range checkpoint.Errors {
+			utils.LogInfo(fmt.Sprintf("Row %d failed - %s", rowErr.Row, rowErr.Error))
+		}
+		if checkpoint.Done {
+			_ = os.Remove(checkpointFile)
+			return nil
+		}
+		utils.LogInfo(fmt.Sprintf("Copied %d rows", copied))
+		// Sources which cannot resume a copy don't send a cursor
+		if checkpoint.Cursor == "" {
+			return nil
+		}
+		return ioutil.WriteFile(checkpointFile, []byte(checkpoint.Cursor), 0644)
+	})
+	if err != nil {
+		return err
+	}
+
+	utils.LogInfo(fmt.Sprintf("Successfully copied %d rows to collection (%s) of database (%s)", copied, targetCol, args[2]))
+	return nil
+}
+
+// CopyTable asks space cloud to copy a collection and invokes the callback with every checkpoint it streams back
+func CopyTable(project, dbAlias, col string, body map[string]interface{}, cb func(checkpoint *CopyCheckpoint) error) error {
+	account, token, err := utils.LoginWithSelectedAccount()
+	if err != nil {
+		return utils.LogError("Couldn't get account details or login token", err)
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/v1/config/projects/%s/database/%s/collections/%s/copy", account.ServerURL, project, dbAlias, col)
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer utils.CloseTheCloser(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		respBody := map[string]interface{}{}
+		_ = json.NewDecoder(resp.Body).Decode(&respBody)
+		_ = utils.LogError(fmt.Sprintf("error while copying collection got http status code %s - %s", resp.Status, respBody["error"]), nil)
+		return fmt.Errorf("received invalid status code (%d)", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	done := false
+	for scanner.Scan() {
+		checkpoint := new(CopyCheckpoint)
+		if err := json.Unmarshal(scanner.Bytes(), checkpoint); err != nil {
+			return err
+		}
+		if checkpoint.Error != "" {
+			return utils.LogError(fmt.Sprintf("Copy stopped before all the rows were copied - %s. Use the --resume flag to resume it", checkpoint.Error), nil)
+		}
+		if err := cb(checkpoint); err != nil {
+			return err
+		}
+		done = checkpoint.Done
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if !done {
+		return utils.LogError("Copy stopped before all the rows were copied. Use the --resume flag to resume it", nil)
+	}
+	return nil
+}
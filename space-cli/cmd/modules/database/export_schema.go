@@ -0,0 +1,89 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/spaceuptech/space-cloud/space-cli/cmd/utils"
+)
+
+func exportSchemaCommand() *cobra.Command {
+	var exportCmd = &cobra.Command{
+		Use:     "export-schema [db-alias] [collection]",
+		Short:   "Exports the schemas of the collections as json schema documents or as an openapi spec of their crud endpoints",
+		Example: "1) space-cli db export-schema --project myproject > openapi.yaml\n2) space-cli db export-schema db users --format jsonschema --output json --project myproject",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			err := viper.BindPFlag("format", cmd.Flags().Lookup("format"))
+			if err != nil {
+				_ = utils.LogError("Unable to bind the flag ('format')", nil)
+			}
+			err = viper.BindPFlag("output", cmd.Flags().Lookup("output"))
+			if err != nil {
+				_ = utils.LogError("Unable to bind the flag ('output')", nil)
+			}
+		},
+		RunE: actionExportSchema,
+	}
+
+	exportCmd.Flags().StringP("format", "", "openapi", "The format to export the schemas in (openapi or jsonschema)")
+	exportCmd.Flags().StringP("output", "o", "yaml", "The format to print the export in (yaml or json)")
+
+	if err := exportCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"openapi", "jsonschema"}, cobra.ShellCompDirectiveDefault
+	}); err != nil {
+		utils.LogDebug("Unable to provide suggetion for flag ('format')", nil)
+	}
+	return exportCmd
+}
+
+func actionExportSchema(cmd *cobra.Command, args []string) error {
+	project, check := utils.GetProjectID()
+	if !check {
+		return utils.LogError("Project not specified in flag", nil)
+	}
+	if len(args) > 2 {
+		return utils.LogError("Only the database alias and collection can be provided", nil)
+	}
+
+	params := map[string]string{"format": viper.GetString("format")}
+	if len(args) > 0 {
+		params["dbAlias"] = args[0]
+	}
+	if len(args) > 1 {
+		params["col"] = args[1]
+	}
+
+	payload := new(struct {
+		Result json.RawMessage `json:"result"`
+	})
+	url := fmt.Sprintf("/v1/config/projects/%s/database/collections/schema/export", project)
+	if err := utils.Get(http.MethodGet, url, params, payload); err != nil {
+		return err
+	}
+
+	var data []byte
+	var err error
+	switch output := viper.GetString("output"); output {
+	case "json":
+		var v interface{}
+		if err := json.Unmarshal(payload.Result, &v); err != nil {
+			return err
+		}
+		data, err = json.MarshalIndent(v, "", "  ")
+	case "yaml":
+		data, err = yaml.JSONToYAML(payload.Result)
+	default:
+		return utils.LogError(fmt.Sprintf("Invalid output format (%s) provided - wanted yaml or json", output), nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/spaceuptech/space-cloud/space-cli/cmd/utils"
+)
+
+// importCommand returns the command to import the rows of an ndjson or csv file into a collection
+func importCommand() *cobra.Command {
+	var importData = &cobra.Command{
+		Use:     "import [db-alias] [collection] [path to file]",
+		Short:   "Imports the rows of an ndjson or csv file into a collection",
+		Example: "1) space-cli db import db users users.ndjson --project myproject\n2) space-cli db import db users users.csv --project myproject --mode atomic --chunk-size 500",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			err := viper.BindPFlag("format", cmd.Flags().Lookup("format"))
+			if err != nil {
+				_ = utils.LogError("Unable to bind the flag ('format')", nil)
+			}
+			err = viper.BindPFlag("mode", cmd.Flags().Lookup("mode"))
+			if err != nil {
+				_ = utils.LogError("Unable to bind the flag ('mode')", nil)
+			}
+			err = viper.BindPFlag("chunk-size", cmd.Flags().Lookup("chunk-size"))
+			if err != nil {
+				_ = utils.LogError("Unable to bind the flag ('chunk-size')", nil)
+			}
+		},
+		RunE: actionImportData,
+	}
+
+	importData.Flags().StringP("format", "", "", "The format of the file (ndjson or csv). Defaults to the extension of the file")
+	importData.Flags().StringP("mode", "", "best-effort", "Insert all the rows or none of them (atomic) or insert the valid rows only (best-effort)")
+	importData.Flags().IntP("chunk-size", "", 0, "The number of rows inserted at once")
+
+	if err := importData.RegisterFlagCompletionFunc("mode", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"atomic", "best-effort"}, cobra.ShellCompDirectiveDefault
+	}); err != nil {
+		utils.LogDebug("Unable to provide suggetion for flag ('mode')", nil)
+	}
+	return importData
+}
+
+func actionImportData(cmd *cobra.Command, args []string) error {
+	project, check := utils.GetProjectID()
+	if !check {
+		return utils.LogError("Project not specified in flag", nil)
+	}
+	if len(args) != 3 {
+		return utils.LogError("Database alias, collection and file must be provided", nil)
+	}
+
+	format := viper.GetString("format")
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(args[2]), ".")
+	}
+
+	report, err := ImportData(project, args[0], args[1], args[2], format, viper.GetString("mode"), viper.GetInt("chunk-size"))
+	if err != nil {
+		return err
+	}
+
+	utils.LogInfo(fmt.Sprintf("Inserted %d rows", report.Inserted))
+	for _, rowErr := range report.Errors {
+		utils.LogInfo(fmt.Sprintf("Row %d failed - %s", rowErr.Row, rowErr.Error))
+	}
+	return nil
+}
+
+// ImportReport is the report of an import sent by space cloud
+type ImportReport struct {
+	Inserted int64 `json:"inserted"`
+	Errors   []struct {
+		Row   int    `json:"row"`
+		Error string `json:"error"`
+	} `json:"errors"`
+}
+
+// ImportData streams the file to space cloud to be imported
+func ImportData(project, dbAlias, col, path, format, mode string, chunkSize int) (*ImportReport, error) {
+	if format != "ndjson" && format != "csv" {
+		return nil, utils.LogError(fmt.Sprintf("Invalid format (%s) provided. Use ndjson or csv", format), nil)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, utils.LogError(fmt.Sprintf("Unable to open file (%s)", path), err)
+	}
+	defer utils.CloseTheCloser(file)
+
+	account, token, err := utils.LoginWithSelectedAccount()
+	if err != nil {
+		return nil, utils.LogError("Couldn't get account details or login token", err)
+	}
+
+	params := url.Values{}
+	params.Set("format", format)
+	params.Set("mode", mode)
+	if chunkSize > 0 {
+		params.Set("chunkSize", strconv.Itoa(chunkSize))
+	}
+	u := fmt.Sprintf("%s/v1/api/%s/crud/%s/%s/import?%s", account.ServerURL, project, dbAlias, col, params.Encode())
+
+	req, err := http.NewRequest(http.MethodPost, u, file)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CloseTheCloser(resp.Body)
+
+	data, _ := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		respBody := map[string]interface{}{}
+		if err := json.Unmarshal(data, &respBody); err != nil {
+			return nil, err
+		}
+		_ = utils.LogError(fmt.Sprintf("error while importing data got http status code %s - %s", resp.Status, respBody["error"]), nil)
+		return nil, fmt.Errorf("received invalid status code (%d)", resp.StatusCode)
+	}
+
+	report := new(ImportReport)
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,191 @@
+package database
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/spaceuptech/space-cloud/space-cli/cmd/utils"
+)
+
+// SchemaMigration is a versioned change to the schema of a table
+type SchemaMigration struct {
+	Version        int      `json:"version" yaml:"version"`
+	DbAlias        string   `json:"dbAlias" yaml:"dbAlias"`
+	Table          string   `json:"table" yaml:"table"`
+	Schema         string   `json:"schema" yaml:"schema"`
+	PreviousSchema string   `json:"previousSchema" yaml:"previousSchema"`
+	Up             []string `json:"up" yaml:"up"`
+	Down           []string `json:"down" yaml:"down"`
+	Status         string   `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// migrateCommand returns the command to plan, apply and rollback the schema migrations of a database
+func migrateCommand() *cobra.Command {
+	var migrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Plan, apply and rollback the schema migrations of a database",
+	}
+
+	var planCmd = &cobra.Command{
+		Use:     "plan [db-alias] [table] [path to schema file]",
+		Short:   "Prints the queries which would be run to change the schema of a table without running them",
+		Example: "space-cli db migrate plan db users users.graphql --project myproject",
+		RunE:    actionPlanMigration,
+	}
+
+	var listCmd = &cobra.Command{
+		Use:     "list [db-alias]",
+		Short:   "Lists the schema migrations of a database",
+		Example: "space-cli db migrate list db --project myproject",
+		RunE:    actionListMigrations,
+	}
+
+	var applyCmd = &cobra.Command{
+		Use:     "apply [db-alias] [version]",
+		Short:   "Applies the rolled back schema migrations of a database till the version provided",
+		Example: "space-cli db migrate apply db 4 --project myproject",
+		RunE:    actionApplyMigrations,
+	}
+
+	var rollbackCmd = &cobra.Command{
+		Use:     "rollback [db-alias] [version]",
+		Short:   "Rolls back the schema migrations of a database newer than the version provided",
+		Example: "space-cli db migrate rollback db 2 --project myproject",
+		RunE:    actionRollbackMigrations,
+	}
+
+	migrateCmd.AddCommand(planCmd, listCmd, applyCmd, rollbackCmd)
+	return migrateCmd
+}
+
+func actionPlanMigration(cmd *cobra.Command, args []string) error {
+	project, check := utils.GetProjectID()
+	if !check {
+		return utils.LogError("Project not specified in flag", nil)
+	}
+	if len(args) != 3 {
+		return utils.LogError("Database alias, table and schema file must be provided", nil)
+	}
+
+	schema, err := ioutil.ReadFile(args[2])
+	if err != nil {
+		return utils.LogError(fmt.Sprintf("Unable to read schema file (%s)", args[2]), err)
+	}
+
+	migrations := make([]*SchemaMigration, 0)
+	url := fmt.Sprintf("/v1/config/projects/%s/database/%s/collections/%s/schema/plan", project, args[0], args[1])
+	if err := postMigrationRequest(url, map[string]interface{}{"schema": string(schema)}, &migrations); err != nil {
+		return err
+	}
+	return printMigrations(migrations)
+}
+
+func actionListMigrations(cmd *cobra.Command, args []string) error {
+	project, check := utils.GetProjectID()
+	if !check {
+		return utils.LogError("Project not specified in flag", nil)
+	}
+	if len(args) != 1 {
+		return utils.LogError("Database alias must be provided", nil)
+	}
+
+	payload := new(struct {
+		Result []*SchemaMigration `json:"result"`
+	})
+	url := fmt.Sprintf("/v1/config/projects/%s/database/%s/schema/migrations", project, args[0])
+	if err := utils.Get(http.MethodGet, url, map[string]string{}, payload); err != nil {
+		return err
+	}
+	return printMigrations(payload.Result)
+}
+
+func actionApplyMigrations(cmd *cobra.Command, args []string) error {
+	return migrate(args, "apply")
+}
+
+func actionRollbackMigrations(cmd *cobra.Command, args []string) error {
+	return migrate(args, "rollback")
+}
+
+func migrate(args []string, operation string) error {
+	project, check := utils.GetProjectID()
+	if !check {
+		return utils.LogError("Project not specified in flag", nil)
+	}
+	if len(args) != 2 {
+		return utils.LogError("Database alias and version must be provided", nil)
+	}
+	version, err := strconv.Atoi(args[1])
+	if err != nil {
+		return utils.LogError(fmt.Sprintf("Invalid version (%s) provided", args[1]), err)
+	}
+
+	url := fmt.Sprintf("/v1/config/projects/%s/database/%s/schema/migrations/%s", project, args[0], operation)
+	if err := postMigrationRequest(url, map[string]interface{}{"version": version}, nil); err != nil {
+		return err
+	}
+
+	utils.LogInfo(fmt.Sprintf("Successfully migrated schema of database (%s) to version %d", args[0], version))
+	return nil
+}
+
+func printMigrations(migrations []*SchemaMigration) error {
+	for _, migration := range migrations {
+		b, err := yaml.Marshal(migration)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(b))
+		fmt.Println("---")
+	}
+	return nil
+}
+
+// postMigrationRequest sends the request to space cloud and stores the result of the response in vPtr
+func postMigrationRequest(path string, body map[string]interface{}, vPtr interface{}) error {
+	account, token, err := utils.LoginWithSelectedAccount()
+	if err != nil {
+		return utils.LogError("Couldn't get account details or login token", err)
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, account.ServerURL+path, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer utils.CloseTheCloser(resp.Body)
+
+	respBody := struct {
+		Result json.RawMessage `json:"result"`
+		Error  string          `json:"error"`
+	}{}
+	_ = json.NewDecoder(resp.Body).Decode(&respBody)
+
+	if resp.StatusCode != http.StatusOK {
+		_ = utils.LogError(fmt.Sprintf("error while migrating schema got http status code %s - %s", resp.Status, respBody.Error), nil)
+		return fmt.Errorf("received invalid status code (%d)", resp.StatusCode)
+	}
+
+	if vPtr == nil || len(respBody.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody.Result, vPtr)
+}
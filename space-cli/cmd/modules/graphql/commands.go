@@ -0,0 +1,88 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/spaceuptech/space-cloud/space-cli/cmd/model"
+	"github.com/spaceuptech/space-cloud/space-cli/cmd/modules/operations"
+	"github.com/spaceuptech/space-cloud/space-cli/cmd/utils"
+)
+
+// Commands is the list of commands the graphql module exposes
+func Commands() []*cobra.Command {
+	var graphqlCmd = &cobra.Command{
+		Use:           "graphql",
+		Short:         "Manage the graphql queries of a project",
+		SilenceErrors: true,
+	}
+
+	var registerCmd = &cobra.Command{
+		Use:     "register [paths to files or directories]",
+		Short:   "Registers the graphql queries used by the client code as persisted queries",
+		Example: "1) space-cli graphql register src --project myproject\n2) space-cli graphql register queries.graphql persisted-query-manifest.json --project myproject --dry-run",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			err := viper.BindPFlag("dry-run", cmd.Flags().Lookup("dry-run"))
+			if err != nil {
+				_ = utils.LogError("Unable to bind the flag ('dry-run')", nil)
+			}
+		},
+		RunE: actionRegisterQueries,
+	}
+	registerCmd.Flags().BoolP("dry-run", "", false, "Prints the config of the extracted queries instead of registering them")
+
+	graphqlCmd.AddCommand(registerCmd)
+	return []*cobra.Command{graphqlCmd}
+}
+
+func actionRegisterQueries(cmd *cobra.Command, args []string) error {
+	project, check := utils.GetProjectID()
+	if !check {
+		return utils.LogError("Project not specified in flag", nil)
+	}
+	if len(args) == 0 {
+		return utils.LogError("Path to the files or directories containing the queries must be provided", nil)
+	}
+
+	queries, err := ExtractQueries(args)
+	if err != nil {
+		return err
+	}
+
+	specs, err := generatePersistedQuerySpecs(project, queries)
+	if err != nil {
+		return err
+	}
+
+	if viper.GetBool("dry-run") {
+		return utils.PrintYaml(specs)
+	}
+
+	account, token, err := utils.LoginWithSelectedAccount()
+	if err != nil {
+		return utils.LogError("Couldn't get account details or login token", err)
+	}
+	for _, spec := range specs {
+		if err := operations.ApplySpec(token, account, spec); err != nil {
+			return err
+		}
+	}
+
+	utils.LogInfo(fmt.Sprintf("Registered %d queries", len(specs)))
+	return nil
+}
+
+func generatePersistedQuerySpecs(project string, queries []*PersistedQuery) ([]*model.SpecObject, error) {
+	specs := make([]*model.SpecObject, 0, len(queries))
+	for _, query := range queries {
+		meta := map[string]string{"project": project, "id": query.ID}
+		spec, err := utils.CreateSpecObject("/v1/config/projects/{project}/graphql/persisted-queries/{id}", "graphql-persisted-query", meta, map[string]interface{}{"name": query.Name, "query": query.Query})
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
@@ -0,0 +1,159 @@
+package graphql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/spaceuptech/space-cloud/space-cli/cmd/utils"
+)
+
+// PersistedQuery is a graphql query to be added to the allow list of a project
+type PersistedQuery struct {
+	ID    string `json:"id" yaml:"id"`
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	Query string `json:"query" yaml:"query"`
+}
+
+// taggedTemplateRegex matches the graphql queries written in javascript and typescript files using the gql or graphql tags
+var taggedTemplateRegex = regexp.MustCompile("(?:gql|graphql)\\s*`([^`]*)`")
+
+// ExtractQueries extracts the graphql operations from the files and directories provided. Graphql documents (.graphql,
+// .gql), javascript and typescript files (.js, .jsx, .ts, .tsx) and persisted query manifests (.json) are supported.
+func ExtractQueries(paths []string) ([]*PersistedQuery, error) {
+	queries := make([]*PersistedQuery, 0)
+	seen := map[string]bool{}
+	for _, path := range paths {
+		err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if info.Name() == "node_modules" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			extracted, err := extractQueriesFromFile(filePath)
+			if err != nil {
+				return err
+			}
+			for _, query := range extracted {
+				if !seen[query.ID] {
+					seen[query.ID] = true
+					queries = append(queries, query)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, utils.LogError(fmt.Sprintf("Unable to extract queries from (%s)", path), err)
+		}
+	}
+	return queries, nil
+}
+
+func extractQueriesFromFile(path string) ([]*PersistedQuery, error) {
+	var documents []string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".graphql", ".gql":
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, string(data))
+
+	case ".js", ".jsx", ".ts", ".tsx":
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range taggedTemplateRegex.FindAllStringSubmatch(string(data), -1) {
+			if strings.Contains(match[1], "${") {
+				utils.LogInfo(fmt.Sprintf("Skipping a query in (%s) since it uses template interpolation", path))
+				continue
+			}
+			documents = append(documents, match[1])
+		}
+
+	case ".json":
+		return readManifest(path)
+	}
+
+	queries := make([]*PersistedQuery, 0)
+	for _, document := range documents {
+		extracted, err := extractOperations(document)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse graphql document in (%s) - %v", path, err)
+		}
+		queries = append(queries, extracted...)
+	}
+	return queries, nil
+}
+
+// extractOperations returns each operation of the graphql document as a separate query. The operations are printed in
+// the normalized format used by space cloud to match the queries.
+func extractOperations(document string) ([]*PersistedQuery, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(document)})})
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make([]*PersistedQuery, 0)
+	for _, definition := range doc.Definitions {
+		op, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		query, _ := printer.Print(&ast.Document{Kind: doc.Kind, Definitions: []ast.Node{op}}).(string)
+		name := ""
+		if op.Name != nil {
+			name = op.Name.Value
+		}
+		queries = append(queries, &PersistedQuery{ID: hashQuery(query), Name: name, Query: query})
+	}
+	return queries, nil
+}
+
+// readManifest reads the queries from a persisted query manifest generated by the graphql client
+func readManifest(path string) ([]*PersistedQuery, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := struct {
+		Operations []struct {
+			Name string `json:"name"`
+			Body string `json:"body"`
+		} `json:"operations"`
+	}{}
+	if err := json.Unmarshal(data, &manifest); err != nil || len(manifest.Operations) == 0 {
+		// Ignore the json files which aren't manifests
+		return nil, nil
+	}
+
+	queries := make([]*PersistedQuery, 0, len(manifest.Operations))
+	for _, op := range manifest.Operations {
+		queries = append(queries, &PersistedQuery{ID: hashQuery(op.Body), Name: op.Name, Query: op.Body})
+	}
+	return queries, nil
+}
+
+func hashQuery(query string) string {
+	hash := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,82 @@
+package graphql
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractQueries(t *testing.T) {
+	const getUsers = "query getUsers {\n  users @db {\n    id\n  }\n}\n"
+	const addUser = "mutation addUser($id: ID) {\n  insert_users(docs: [{id: $id}]) @db {\n    status\n  }\n}\n"
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []*PersistedQuery
+	}{
+		{
+			name: "graphql document with multiple operations",
+			files: map[string]string{
+				"queries.graphql": "query getUsers { users @db { id } }\n\nmutation addUser($id: ID) { insert_users(docs: [{id: $id}]) @db { status } }",
+			},
+			want: []*PersistedQuery{
+				{ID: hashQuery(getUsers), Name: "getUsers", Query: getUsers},
+				{ID: hashQuery(addUser), Name: "addUser", Query: addUser},
+			},
+		},
+		{
+			name: "tagged templates in javascript files",
+			files: map[string]string{
+				"src/users.js":     "const GET_USERS = gql`\n  query getUsers {\n    users @db { id }\n  }\n`\nconst q = gql`query { users(where: {id: ${id}}) @db { id } }`",
+				"src/duplicate.ts": "export const query = graphql`query getUsers { users @db { id } }`",
+			},
+			want: []*PersistedQuery{
+				{ID: hashQuery(getUsers), Name: "getUsers", Query: getUsers},
+			},
+		},
+		{
+			name: "persisted query manifest",
+			files: map[string]string{
+				"manifest.json": `{"format": "apollo-persisted-query-manifest", "version": 1, "operations": [{"id": "1", "name": "getUsers", "type": "query", "body": "query getUsers { users @db { id } }"}]}`,
+				"package.json":  `{"name": "app"}`,
+			},
+			want: []*PersistedQuery{
+				{ID: hashQuery("query getUsers { users @db { id } }"), Name: "getUsers", Query: "query getUsers { users @db { id } }"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "graphql-queries")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			for name, content := range tt.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := ExtractQueries([]string{dir})
+			if err != nil {
+				t.Errorf("ExtractQueries() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				for i, q := range got {
+					t.Logf("got query %d - %s %q", i, q.Name, q.Query)
+				}
+				t.Errorf("ExtractQueries() got queries which don't match the wanted queries")
+			}
+		})
+	}
+}
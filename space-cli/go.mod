@@ -10,6 +10,7 @@ require (
 	github.com/go-test/deep v1.0.7
 	github.com/google/go-cmp v0.4.1
 	github.com/google/uuid v1.1.1
+	github.com/graphql-go/graphql v0.7.8
 	github.com/olekukonko/tablewriter v0.0.4
 	github.com/segmentio/ksuid v1.0.2
 	github.com/sirupsen/logrus v1.7.0